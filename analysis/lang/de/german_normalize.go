@@ -0,0 +1,97 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package de
+
+import (
+	"bytes"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const NormalizeName = "normalize_de"
+
+const (
+	stateN = 0 /* ordinary state */
+	stateV = 1 /* stops 'u' from entering umlaut state */
+	stateU = 2 /* umlaut state, allows e-deletion */
+)
+
+// GermanNormalizeFilter folds the umlauts ä, ö and ü, and their
+// transcriptions ae, oe and ue, to a, o and u, and replaces ß with ss.
+type GermanNormalizeFilter struct {
+}
+
+func NewGermanNormalizeFilter() *GermanNormalizeFilter {
+	return &GermanNormalizeFilter{}
+}
+
+func (s *GermanNormalizeFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		term := normalize(token.Term)
+		token.Term = term
+	}
+	return input
+}
+
+func normalize(input []byte) []byte {
+	state := stateN
+	runes := bytes.Runes(input)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case 'a', 'o':
+			state = stateU
+		case 'u':
+			if state == stateN {
+				state = stateU
+			} else {
+				state = stateV
+			}
+		case 'e':
+			if state == stateU {
+				runes = analysis.DeleteRune(runes, i)
+				i--
+			}
+			state = stateV
+		case 'i', 'q', 'y':
+			state = stateV
+		case 'ä':
+			runes[i] = 'a'
+			state = stateV
+		case 'ö':
+			runes[i] = 'o'
+			state = stateV
+		case 'ü':
+			runes[i] = 'u'
+			state = stateV
+		case 'ß':
+			runes[i] = 's'
+			i++
+			runes = analysis.InsertRune(runes, i, 's')
+			state = stateN
+		default:
+			state = stateN
+		}
+	}
+	return analysis.BuildTermFromRunes(runes)
+}
+
+func NormalizerFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewGermanNormalizeFilter(), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(NormalizeName, NormalizerFilterConstructor)
+}
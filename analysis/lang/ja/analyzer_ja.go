@@ -0,0 +1,65 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ja
+
+import (
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+
+	"github.com/edwindvinas/bleve/analysis/lang/cjk"
+	"github.com/edwindvinas/bleve/analysis/token/lowercase"
+)
+
+// AnalyzerName is the type of the Japanese analyzer, which must be
+// defined in the index mapping with the "dictionary" property naming the
+// IPADIC files to load, and optionally a "user_dictionary":
+//
+//	"analyzers": {
+//		"japanese": {
+//			"type": "ja_ipadic",
+//			"dictionary": ["/usr/share/ipadic/Noun.csv", "/usr/share/ipadic/Verb.csv"]
+//		}
+//	}
+const AnalyzerName = "ja_ipadic"
+
+func AnalyzerConstructor(config map[string]interface{}, cache *registry.Cache) (*analysis.Analyzer, error) {
+	tokenizer, err := TokenizerConstructor(config, cache)
+	if err != nil {
+		return nil, err
+	}
+	dict := tokenizer.(*lattice.DictionaryTokenizer).Dictionary()
+	widthFilter, err := cache.TokenFilterNamed(cjk.WidthName)
+	if err != nil {
+		return nil, err
+	}
+	toLowerFilter, err := cache.TokenFilterNamed(lowercase.Name)
+	if err != nil {
+		return nil, err
+	}
+	rv := analysis.Analyzer{
+		Tokenizer: tokenizer,
+		TokenFilters: []analysis.TokenFilter{
+			lattice.NewBaseFormFilter(dict),
+			widthFilter,
+			toLowerFilter,
+		},
+	}
+	return &rv, nil
+}
+
+func init() {
+	registry.RegisterAnalyzer(AnalyzerName, AnalyzerConstructor)
+}
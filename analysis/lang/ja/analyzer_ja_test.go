@@ -0,0 +1,115 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ja
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// testIPADIC is an excerpt of IPADIC
+const testIPADIC = `東京,1293,1293,3003,名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー
+市場,1285,1285,3500,名詞,一般,*,*,*,*,市場,シジョウ,シジョー
+に,13,13,4304,助詞,格助詞,一般,*,*,*,に,ニ,ニ
+に,440,440,3000,助動詞,*,*,*,特殊・ダ,連用ニ接続,だ,ニ,ニ
+住ん,772,772,7653,動詞,自立,*,*,五段・マ行,連用タ接続,住む,スン,スン
+で,291,291,1500,助詞,接続助詞,*,*,*,*,で,デ,デ
+い,1000,1000,5000,動詞,非自立,*,*,一段,連用形,いる,イ,イ
+ます,500,500,2000,助動詞,*,*,*,特殊・マス,基本形,ます,マス,マス
+を,156,156,4183,助詞,格助詞,一般,*,*,*,を,ヲ,ヲ
+使っ,654,654,7000,動詞,自立,*,*,五段・ワ行促音便,連用タ接続,使う,ツカッ,ツカッ
+た,517,517,2000,助動詞,*,*,*,特殊・タ,基本形,た,タ,タ
+`
+
+// testMatrix holds some of the connection costs of IPADIC, to choose
+// the particle に after a noun rather than the auxiliary verb
+const testMatrix = `1316 1316
+0 1293 -300
+1293 13 -1500
+1293 440 1500
+13 772 -200
+772 291 -3000
+291 1000 -500
+1000 500 -1000
+`
+
+// writeTestIPADIC writes the test dictionary in a temporary directory,
+// which the caller removes.
+func writeTestIPADIC(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ipadic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "test.csv"), []byte(testIPADIC), 0600)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, "matrix.def"), []byte(testMatrix), 0600)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestJapaneseAnalyzer(t *testing.T) {
+	path := writeTestIPADIC(t)
+	defer func() {
+		_ = os.RemoveAll(path)
+	}()
+
+	tests := []struct {
+		input  []byte
+		output []string
+	}{
+		{
+			input:  []byte("東京に住んでいます"),
+			output: []string{"東京", "に", "住む", "で", "いる", "ます"},
+		},
+		{
+			input:  []byte("ＢＬＥＶＥを使った"),
+			output: []string{"bleve", "を", "使う", "た"},
+		},
+	}
+
+	cache := registry.NewCache()
+	analyzer, err := cache.DefineAnalyzer("japanese", map[string]interface{}{
+		"type":       AnalyzerName,
+		"dictionary": path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		var actual []string
+		for _, token := range analyzer.Analyze(test.input) {
+			actual = append(actual, string(token.Term))
+		}
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %v, got %v", test.output, actual)
+		}
+	}
+
+	_, err = cache.DefineAnalyzer("no_dictionary", map[string]interface{}{
+		"type": AnalyzerName,
+	})
+	if err == nil {
+		t.Errorf("expected error without dictionary")
+	}
+}
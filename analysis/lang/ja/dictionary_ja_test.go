@@ -0,0 +1,545 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ja
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// sampleTokenizerName is the name of a tokenizer segmenting with the
+// sample dictionary, for the tests. It takes the same "user_dictionary"
+// property as the Japanese dictionary tokenizer.
+const sampleTokenizerName = "ja_sample_dictionary"
+
+var sampleDict *lattice.Dictionary
+var sampleDictOnce sync.Once
+
+// sampleDictionary returns the sample dictionary, which only covers a few
+// hundred common words.
+func sampleDictionary() *lattice.Dictionary {
+	sampleDictOnce.Do(func() {
+		sampleDict = buildDictionary(sampleDictionaryData)
+	})
+	return sampleDict
+}
+
+func sampleTokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	return newTokenizer(sampleDictionary(), config)
+}
+
+func init() {
+	registry.RegisterTokenizer(sampleTokenizerName, sampleTokenizerConstructor)
+}
+
+// the cost of the entries, by type, on the scale of the IPADIC costs
+var typeCosts = map[analysis.TokenType]int{
+	analysis.Noun:          2000,
+	analysis.Pronoun:       1600,
+	analysis.Verb:          1600,
+	analysis.Adjective:     1600,
+	analysis.Adverb:        2000,
+	analysis.Particle:      800,
+	analysis.AuxiliaryVerb: 800,
+	analysis.Conjunction:   2000,
+	analysis.Interjection:  2400,
+	analysis.Prefix:        2800,
+	analysis.Suffix:        2400,
+}
+
+// newDictionary returns a dictionary using the connection penalties
+// between types in place of a matrix, for the sample dictionary.
+func newDictionary() *lattice.Dictionary {
+	dict := lattice.NewDictionary()
+	dict.TypeOf = typeOf
+	dict.Connection = connection
+	dict.Unknown = unknown
+	return dict
+}
+
+// connection penalizes the sequences of types which are unlikely to
+// follow each other.
+func connection(left, right analysis.TokenType) int {
+	switch {
+	case left == right && (left == analysis.Verb || left == analysis.Adjective):
+		return 4000
+	case left == analysis.Noun && right == analysis.Noun:
+		return 3200
+	case left == analysis.Particle && right == analysis.Particle:
+		return 3200
+	case left == analysis.Particle && right == analysis.AuxiliaryVerb:
+		return 2400
+	case left == analysis.Prefix && right != analysis.Noun:
+		return 4000
+	case right == analysis.Suffix && left != analysis.Noun && left != analysis.Pronoun:
+		return 4000
+	}
+	return 0
+}
+
+func buildDictionary(data string) *lattice.Dictionary {
+	dict := newDictionary()
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		surface, reading, pos := fields[0], fields[1], fields[2]
+		switch pos {
+		case "五段":
+			addGodan(dict, surface, reading)
+		case "一段":
+			addIchidan(dict, surface, reading)
+		case "サ変":
+			addForms(dict, surface, "動詞-自立", analysis.Verb, []string{
+				"さ", "サ", "し", "シ", "す", "ス", "する", "スル", "すれ", "スレ",
+				"しろ", "シロ", "せよ", "セヨ", "せ", "セ",
+			})
+		case "カ変":
+			stem := strings.TrimSuffix(surface, "る")
+			stem = strings.TrimSuffix(stem, "く")
+			forms := []string{
+				stem, "キ", stem, "コ", stem + "る", "クル", stem + "れ", "クレ",
+				stem + "い", "コイ",
+			}
+			if stem == "" {
+				forms = []string{
+					"き", "キ", "こ", "コ", "くる", "クル", "くれ", "クレ", "こい", "コイ",
+				}
+			}
+			addForms(dict, surface, "動詞-自立", analysis.Verb, forms)
+		case "形容詞":
+			addAdjective(dict, surface, reading)
+		default:
+			e := &lattice.Entry{
+				Surface: surface,
+				Reading: reading,
+				POS:     pos,
+				Type:    typeOf(pos),
+			}
+			e.Cost = typeCosts[e.Type]
+			if len(fields) > 3 {
+				e.Base = fields[3]
+			}
+			dict.Add(e)
+		}
+	}
+	return dict
+}
+
+// addForms adds the conjugated forms of base, given as pairs of surface
+// and reading.
+func addForms(dict *lattice.Dictionary, base, pos string, typ analysis.TokenType, forms []string) {
+	for i := 0; i+1 < len(forms); i += 2 {
+		e := &lattice.Entry{
+			Surface: forms[i],
+			Reading: forms[i+1],
+			POS:     pos,
+			Type:    typ,
+			Cost:    typeCosts[typ],
+		}
+		if forms[i] != base {
+			e.Base = base
+		}
+		dict.Add(e)
+	}
+}
+
+// the rows of the kana ending godan verbs: the a, i, u, e and o forms,
+// and the euphonic form used before た and て
+var godanRows = map[rune][]string{
+	'う': {"わ", "い", "う", "え", "お", "っ"},
+	'く': {"か", "き", "く", "け", "こ", "い"},
+	'ぐ': {"が", "ぎ", "ぐ", "げ", "ご", "い"},
+	'す': {"さ", "し", "す", "せ", "そ", ""},
+	'つ': {"た", "ち", "つ", "て", "と", "っ"},
+	'ぬ': {"な", "に", "ぬ", "ね", "の", "ん"},
+	'ぶ': {"ば", "び", "ぶ", "べ", "ぼ", "ん"},
+	'む': {"ま", "み", "む", "め", "も", "ん"},
+	'る': {"ら", "り", "る", "れ", "ろ", "っ"},
+}
+
+func addGodan(dict *lattice.Dictionary, base, reading string) {
+	rs := []rune(base)
+	row, ok := godanRows[rs[len(rs)-1]]
+	if !ok {
+		return
+	}
+	stem := string(rs[:len(rs)-1])
+	readingStem := string([]rune(reading)[:len([]rune(reading))-1])
+	var forms []string
+	for i, kana := range row {
+		if kana == "" {
+			continue
+		}
+		// 行く is irregular, 行っ instead of 行い
+		if i == 5 && base == "行く" {
+			kana = "っ"
+		}
+		forms = append(forms, stem+kana, readingStem+toKatakana(kana))
+	}
+	addForms(dict, base, "動詞-自立", analysis.Verb, forms)
+}
+
+func addIchidan(dict *lattice.Dictionary, base, reading string) {
+	stem := strings.TrimSuffix(base, "る")
+	readingStem := strings.TrimSuffix(reading, "ル")
+	var forms []string
+	for _, kana := range []string{"", "る", "れ", "ろ", "よ"} {
+		forms = append(forms, stem+kana, readingStem+toKatakana(kana))
+	}
+	addForms(dict, base, "動詞-自立", analysis.Verb, forms)
+}
+
+func addAdjective(dict *lattice.Dictionary, base, reading string) {
+	stem := strings.TrimSuffix(base, "い")
+	readingStem := strings.TrimSuffix(reading, "イ")
+	var forms []string
+	for _, kana := range []string{"い", "く", "かっ", "けれ", "かろ", "き"} {
+		forms = append(forms, stem+kana, readingStem+toKatakana(kana))
+	}
+	addForms(dict, base, "形容詞-自立", analysis.Adjective, forms)
+}
+
+func toKatakana(s string) string {
+	rs := []rune(s)
+	for i, r := range rs {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			rs[i] = r + 'ァ' - 'ぁ'
+		}
+	}
+	return string(rs)
+}
+
+// The sample dictionary, a few hundred common words, one entry per line:
+//
+//	surface reading part-of-speech [base]
+//
+// Verbs and adjectives are listed in their base form, with their
+// conjugation class in place of the part of speech, and their
+// conjugated forms are generated: 五段 (godan verbs), 一段 (ichidan
+// verbs), サ変 (する), カ変 (来る) and 形容詞 (i-adjectives).
+const sampleDictionaryData = `
+私 ワタシ 名詞-代名詞-一般
+僕 ボク 名詞-代名詞-一般
+彼 カレ 名詞-代名詞-一般
+彼女 カノジョ 名詞-代名詞-一般
+あなた アナタ 名詞-代名詞-一般
+これ コレ 名詞-代名詞-一般
+それ ソレ 名詞-代名詞-一般
+あれ アレ 名詞-代名詞-一般
+どれ ドレ 名詞-代名詞-一般
+ここ ココ 名詞-代名詞-一般
+そこ ソコ 名詞-代名詞-一般
+どこ ドコ 名詞-代名詞-一般
+誰 ダレ 名詞-代名詞-一般
+何 ナニ 名詞-代名詞-一般
+この コノ 連体詞
+その ソノ 連体詞
+あの アノ 連体詞
+どの ドノ 連体詞
+大きな オオキナ 連体詞
+人 ヒト 名詞-一般
+日本 ニホン 名詞-固有名詞-地域-国
+日本語 ニホンゴ 名詞-一般
+日本人 ニホンジン 名詞-一般
+中国 チュウゴク 名詞-固有名詞-地域-国
+中国語 チュウゴクゴ 名詞-一般
+英語 エイゴ 名詞-一般
+東京 トウキョウ 名詞-固有名詞-地域-一般
+東京都 トウキョウト 名詞-固有名詞-地域-一般
+大阪 オオサカ 名詞-固有名詞-地域-一般
+京都 キョウト 名詞-固有名詞-地域-一般
+関西 カンサイ 名詞-固有名詞-地域-一般
+国際 コクサイ 名詞-一般
+空港 クウコウ 名詞-一般
+世界 セカイ 名詞-一般
+国 クニ 名詞-一般
+町 マチ 名詞-一般
+言語 ゲンゴ 名詞-一般
+言葉 コトバ 名詞-一般
+自然 シゼン 名詞-形容動詞語幹
+処理 ショリ 名詞-サ変接続
+形態素 ケイタイソ 名詞-一般
+解析 カイセキ 名詞-サ変接続
+辞書 ジショ 名詞-一般
+単語 タンゴ 名詞-一般
+文字 モジ 名詞-一般
+文章 ブンショウ 名詞-一般
+検索 ケンサク 名詞-サ変接続
+全文 ゼンブン 名詞-一般
+情報 ジョウホウ 名詞-一般
+研究 ケンキュウ 名詞-サ変接続
+開発 カイハツ 名詞-サ変接続
+技術 ギジュツ 名詞-一般
+科学 カガク 名詞-一般
+生命 セイメイ 名詞-一般
+経済 ケイザイ 名詞-一般
+政治 セイジ 名詞-一般
+社会 シャカイ 名詞-一般
+文化 ブンカ 名詞-一般
+歴史 レキシ 名詞-一般
+勉強 ベンキョウ 名詞-サ変接続
+旅行 リョコウ 名詞-サ変接続
+買い物 カイモノ 名詞-一般
+料理 リョウリ 名詞-サ変接続
+学校 ガッコウ 名詞-一般
+大学 ダイガク 名詞-一般
+学生 ガクセイ 名詞-一般
+先生 センセイ 名詞-一般
+会社 カイシャ 名詞-一般
+仕事 シゴト 名詞-一般
+友達 トモダチ 名詞-一般
+家族 カゾク 名詞-一般
+子供 コドモ 名詞-一般
+母 ハハ 名詞-一般
+父 チチ 名詞-一般
+名前 ナマエ 名詞-一般
+今日 キョウ 名詞-副詞可能
+明日 アシタ 名詞-副詞可能
+昨日 キノウ 名詞-副詞可能
+今 イマ 名詞-副詞可能
+週末 シュウマツ 名詞-副詞可能
+朝 アサ 名詞-副詞可能
+昼 ヒル 名詞-副詞可能
+夜 ヨル 名詞-副詞可能
+時間 ジカン 名詞-副詞可能
+年 トシ 名詞-一般
+月 ツキ 名詞-一般
+日 ヒ 名詞-一般
+天気 テンキ 名詞-一般
+雨 アメ 名詞-一般
+雪 ユキ 名詞-一般
+山 ヤマ 名詞-一般
+川 カワ 名詞-一般
+海 ウミ 名詞-一般
+空 ソラ 名詞-一般
+水 ミズ 名詞-一般
+木 キ 名詞-一般
+花 ハナ 名詞-一般
+猫 ネコ 名詞-一般
+犬 イヌ 名詞-一般
+鳥 トリ 名詞-一般
+本 ホン 名詞-一般
+車 クルマ 名詞-一般
+電車 デンシャ 名詞-一般
+駅 エキ 名詞-一般
+道 ミチ 名詞-一般
+家 イエ 名詞-一般
+部屋 ヘヤ 名詞-一般
+店 ミセ 名詞-一般
+映画 エイガ 名詞-一般
+音楽 オンガク 名詞-一般
+写真 シャシン 名詞-一般
+手紙 テガミ 名詞-一般
+電話 デンワ 名詞-サ変接続
+新聞 シンブン 名詞-一般
+雑誌 ザッシ 名詞-一般
+問題 モンダイ 名詞-一般
+質問 シツモン 名詞-サ変接続
+答え コタエ 名詞-一般
+意味 イミ 名詞-一般
+話 ハナシ 名詞-一般
+ご飯 ゴハン 名詞-一般
+すもも スモモ 名詞-一般
+もも モモ 名詞-一般
+桃 モモ 名詞-一般
+気 キ 名詞-一般
+事 コト 名詞-非自立-一般
+こと コト 名詞-非自立-一般
+物 モノ 名詞-非自立-一般
+もの モノ 名詞-非自立-一般
+所 トコロ 名詞-非自立-一般
+ところ トコロ 名詞-非自立-一般
+方 ホウ 名詞-非自立-一般
+ため タメ 名詞-非自立-副詞可能
+うち ウチ 名詞-非自立-副詞可能
+一緒 イッショ 名詞-副詞可能
+さん サン 名詞-接尾-人名
+様 サマ 名詞-接尾-人名
+たち タチ 名詞-接尾-一般
+お オ 接頭詞-名詞接続
+ご ゴ 接頭詞-名詞接続
+とても トテモ 副詞-助詞類接続
+よく ヨク 副詞-一般
+もう モウ 副詞-一般
+まだ マダ 副詞-助詞類接続
+すぐ スグ 副詞-助詞類接続
+少し スコシ 副詞-助詞類接続
+たくさん タクサン 副詞-助詞類接続
+ちょっと チョット 副詞-助詞類接続
+いつも イツモ 副詞-一般
+全然 ゼンゼン 副詞-助詞類接続
+また マタ 副詞-一般
+そして ソシテ 接続詞
+しかし シカシ 接続詞
+でも デモ 接続詞
+だから ダカラ 接続詞
+それから ソレカラ 接続詞
+はい ハイ 感動詞
+いいえ イイエ 感動詞
+ああ アア 感動詞
+は ハ 助詞-係助詞
+も モ 助詞-係助詞
+が ガ 助詞-格助詞-一般
+を ヲ 助詞-格助詞-一般
+に ニ 助詞-格助詞-一般
+で デ 助詞-格助詞-一般
+と ト 助詞-格助詞-一般
+の ノ 助詞-連体化
+へ ヘ 助詞-格助詞-一般
+から カラ 助詞-格助詞-一般
+まで マデ 助詞-副助詞
+より ヨリ 助詞-格助詞-一般
+や ヤ 助詞-並立助詞
+か カ 助詞-副助詞／並立助詞／終助詞
+な ナ 助詞-終助詞
+ね ネ 助詞-終助詞
+よ ヨ 助詞-終助詞
+て テ 助詞-接続助詞
+ば バ 助詞-接続助詞
+けど ケド 助詞-接続助詞
+けれど ケレド 助詞-接続助詞
+ので ノデ 助詞-接続助詞
+のに ノニ 助詞-接続助詞
+ながら ナガラ 助詞-接続助詞
+たり タリ 助詞-並立助詞
+って ッテ 助詞-格助詞-連語
+だけ ダケ 助詞-副助詞
+しか シカ 助詞-係助詞
+など ナド 助詞-副助詞
+ます マス 助動詞
+まし マシ 助動詞 ます
+ませ マセ 助動詞 ます
+ましょ マショ 助動詞 ます
+です デス 助動詞
+でし デシ 助動詞 です
+でしょ デショ 助動詞 です
+た タ 助動詞
+だ ダ 助動詞
+だっ ダッ 助動詞 だ
+ない ナイ 助動詞
+なかっ ナカッ 助動詞 ない
+なく ナク 助動詞 ない
+なけれ ナケレ 助動詞 ない
+ん ン 助動詞 ぬ
+ぬ ヌ 助動詞
+たい タイ 助動詞
+たかっ タカッ 助動詞 たい
+たく タク 助動詞 たい
+れる レル 助動詞
+れ レ 助動詞 れる
+られる ラレル 助動詞
+られ ラレ 助動詞 られる
+せる セル 助動詞
+せ セ 助動詞 せる
+させる サセル 助動詞
+させ サセ 助動詞 させる
+う ウ 助動詞
+よう ヨウ 助動詞
+書く カク 五段
+読む ヨム 五段
+行く イク 五段
+聞く キク 五段
+歩く アルク 五段
+働く ハタラク 五段
+泳ぐ オヨグ 五段
+話す ハナス 五段
+出す ダス 五段
+待つ マツ 五段
+持つ モツ 五段
+立つ タツ 五段
+死ぬ シヌ 五段
+遊ぶ アソブ 五段
+呼ぶ ヨブ 五段
+飲む ノム 五段
+住む スム 五段
+休む ヤスム 五段
+買う カウ 五段
+言う イウ 五段
+思う オモウ 五段
+使う ツカウ 五段
+会う アウ 五段
+習う ナラウ 五段
+作る ツクル 五段
+分かる ワカル 五段
+入る ハイル 五段
+帰る カエル 五段
+知る シル 五段
+走る ハシル 五段
+降る フル 五段
+取る トル 五段
+乗る ノル 五段
+送る オクル 五段
+終わる オワル 五段
+始まる ハジマル 五段
+なる ナル 五段
+ある アル 五段
+食べる タベル 一段
+見る ミル 一段
+寝る ネル 一段
+起きる オキル 一段
+出る デル 一段
+いる イル 一段
+着る キル 一段
+教える オシエル 一段
+始める ハジメル 一段
+考える カンガエル 一段
+覚える オボエル 一段
+忘れる ワスレル 一段
+開ける アケル 一段
+閉める シメル 一段
+答える コタエル 一段
+調べる シラベル 一段
+入れる イレル 一段
+する スル サ変
+来る クル カ変
+くる クル カ変
+高い タカイ 形容詞
+安い ヤスイ 形容詞
+新しい アタラシイ 形容詞
+古い フルイ 形容詞
+大きい オオキイ 形容詞
+小さい チイサイ 形容詞
+美しい ウツクシイ 形容詞
+良い ヨイ 形容詞
+よい ヨイ 形容詞
+悪い ワルイ 形容詞
+早い ハヤイ 形容詞
+速い ハヤイ 形容詞
+寒い サムイ 形容詞
+暑い アツイ 形容詞
+楽しい タノシイ 形容詞
+難しい ムズカシイ 形容詞
+面白い オモシロイ 形容詞
+長い ナガイ 形容詞
+短い ミジカイ 形容詞
+多い オオイ 形容詞
+少ない スクナイ 形容詞
+強い ツヨイ 形容詞
+弱い ヨワイ 形容詞
+白い シロイ 形容詞
+黒い クロイ 形容詞
+赤い アカイ 形容詞
+青い アオイ 形容詞
+忙しい イソガシイ 形容詞
+嬉しい ウレシイ 形容詞
+優しい ヤサシイ 形容詞
+いい イイ 形容詞-自立
+`
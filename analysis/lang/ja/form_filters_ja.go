@@ -0,0 +1,64 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ja
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// BaseFormName is the name of the filter replacing inflected words with
+// their base form, for example 読み with 読む.
+const BaseFormName = "ja_base_form"
+
+// ReadingFormName is the name of the filter replacing words with their
+// reading in katakana, for example 読み with ヨミ.
+const ReadingFormName = "ja_reading_form"
+
+// tokenizerDictionary returns the dictionary of the tokenizer named by
+// the "tokenizer" property of a filter config, which is required. The
+// filters look up terms in the dictionary of the tokenizer which
+// produced them, so that user dictionary entries are found.
+func tokenizerDictionary(config map[string]interface{}, cache *registry.Cache) (*lattice.Dictionary, error) {
+	name, ok := config["tokenizer"].(string)
+	if !ok {
+		return nil, fmt.Errorf("must specify tokenizer")
+	}
+	return lattice.TokenizerDictionary(cache, name)
+}
+
+func BaseFormFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	dict, err := tokenizerDictionary(config, cache)
+	if err != nil {
+		return nil, err
+	}
+	return lattice.NewBaseFormFilter(dict), nil
+}
+
+func ReadingFormFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	dict, err := tokenizerDictionary(config, cache)
+	if err != nil {
+		return nil, err
+	}
+	return lattice.NewReadingFormFilter(dict), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(BaseFormName, BaseFormFilterConstructor)
+	registry.RegisterTokenFilter(ReadingFormName, ReadingFormFilterConstructor)
+}
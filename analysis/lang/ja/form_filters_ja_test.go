@@ -0,0 +1,112 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ja
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func TestJapaneseFormFilters(t *testing.T) {
+	tests := []struct {
+		filter   string
+		config   map[string]interface{}
+		input    string
+		keyword  string
+		expected []string
+	}{
+		{
+			filter:   BaseFormName,
+			input:    "彼女は新しい本を買いたかった",
+			expected: []string{"彼女", "は", "新しい", "本", "を", "買う", "たい", "た"},
+		},
+		{
+			filter:   BaseFormName,
+			input:    "雨が降っていたので家で映画を見た",
+			keyword:  "見",
+			expected: []string{"雨", "が", "降る", "て", "いる", "た", "ので", "家", "で", "映画", "を", "見", "た"},
+		},
+		{
+			filter:   ReadingFormName,
+			input:    "彼女は新しい本を買いたかった",
+			expected: []string{"カノジョ", "ハ", "アタラシイ", "ホン", "ヲ", "カイ", "タカッ", "タ"},
+		},
+		{
+			filter: ReadingFormName,
+			config: map[string]interface{}{
+				"tokenizer": "ja_user",
+			},
+			input:    "朝青龍と関西国際空港",
+			expected: []string{"アサショウリュウ", "ト", "カンサイ", "コクサイクウコウ"},
+		},
+	}
+
+	cache := registry.NewCache()
+	_, err := cache.DefineTokenizer("ja_user", map[string]interface{}{
+		"type": sampleTokenizerName,
+		"user_dictionary": []interface{}{
+			"朝青龍,朝青龍,アサショウリュウ,カスタム人名",
+			"関西国際空港,関西 国際空港,カンサイ コクサイクウコウ,カスタム名詞",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		tokenizerName := sampleTokenizerName
+		if test.config != nil {
+			tokenizerName = test.config["tokenizer"].(string)
+		}
+		filter, err := cache.DefineTokenFilter(fmt.Sprintf("%s_%d", test.filter, i), map[string]interface{}{
+			"type":      test.filter,
+			"tokenizer": tokenizerName,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokenizer, err := cache.TokenizerNamed(tokenizerName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens := tokenizer.Tokenize([]byte(test.input))
+		for _, token := range tokens {
+			if string(token.Term) == test.keyword {
+				token.KeyWord = true
+			}
+		}
+		var actual []string
+		for _, token := range filter.Filter(tokens) {
+			actual = append(actual, string(token.Term))
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.filter, test.expected, actual)
+		}
+	}
+
+	_, err = cache.TokenFilterNamed(BaseFormName)
+	if err == nil {
+		t.Errorf("expected error without tokenizer")
+	}
+	_, err = cache.DefineTokenFilter("ja_base_form_unicode", map[string]interface{}{
+		"type":      BaseFormName,
+		"tokenizer": "unicode",
+	})
+	if err == nil {
+		t.Errorf("expected error for a tokenizer without dictionary")
+	}
+}
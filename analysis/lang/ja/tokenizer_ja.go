@@ -0,0 +1,305 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ja implements Japanese analysis, segmenting text with a
+// morphological dictionary in the style of IPADIC.
+//
+// No dictionary is built into the package: the tokenizer loads the CSV
+// files and the matrix.def file of IPADIC, converted to UTF-8, named by
+// its "dictionary" property, and the form filters refer to that
+// tokenizer. Alternatively, define an analyzer of type ja_ipadic:
+//
+//	"tokenizers": {
+//		"ipadic": {
+//			"type": "ja_dictionary",
+//			"dictionary": "/usr/share/ipadic"
+//		}
+//	},
+//	"token_filters": {
+//		"ipadic_base_form": {"type": "ja_base_form", "tokenizer": "ipadic"}
+//	}
+//
+// The unknown words and the user dictionary entries are given the
+// context ids of the first dictionary entry with the same part of
+// speech, or else those of 名詞-一般.
+package ja
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// TokenizerName is the name of the Japanese dictionary tokenizer. Its
+// constructor takes a "dictionary" property, the IPADIC directory or the
+// list of paths of the IPADIC files to load, and an optional
+// "user_dictionary" property, a list of lines in the format described by
+// lattice.AddUserDictionary.
+const TokenizerName = "ja_dictionary"
+
+var ipadicCache = lattice.NewDictionaryCache(LoadIPADIC)
+
+// the name of the file of the connection costs of IPADIC
+const matrixFile = "matrix.def"
+
+// LoadIPADIC reads a dictionary from IPADIC files encoded in UTF-8, given
+// as directories, whose CSV files and matrix.def are read, or as files.
+// The CSV files hold one entry per line:
+//
+//	surface,left id,right id,cost,pos1,pos2,pos3,pos4,conjugation type,conjugation form,base,reading,pronunciation
+//
+// and matrix.def, which is required, the connection costs between the
+// context ids, in the format read by lattice.LoadMatrix.
+func LoadIPADIC(paths []string) (*lattice.Dictionary, error) {
+	var csvPaths []string
+	matrixPath := ""
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening dictionary: %v", err)
+		}
+		if !fi.IsDir() {
+			if filepath.Base(path) == matrixFile {
+				matrixPath = path
+			} else {
+				csvPaths = append(csvPaths, path)
+			}
+			continue
+		}
+		names, err := filepath.Glob(filepath.Join(path, "*.csv"))
+		if err != nil {
+			return nil, err
+		}
+		csvPaths = append(csvPaths, names...)
+		if _, err := os.Stat(filepath.Join(path, matrixFile)); err == nil {
+			matrixPath = filepath.Join(path, matrixFile)
+		}
+	}
+	if matrixPath == "" {
+		return nil, fmt.Errorf("must specify %s with the dictionary", matrixFile)
+	}
+	matrix, err := lattice.LoadMatrix(matrixPath)
+	if err != nil {
+		return nil, err
+	}
+	rightSize, leftSize := matrix.Size()
+
+	dict := lattice.NewDictionary()
+	dict.TypeOf = typeOf
+	dict.Matrix = matrix
+	// the context ids of the first entry of each part of speech
+	contexts := make(map[string][2]int)
+	err = lattice.ReadDictionaryFiles(csvPaths, func(line string) error {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 11 {
+			return fmt.Errorf("expected at least 11 fields, got %d", len(fields))
+		}
+		left, err := strconv.Atoi(fields[1])
+		if err != nil || left < 0 || left >= leftSize {
+			return fmt.Errorf("invalid left id '%s'", fields[1])
+		}
+		right, err := strconv.Atoi(fields[2])
+		if err != nil || right < 0 || right >= rightSize {
+			return fmt.Errorf("invalid right id '%s'", fields[2])
+		}
+		cost, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return fmt.Errorf("invalid cost '%s'", fields[3])
+		}
+		var pos []string
+		for _, p := range fields[4:8] {
+			if p != "*" {
+				pos = append(pos, p)
+			}
+		}
+		e := &lattice.Entry{
+			Surface: fields[0],
+			POS:     strings.Join(pos, "-"),
+			Cost:    cost,
+			LeftID:  left,
+			RightID: right,
+		}
+		e.Type = typeOf(e.POS)
+		if base := fields[10]; base != "*" && base != e.Surface {
+			e.Base = base
+		}
+		if len(fields) > 11 && fields[11] != "*" {
+			e.Reading = fields[11]
+		}
+		if _, ok := contexts[e.POS]; !ok {
+			contexts[e.POS] = [2]int{left, right}
+		}
+		dict.Add(e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dict.Context = func(pos string) (int, int) {
+		c, ok := contexts[pos]
+		if !ok {
+			c = contexts["名詞-一般"]
+		}
+		return c[0], c[1]
+	}
+	dict.Unknown = func(rs []rune, i int) []*lattice.Entry {
+		rv := unknown(rs, i)
+		for _, e := range rv {
+			e.LeftID, e.RightID = dict.Context(e.POS)
+		}
+		return rv
+	}
+	return dict, nil
+}
+
+func TokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	paths := lattice.DictionaryPaths(config)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("must specify dictionary")
+	}
+	dict, err := ipadicCache.Dictionary(paths)
+	if err != nil {
+		return nil, err
+	}
+	return newTokenizer(dict, config)
+}
+
+// newTokenizer returns a tokenizer segmenting with dict, extended with
+// the "user_dictionary" of config.
+func newTokenizer(dict *lattice.Dictionary, config map[string]interface{}) (analysis.Tokenizer, error) {
+	if lines := lattice.UserDictionaryLines(config); len(lines) > 0 {
+		dict = dict.Copy()
+		err := dict.AddUserDictionary(lines)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lattice.NewDictionaryTokenizer(dict), nil
+}
+
+func init() {
+	registry.RegisterTokenizer(TokenizerName, TokenizerConstructor)
+}
+
+// typeOf returns the type of a part of speech, from its first level.
+func typeOf(pos string) analysis.TokenType {
+	switch {
+	case strings.HasPrefix(pos, "名詞-代名詞"):
+		return analysis.Pronoun
+	case strings.HasPrefix(pos, "名詞-接尾"):
+		return analysis.Suffix
+	case strings.HasPrefix(pos, "動詞"):
+		return analysis.Verb
+	case strings.HasPrefix(pos, "形容詞"), strings.HasPrefix(pos, "連体詞"):
+		return analysis.Adjective
+	case strings.HasPrefix(pos, "副詞"):
+		return analysis.Adverb
+	case strings.HasPrefix(pos, "助動詞"):
+		return analysis.AuxiliaryVerb
+	case strings.HasPrefix(pos, "助詞"):
+		return analysis.Particle
+	case strings.HasPrefix(pos, "接続詞"):
+		return analysis.Conjunction
+	case strings.HasPrefix(pos, "感動詞"):
+		return analysis.Interjection
+	case strings.HasPrefix(pos, "接頭詞"):
+		return analysis.Prefix
+	case strings.HasPrefix(pos, "記号"):
+		return analysis.Symbol
+	}
+	return analysis.Noun
+}
+
+func isKatakana(r rune) bool {
+	return unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) || r == '々'
+}
+
+func isOther(r rune) bool {
+	return !isKanji(r) && !isKatakana(r) && !unicode.Is(unicode.Hiragana, r)
+}
+
+// unknown returns the unknown words starting at i: katakana and other
+// scripts are grouped in runs, kanji make words of up to three letters,
+// and hiragana words of one letter.
+func unknown(rs []rune, i int) []*lattice.Entry {
+	r := rs[i]
+	switch {
+	case isKatakana(r):
+		j := i + 1
+		for j < len(rs) && isKatakana(rs[j]) {
+			j++
+		}
+		return []*lattice.Entry{
+			&lattice.Entry{
+				Surface: string(rs[i:j]),
+				POS:     "名詞-一般",
+				Type:    analysis.Noun,
+				Cost:    6000,
+			},
+		}
+	case isKanji(r):
+		var rv []*lattice.Entry
+		for l := 1; l <= 3 && i+l <= len(rs) && isKanji(rs[i+l-1]); l++ {
+			rv = append(rv, &lattice.Entry{
+				Surface: string(rs[i : i+l]),
+				POS:     "名詞-一般",
+				Type:    analysis.Noun,
+				Cost:    8000 + 4000*l,
+			})
+		}
+		return rv
+	case isOther(r):
+		j := i + 1
+		numeric := unicode.IsNumber(r)
+		for j < len(rs) && isOther(rs[j]) {
+			numeric = numeric && unicode.IsNumber(rs[j])
+			j++
+		}
+		typ := analysis.AlphaNumeric
+		if numeric {
+			typ = analysis.Numeric
+		}
+		return []*lattice.Entry{
+			&lattice.Entry{
+				Surface: string(rs[i:j]),
+				POS:     "名詞-一般",
+				Type:    typ,
+				Cost:    4000,
+			},
+		}
+	}
+	return []*lattice.Entry{
+		&lattice.Entry{
+			Surface: string(r),
+			POS:     "名詞-一般",
+			Type:    analysis.Noun,
+			Cost:    12000,
+		},
+	}
+}
@@ -0,0 +1,370 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ja
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func TestJapaneseTokenizer(t *testing.T) {
+	tests := []struct {
+		input  []byte
+		output analysis.TokenStream
+	}{
+		{
+			input: []byte("すもももももももものうち"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("すもも"),
+					Type:     analysis.Noun,
+					Position: 1,
+					Start:    0,
+					End:      9,
+				},
+				&analysis.Token{
+					Term:     []byte("も"),
+					Type:     analysis.Particle,
+					Position: 2,
+					Start:    9,
+					End:      12,
+				},
+				&analysis.Token{
+					Term:     []byte("もも"),
+					Type:     analysis.Noun,
+					Position: 3,
+					Start:    12,
+					End:      18,
+				},
+				&analysis.Token{
+					Term:     []byte("も"),
+					Type:     analysis.Particle,
+					Position: 4,
+					Start:    18,
+					End:      21,
+				},
+				&analysis.Token{
+					Term:     []byte("もも"),
+					Type:     analysis.Noun,
+					Position: 5,
+					Start:    21,
+					End:      27,
+				},
+				&analysis.Token{
+					Term:     []byte("の"),
+					Type:     analysis.Particle,
+					Position: 6,
+					Start:    27,
+					End:      30,
+				},
+				&analysis.Token{
+					Term:     []byte("うち"),
+					Type:     analysis.Noun,
+					Position: 7,
+					Start:    30,
+					End:      36,
+				},
+			},
+		},
+		{
+			input: []byte("本を読みました。"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("本"),
+					Type:     analysis.Noun,
+					Position: 1,
+					Start:    0,
+					End:      3,
+				},
+				&analysis.Token{
+					Term:     []byte("を"),
+					Type:     analysis.Particle,
+					Position: 2,
+					Start:    3,
+					End:      6,
+				},
+				&analysis.Token{
+					Term:     []byte("読み"),
+					Type:     analysis.Verb,
+					Position: 3,
+					Start:    6,
+					End:      12,
+				},
+				&analysis.Token{
+					Term:     []byte("まし"),
+					Type:     analysis.AuxiliaryVerb,
+					Position: 4,
+					Start:    12,
+					End:      18,
+				},
+				&analysis.Token{
+					Term:     []byte("た"),
+					Type:     analysis.AuxiliaryVerb,
+					Position: 5,
+					Start:    18,
+					End:      21,
+				},
+			},
+		},
+		{
+			input: []byte("日本語の形態素解析エンジン、Bleve"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("日本語"),
+					Type:     analysis.Noun,
+					Position: 1,
+					Start:    0,
+					End:      9,
+				},
+				&analysis.Token{
+					Term:     []byte("の"),
+					Type:     analysis.Particle,
+					Position: 2,
+					Start:    9,
+					End:      12,
+				},
+				&analysis.Token{
+					Term:     []byte("形態素"),
+					Type:     analysis.Noun,
+					Position: 3,
+					Start:    12,
+					End:      21,
+				},
+				&analysis.Token{
+					Term:     []byte("解析"),
+					Type:     analysis.Noun,
+					Position: 4,
+					Start:    21,
+					End:      27,
+				},
+				&analysis.Token{
+					Term:     []byte("エンジン"),
+					Type:     analysis.Noun,
+					Position: 5,
+					Start:    27,
+					End:      39,
+				},
+				&analysis.Token{
+					Term:     []byte("Bleve"),
+					Type:     analysis.AlphaNumeric,
+					Position: 6,
+					Start:    42,
+					End:      47,
+				},
+			},
+		},
+	}
+
+	cache := registry.NewCache()
+	tokenizer, err := cache.TokenizerNamed(sampleTokenizerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		actual := tokenizer.Tokenize(test.input)
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %v, got %v", test.output, actual)
+		}
+	}
+
+	_, err = cache.TokenizerNamed(TokenizerName)
+	if err == nil {
+		t.Errorf("expected error without dictionary")
+	}
+}
+
+func TestJapaneseTokenizerUserDictionary(t *testing.T) {
+	cache := registry.NewCache()
+	tokenizer, err := cache.DefineTokenizer("ja_user", map[string]interface{}{
+		"type": sampleTokenizerName,
+		"user_dictionary": []interface{}{
+			"# custom entries",
+			"朝青龍,朝青龍,アサショウリュウ,カスタム人名",
+			"関西国際空港,関西 国際空港,カンサイ コクサイクウコウ,カスタム名詞",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := analysis.TokenStream{
+		&analysis.Token{
+			Term:     []byte("朝青龍"),
+			Type:     analysis.Noun,
+			Position: 1,
+			Start:    0,
+			End:      9,
+		},
+		&analysis.Token{
+			Term:     []byte("と"),
+			Type:     analysis.Particle,
+			Position: 2,
+			Start:    9,
+			End:      12,
+		},
+		&analysis.Token{
+			Term:     []byte("関西"),
+			Type:     analysis.Noun,
+			Position: 3,
+			Start:    12,
+			End:      18,
+		},
+		&analysis.Token{
+			Term:     []byte("国際空港"),
+			Type:     analysis.Noun,
+			Position: 4,
+			Start:    18,
+			End:      30,
+		},
+	}
+	actual := tokenizer.Tokenize([]byte("朝青龍と関西国際空港"))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	// the sample dictionary is not modified
+	tokenizer, err = cache.TokenizerNamed(sampleTokenizerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = analysis.TokenStream{
+		&analysis.Token{
+			Term:     []byte("関西"),
+			Type:     analysis.Noun,
+			Position: 1,
+			Start:    0,
+			End:      6,
+		},
+		&analysis.Token{
+			Term:     []byte("国際"),
+			Type:     analysis.Noun,
+			Position: 2,
+			Start:    6,
+			End:      12,
+		},
+		&analysis.Token{
+			Term:     []byte("空港"),
+			Type:     analysis.Noun,
+			Position: 3,
+			Start:    12,
+			End:      18,
+		},
+	}
+	actual = tokenizer.Tokenize([]byte("関西国際空港"))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	_, err = cache.DefineTokenizer("ja_invalid", map[string]interface{}{
+		"type":            sampleTokenizerName,
+		"user_dictionary": "関西国際空港,関西 国際,カンサイ コクサイ,カスタム名詞",
+	})
+	if err == nil {
+		t.Errorf("expected error for segmentation not matching the surface")
+	}
+}
+
+func TestJapaneseTokenizerIPADIC(t *testing.T) {
+	path := writeTestIPADIC(t)
+	defer func() {
+		_ = os.RemoveAll(path)
+	}()
+
+	cache := registry.NewCache()
+	tokenizer, err := cache.DefineTokenizer("ipadic", map[string]interface{}{
+		"type":       TokenizerName,
+		"dictionary": []interface{}{path},
+		"user_dictionary": []interface{}{
+			"株式市場,株式 市場,カブシキ シジョウ,カスタム名詞",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the particle に follows the noun at a lower connection cost than the
+	// auxiliary verb に, despite its higher word cost
+	expectedTypes := []analysis.TokenType{analysis.Noun, analysis.Particle, analysis.Noun, analysis.Noun}
+	var actual []string
+	var actualTypes []analysis.TokenType
+	for _, token := range tokenizer.Tokenize([]byte("東京に株式市場")) {
+		actual = append(actual, string(token.Term))
+		actualTypes = append(actualTypes, token.Type)
+	}
+	expected := []string{"東京", "に", "株式", "市場"}
+	if !reflect.DeepEqual(actual, expected) || !reflect.DeepEqual(actualTypes, expectedTypes) {
+		t.Errorf("expected %v %v, got %v %v", expected, expectedTypes, actual, actualTypes)
+	}
+
+	// the user entries have the context ids of 名詞-一般
+	dict := tokenizer.(*lattice.DictionaryTokenizer).Dictionary()
+	for _, e := range dict.Lookup("株式市場") {
+		if e.LeftID != 1285 || e.RightID != 1285 {
+			t.Errorf("expected context ids 1285, got %d and %d", e.LeftID, e.RightID)
+		}
+	}
+
+	filter, err := cache.DefineTokenFilter("ipadic_reading", map[string]interface{}{
+		"type":      ReadingFormName,
+		"tokenizer": "ipadic",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = nil
+	for _, token := range filter.Filter(tokenizer.Tokenize([]byte("東京に住んで"))) {
+		actual = append(actual, string(token.Term))
+	}
+	expected = []string{"トウキョウ", "ニ", "スン", "デ"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	// the files can be listed in place of the directory
+	csvPath := filepath.Join(path, "test.csv")
+	matrixPath := filepath.Join(path, "matrix.def")
+	_, err = LoadIPADIC([]string{csvPath, matrixPath})
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = LoadIPADIC([]string{path + ".missing"})
+	if err == nil {
+		t.Errorf("expected error for missing dictionary")
+	}
+	_, err = LoadIPADIC([]string{csvPath})
+	if err == nil {
+		t.Errorf("expected error for missing matrix")
+	}
+	badPath := filepath.Join(path, "bad.txt")
+	for _, line := range []string{
+		"東京,1293,1293\n",
+		"東京,1316,1293,3003,名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー\n",
+		"東京,1293,-1,3003,名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー\n",
+	} {
+		err = ioutil.WriteFile(badPath, []byte(line), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = LoadIPADIC([]string{badPath, matrixPath})
+		if err == nil {
+			t.Errorf("expected error for invalid dictionary line %q", line)
+		}
+	}
+}
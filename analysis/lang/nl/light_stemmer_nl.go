@@ -0,0 +1,112 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nl
+
+import (
+	"bytes"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const LightStemmerName = "stemmer_nl_light"
+
+// DutchLightStemmerFilter removes the plural and inflectional endings
+// -en, -s and -e, undoubling the consonants and restoring the long
+// vowels and the voiceless consonants of the resulting stem, so that
+// katten, bomen and huizen stem to kat, boom and huis.
+type DutchLightStemmerFilter struct {
+}
+
+func NewDutchLightStemmerFilter() *DutchLightStemmerFilter {
+	return &DutchLightStemmerFilter{}
+}
+
+func (s *DutchLightStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		runes := bytes.Runes(token.Term)
+		runes = stem(runes)
+		token.Term = analysis.BuildTermFromRunes(runes)
+	}
+	return input
+}
+
+func isVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y', 'è':
+		return true
+	}
+	return false
+}
+
+func stem(input []rune) []rune {
+
+	l := len(input)
+
+	if l > 6 && analysis.RunesEndsWith(input, "heden") {
+		return append(input[:l-5], 'h', 'e', 'i', 'd')
+	}
+
+	if l > 4 && analysis.RunesEndsWith(input, "en") && !isVowel(input[l-3]) &&
+		!analysis.RunesEndsWith(input, "gemen") {
+		return normStem(input[:l-2])
+	}
+
+	if l > 3 && input[l-1] == 's' && !isVowel(input[l-2]) && input[l-2] != 'j' {
+		return input[:l-1]
+	}
+
+	if l > 3 && input[l-1] == 'e' && !isVowel(input[l-2]) {
+		return normStem(input[:l-1])
+	}
+
+	return input
+}
+
+// normStem restores the stem of a word whose ending was removed
+func normStem(input []rune) []rune {
+	l := len(input)
+
+	// katt -> kat
+	if l > 2 && input[l-1] == input[l-2] && !isVowel(input[l-1]) {
+		return input[:l-1]
+	}
+
+	// huiz -> huis, briev -> brief
+	switch input[l-1] {
+	case 'z':
+		input[l-1] = 's'
+	case 'v':
+		input[l-1] = 'f'
+	}
+
+	// bom -> boom, when the vowel ended an open syllable
+	if l > 2 && !isVowel(input[l-1]) && !isVowel(input[l-3]) {
+		switch input[l-2] {
+		case 'a', 'e', 'o', 'u':
+			input = append(input[:l-1], input[l-2], input[l-1])
+		}
+	}
+
+	return input
+}
+
+func DutchLightStemmerFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewDutchLightStemmerFilter(), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(LightStemmerName, DutchLightStemmerFilterConstructor)
+}
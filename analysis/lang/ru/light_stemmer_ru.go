@@ -0,0 +1,113 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ru
+
+import (
+	"bytes"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const LightStemmerName = "stemmer_ru_light"
+
+type RussianLightStemmerFilter struct {
+}
+
+func NewRussianLightStemmerFilter() *RussianLightStemmerFilter {
+	return &RussianLightStemmerFilter{}
+}
+
+func (s *RussianLightStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		runes := bytes.Runes(token.Term)
+		runes = stem(runes)
+		token.Term = analysis.BuildTermFromRunes(runes)
+	}
+	return input
+}
+
+func stem(input []rune) []rune {
+	input = removeCase(input)
+	return norm(input)
+}
+
+func norm(input []rune) []rune {
+	l := len(input)
+	if l > 3 {
+		switch input[l-1] {
+		case 'ь', 'и':
+			return input[:l-1]
+		case 'н':
+			if input[l-2] == 'н' {
+				return input[:l-1]
+			}
+		}
+	}
+	return input
+}
+
+// case endings, by length, each only removed from long enough words
+var caseEndings = []struct {
+	minLen   int
+	suffixes []string
+}{
+	{
+		minLen:   7,
+		suffixes: []string{"иями", "оями"},
+	},
+	{
+		minLen: 6,
+		suffixes: []string{"иям", "иях", "оях", "ями", "оям", "ами", "его", "ему",
+			"ери", "ими", "ого", "ому", "ыми", "оев"},
+	},
+	{
+		minLen: 5,
+		suffixes: []string{"ая", "яя", "ях", "юю", "ах", "ею", "их", "ия", "ию",
+			"ою", "ую", "ям", "ых", "ея", "ам", "ем", "ей", "ём", "ев", "ий",
+			"ой", "ом", "ый", "ые", "ов", "ое", "ие", "ми"},
+	},
+}
+
+func removeCase(input []rune) []rune {
+	l := len(input)
+	for _, endings := range caseEndings {
+		if l < endings.minLen {
+			continue
+		}
+		for _, suffix := range endings.suffixes {
+			if analysis.RunesEndsWith(input, suffix) {
+				return input[:l-len([]rune(suffix))]
+			}
+		}
+	}
+
+	if l > 3 {
+		switch input[l-1] {
+		case 'а', 'е', 'и', 'о', 'у', 'й', 'ы', 'я', 'ь':
+			return input[:l-1]
+		}
+	}
+
+	return input
+}
+
+func RussianLightStemmerFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewRussianLightStemmerFilter(), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(LightStemmerName, RussianLightStemmerFilterConstructor)
+}
@@ -0,0 +1,92 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sv
+
+import (
+	"bytes"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const LightStemmerName = "stemmer_sv_light"
+
+type SwedishLightStemmerFilter struct {
+}
+
+func NewSwedishLightStemmerFilter() *SwedishLightStemmerFilter {
+	return &SwedishLightStemmerFilter{}
+}
+
+func (s *SwedishLightStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		runes := bytes.Runes(token.Term)
+		runes = stem(runes)
+		token.Term = analysis.BuildTermFromRunes(runes)
+	}
+	return input
+}
+
+func endsWithAny(input []rune, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if analysis.RunesEndsWith(input, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func stem(input []rune) []rune {
+
+	l := len(input)
+
+	if l > 4 && input[l-1] == 's' {
+		input = input[:l-1]
+		l = len(input)
+	}
+
+	if l > 7 && endsWithAny(input, "elser", "heten") {
+		return input[:l-5]
+	}
+
+	if l > 6 && endsWithAny(input, "arna", "arne", "erna", "ande", "else", "aste", "orna", "aren") {
+		return input[:l-4]
+	}
+
+	if l > 5 && endsWithAny(input, "are", "ast", "het") {
+		return input[:l-3]
+	}
+
+	if l > 4 && endsWithAny(input, "ar", "er", "or", "en", "at", "te", "et") {
+		return input[:l-2]
+	}
+
+	if l > 3 {
+		switch input[l-1] {
+		case 't', 'a', 'e', 'n':
+			return input[:l-1]
+		}
+	}
+
+	return input
+}
+
+func SwedishLightStemmerFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewSwedishLightStemmerFilter(), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(LightStemmerName, SwedishLightStemmerFilterConstructor)
+}
@@ -0,0 +1,67 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sv
+
+import (
+	"bytes"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const NormalizeName = "normalize_sv"
+
+// SwedishNormalizeFilter replaces the Danish and Norwegian æ and ø with
+// the Swedish ä and ö, and removes the accents of borrowed words such as
+// idé or à.
+type SwedishNormalizeFilter struct {
+}
+
+func NewSwedishNormalizeFilter() *SwedishNormalizeFilter {
+	return &SwedishNormalizeFilter{}
+}
+
+func (s *SwedishNormalizeFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		term := normalize(token.Term)
+		token.Term = term
+	}
+	return input
+}
+
+func normalize(input []byte) []byte {
+	runes := bytes.Runes(input)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case 'æ':
+			runes[i] = 'ä'
+		case 'ø':
+			runes[i] = 'ö'
+		case 'à', 'á':
+			runes[i] = 'a'
+		case 'è', 'é', 'ê', 'ë':
+			runes[i] = 'e'
+		}
+	}
+	return analysis.BuildTermFromRunes(runes)
+}
+
+func NormalizerFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewSwedishNormalizeFilter(), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(NormalizeName, NormalizerFilterConstructor)
+}
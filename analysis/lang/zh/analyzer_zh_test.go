@@ -0,0 +1,135 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zh
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// testJieba is an excerpt of the jieba dictionary, the frequencies of the
+// words left out are counted in the last line
+const testJieba = `我 328841 r
+喜欢 8454 v
+搜索 5026 vn
+引擎 2165 n
+搜索引擎 154 n
+研究 27290 vn
+研究生 2467 n
+生命 8044 n
+科学 22446 n
+命 3412 n
+北京大学 2053 nt
+股票 3765 n
+市场 19456 n
+图书馆 2896 n
+的 318825 uj
+剩余 60000000
+`
+
+func writeTestJieba(t *testing.T) string {
+	f, err := ioutil.TempFile("", "jieba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString(testJieba)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestChineseAnalyzer(t *testing.T) {
+	path := writeTestJieba(t)
+	defer func() {
+		_ = os.Remove(path)
+	}()
+
+	tests := []struct {
+		input  []byte
+		output []string
+	}{
+		{
+			input:  []byte("我喜欢ＢＬＥＶＥ搜索引擎"),
+			output: []string{"我", "喜欢", "bleve", "搜索引擎"},
+		},
+		{
+			input:  []byte("研究生命科学"),
+			output: []string{"研究", "生命", "科学"},
+		},
+		{
+			input:  []byte("北京大学的图书馆"),
+			output: []string{"北京大学", "的", "图书馆"},
+		},
+	}
+
+	cache := registry.NewCache()
+	analyzer, err := cache.DefineAnalyzer("chinese", map[string]interface{}{
+		"type":       AnalyzerName,
+		"dictionary": path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		var actual []string
+		for _, token := range analyzer.Analyze(test.input) {
+			actual = append(actual, string(token.Term))
+		}
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %v, got %v", test.output, actual)
+		}
+	}
+
+	_, err = cache.DefineAnalyzer("no_dictionary", map[string]interface{}{
+		"type": AnalyzerName,
+	})
+	if err == nil {
+		t.Errorf("expected error without dictionary")
+	}
+}
+
+func TestLoadJieba(t *testing.T) {
+	_, err := LoadJieba([]string{"/nonexistent/dict.txt"})
+	if err == nil {
+		t.Errorf("expected error for missing dictionary")
+	}
+
+	f, err := ioutil.TempFile("", "jieba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	_, err = f.WriteString("北京 many ns\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadJieba([]string{f.Name()})
+	if err == nil {
+		t.Errorf("expected error for invalid frequency")
+	}
+}
@@ -0,0 +1,201 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zh
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// sampleTokenizerName is the name of a tokenizer segmenting with the
+// sample dictionary, for the tests. It takes the same "user_dictionary"
+// property as the Chinese dictionary tokenizer.
+const sampleTokenizerName = "zh_sample_dictionary"
+
+// the number of words the frequencies of the sample dictionary were
+// counted in, the one of the jieba dictionary
+const sampleCorpusSize = 60000000
+
+var sampleDict *lattice.Dictionary
+var sampleDictOnce sync.Once
+
+// sampleDictionary returns the sample dictionary, which only covers about
+// 130 common words.
+func sampleDictionary() *lattice.Dictionary {
+	sampleDictOnce.Do(func() {
+		sampleDict = newDictionary(sampleCorpusSize)
+		for _, line := range strings.Split(sampleDictionaryData, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				continue
+			}
+			freq, err := strconv.Atoi(fields[1])
+			if err != nil || freq < 1 {
+				continue
+			}
+			addWord(sampleDict, sampleCorpusSize, fields[0], freq, fields[2])
+		}
+	})
+	return sampleDict
+}
+
+func sampleTokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	return newTokenizer(sampleDictionary(), config)
+}
+
+func init() {
+	registry.RegisterTokenizer(sampleTokenizerName, sampleTokenizerConstructor)
+}
+
+// The sample dictionary, one word per line with its frequency and part
+// of speech tag, as in the jieba dictionary.
+const sampleDictionaryData = `
+我 328841 r
+你 167000 r
+他 256000 r
+她 78000 r
+它 45000 r
+我们 96000 r
+你们 18000 r
+他们 86000 r
+这 96000 r
+那 53000 r
+这个 52000 r
+什么 45000 r
+自己 82000 r
+的 3188252 uj
+了 883634 ul
+着 86000 uz
+过 60000 ug
+地 9000 uv
+得 100000 ud
+是 796991 v
+有 423765 v
+来 175000 v
+到 167000 v
+来到 14000 v
+去 110000 v
+说 213000 v
+看 96000 v
+想 70000 v
+要 240000 v
+会 140000 v
+能 110000 v
+可以 100000 v
+喜欢 19000 v
+学习 26000 v
+使用 22000 v
+搜索 2300 v
+处理 22000 v
+计算 6000 v
+工作 48000 vn
+研究 28000 vn
+生活 36000 vn
+发展 60000 vn
+在 944000 p
+与 140000 p
+于 90000 p
+从 90000 p
+对 150000 p
+把 70000 p
+被 60000 p
+给 60000 p
+和 555815 c
+或 40000 c
+但是 26000 c
+因为 41000 c
+所以 31000 c
+而且 16000 c
+如果 30000 c
+不 360000 d
+很 71000 d
+也 307000 d
+都 202000 d
+就 280000 d
+还 124000 d
+非常 26000 d
+已经 52000 d
+最 73000 d
+好 110000 a
+大 140000 a
+小 70000 a
+新 55000 a
+长 20000 a
+吗 20000 y
+呢 20000 y
+吧 15000 y
+啊 12000 y
+一 200000 m
+一个 210000 m
+个 200000 q
+人 420000 n
+时间 36000 n
+问题 60000 n
+工程 9000 n
+引擎 800 n
+网站 5000 n
+信息 26000 n
+文本 700 n
+技术 32000 n
+经济 50000 n
+社会 50000 n
+公司 40000 n
+市场 40000 n
+人民 38000 n
+共和国 4800 n
+大学 20000 n
+学生 18000 n
+老师 11000 n
+硕士 2000 n
+博士 3300 n
+毕业 5800 n
+科学 18000 n
+科学院 2500 n
+计算所 60 n
+计算机 7000 n
+生命 8500 n
+研究生 2500 n
+自然 10000 n
+语言 9800 n
+自然语言 100 l
+天气 4200 n
+分词 100 n
+市 30000 n
+市长 4000 n
+江 5000 n
+桥 3000 n
+大桥 2000 n
+今天 20000 t
+明天 9000 t
+中文 3500 nz
+汉语 2600 nz
+中国 128000 ns
+北京 34000 ns
+上海 16000 ns
+南京 10000 ns
+南京市 2500 ns
+长江 5000 ns
+长江大桥 3000 ns
+中华人民共和国 3000 ns
+清华 1000 nz
+清华大学 1200 nt
+北京大学 1900 nt
+中国科学院 900 nt
+`
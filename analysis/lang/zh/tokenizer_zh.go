@@ -0,0 +1,214 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zh implements Chinese analysis, segmenting text with a word
+// frequency dictionary in the style of jieba.
+//
+// No dictionary is built into the package: the tokenizer loads the jieba
+// dictionary, dict.txt, named by its "dictionary" property. The words
+// the dictionary lacks are split into single ideographs. Alternatively,
+// define an analyzer of type zh_jieba:
+//
+//	"analyzers": {
+//		"chinese": {
+//			"type": "zh_jieba",
+//			"dictionary": "/usr/share/jieba/dict.txt"
+//		}
+//	}
+package zh
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/tokenizer/lattice"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// TokenizerName is the name of the Chinese dictionary tokenizer. Its
+// constructor takes a "dictionary" property, the path or list of paths
+// of the jieba dictionaries to load, and an optional "user_dictionary"
+// property, a list of lines in the format
+// described by lattice.AddUserDictionary, where the part of speech is a
+// jieba tag such as n or v.
+const TokenizerName = "zh_dictionary"
+
+var jiebaCache = lattice.NewDictionaryCache(LoadJieba)
+
+// LoadJieba reads a dictionary from files in the format of the jieba
+// dictionary, one word per line with its frequency and optionally its
+// part of speech tag, separated by spaces:
+//
+//	北京大学 2053 nt
+func LoadJieba(paths []string) (*lattice.Dictionary, error) {
+	type word struct {
+		surface string
+		freq    int
+		pos     string
+	}
+	var words []word
+	total := 0
+	err := lattice.ReadDictionaryFiles(paths, func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return nil
+		}
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("expected 2 or 3 fields, got %d", len(fields))
+		}
+		freq, err := strconv.Atoi(fields[1])
+		if err != nil || freq < 0 {
+			return fmt.Errorf("invalid frequency '%s'", fields[1])
+		}
+		w := word{surface: fields[0], freq: freq}
+		if len(fields) == 3 {
+			w.pos = fields[2]
+		}
+		words = append(words, w)
+		total += freq
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dict := newDictionary(total)
+	for _, w := range words {
+		if w.freq > 0 {
+			addWord(dict, total, w.surface, w.freq, w.pos)
+		}
+	}
+	return dict, nil
+}
+
+func TokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	paths := lattice.DictionaryPaths(config)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("must specify dictionary")
+	}
+	dict, err := jiebaCache.Dictionary(paths)
+	if err != nil {
+		return nil, err
+	}
+	return newTokenizer(dict, config)
+}
+
+// newTokenizer returns a tokenizer segmenting with dict, extended with
+// the "user_dictionary" of config.
+func newTokenizer(dict *lattice.Dictionary, config map[string]interface{}) (analysis.Tokenizer, error) {
+	if lines := lattice.UserDictionaryLines(config); len(lines) > 0 {
+		dict = dict.Copy()
+		err := dict.AddUserDictionary(lines)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lattice.NewDictionaryTokenizer(dict), nil
+}
+
+func init() {
+	registry.RegisterTokenizer(TokenizerName, TokenizerConstructor)
+}
+
+// cost returns the cost of a word of frequency freq among total words,
+// the opposite of the logarithm of its probability, so that the lowest
+// cost path is the most probable segmentation.
+func cost(total, freq int) int {
+	return int(1000 * math.Log(float64(total)/float64(freq)))
+}
+
+func newDictionary(total int) *lattice.Dictionary {
+	dict := lattice.NewDictionary()
+	dict.TypeOf = typeOf
+	dict.Unknown = func(rs []rune, i int) []*lattice.Entry {
+		return unknown(rs, i, cost(total, 1))
+	}
+	return dict
+}
+
+func addWord(dict *lattice.Dictionary, total int, surface string, freq int, pos string) {
+	dict.Add(&lattice.Entry{
+		Surface: surface,
+		POS:     pos,
+		Type:    typeOf(pos),
+		Cost:    cost(total, freq),
+	})
+}
+
+// typeOf returns the type of a jieba part of speech tag.
+func typeOf(pos string) analysis.TokenType {
+	switch {
+	case pos == "":
+		return analysis.Noun
+	case pos == "r":
+		return analysis.Pronoun
+	case pos[0] == 'v':
+		return analysis.Verb
+	case pos[0] == 'a':
+		return analysis.Adjective
+	case pos[0] == 'd':
+		return analysis.Adverb
+	case pos[0] == 'u', pos[0] == 'p', pos[0] == 'y':
+		return analysis.Particle
+	case pos[0] == 'c':
+		return analysis.Conjunction
+	case pos[0] == 'e':
+		return analysis.Interjection
+	case pos[0] == 'm':
+		return analysis.Numeric
+	case pos[0] == 'x':
+		return analysis.Symbol
+	}
+	return analysis.Noun
+}
+
+func isOther(r rune) bool {
+	return !unicode.Is(unicode.Han, r)
+}
+
+// unknown returns the unknown word starting at i: a single ideograph
+// with the cost of the rarest words, or a run of letters and numbers of
+// other scripts.
+func unknown(rs []rune, i int, ideographCost int) []*lattice.Entry {
+	if !isOther(rs[i]) {
+		return []*lattice.Entry{
+			&lattice.Entry{
+				Surface: string(rs[i]),
+				Type:    analysis.Ideographic,
+				Cost:    ideographCost,
+			},
+		}
+	}
+	j := i + 1
+	numeric := unicode.IsNumber(rs[i])
+	for j < len(rs) && isOther(rs[j]) {
+		numeric = numeric && unicode.IsNumber(rs[j])
+		j++
+	}
+	typ := analysis.AlphaNumeric
+	if numeric {
+		typ = analysis.Numeric
+	}
+	return []*lattice.Entry{
+		&lattice.Entry{
+			Surface: string(rs[i:j]),
+			Type:    typ,
+			Cost:    1000,
+		},
+	}
+}
@@ -0,0 +1,232 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zh
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func TestChineseTokenizer(t *testing.T) {
+	tests := []struct {
+		input  []byte
+		output analysis.TokenStream
+	}{
+		{
+			input: []byte("我来到北京清华大学"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("我"),
+					Type:     analysis.Pronoun,
+					Position: 1,
+					Start:    0,
+					End:      3,
+				},
+				&analysis.Token{
+					Term:     []byte("来到"),
+					Type:     analysis.Verb,
+					Position: 2,
+					Start:    3,
+					End:      9,
+				},
+				&analysis.Token{
+					Term:     []byte("北京"),
+					Type:     analysis.Noun,
+					Position: 3,
+					Start:    9,
+					End:      15,
+				},
+				&analysis.Token{
+					Term:     []byte("清华大学"),
+					Type:     analysis.Noun,
+					Position: 4,
+					Start:    15,
+					End:      27,
+				},
+			},
+		},
+		{
+			input: []byte("南京市长江大桥"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("南京市"),
+					Type:     analysis.Noun,
+					Position: 1,
+					Start:    0,
+					End:      9,
+				},
+				&analysis.Token{
+					Term:     []byte("长江大桥"),
+					Type:     analysis.Noun,
+					Position: 2,
+					Start:    9,
+					End:      21,
+				},
+			},
+		},
+		{
+			input: []byte("他们使用Bleve 2搜索中文。"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("他们"),
+					Type:     analysis.Pronoun,
+					Position: 1,
+					Start:    0,
+					End:      6,
+				},
+				&analysis.Token{
+					Term:     []byte("使用"),
+					Type:     analysis.Verb,
+					Position: 2,
+					Start:    6,
+					End:      12,
+				},
+				&analysis.Token{
+					Term:     []byte("Bleve"),
+					Type:     analysis.AlphaNumeric,
+					Position: 3,
+					Start:    12,
+					End:      17,
+				},
+				&analysis.Token{
+					Term:     []byte("2"),
+					Type:     analysis.Numeric,
+					Position: 4,
+					Start:    18,
+					End:      19,
+				},
+				&analysis.Token{
+					Term:     []byte("搜索"),
+					Type:     analysis.Verb,
+					Position: 5,
+					Start:    19,
+					End:      25,
+				},
+				&analysis.Token{
+					Term:     []byte("中文"),
+					Type:     analysis.Noun,
+					Position: 6,
+					Start:    25,
+					End:      31,
+				},
+			},
+		},
+		{
+			input: []byte("小明硕士毕业于中国科学院"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("小"),
+					Type:     analysis.Adjective,
+					Position: 1,
+					Start:    0,
+					End:      3,
+				},
+				&analysis.Token{
+					Term:     []byte("明"),
+					Type:     analysis.Ideographic,
+					Position: 2,
+					Start:    3,
+					End:      6,
+				},
+				&analysis.Token{
+					Term:     []byte("硕士"),
+					Type:     analysis.Noun,
+					Position: 3,
+					Start:    6,
+					End:      12,
+				},
+				&analysis.Token{
+					Term:     []byte("毕业"),
+					Type:     analysis.Noun,
+					Position: 4,
+					Start:    12,
+					End:      18,
+				},
+				&analysis.Token{
+					Term:     []byte("于"),
+					Type:     analysis.Particle,
+					Position: 5,
+					Start:    18,
+					End:      21,
+				},
+				&analysis.Token{
+					Term:     []byte("中国科学院"),
+					Type:     analysis.Noun,
+					Position: 6,
+					Start:    21,
+					End:      36,
+				},
+			},
+		},
+	}
+
+	cache := registry.NewCache()
+	tokenizer, err := cache.TokenizerNamed(sampleTokenizerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		actual := tokenizer.Tokenize(test.input)
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %v, got %v", test.output, actual)
+		}
+	}
+
+	_, err = cache.TokenizerNamed(TokenizerName)
+	if err == nil {
+		t.Errorf("expected error without dictionary")
+	}
+}
+
+func TestChineseTokenizerUserDictionary(t *testing.T) {
+	cache := registry.NewCache()
+	tokenizer, err := cache.DefineTokenizer("zh_user", map[string]interface{}{
+		"type":            sampleTokenizerName,
+		"user_dictionary": "小明,小明,,nr\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := analysis.TokenStream{
+		&analysis.Token{
+			Term:     []byte("小明"),
+			Type:     analysis.Noun,
+			Position: 1,
+			Start:    0,
+			End:      6,
+		},
+		&analysis.Token{
+			Term:     []byte("硕士"),
+			Type:     analysis.Noun,
+			Position: 2,
+			Start:    6,
+			End:      12,
+		},
+		&analysis.Token{
+			Term:     []byte("毕业"),
+			Type:     analysis.Noun,
+			Position: 3,
+			Start:    12,
+			End:      18,
+		},
+	}
+	actual := tokenizer.Tokenize([]byte("小明硕士毕业"))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,143 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// SynonymMap maps a sequence of tokens to the token sequences it should
+// be expanded to. Token sequences are stored with their tokens joined
+// by a single space.
+type SynonymMap map[string][]string
+
+func NewSynonymMap() SynonymMap {
+	return make(SynonymMap, 0)
+}
+
+// LoadFile reads in a list of synonym rules from a text file,
+// one per line.
+// Comments are supported using `#`
+func (s SynonymMap) LoadFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return s.LoadBytes(data)
+}
+
+// LoadBytes reads in a list of synonym rules from memory,
+// one per line.
+// Comments are supported using `#`
+func (s SynonymMap) LoadBytes(data []byte) error {
+	bytesReader := bytes.NewReader(data)
+	bufioReader := bufio.NewReader(bytesReader)
+	line, err := bufioReader.ReadString('\n')
+	for err == nil {
+		s.LoadLine(line)
+		line, err = bufioReader.ReadString('\n')
+	}
+	// if the err was EOF we still need to process the last value
+	if err == io.EOF {
+		s.LoadLine(line)
+		return nil
+	}
+	return err
+}
+
+// LoadLine parses a single synonym rule. Rules are either a comma
+// separated list of equivalent token sequences:
+//
+//	tv, television
+//
+// or an explicit mapping, where the token sequences on the left are
+// replaced by all of the token sequences on the right:
+//
+//	usa, u.s.a. => united states of america
+func (s SynonymMap) LoadLine(line string) {
+	// find the start of a comment, if any
+	startComment := strings.IndexByte(line, '#')
+	if startComment >= 0 {
+		line = line[:startComment]
+	}
+
+	arrow := strings.Index(line, "=>")
+	if arrow >= 0 {
+		s.AddMapping(splitSynonyms(line[:arrow]), splitSynonyms(line[arrow+2:]))
+		return
+	}
+	s.AddSynonyms(splitSynonyms(line))
+}
+
+// AddSynonyms makes all of the token sequences equivalent, each of them
+// is expanded to all of the others, and also kept.
+func (s SynonymMap) AddSynonyms(synonyms []string) {
+	if len(synonyms) < 2 {
+		return
+	}
+	s.AddMapping(synonyms, synonyms)
+}
+
+// AddMapping replaces each of the token sequences in from with all of
+// the token sequences in to.
+func (s SynonymMap) AddMapping(from, to []string) {
+	for _, source := range from {
+		source = normalizeSynonym(source)
+		if source == "" {
+			continue
+		}
+		for _, target := range to {
+			target = normalizeSynonym(target)
+			if target != "" && !s.Contains(source, target) {
+				s[source] = append(s[source], target)
+			}
+		}
+	}
+}
+
+// Contains checks whether source is expanded to target.
+func (s SynonymMap) Contains(source, target string) bool {
+	for _, existing := range s[source] {
+		if existing == target {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxLength returns the number of tokens in the longest source token
+// sequence.
+func (s SynonymMap) MaxLength() int {
+	rv := 0
+	for source := range s {
+		n := strings.Count(source, " ") + 1
+		if n > rv {
+			rv = n
+		}
+	}
+	return rv
+}
+
+func splitSynonyms(list string) []string {
+	return strings.Split(list, ",")
+}
+
+func normalizeSynonym(synonym string) string {
+	return strings.Join(strings.Fields(synonym), " ")
+}
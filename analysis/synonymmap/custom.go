@@ -0,0 +1,64 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package synonymmap implements a generic SynonymMap, used in conjunction
+// with the synonym token filter to expand tokens into their synonyms.
+//
+// Its constructor takes the following arguments:
+//
+// "filename" (string): the path of a file listing the synonym rules, one
+// per line, followed by an optional comment starting with a "#" character.
+//
+// "synonyms" ([]interface{}): if "filename" is not specified, rules can be
+// passed directly as a sequence of strings wrapped in a []interface{}.
+//
+// Each rule is either a comma separated list of equivalent token sequences,
+// "tv, television", or an explicit mapping, "usa => united states of america".
+package synonymmap
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const Name = "custom"
+
+func GenericSynonymMapConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.SynonymMap, error) {
+	rv := analysis.NewSynonymMap()
+
+	// first: try to load by filename
+	filename, ok := config["filename"].(string)
+	if ok {
+		err := rv.LoadFile(filename)
+		return rv, err
+	}
+	// next: look for an inline rule list
+	synonyms, ok := config["synonyms"].([]interface{})
+	if ok {
+		for _, synonym := range synonyms {
+			synonymStr, ok := synonym.(string)
+			if ok {
+				rv.LoadLine(synonymStr)
+			}
+		}
+		return rv, nil
+	}
+	return nil, fmt.Errorf("must specify filename or list of synonyms for synonym map")
+}
+
+func init() {
+	registry.RegisterSynonymMap(Name, GenericSynonymMapConstructor)
+}
@@ -0,0 +1,53 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSynonymMapLoadFile(t *testing.T) {
+	synonymMap := NewSynonymMap()
+	err := synonymMap.LoadFile("test_synonyms.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSynonyms := SynonymMap{
+		"tv":         []string{"tv", "television"},
+		"television": []string{"tv", "television"},
+		"usa":        []string{"united states of america"},
+		"u.s.a.":     []string{"united states of america"},
+		"couch":      []string{"couch", "sofa", "settee"},
+		"sofa":       []string{"couch", "sofa", "settee"},
+		"settee":     []string{"couch", "sofa", "settee"},
+	}
+
+	if !reflect.DeepEqual(synonymMap, expectedSynonyms) {
+		t.Errorf("expected %#v, got %#v", expectedSynonyms, synonymMap)
+	}
+}
+
+func TestSynonymMapMaxLength(t *testing.T) {
+	synonymMap := NewSynonymMap()
+	synonymMap.LoadLine("nyc, new  york city")
+	if synonymMap.MaxLength() != 3 {
+		t.Errorf("expected max length 3, got %d", synonymMap.MaxLength())
+	}
+	if !synonymMap.Contains("new york city", "nyc") {
+		t.Errorf("expected 'new york city' to expand to 'nyc'")
+	}
+}
@@ -0,0 +1,197 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package folding implements TokenFilters removing the differences between
+// characters which are usually not significant for matching, such as
+// accents and case.
+//
+// The fold_ascii filter replaces characters outside the ASCII range with
+// their closest ASCII equivalent, if any: "Ærøskøbing" -> "AEroskobing".
+// The case of the characters is kept.
+//
+// The fold_unicode filter applies full Unicode case folding, compatibility
+// decomposition and removes the diacritics of the Latin, Greek, Cyrillic,
+// Arabic and Hebrew scripts: "İstanbul" -> "istanbul", "Straße" ->
+// "strasse", "Ἀθῆναι" -> "αθηναι". Other scripts keep their combining
+// marks, which are significant there.
+//
+// Both filters accept a preserve_original option, which keeps the original
+// token along with the folded one when they differ.
+package folding
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const ASCIIName = "fold_ascii"
+const UnicodeName = "fold_unicode"
+
+// letters and punctuation which have no decomposition to an ASCII base
+var asciiFoldings = map[rune]string{
+	'Æ': "AE", 'æ': "ae",
+	'Ð': "D", 'ð': "d",
+	'Đ': "D", 'đ': "d",
+	'Ħ': "H", 'ħ': "h",
+	'ı': "i",
+	'ĸ': "q",
+	'Ł': "L", 'ł': "l",
+	'Ŋ': "N", 'ŋ': "n",
+	'Ø': "O", 'ø': "o",
+	'Œ': "OE", 'œ': "oe",
+	'ß': "ss", 'ẞ': "SS",
+	'Þ': "TH", 'þ': "th",
+	'Ŧ': "T", 'ŧ': "t",
+	'Ɓ': "B", 'ƀ': "b",
+	'Ƒ': "F", 'ƒ': "f",
+	'Ɨ': "I", 'ɨ': "i",
+	'‘': "'", '’': "'", '‚': "'", '‛': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '«': "\"", '»': "\"",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-",
+}
+
+// scripts whose combining marks are removed by UnicodeFold
+var diacriticScripts = []*unicode.RangeTable{
+	unicode.Latin,
+	unicode.Greek,
+	unicode.Cyrillic,
+	unicode.Arabic,
+	unicode.Hebrew,
+}
+
+// ASCIIFold replaces the characters of term with their ASCII equivalent,
+// when there is one.
+func ASCIIFold(term []byte) []byte {
+	rv := make([]byte, 0, len(term))
+	for i := 0; i < len(term); {
+		r, size := utf8.DecodeRune(term[i:])
+		switch {
+		case r < utf8.RuneSelf:
+			rv = append(rv, byte(r))
+		case asciiFoldings[r] != "":
+			rv = append(rv, asciiFoldings[r]...)
+		default:
+			rv = append(rv, asciiDecomposition(term[i:i+size])...)
+		}
+		i += size
+	}
+	return rv
+}
+
+// asciiDecomposition returns the compatibility decomposition of a single
+// character without its combining marks if that is ASCII, otherwise the
+// character itself
+func asciiDecomposition(char []byte) []byte {
+	decomposed := norm.NFKD.Bytes(char)
+	rv := make([]byte, 0, len(decomposed))
+	for _, r := range string(decomposed) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r >= utf8.RuneSelf {
+			return char
+		}
+		rv = append(rv, byte(r))
+	}
+	if len(rv) == 0 {
+		return char
+	}
+	return rv
+}
+
+// UnicodeFold case folds term, and removes its compatibility differences
+// and the diacritics of the scripts using them as accents.
+func UnicodeFold(term []byte) []byte {
+	// a Caser keeps state, so it can't be shared between goroutines
+	folded := norm.NFKD.Bytes(cases.Fold().Bytes(term))
+	rv := make([]byte, 0, len(folded))
+	stripMarks := false
+	for _, r := range string(folded) {
+		if unicode.Is(unicode.Mn, r) {
+			if stripMarks {
+				continue
+			}
+		} else {
+			stripMarks = unicode.In(r, diacriticScripts...)
+		}
+		if folding, ok := asciiFoldings[r]; ok {
+			rv = append(rv, folding...)
+			continue
+		}
+		rv = append(rv, string(r)...)
+	}
+	return norm.NFC.Bytes(rv)
+}
+
+type FoldingFilter struct {
+	fold             func([]byte) []byte
+	preserveOriginal bool
+}
+
+func NewASCIIFoldingFilter(preserveOriginal bool) *FoldingFilter {
+	return &FoldingFilter{
+		fold:             ASCIIFold,
+		preserveOriginal: preserveOriginal,
+	}
+}
+
+func NewUnicodeFoldingFilter(preserveOriginal bool) *FoldingFilter {
+	return &FoldingFilter{
+		fold:             UnicodeFold,
+		preserveOriginal: preserveOriginal,
+	}
+}
+
+func (f *FoldingFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input))
+
+	for _, token := range input {
+		folded := f.fold(token.Term)
+		if f.preserveOriginal && !bytes.Equal(folded, token.Term) {
+			rv = append(rv, token)
+			token = &analysis.Token{
+				Start:    token.Start,
+				End:      token.End,
+				Position: token.Position,
+				Type:     token.Type,
+				KeyWord:  token.KeyWord,
+			}
+		}
+		token.Term = folded
+		rv = append(rv, token)
+	}
+
+	return rv
+}
+
+func ASCIIFoldingFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	preserveOriginal, _ := config["preserve_original"].(bool)
+	return NewASCIIFoldingFilter(preserveOriginal), nil
+}
+
+func UnicodeFoldingFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	preserveOriginal, _ := config["preserve_original"].(bool)
+	return NewUnicodeFoldingFilter(preserveOriginal), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(ASCIIName, ASCIIFoldingFilterConstructor)
+	registry.RegisterTokenFilter(UnicodeName, UnicodeFoldingFilterConstructor)
+}
@@ -0,0 +1,137 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package folding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func TestASCIIFold(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"résumé", "resume"},
+		{"Ærøskøbing", "AEroskobing"},
+		{"Straße", "Strasse"},
+		{"Łódź", "Lodz"},
+		{"ﬁancée", "fiancee"},
+		{"Ｗｉ－Ｆｉ", "Wi-Fi"},
+		{"“quoted”", "\"quoted\""},
+		{"Москва", "Москва"},
+		{"東京", "東京"},
+		{"plain", "plain"},
+	}
+
+	for _, test := range tests {
+		actual := string(ASCIIFold([]byte(test.input)))
+		if actual != test.output {
+			t.Errorf("expected %s to fold to %s, got %s", test.input, test.output, actual)
+		}
+	}
+}
+
+func TestUnicodeFold(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"Résumé", "resume"},
+		{"Straße", "strasse"},
+		{"İstanbul", "istanbul"},
+		{"ISTANBUL", "istanbul"},
+		{"ıstanbul", "istanbul"},
+		{"Ἀθῆναι", "αθηναι"},
+		{"ΟΔΟΣ", "οδοσ"},
+		{"Ёлка", "елка"},
+		{"Ｗｉ－Ｆｉ", "wi-fi"},
+		// combining marks are significant outside of the folded scripts
+		{"がっこう", "がっこう"},
+		{"हिन्दी", "हिन्दी"},
+		{"한국어", "한국어"},
+	}
+
+	for _, test := range tests {
+		actual := string(UnicodeFold([]byte(test.input)))
+		if actual != test.output {
+			t.Errorf("expected %s to fold to %s, got %s", test.input, test.output, actual)
+		}
+	}
+}
+
+func TestFoldingFilter(t *testing.T) {
+	inputTokenStream := analysis.TokenStream{
+		&analysis.Token{Term: []byte("Crème"), Position: 1, Start: 0, End: 6, Type: analysis.AlphaNumeric},
+		&analysis.Token{Term: []byte("brûlée"), Position: 2, Start: 7, End: 15, Type: analysis.AlphaNumeric},
+		&analysis.Token{Term: []byte("recipe"), Position: 3, Start: 16, End: 22, Type: analysis.AlphaNumeric},
+	}
+
+	tests := []struct {
+		filter analysis.TokenFilter
+		output analysis.TokenStream
+	}{
+		{
+			filter: NewASCIIFoldingFilter(false),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("Creme"), Position: 1, Start: 0, End: 6, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("brulee"), Position: 2, Start: 7, End: 15, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("recipe"), Position: 3, Start: 16, End: 22, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			filter: NewUnicodeFoldingFilter(true),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("Crème"), Position: 1, Start: 0, End: 6, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("creme"), Position: 1, Start: 0, End: 6, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("brûlée"), Position: 2, Start: 7, End: 15, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("brulee"), Position: 2, Start: 7, End: 15, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("recipe"), Position: 3, Start: 16, End: 22, Type: analysis.AlphaNumeric},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		input := make(analysis.TokenStream, len(inputTokenStream))
+		for i, token := range inputTokenStream {
+			copied := *token
+			input[i] = &copied
+		}
+		actual := test.filter.Filter(input)
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %s, got %s", test.output, actual)
+		}
+	}
+}
+
+func TestFoldingFilterConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	filter, err := cache.DefineTokenFilter("fold_test", map[string]interface{}{
+		"type":              ASCIIName,
+		"preserve_original": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := filter.Filter(analysis.TokenStream{
+		&analysis.Token{Term: []byte("naïve"), Position: 1},
+	})
+	if len(actual) != 2 || string(actual[0].Term) != "naïve" || string(actual[1].Term) != "naive" {
+		t.Errorf("expected original and folded tokens, got %s", actual)
+	}
+}
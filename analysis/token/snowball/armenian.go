@@ -0,0 +1,85 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// ArmenianName is the name of the token filter for the Armenian
+// algorithm.
+const ArmenianName = NamePrefix + "hy"
+
+const armenianVowels = "աէիօւեոը"
+
+// the endings, removed in R2
+var armenianEndings = []string{
+	"ները", "ներն", "ների", "ներդ", "երից", "ներից", "երի", "երդ",
+	"երն", "երը", "ներին", "ությանն", "ությանը", "ությանս", "ությանդ",
+	"ության", "երին", "ին", "սա", "ոջ", "ից", "երով", "ներով", "երում",
+	"ներում", "ուն", "ուդ", "վանս", "վանը", "վանդ", "անը", "անդ", "վան",
+	"ոջը", "ոջս", "ոջդ", "ոց", "ուց", "ոջից", "ցից", "վից", "վի", "վով",
+	"ով", "անով", "անում", "վանից", "ամբ", "ան", "ներ", "եր", "վա", "ը",
+	"ն", "դ", "ց", "ի",
+}
+
+// the verb suffixes
+var armenianVerbSuffixes = []string{
+	"ում", "վում", "ալու", "ելու", "վել", "անալ", "ելուց", "ալուց",
+	"ըալ", "ըել", "ալով", "ելով", "ալիս", "ելիս", "ենալ", "ացնալ",
+	"եցնել", "ցնել", "նել", "ատել", "ոտել", "կոտել", "տել", "ված",
+	"եցվել", "ացվել", "եցիր", "ացիր", "եցինք", "ացինք", "վեցիր",
+	"վեցինք", "վեցիք", "վեցին", "ացրիր", "ացրեց", "ացրինք", "ացրիք",
+	"ացրին", "եցիք", "ացիք", "եցին", "ացին", "ացար", "ացավ", "ացանք",
+	"ացաք", "ացան", "վեցի", "ացրի", "եցար", "եցավ", "ցանք", "ցաք",
+	"ցան", "ացա", "ացի", "եցա", "չել", "եցի", "ար", "ավ", "անք", "աք",
+	"ան", "ալ", "ել", "եց", "աց", "վե", "ա",
+}
+
+// the adjective suffixes
+var armenianAdjectiveSuffixes = []string{
+	"բար", "պես", "որէն", "ովին", "ակի", "լայն", "րորդ", "երորդ",
+	"ական", "ալի", "կոտ", "եկեն", "որակ", "եղ", "վուն", "երեն", "արան",
+	"են", "ավետ", "գին", "իվ", "ատ", "ին",
+}
+
+// the noun suffixes
+var armenianNounSuffixes = []string{
+	"ածո", "անակ", "անօց", "արան", "արք", "պան", "ստան", "եղէն", "ենք",
+	"իկ", "իչ", "իք", "մունք", "յակ", "յուն", "ոնք", "որդ", "ոց", "չեք",
+	"վածք", "վոր", "ավոր", "ություն", "ուկ", "ուհի", "ույթ", "ույք",
+	"ուստ", "ուս", "ցի", "ալիք", "անիք", "իլ", "իչք", "ունք", "գար",
+	"ու", "ակ", "ան", "ք",
+}
+
+func init() {
+	Register("hy", stemArmenian)
+}
+
+func stemArmenian(w *Word) {
+	w.MarkRegions(armenianVowels)
+	// the suffixes are only searched after the first vowel
+	start := len(w.RS)
+	for i := range w.RS {
+		if w.isVowel(i, armenianVowels) {
+			start = i + 1
+			break
+		}
+	}
+	if s := w.SuffixIn(start, armenianEndings...); s != "" && w.In(w.R2, s) {
+		w.Delete(s)
+	}
+	for _, suffixes := range [][]string{armenianVerbSuffixes, armenianAdjectiveSuffixes, armenianNounSuffixes} {
+		if s := w.SuffixIn(start, suffixes...); s != "" {
+			w.Delete(s)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// BasqueName is the name of the token filter for the Basque algorithm.
+const BasqueName = NamePrefix + "eu"
+
+const basqueVowels = "aeiou"
+
+// A basqueTable lists the suffixes of a Basque step by the region they
+// must lie in to be deleted, along with the suffixes replaced wherever
+// they are.
+type basqueTable struct {
+	rv, r1, r2 []string
+	replace    map[string]string
+}
+
+var basqueVerbSuffixes = &basqueTable{
+	rv: []string{
+		"idea", "bidea", "kidea", "pidea", "kundea", "galea", "tailea",
+		"tzailea", "gunea", "kunea", "tzaga", "gaia", "aldia", "taldia",
+		"karia", "karria", "ka", "tzaka", "la", "mena", "pena", "kina",
+		"ezina", "tezina", "kuna", "tuna", "kizuna", "era", "bera", "kera",
+		"pera", "orra", "korra", "dura", "gura", "kura", "tura", "eta",
+		"keta", "gailua", "eza", "erreza", "gaitza", "kaitza", "kuntza",
+		"ide", "bide", "kide", "pide", "kunde", "tzake", "tzeke", "le",
+		"gale", "taile", "tzaile", "gune", "kune", "tze", "atze", "gai",
+		"aldi", "taldi", "ki", "ari", "kari", "lari", "tari", "etari",
+		"karri", "arazi", "tarazi", "an", "ean", "rean", "kan", "etan",
+		"men", "pen", "kin", "rekin", "ezin", "tezin", "tun", "kizun", "go",
+		"ago", "tio", "dako", "or", "kor", "tzat", "du", "gailu", "tu",
+		"atu", "aldatu", "tatu", "ez", "errez", "tzez", "gaitz", "kaitz",
+	},
+	r2: []string{
+		"garria", "tza", "garri",
+	},
+	replace: map[string]string{
+		"atseden": "atseden",
+		"arabera": "arabera",
+		"baditu":  "baditu",
+	},
+}
+
+var basqueNounSuffixes = &basqueTable{
+	rv: []string{
+		"ada", "kada", "anda", "denda", "gabea", "kabea", "aldea", "kaldea",
+		"taldea", "ordea", "zalea", "tzalea", "gilea", "emea", "kumea",
+		"nea", "enea", "zionea", "unea", "gunea", "pea", "aurrea", "tea",
+		"kotea", "artea", "ostea", "etxea", "ga", "anga", "gaia", "aldia",
+		"taldia", "handia", "mendia", "geia", "egia", "degia", "tegia",
+		"nahia", "ohia", "kia", "tokia", "oia", "koia", "aria", "karia",
+		"laria", "taria", "eria", "keria", "teria", "larria", "kirria",
+		"duria", "asia", "tia", "ezia", "bizia", "ontzia", "ka", "ska",
+		"xka", "zka", "gibela", "gela", "kaila", "skila", "tila", "ola",
+		"na", "kana", "ena", "garrena", "gerrena", "urrena", "zaina",
+		"tzaina", "kina", "mina", "garna", "una", "duna", "asuna", "tasuna",
+		"ondoa", "kondoa", "ngoa", "zioa", "koa", "takoa", "zkoa", "noa",
+		"zinoa", "aroa", "taroa", "zaroa", "eroa", "oroa", "osoa", "toa",
+		"ttoa", "ztoa", "txoa", "tzoa", "ñoa", "ra", "ara", "dara", "liara",
+		"tiara", "tara", "etara", "tzara", "bera", "kera", "pera", "tzarra",
+		"korra", "tra", "sa", "osa", "ta", "eta", "keta", "sta", "dua",
+		"mendua", "ordua", "lekua", "burua", "durua", "tsua", "tua",
+		"mentua", "estua", "txua", "zua", "tzua", "za", "eza", "eroza",
+		"koitza", "antza", "gintza", "kintza", "kuntza", "gabe", "kabe",
+		"kide", "alde", "kalde", "talde", "orde", "ge", "zale", "tzale",
+		"gile", "eme", "kume", "ne", "zione", "une", "gune", "pe", "aurre",
+		"te", "kote", "arte", "oste", "etxe", "gai", "di", "aldi", "taldi",
+		"handi", "mendi", "gei", "egi", "degi", "tegi", "nahi", "ohi", "ki",
+		"toki", "oi", "goi", "koi", "ari", "kari", "lari", "tari", "larri",
+		"kirri", "duri", "asi", "ti", "ontzi", "ñi", "ak", "ek", "tarik",
+		"gibel", "ail", "kail", "kan", "tan", "etan", "garren", "gerren",
+		"urren", "zain", "tzain", "kin", "min", "dun", "asun", "tasun",
+		"aizun", "ondo", "kondo", "go", "ngo", "zio", "ko", "tako", "etako",
+		"eko", "tariko", "sko", "tuko", "zko", "no", "zino", "ro", "aro",
+		"taro", "zaro", "ero", "giro", "oro", "oso", "to", "tto", "zto",
+		"txo", "tzo", "gintzo", "ño", "zp", "ar", "dar", "behar", "liar",
+		"tiar", "tar", "tzar", "kor", "os", "ket", "du", "mendu", "ordu",
+		"leku", "duru", "tsu", "tu", "mentu", "estu", "txu", "zu", "tzu",
+		"gintzu", "z", "ez", "eroz", "tz", "koitz",
+	},
+	r1: []string{
+		"en", "ten", "tzen", "tatu",
+	},
+	r2: []string{
+		"garria", "ora", "tza", "garri", "ren", "or", "buru",
+	},
+	replace: map[string]string{
+		"joka":     "jok",
+		"trako":    "tra",
+		"minutuko": "minutu",
+		"zehar":    "zehar",
+		"geldi":    "geldi",
+		"igaro":    "igaro",
+		"aurka":    "aurka",
+	},
+}
+
+var basqueAdjectiveSuffixes = &basqueTable{
+	rv: []string{
+		"keria", "la", "era", "dade", "tade", "date", "tate", "gi", "ki",
+		"ik", "lanik", "rik", "larik", "ztik", "go", "ro", "ero", "to",
+	},
+	replace: map[string]string{
+		"zlea": "z",
+	},
+}
+
+func init() {
+	Register("eu", stemBasque)
+}
+
+func stemBasque(w *Word) {
+	w.MarkRegions(basqueVowels)
+	w.MarkRV(basqueVowels)
+
+	// each step works on the word before the suffix found by the
+	// previous one, even when it was replaced rather than deleted
+	end := len(w.RS)
+	for ok := true; ok; {
+		end, ok = basqueStep(w, end, basqueVerbSuffixes)
+	}
+	for ok := true; ok; {
+		end, ok = basqueStep(w, end, basqueNounSuffixes)
+	}
+	basqueStep(w, end, basqueAdjectiveSuffixes)
+}
+
+// basqueStep removes or replaces the longest suffix of the table the
+// word ends with before end, and returns where that suffix started.
+func basqueStep(w *Word, end int, t *basqueTable) (int, bool) {
+	before := &Word{RS: w.RS[:end:end]}
+	s, region := before.Suffix(t.rv...), w.RV
+	if r := before.Suffix(t.r1...); runeLen(r) > runeLen(s) {
+		s, region = r, w.R1
+	}
+	if r := before.Suffix(t.r2...); runeLen(r) > runeLen(s) {
+		s, region = r, w.R2
+	}
+	repl, replace := "", false
+	if r := before.Suffix(keys(t.replace)...); runeLen(r) > runeLen(s) {
+		s, repl, replace = r, t.replace[r], true
+	}
+	if s == "" {
+		return end, false
+	}
+	start := before.Before(s)
+	if !replace && start < region {
+		return end, false
+	}
+	rs := append([]rune(repl), w.RS[end:]...)
+	w.RS = append(w.RS[:start], rs...)
+	return start, true
+}
@@ -0,0 +1,74 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// DanishName is the name of the token filter for the Danish algorithm.
+const DanishName = NamePrefix + "da"
+
+const danishVowels = "aeiouyæåø"
+
+var danishMainSuffixes = []string{
+	"hed", "ethed", "ered", "e", "erede", "ende", "erende", "ene", "erne",
+	"ere", "en", "heden", "eren", "er", "heder", "erer", "heds", "es",
+	"endes", "erendes", "enes", "ernes", "eres", "ens", "hedens", "erens",
+	"ers", "ets", "erets", "et", "eret", "s",
+}
+
+func init() {
+	Register("da", stemDanish)
+}
+
+func stemDanish(w *Word) {
+	w.MarkRegions(danishVowels)
+	w.MinR1(3)
+
+	switch s := w.SuffixIn(w.R1, danishMainSuffixes...); s {
+	case "":
+	case "s":
+		switch w.At(-2) {
+		case 'a', 'b', 'c', 'd', 'f', 'g', 'h', 'j', 'k', 'l', 'm', 'n', 'o',
+			'p', 'r', 't', 'v', 'y', 'z', 'å':
+			w.Delete(s)
+		}
+	default:
+		w.Delete(s)
+	}
+
+	danishConsonantPair(w)
+
+	if w.HasSuffix("igst") {
+		w.Delete("st")
+	}
+	switch s := w.SuffixIn(w.R1, "ig", "lig", "elig", "els", "løst"); s {
+	case "":
+	case "løst":
+		w.Replace(s, "løs")
+	default:
+		w.Delete(s)
+		danishConsonantPair(w)
+	}
+
+	// undouble a final consonant in R1
+	if n := len(w.RS); n > w.R1 && n > 1 &&
+		!w.isVowel(n-1, danishVowels) && w.RS[n-2] == w.RS[n-1] {
+		w.RS = w.RS[:n-1]
+	}
+}
+
+func danishConsonantPair(w *Word) {
+	if w.SuffixIn(w.R1, "gd", "dt", "gt", "kt") != "" {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+}
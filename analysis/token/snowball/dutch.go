@@ -0,0 +1,158 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// DutchName is the name of the token filter for the Dutch algorithm.
+const DutchName = NamePrefix + "nl"
+
+const dutchVowels = "aeiouyè"
+
+func init() {
+	Register("nl", stemDutch)
+}
+
+func stemDutch(w *Word) {
+	for i, r := range w.RS {
+		switch r {
+		case 'ä', 'á':
+			w.RS[i] = 'a'
+		case 'ë', 'é':
+			w.RS[i] = 'e'
+		case 'ï', 'í':
+			w.RS[i] = 'i'
+		case 'ö', 'ó':
+			w.RS[i] = 'o'
+		case 'ü', 'ú':
+			w.RS[i] = 'u'
+		}
+	}
+	// initial y, y after a vowel and i between vowels are consonants
+	if len(w.RS) > 0 && w.RS[0] == 'y' {
+		w.RS[0] = 'Y'
+	}
+	from := 0
+	for i := 1; i < len(w.RS); i++ {
+		if i-1 < from || !w.isVowel(i-1, dutchVowels) {
+			continue
+		}
+		if w.RS[i] == 'y' {
+			w.RS[i] = 'Y'
+			from = i + 1
+		} else if w.RS[i] == 'i' && w.IsVowel(i+1, dutchVowels) {
+			w.RS[i] = 'I'
+			from = i + 2
+		}
+	}
+	w.MarkRegions(dutchVowels)
+	w.MinR1(3)
+
+	switch s := w.Suffix("heden", "en", "ene", "s", "se"); s {
+	case "heden":
+		if w.In(w.R1, s) {
+			w.Replace(s, "heid")
+		}
+	case "en", "ene":
+		dutchEnEnding(w, s)
+	case "s", "se":
+		if w.In(w.R1, s) {
+			if r := w.At(w.Before(s) - 1); r != 0 && r != 'j' &&
+				!w.IsVowel(w.Before(s)-1, dutchVowels) {
+				w.Delete(s)
+			}
+		}
+	}
+
+	eFound := dutchEEnding(w)
+
+	if w.HasSuffix("heid") && w.In(w.R2, "heid") && !w.HasSuffixBefore("c", "heid") {
+		w.Delete("heid")
+		if w.HasSuffix("en") {
+			dutchEnEnding(w, "en")
+		}
+	}
+
+	switch s := w.Suffix("end", "ing", "ig", "lijk", "baar", "bar"); {
+	case s == "" || !w.In(w.R2, s):
+	case s == "end" || s == "ing":
+		w.Delete(s)
+		if w.HasSuffix("ig") && w.In(w.R2, "ig") && !w.HasSuffix("eig") {
+			w.Delete("ig")
+		} else {
+			dutchUndouble(w)
+		}
+	case s == "ig":
+		if !w.HasSuffixBefore("e", s) {
+			w.Delete(s)
+		}
+	case s == "lijk":
+		w.Delete(s)
+		dutchEEnding(w)
+	case s == "baar":
+		w.Delete(s)
+	case s == "bar":
+		if eFound {
+			w.Delete(s)
+		}
+	}
+
+	// undouble the vowel of a final non-vowel, double vowel, non-vowel
+	// sequence
+	if n := len(w.RS); n >= 4 && w.RS[n-1] != 'I' &&
+		!w.isVowel(n-1, dutchVowels) && w.RS[n-3] == w.RS[n-2] &&
+		!w.isVowel(n-4, dutchVowels) {
+		switch w.RS[n-2] {
+		case 'a', 'e', 'o', 'u':
+			w.RS = append(w.RS[:n-2], w.RS[n-1])
+		}
+	}
+
+	w.ReplaceAll('I', 'i')
+	w.ReplaceAll('Y', 'y')
+}
+
+// dutchEnEnding deletes suffix, which is en or ene, if it is in R1 and
+// preceded by a non-vowel which does not end gem.
+func dutchEnEnding(w *Word, suffix string) {
+	if !w.In(w.R1, suffix) {
+		return
+	}
+	p := w.Before(suffix) - 1
+	if p < 0 || w.isVowel(p, dutchVowels) || w.HasSuffixBefore("gem", suffix) {
+		return
+	}
+	w.Delete(suffix)
+	dutchUndouble(w)
+}
+
+// dutchEEnding deletes a final e in R1 preceded by a non-vowel, and
+// returns true if it did.
+func dutchEEnding(w *Word) bool {
+	if !w.HasSuffix("e") || !w.In(w.R1, "e") {
+		return false
+	}
+	p := w.Before("e") - 1
+	if p < 0 || w.isVowel(p, dutchVowels) {
+		return false
+	}
+	w.Delete("e")
+	dutchUndouble(w)
+	return true
+}
+
+func dutchUndouble(w *Word) {
+	if w.Suffix("kk", "dd", "tt") != "" {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+}
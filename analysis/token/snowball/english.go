@@ -0,0 +1,279 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// EnglishName is the name of the token filter for the English (Porter2)
+// algorithm.
+const EnglishName = NamePrefix + "en"
+
+const englishVowels = "aeiouy"
+
+var englishExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// words left as they are after step 1a
+var englishStep1aExceptions = map[string]bool{
+	"inning":  true,
+	"outing":  true,
+	"canning": true,
+	"herring": true,
+	"earring": true,
+	"proceed": true,
+	"exceed":  true,
+	"succeed": true,
+}
+
+var englishStep2Table = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"ogi":     "og",
+	"fulli":   "ful",
+	"lessli":  "less",
+	"li":      "",
+}
+
+var englishStep3Table = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+	"ative":   "",
+}
+
+var englishStep2Suffixes = keys(englishStep2Table)
+var englishStep3Suffixes = keys(englishStep3Table)
+
+var englishStep4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+	"ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+func init() {
+	Register("en", stemEnglish)
+}
+
+func stemEnglish(w *Word) {
+	if stem, ok := englishExceptions[w.String()]; ok {
+		w.RS = []rune(stem)
+		return
+	}
+	if len(w.RS) <= 2 {
+		return
+	}
+
+	if w.RS[0] == '\'' {
+		w.RS = w.RS[1:]
+	}
+	for i, r := range w.RS {
+		if r == 'y' && (i == 0 || w.isVowel(i-1, englishVowels)) {
+			w.RS[i] = 'Y'
+		}
+	}
+	englishMarkRegions(w)
+
+	englishStep0(w)
+	englishStep1a(w)
+	if !englishStep1aExceptions[w.String()] {
+		englishStep1b(w)
+		englishStep1c(w)
+		englishStep2(w)
+		englishStep3(w)
+		englishStep4(w)
+		englishStep5(w)
+	}
+
+	w.ReplaceAll('Y', 'y')
+}
+
+func englishMarkRegions(w *Word) {
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		n := runeLen(prefix)
+		if len(w.RS) >= n && string(w.RS[:n]) == prefix {
+			w.R1 = n
+			w.R2 = w.regionAfter(n, englishVowels)
+			return
+		}
+	}
+	w.MarkRegions(englishVowels)
+}
+
+// englishShortSyllable returns true if the letters before end are a
+// short syllable: a vowel followed by a non-vowel other than w, x or Y
+// and preceded by a non-vowel, or a vowel at the beginning of the word
+// followed by a non-vowel.
+func englishShortSyllable(w *Word, end int) bool {
+	if end >= 3 {
+		return !w.isVowel(end-1, englishVowels) && w.RS[end-1] != 'w' &&
+			w.RS[end-1] != 'x' && w.RS[end-1] != 'Y' &&
+			w.isVowel(end-2, englishVowels) &&
+			!w.isVowel(end-3, englishVowels)
+	}
+	return end == 2 && !w.isVowel(1, englishVowels) &&
+		w.isVowel(0, englishVowels)
+}
+
+func englishStep0(w *Word) {
+	if s := w.Suffix("'", "'s", "'s'"); s != "" {
+		w.Delete(s)
+	}
+}
+
+func englishStep1a(w *Word) {
+	switch s := w.Suffix("sses", "ied", "ies", "s", "us", "ss"); s {
+	case "sses":
+		w.Replace(s, "ss")
+	case "ied", "ies":
+		if w.Before(s) > 1 {
+			w.Replace(s, "i")
+		} else {
+			w.Replace(s, "ie")
+		}
+	case "s":
+		// the vowel must not be immediately before the s
+		if w.ContainsVowel(w.Before(s)-1, englishVowels) {
+			w.Delete(s)
+		}
+	}
+}
+
+func englishStep1b(w *Word) {
+	switch s := w.Suffix("eed", "eedly", "ed", "edly", "ing", "ingly"); s {
+	case "eed", "eedly":
+		if w.In(w.R1, s) {
+			w.Replace(s, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !w.ContainsVowel(w.Before(s), englishVowels) {
+			return
+		}
+		w.Delete(s)
+		if w.Suffix("at", "bl", "iz") != "" {
+			w.RS = append(w.RS, 'e')
+		} else if d := w.Suffix("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt"); d != "" {
+			w.RS = w.RS[:len(w.RS)-1]
+		} else if len(w.RS) == w.R1 && englishShortSyllable(w, len(w.RS)) {
+			w.RS = append(w.RS, 'e')
+		}
+	}
+}
+
+func englishStep1c(w *Word) {
+	n := len(w.RS)
+	if n > 2 && (w.RS[n-1] == 'y' || w.RS[n-1] == 'Y') &&
+		!w.isVowel(n-2, englishVowels) {
+		w.RS[n-1] = 'i'
+	}
+}
+
+func englishStep2(w *Word) {
+	s := w.Suffix(englishStep2Suffixes...)
+	if s == "" || !w.In(w.R1, s) {
+		return
+	}
+	switch s {
+	case "ogi":
+		if w.At(w.Before(s)-1) == 'l' {
+			w.Replace(s, "og")
+		}
+	case "li":
+		switch w.At(w.Before(s) - 1) {
+		case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+			w.Delete(s)
+		}
+	default:
+		w.Replace(s, englishStep2Table[s])
+	}
+}
+
+func englishStep3(w *Word) {
+	s := w.Suffix(englishStep3Suffixes...)
+	if s == "" || !w.In(w.R1, s) {
+		return
+	}
+	if s == "ative" {
+		if w.In(w.R2, s) {
+			w.Delete(s)
+		}
+		return
+	}
+	w.Replace(s, englishStep3Table[s])
+}
+
+func englishStep4(w *Word) {
+	s := w.Suffix(englishStep4Suffixes...)
+	if s == "" || !w.In(w.R2, s) {
+		return
+	}
+	if s == "ion" {
+		if r := w.At(w.Before(s) - 1); r != 's' && r != 't' {
+			return
+		}
+	}
+	w.Delete(s)
+}
+
+func englishStep5(w *Word) {
+	switch s := w.Suffix("e", "l"); s {
+	case "e":
+		if w.In(w.R2, s) ||
+			(w.In(w.R1, s) && !englishShortSyllable(w, w.Before(s))) {
+			w.Delete(s)
+		}
+	case "l":
+		if w.In(w.R2, s) && w.At(-2) == 'l' {
+			w.Delete(s)
+		}
+	}
+}
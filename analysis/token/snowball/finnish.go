@@ -0,0 +1,208 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// FinnishName is the name of the token filter for the Finnish
+// algorithm.
+const FinnishName = NamePrefix + "fi"
+
+const finnishVowels = "aeiouyäö"
+
+var finnishCaseEndings = []string{
+	"a", "lla", "na", "ssa", "ta", "lta", "sta", "tta", "lle", "ine",
+	"ksi", "n", "han", "den", "seen", "hen", "tten", "hin", "siin", "hon",
+	"hän", "hön", "ä", "llä", "nä", "ssä", "tä", "ltä", "stä", "ttä",
+}
+
+func init() {
+	Register("fi", stemFinnish)
+}
+
+func stemFinnish(w *Word) {
+	w.MarkRegions(finnishVowels)
+
+	finnishParticle(w)
+	finnishPossessive(w)
+	removed := finnishCaseEnding(w)
+	finnishOtherEnding(w)
+	if removed {
+		finnishIPlural(w)
+	} else {
+		finnishTPlural(w)
+	}
+	finnishTidy(w)
+}
+
+func finnishParticle(w *Word) {
+	switch s := w.SuffixIn(w.R1, "pa", "sti", "kaan", "han", "kin", "hän", "kään", "ko", "pä", "kö"); s {
+	case "":
+	case "sti":
+		if w.In(w.R2, s) {
+			w.Delete(s)
+		}
+	default:
+		// preceded by a particle end
+		if w.IsVowel(w.Before(s)-1, "aeinotuyäö") {
+			w.Delete(s)
+		}
+	}
+}
+
+func finnishPossessive(w *Word) {
+	switch s := w.SuffixIn(w.R1, "nsa", "mme", "nne", "ni", "si", "an", "en", "än", "nsä"); s {
+	case "":
+	case "si":
+		if !w.HasSuffixBefore("k", s) {
+			w.Delete(s)
+		}
+	case "ni":
+		w.Delete(s)
+		if w.HasSuffix("kse") {
+			w.Replace("kse", "ksi")
+		}
+	case "an":
+		if w.HasSuffixBefore("ta", s) || w.HasSuffixBefore("na", s) ||
+			w.HasSuffixBefore("ssa", s) || w.HasSuffixBefore("lla", s) ||
+			w.HasSuffixBefore("lta", s) || w.HasSuffixBefore("sta", s) {
+			w.Delete(s)
+		}
+	case "än":
+		if w.HasSuffixBefore("tä", s) || w.HasSuffixBefore("nä", s) ||
+			w.HasSuffixBefore("ssä", s) || w.HasSuffixBefore("llä", s) ||
+			w.HasSuffixBefore("ltä", s) || w.HasSuffixBefore("stä", s) {
+			w.Delete(s)
+		}
+	case "en":
+		if w.HasSuffixBefore("lle", s) || w.HasSuffixBefore("ine", s) {
+			w.Delete(s)
+		}
+	default:
+		w.Delete(s)
+	}
+}
+
+// finnishLong returns true if the word ends with a long vowel before
+// position end.
+func finnishLong(w *Word, end int) bool {
+	return end >= 2 && w.RS[end-1] == w.RS[end-2] &&
+		w.isVowel(end-1, "aeiouäö")
+}
+
+// finnishCaseEnding removes a case ending and returns true if it did.
+func finnishCaseEnding(w *Word) bool {
+	s := w.SuffixIn(w.R1, finnishCaseEndings...)
+	before := w.Before(s)
+	// the endings after i and a vowel or after a long vowel are n
+	// endings otherwise
+	switch s {
+	case "den", "tten", "siin":
+		if w.At(before-1) != 'i' || !w.IsVowel(before-2, "aeiouäö") {
+			s = "n"
+		}
+	case "seen":
+		if !finnishLong(w, before) {
+			s = "n"
+		}
+	}
+	before = w.Before(s)
+
+	switch s {
+	case "":
+		return false
+	case "han", "hen", "hin", "hon", "hän", "hön":
+		// preceded by the vowel of the ending
+		if w.At(before-1) != []rune(s)[1] {
+			return false
+		}
+	case "n":
+		// the long vowel or ie before n goes with it
+		if finnishLong(w, before) || w.HasSuffixBefore("ie", s) {
+			s = string(w.RS[before-1:])
+		}
+	case "a", "ä":
+		if before < 2 || !w.isVowel(before-1, finnishVowels) ||
+			w.isVowel(before-2, finnishVowels) {
+			return false
+		}
+	case "tta", "ttä":
+		if w.At(before-1) != 'e' {
+			return false
+		}
+	}
+	w.Delete(s)
+	return true
+}
+
+func finnishOtherEnding(w *Word) {
+	switch s := w.SuffixIn(w.R2, "eja", "mma", "imma", "mpa", "impa", "mmi", "immi", "mpi", "impi", "ejä", "mmä", "immä", "mpä", "impä"); s {
+	case "":
+	case "mma", "mpa", "mmi", "mpi", "mmä", "mpä":
+		if !w.HasSuffixBefore("po", s) {
+			w.Delete(s)
+		}
+	default:
+		w.Delete(s)
+	}
+}
+
+func finnishIPlural(w *Word) {
+	if s := w.SuffixIn(w.R1, "i", "j"); s != "" {
+		w.Delete(s)
+	}
+}
+
+func finnishTPlural(w *Word) {
+	if !w.HasSuffix("t") || !w.In(w.R1, "t") || len(w.RS)-2 < w.R1 ||
+		!w.IsVowel(-2, finnishVowels) {
+		return
+	}
+	w.Delete("t")
+	switch s := w.SuffixIn(w.R2, "mma", "imma"); s {
+	case "mma":
+		if !w.HasSuffixBefore("po", s) {
+			w.Delete(s)
+		}
+	case "imma":
+		w.Delete(s)
+	}
+}
+
+func finnishTidy(w *Word) {
+	if len(w.RS) < w.R1 {
+		return
+	}
+	if len(w.RS)-2 >= w.R1 && finnishLong(w, len(w.RS)) {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+	if len(w.RS)-2 >= w.R1 && w.IsVowel(-1, "aeiä") &&
+		!w.isVowel(len(w.RS)-2, finnishVowels) {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+	if s := w.SuffixIn(w.R1, "oj", "uj"); s != "" {
+		w.Delete("j")
+	}
+	if w.SuffixIn(w.R1, "jo") != "" {
+		w.Delete("o")
+	}
+
+	// undouble the last consonant
+	i := len(w.RS) - 1
+	for i >= 0 && w.isVowel(i, finnishVowels) {
+		i--
+	}
+	if i > 0 && w.RS[i-1] == w.RS[i] {
+		w.RS = append(w.RS[:i], w.RS[i+1:]...)
+	}
+}
@@ -0,0 +1,335 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// FrenchName is the name of the token filter for the French algorithm.
+const FrenchName = NamePrefix + "fr"
+
+const frenchVowels = "aeiouyâàëéêèïîôûù"
+
+var frenchStandardSuffixes = []string{
+	"ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes",
+	"ismes", "ables", "istes", "atrice", "ateur", "ation", "atrices",
+	"ateurs", "ations", "logie", "logies", "usion", "ution", "usions",
+	"utions", "ence", "ences", "ement", "ements", "ité", "ités", "if",
+	"ive", "ifs", "ives", "eaux", "aux", "euse", "euses", "issement",
+	"issements", "amment", "emment", "ment", "ments",
+}
+
+var frenchIVerbSuffixes = []string{
+	"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai", "iraIent",
+	"irais", "irait", "iras", "irent", "irez", "iriez", "irions", "irons",
+	"iront", "is", "issaIent", "issais", "issait", "issant", "issante",
+	"issantes", "issants", "isse", "issent", "isses", "issez", "issiez",
+	"issions", "issons", "it",
+}
+
+var frenchVerbSuffixes = []string{
+	"ions", "é", "ée", "ées", "és", "èrent", "er", "era", "erai", "eraIent",
+	"erais", "erait", "eras", "erez", "eriez", "erions", "erons", "eront",
+	"ez", "iez", "âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait",
+	"ant", "ante", "antes", "ants", "as", "asse", "assent", "asses",
+	"assiez", "assions",
+}
+
+func init() {
+	Register("fr", stemFrench)
+}
+
+func stemFrench(w *Word) {
+	frenchPrelude(w)
+	frenchMarkRegions(w)
+
+	altered := false
+	if ok, checkVerb := frenchStandardSuffix(w); ok {
+		altered = true
+	} else if checkVerb {
+		altered = frenchIVerbSuffix(w) || frenchVerbSuffix(w)
+	}
+	if altered {
+		if w.HasSuffix("Y") {
+			w.Replace("Y", "i")
+		} else if w.HasSuffix("ç") {
+			w.Replace("ç", "c")
+		}
+	} else {
+		frenchResidualSuffix(w)
+	}
+
+	if w.Suffix("enn", "onn", "ett", "ell", "eill") != "" {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+
+	// remove the accent of a final é or è followed by non-vowels
+	i := len(w.RS) - 1
+	for i >= 0 && !w.isVowel(i, frenchVowels) {
+		i--
+	}
+	if i >= 0 && i < len(w.RS)-1 && (w.RS[i] == 'é' || w.RS[i] == 'è') {
+		w.RS[i] = 'e'
+	}
+
+	w.ReplaceAll('I', 'i')
+	w.ReplaceAll('U', 'u')
+	w.ReplaceAll('Y', 'y')
+}
+
+// frenchPrelude marks u and i between vowels, y next to a vowel, and u
+// after q as consonants by putting them in upper case.
+func frenchPrelude(w *Word) {
+	from := 0
+	for i := 0; i < len(w.RS); i++ {
+		if i < from {
+			continue
+		}
+		r := w.RS[i]
+		switch {
+		case i > 0 && i-1 >= from && w.isVowel(i-1, frenchVowels) &&
+			(r == 'u' || r == 'i') && w.IsVowel(i+1, frenchVowels):
+			w.RS[i] = r - 'a' + 'A'
+			from = i + 2
+		case i > 0 && i-1 >= from && w.isVowel(i-1, frenchVowels) && r == 'y':
+			w.RS[i] = 'Y'
+			from = i + 1
+		case r == 'y' && w.IsVowel(i+1, frenchVowels):
+			w.RS[i] = 'Y'
+			from = i + 2
+		case r == 'u' && i > 0 && w.RS[i-1] == 'q':
+			w.RS[i] = 'U'
+			from = i + 1
+		}
+	}
+}
+
+func frenchMarkRegions(w *Word) {
+	w.MarkRegions(frenchVowels)
+	w.RV = len(w.RS)
+	switch {
+	case len(w.RS) >= 3 && w.isVowel(0, frenchVowels) && w.isVowel(1, frenchVowels):
+		w.RV = 3
+	case len(w.RS) >= 3 && (string(w.RS[:3]) == "par" ||
+		string(w.RS[:3]) == "col" || string(w.RS[:3]) == "tap"):
+		w.RV = 3
+	default:
+		for i := 1; i < len(w.RS); i++ {
+			if w.isVowel(i, frenchVowels) {
+				w.RV = i + 1
+				break
+			}
+		}
+	}
+}
+
+// frenchStandardSuffix performs step 1, returning whether a suffix was
+// removed, and whether the verb suffixes should be checked.
+func frenchStandardSuffix(w *Word) (bool, bool) {
+	s := w.Suffix(frenchStandardSuffixes...)
+	switch s {
+	case "":
+		return false, true
+	case "ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes",
+		"ismes", "ables", "istes":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Delete(s)
+	case "atrice", "ateur", "ation", "atrices", "ateurs", "ations":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Delete(s)
+		if w.HasSuffix("ic") {
+			frenchDeleteOr(w, "ic", w.R2, "iqU")
+		}
+	case "logie", "logies":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Replace(s, "log")
+	case "usion", "ution", "usions", "utions":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Replace(s, "u")
+	case "ence", "ences":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Replace(s, "ent")
+	case "ement", "ements":
+		if !w.In(w.RV, s) {
+			return false, true
+		}
+		w.Delete(s)
+		switch p := w.Suffix("iv", "eus", "abl", "iqU", "ièr", "Ièr"); p {
+		case "iv":
+			if w.In(w.R2, p) {
+				w.Delete(p)
+				if w.HasSuffix("at") && w.In(w.R2, "at") {
+					w.Delete("at")
+				}
+			}
+		case "eus":
+			if w.In(w.R2, p) {
+				w.Delete(p)
+			} else if w.In(w.R1, p) {
+				w.Replace(p, "eux")
+			}
+		case "abl", "iqU":
+			if w.In(w.R2, p) {
+				w.Delete(p)
+			}
+		case "ièr", "Ièr":
+			if w.In(w.RV, p) {
+				w.Replace(p, "i")
+			}
+		}
+	case "ité", "ités":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Delete(s)
+		switch p := w.Suffix("abil", "ic", "iv"); p {
+		case "abil":
+			frenchDeleteOr(w, p, w.R2, "abl")
+		case "ic":
+			frenchDeleteOr(w, p, w.R2, "iqU")
+		case "iv":
+			if w.In(w.R2, p) {
+				w.Delete(p)
+			}
+		}
+	case "if", "ive", "ifs", "ives":
+		if !w.In(w.R2, s) {
+			return false, true
+		}
+		w.Delete(s)
+		if w.HasSuffix("at") && w.In(w.R2, "at") {
+			w.Delete("at")
+			if w.HasSuffix("ic") {
+				frenchDeleteOr(w, "ic", w.R2, "iqU")
+			}
+		}
+	case "eaux":
+		w.Replace(s, "eau")
+	case "aux":
+		if !w.In(w.R1, s) {
+			return false, true
+		}
+		w.Replace(s, "al")
+	case "euse", "euses":
+		if w.In(w.R2, s) {
+			w.Delete(s)
+		} else if w.In(w.R1, s) {
+			w.Replace(s, "eux")
+		} else {
+			return false, true
+		}
+	case "issement", "issements":
+		if !w.In(w.R1, s) || w.IsVowel(w.Before(s)-1, frenchVowels) ||
+			w.Before(s) == 0 {
+			return false, true
+		}
+		w.Delete(s)
+	case "amment":
+		// the verb suffixes are still checked after the replacement
+		if w.In(w.RV, s) {
+			w.Replace(s, "ant")
+		}
+		return false, true
+	case "emment":
+		if w.In(w.RV, s) {
+			w.Replace(s, "ent")
+		}
+		return false, true
+	case "ment", "ments":
+		if p := w.Before(s) - 1; p >= w.RV && w.IsVowel(p, frenchVowels) {
+			w.Delete(s)
+		}
+		return false, true
+	}
+	return true, false
+}
+
+// frenchDeleteOr deletes suffix if it is in the region starting at
+// start, or replaces it with repl otherwise.
+func frenchDeleteOr(w *Word, suffix string, start int, repl string) {
+	if w.In(start, suffix) {
+		w.Delete(suffix)
+	} else {
+		w.Replace(suffix, repl)
+	}
+}
+
+func frenchIVerbSuffix(w *Word) bool {
+	s := w.SuffixIn(w.RV, frenchIVerbSuffixes...)
+	if s == "" {
+		return false
+	}
+	p := w.Before(s) - 1
+	if p < w.RV || w.isVowel(p, frenchVowels) {
+		return false
+	}
+	w.Delete(s)
+	return true
+}
+
+func frenchVerbSuffix(w *Word) bool {
+	s := w.SuffixIn(w.RV, frenchVerbSuffixes...)
+	switch s {
+	case "":
+		return false
+	case "ions":
+		if !w.In(w.R2, s) {
+			return false
+		}
+		w.Delete(s)
+	case "âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant",
+		"ante", "antes", "ants", "as", "asse", "assent", "asses", "assiez",
+		"assions":
+		w.Delete(s)
+		if w.HasSuffix("e") && w.In(w.RV, "e") {
+			w.Delete("e")
+		}
+	default:
+		w.Delete(s)
+	}
+	return true
+}
+
+func frenchResidualSuffix(w *Word) {
+	if w.HasSuffix("s") {
+		switch w.At(-2) {
+		case 0, 'a', 'i', 'o', 'u', 'è', 's':
+		default:
+			w.Delete("s")
+		}
+	}
+	switch s := w.SuffixIn(w.RV, "ion", "ier", "ière", "Ier", "Ière", "e", "ë"); s {
+	case "ion":
+		if p := w.Before(s) - 1; w.In(w.R2, s) && p >= w.RV &&
+			(w.RS[p] == 's' || w.RS[p] == 't') {
+			w.Delete(s)
+		}
+	case "ier", "ière", "Ier", "Ière":
+		w.Replace(s, "i")
+	case "e":
+		w.Delete(s)
+	case "ë":
+		if w.HasSuffixBefore("gu", s) && w.Before(s)-2 >= w.RV {
+			w.Delete(s)
+		}
+	}
+}
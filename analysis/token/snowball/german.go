@@ -0,0 +1,123 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// GermanName is the name of the token filter for the German algorithm.
+const GermanName = NamePrefix + "de"
+
+const germanVowels = "aeiouyäöü"
+
+func init() {
+	Register("de", stemGerman)
+}
+
+func stemGerman(w *Word) {
+	rs := make([]rune, 0, len(w.RS))
+	for _, r := range w.RS {
+		if r == 'ß' {
+			rs = append(rs, 's', 's')
+		} else {
+			rs = append(rs, r)
+		}
+	}
+	w.RS = rs
+	// u and y between vowels are consonants
+	from := 0
+	for i := 1; i < len(w.RS)-1; i++ {
+		if i-1 < from || !w.isVowel(i-1, germanVowels) ||
+			!w.isVowel(i+1, germanVowels) {
+			continue
+		}
+		switch w.RS[i] {
+		case 'u':
+			w.RS[i] = 'U'
+			from = i + 2
+		case 'y':
+			w.RS[i] = 'Y'
+			from = i + 2
+		}
+	}
+	w.MarkRegions(germanVowels)
+	w.MinR1(3)
+
+	switch s := w.Suffix("em", "ern", "er", "e", "en", "es", "s"); {
+	case s == "" || !w.In(w.R1, s):
+	case s == "s":
+		switch w.At(-2) {
+		case 'b', 'd', 'f', 'g', 'h', 'k', 'l', 'm', 'n', 'r', 't':
+			w.Delete(s)
+		}
+	case s == "e" || s == "en" || s == "es":
+		w.Delete(s)
+		if w.HasSuffix("niss") {
+			w.Delete("s")
+		}
+	default:
+		w.Delete(s)
+	}
+
+	switch s := w.Suffix("en", "er", "est", "st"); {
+	case s == "" || !w.In(w.R1, s):
+	case s == "st":
+		// preceded by a valid st-ending, itself preceded by at least 3
+		// letters
+		switch w.At(-3) {
+		case 'b', 'd', 'f', 'g', 'h', 'k', 'l', 'm', 'n', 't':
+			if w.Before(s) > 3 {
+				w.Delete(s)
+			}
+		}
+	default:
+		w.Delete(s)
+	}
+
+	switch s := w.Suffix("end", "ung", "ig", "ik", "isch", "lich", "heit", "keit"); {
+	case s == "" || !w.In(w.R2, s):
+	case s == "end" || s == "ung":
+		w.Delete(s)
+		if w.HasSuffix("ig") && w.In(w.R2, "ig") && !w.HasSuffix("eig") {
+			w.Delete("ig")
+		}
+	case s == "ig" || s == "ik" || s == "isch":
+		if w.At(w.Before(s)-1) != 'e' {
+			w.Delete(s)
+		}
+	case s == "lich" || s == "heit":
+		w.Delete(s)
+		if p := w.Suffix("er", "en"); p != "" && w.In(w.R1, p) {
+			w.Delete(p)
+		}
+	case s == "keit":
+		w.Delete(s)
+		if p := w.Suffix("lich", "ig"); p != "" && w.In(w.R2, p) {
+			w.Delete(p)
+		}
+	}
+
+	for i, r := range w.RS {
+		switch r {
+		case 'U':
+			w.RS[i] = 'u'
+		case 'Y':
+			w.RS[i] = 'y'
+		case 'ä':
+			w.RS[i] = 'a'
+		case 'ö':
+			w.RS[i] = 'o'
+		case 'ü':
+			w.RS[i] = 'u'
+		}
+	}
+}
@@ -0,0 +1,844 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// GreekName is the name of the token filter for the Greek algorithm.
+const GreekName = NamePrefix + "el"
+
+const greekVowels = "αεηιουω"
+
+// the vowels, without υ
+const greekVowels2 = "αεηιοω"
+
+// the lower case letters, without accents, of the Greek letters. As in
+// Snowball, ϊ and ΐ become η.
+var greekLower = map[rune]rune{
+	'Ά': 'α', 'Α': 'α', 'ά': 'α',
+	'Β': 'β',
+	'Γ': 'γ',
+	'Δ': 'δ',
+	'Έ': 'ε', 'Ε': 'ε', 'έ': 'ε',
+	'Ζ': 'ζ',
+	'Ή': 'η', 'Η': 'η', 'ή': 'η', 'ϊ': 'η', 'ΐ': 'η',
+	'Θ': 'θ',
+	'Ί': 'ι', 'Ι': 'ι', 'Ϊ': 'ι', 'ί': 'ι',
+	'Κ': 'κ',
+	'Λ': 'λ',
+	'Μ': 'μ',
+	'Ν': 'ν',
+	'Ξ': 'ξ',
+	'Ό': 'ο', 'ό': 'ο', 'Ο': 'ο',
+	'Π': 'π',
+	'Ρ': 'ρ',
+	'ς': 'σ', 'Σ': 'σ',
+	'Τ': 'τ',
+	'ϋ': 'υ', 'ύ': 'υ', 'Ύ': 'υ', 'Υ': 'υ', 'Ϋ': 'υ', 'ΰ': 'υ',
+	'Φ': 'φ',
+	'Χ': 'χ',
+	'Ψ': 'ψ',
+	'ώ': 'ω', 'Ώ': 'ω', 'Ω': 'ω',
+}
+
+// the irregular suffixes, by their replacement
+var greekIrregular = map[string]string{
+	"φαγιου":     "φα",
+	"φαγια":      "φα",
+	"φαγιων":     "φα",
+	"σκαγιου":    "σκα",
+	"σκαγια":     "σκα",
+	"σκαγιων":    "σκα",
+	"ολογιου":    "ολο",
+	"ολογια":     "ολο",
+	"ολογιων":    "ολο",
+	"σογιου":     "σο",
+	"σογια":      "σο",
+	"σογιων":     "σο",
+	"τατογιου":   "τατο",
+	"τατογια":    "τατο",
+	"τατογιων":   "τατο",
+	"κρεασ":      "κρε",
+	"κρεατοσ":    "κρε",
+	"κρεατα":     "κρε",
+	"κρεατων":    "κρε",
+	"περασ":      "περ",
+	"περατοσ":    "περ",
+	"περατα":     "περ",
+	"περατη":     "περ",
+	"περατων":    "περ",
+	"τερασ":      "τερ",
+	"τερατοσ":    "τερ",
+	"τερατα":     "τερ",
+	"τερατων":    "τερ",
+	"φωσ":        "φω",
+	"φωτοσ":      "φω",
+	"φωτα":       "φω",
+	"φωτων":      "φω",
+	"καθεστωσ":   "καθεστ",
+	"καθεστωτοσ": "καθεστ",
+	"καθεστωτα":  "καθεστ",
+	"καθεστωτων": "καθεστ",
+	"γεγονοτοσ":  "γεγον",
+	"γεγονοσ":    "γεγον",
+	"γεγονοτα":   "γεγον",
+	"γεγονοτων":  "γεγον",
+}
+
+// the adjectives replaced by their stem after a suffix of step s6
+var greekAdjectives = map[string]string{
+	"αγνωστικ":   "αγνωστ",
+	"ατομικ":     "ατομ",
+	"γνωστικ":    "γνωστ",
+	"εθνικ":      "εθν",
+	"εκλεκτικ":   "εκλεκτ",
+	"σκεπτικ":    "σκεπτ",
+	"τοπικ":      "τοπ",
+	"αλεξανδριν": "αλεξανδρ",
+	"βυζαντιν":   "βυζαντ",
+	"θεατριν":    "θεατρ",
+}
+
+// the suffixes of step s1, restored to ι or ιζ after some words
+var greekSuffixesS1 = []string{
+	"ιζεσ", "ιζεισ", "ιζω", "ιζα", "ιζατε", "ιζετε", "ιζε", "ιζουμε",
+	"ιζαμε", "ιζουνε", "ιζανε", "ιζει", "ιζουν", "ιζαν",
+}
+
+// the words taking back ι after a suffix of step s1
+var greekWordsS1I = []string{
+	"πα", "ξαναπα", "επα", "περιπα", "αναμπα", "εμπα", "δανε", "αθρο",
+	"συναθρο",
+}
+
+// the words taking back ιζ after a suffix of step s1
+var greekWordsS1Iz = []string{
+	"π", "ιμπ", "ρ", "πρ", "μπρ", "αρρ", "γλυκυρ", "πολυρ", "αμπαρ",
+	"μαρ", "γκρ", "πιπερορ", "βολβορ", "γλυκορ", "λου", "β", "βαθυρι",
+	"βαρκ", "μαρκ", "λ", "μ", "κορν",
+}
+
+// the suffixes of step s2, restored to ων after some words
+var greekSuffixesS2 = []string{
+	"ωθηκεσ", "ωθηκα", "ωθηκατε", "ωθηκε", "ωθηκαμε", "ωθηκανε",
+	"ωθηκαν",
+}
+
+// the words taking back ων after a suffix of step s2
+var greekWordsS2 = []string{
+	"σ", "χ", "υψ", "ζω", "βι", "λι", "αλ", "εν",
+}
+
+// the suffixes of step s3, restored to ι or ισ after some words
+var greekSuffixesS3 = []string{
+	"ισεσ", "ισα", "ισε", "ισατε", "ισαμε", "ισανε", "ισαν",
+}
+
+// the words taking back ι after a suffix of step s3
+var greekWordsS3I = []string{
+	"ξαναπα", "επα", "περιπα", "αναμπα", "εμπα", "χαρτοπα", "εξαρχα",
+	"πε", "επε", "μετεπε", "εσε", "κλε", "εσωκλε", "εκλε", "απεκλε",
+	"αποκλε", "δανε", "αθρο", "συναθρο",
+}
+
+// the words taking back ισ after a suffix of step s3
+var greekWordsS3Is = []string{
+	"π", "λαρ", "δημοκρατ", "αφ", "γιγαντοαφ", "γε", "γκε", "γκ", "μ",
+	"πουκαμ", "κομ", "αν", "ολο",
+}
+
+// the suffixes of step s4, restored to ι after some words
+var greekSuffixesS4 = []string{
+	"ισεισ", "ισω", "ισετε", "ισουμε", "ισουνε", "ισει", "ισουν",
+}
+
+// the words taking back ι after a suffix of step s4
+var greekWordsS4 = []string{
+	"ξαναπα", "επα", "περιπα", "αναμπα", "εμπα", "χαρτοπα", "εξαρχα",
+	"πε", "επε", "μετεπε", "εσε", "κλε", "εσωκλε", "εκλε", "απεκλε",
+	"αποκλε", "δανε", "αθρο", "συναθρο",
+}
+
+// the suffixes of step s5, restored to ι or ιστ after some words
+var greekSuffixesS5 = []string{
+	"ιστουσ", "ιστεσ", "ιστησ", "ιστοσ", "ιστου", "ιστα", "ιστε",
+	"ιστη", "ιστοι", "ιστων", "ιστο",
+}
+
+// the words taking back ι after a suffix of step s5
+var greekWordsS5I = []string{
+	"σε", "ασε", "πλε", "κλε", "εσωκλε", "δανε", "συναθρο",
+}
+
+// the words taking back ιστ after a suffix of step s5
+var greekWordsS5Ist = []string{
+	"π", "ευπ", "απ", "εμπ", "γυρ", "χρ", "χωρ", "αρ", "αορ", "χτ",
+	"αχτ", "κτ", "ακτ", "σχ", "ασχ", "ταχ", "υψ", "ατα", "φα", "ηφα",
+	"λυγ", "μεγ", "ηδ", "εχθ", "καθ", "σκ", "κακ", "μακ", "κυλ", "φιλ",
+	"μ", "γεμ", "αχν",
+}
+
+// the suffixes of step s6, restored to ισμ or ι after some words
+var greekSuffixesS6 = []string{
+	"ισμουσ", "ισμοσ", "ισμου", "ισμοι", "ισμων", "ισμο",
+}
+
+// the words taking back ισμ after a suffix of step s6
+var greekWordsS6Ism = []string{
+	"σε", "μετασε", "μικροσε", "εγκλε", "αποκλε",
+}
+
+// the words taking back ι after a suffix of step s6
+var greekWordsS6I = []string{
+	"δανε", "αντιδανε",
+}
+
+// the suffixes of step s7, restored to αρακ after some words
+var greekSuffixesS7 = []string{
+	"αρακια", "ουδακια", "αρακι", "ουδακι",
+}
+
+// the words taking back αρακ after a suffix of step s7
+var greekWordsS7 = []string{
+	"σ", "χ",
+}
+
+// the suffixes of step s8, restored to ακ or ιτσ after some words
+var greekSuffixesS8 = []string{
+	"ιτσασ", "ιτσεσ", "ιτσα", "ακια", "αρακια", "ακι", "αρακι", "ιτσων",
+}
+
+// the words taking back ακ after a suffix of step s8
+var greekWordsS8Ak = []string{
+	"κατραπ", "ρ", "βρ", "λαβρ", "αμβρ", "μερ", "ανθρ", "κορ", "σ",
+	"ναγκασ", "μουστ", "ρυ", "φ", "σφ", "αλισφ", "χ", "βαμβ", "σλοβ",
+	"τσεχοσλοβ", "τζ", "κ", "σκ", "καπακ", "σοκ", "πλ", "φυλ", "λουλ",
+	"μαλ", "φαρμ", "καιμ", "κλιμ", "σπαν", "κον",
+}
+
+// the words taking back ιτσ after a suffix of step s8
+var greekWordsS8Its = []string{
+	"π", "πατερ", "τοσ", "νυφ", "β", "καρδ", "ζ", "σκ", "βαλ", "γλ",
+	"τριπολ", "μακρυν", "γιαν", "ηγουμεν", "κον",
+}
+
+// the suffixes of step s9, restored to ιδ after some words
+var greekSuffixesS9 = []string{
+	"ιδια", "ιδιων", "ιδιο",
+}
+
+// the words taking back ιδ after a suffix of step s9
+var greekWordsS9 = []string{
+	"ιρ", "ψαλ", "αιφν", "ολο",
+}
+
+// the endings taking back ιδ after a suffix of step s9
+var greekEndingsS9 = []string{
+	"ε", "παιχν",
+}
+
+// the suffixes of step s10, restored to ισκ after some words
+var greekSuffixesS10 = []string{
+	"ισκοσ", "ισκου", "ισκε", "ισκο",
+}
+
+// the words taking back ισκ after a suffix of step s10
+var greekWordsS10 = []string{
+	"ρ", "ιβ", "δ", "λυκ", "φραγκ", "οβελ", "μην",
+}
+
+// the endings keeping αδεσ and αδων
+var greekEndings2a = []string{
+	"μπαμπ", "κυρ", "πατερ", "πεθερ", "νταντ", "γιαγι", "θει", "οκ",
+	"μαμ", "μαν",
+}
+
+// the endings taking back εδ after εδεσ and εδων
+var greekEndings2b = []string{
+	"κρασπ", "υπ", "δαπ", "γηπ", "ιπ", "εμπ", "οπ", "μιλ",
+}
+
+// the endings taking back ουδ after ουδεσ and ουδων
+var greekEndings2c = []string{
+	"σπ", "φρ", "σ", "λιχ", "τραγ", "φε", "αρκ", "σκ", "καλιακ", "λουλ",
+	"φλ", "πεταλ", "βελ", "χν", "πλεξ",
+}
+
+// the words taking back ε after εωσ and εων
+var greekWords2d = []string{
+	"π", "παρ", "δ", "ιδ", "θ", "γαλ", "ελ", "ν",
+}
+
+// the words taking back ικ after ικου, ικα, ικων and ικο
+var greekWords4 = []string{
+	"καλπ", "γερ", "πλιατσ", "πετσ", "πιτσ", "φυσ", "χασ", "μποσ",
+	"σερτ", "μπαγιατ", "νιτ", "πικαντ", "εξωδ", "αδ", "καταδ", "συναδ",
+	"αντιδ", "ενδ", "υποδ", "πρωτοδ", "φυλοδ", "ηθ", "ανηθ", "ξικ",
+	"μουλ", "αλ", "αμμοχαλ", "συνομηλ", "μπολ", "βρωμ", "τσαμ", "μπαν",
+	"αμαν", "καλλιν", "ποστελν", "φιλον",
+}
+
+// the suffixes removed before αμε
+var greekSuffixes5a = []string{
+	"ουσαμε", "ησαμε", "αγαμε", "ηκαμε", "ηθηκαμε",
+}
+
+// the words taking back αμ after αμε
+var greekWords5a = []string{
+	"αναπ", "πικρ", "αποστ", "ποτ", "χ", "σιχ", "βουβ", "πεθ", "ξεθ",
+	"αποθ", "αποκ", "ουλ",
+}
+
+// the suffixes removed before ανε, restored to αγαν after τρ and τσ
+var greekSuffixes5b = []string{
+	"ουσανε", "ησανε", "ουντανε", "ιουντανε", "οντανε", "ιοντανε",
+	"οτανε", "ιοτανε", "αγανε", "ηκανε", "ηθηκανε",
+}
+
+// the words taking back αν after ανε
+var greekWords5b = []string{
+	"π", "σπ", "πολυδαπ", "αδαπ", "χαμηλοδαπ", "τσοπ", "κοπ", "υποκοπ",
+	"περιτρ", "ουρ", "ερ", "βετερ", "γερ", "λουθηρ", "κορμορ", "σ",
+	"σαρακατσ", "θυσ", "βασ", "πολισ", "καστ", "διατ", "πλατ",
+	"τσαρλατ", "τετ", "πουριτ", "σουλτ", "ζωντ", "μαιντ", "φ",
+	"πενταρφ", "κοιλαρφ", "ορφ", "διαφ", "στεφ", "φωτοστεφ", "περηφ",
+	"υπερηφ", "χ", "πολυμηχ", "αμηχ", "βιομηχ", "μικροβιομηχ",
+	"μεγλοβιομηχ", "καπνοβιομηχ", "λιχ", "ταβ", "νταβ", "ψηλοταβ",
+	"λιβ", "κλιβ", "ξηροκλιβ", "γ", "ανοργ", "ενοργ", "αγ", "τραγ",
+	"τσαγ", "τσιγγ", "ατσιγγ", "αθιγγ", "στεγ", "απηγ", "σιγ",
+	"καλπουζ", "θ", "μωαμεθ", "πιθ", "απιθ", "βασκ", "βραχυκ", "δεκ",
+	"πελεκ", "ικ", "ανικ", "βουλκ", "πλ", "διπλ", "ψυχοπλ", "λαοπλ",
+	"ουλ", "γαλ", "βαθυγαλ", "καταγαλ", "ολογαλ", "καστελ", "μελ",
+	"πορτολ", "μ", "δραδουμ", "βραχμ", "ολιγοδαμ", "μουσουλμ", "ν",
+	"αμερικαν",
+}
+
+// the endings taking back ετ after ετε
+var greekEndings5c = []string{
+	"πυρ", "ευρ", "χωρ", "βαρ", "βρ", "αιρ", "φορ", "νετ", "σχ", "συνδ",
+	"ενδ", "οδ", "υπερθ", "σθ", "ευθ", "ραθ", "ταθ", "διαθ", "καθ",
+	"τιθ", "εκθ", "συνθ", "ενθ", "ροθ", "αρκ", "ωφελ", "βολ", "συν",
+	"αιν", "πον", "ρον",
+}
+
+// the words taking back ετ after ετε
+var greekWords5c = []string{
+	"σερπ", "κοπ", "θαρρ", "ντρ", "αβαρ", "εναρ", "αβρ", "μπορ", "υ",
+	"συρφ", "νιφ", "παγ", "δ", "αδ", "θ", "αθ", "σκ", "τοκ", "απλ",
+	"παρακαλ", "σκελ", "εμ", "αν", "βεν", "βαρον",
+}
+
+// the words taking back ιεστ after ιεστε
+var greekWords5fIeste = []string{
+	"π", "απ", "ακαταπ", "συμπ", "ασυμπ", "αμεταμφ",
+}
+
+// the words taking back ιεστ after εστε
+var greekWords5fEste = []string{
+	"αρ", "νισ", "ζ", "αλ", "παρακαλ", "εκτελ", "μ", "ξ", "προ",
+}
+
+// the endings taking back ηκ after ηκεσ, ηκα and ηκε
+var greekEndings5g = []string{
+	"σφ", "ναρθ", "πιθ", "οθ", "σκουλ", "σκωλ",
+}
+
+// the words taking back ηκ after ηκεσ, ηκα and ηκε
+var greekWords5g = []string{
+	"θ", "προσθ", "παρακαταθ", "διαθ", "συνθ",
+}
+
+// the endings taking back ουσ after ουσεσ, ουσα and ουσε
+var greekEndings5h = []string{
+	"βλεπ", "ποδαρ", "πρωτ", "κυματ", "πανταχ", "λαχ", "φαγ", "ληγ",
+	"φρυδ", "μαντιλ", "μαλλ", "ομ",
+}
+
+// the words taking back ουσ after ουσεσ, ουσα and ουσε
+var greekWords5h = []string{
+	"εκλιπ", "ρ", "αναρρ", "ενδιαφερ", "πατ", "καθαρευ", "δευτερευ",
+	"λεχ", "τσα", "χαδ", "μεδ", "λαμπιδ", "δε", "πλε", "μεσαζ",
+	"δεσποζ", "αιθ", "φαρμακ", "αγκ", "ανηκ", "λ", "μ", "αμ", "βρομ",
+	"υποτειν",
+}
+
+// the endings taking back αγ after αγεσ, αγα and αγε
+var greekEndings5i = []string{
+	"ρπ", "πρ", "φρ", "χορτ", "σφ", "οφ", "λοχ", "πελ", "λλ", "σμην",
+}
+
+// the words taking back αγ after αγεσ, αγα and αγε
+var greekWords5i = []string{
+	"π", "ασπ", "ανυπ", "αρτιπ", "αειπ", "συμπ", "προσωποπ", "σιδηροπ",
+	"δροσοπ", "νεοπ", "κροκαλοπ", "ολοπ", "ρ", "τρ", "ουρ", "ασπαρ",
+	"χαρ", "αχαρ", "απερ", "τ", "ανυστ", "αβαστ", "προστ", "αιμοστ",
+	"διατ", "επιτ", "συντ", "υποτ", "αποτ", "ομοτ", "νομοτ", "ναυ",
+	"πολυφ", "αφ", "ξεφ", "αδηφ", "παμφ", "αμαλλι", "λ", "αμαλ", "μ",
+	"ουλαμ", "εν", "δερβεν",
+}
+
+// the words taking back ησ after ησου, ησα and ησε
+var greekWords5j = []string{
+	"ν", "επταν", "δωδεκαν", "χερσον", "μεγαλον", "ερημον",
+}
+
+// the words taking back ηστ after ηστε
+var greekWords5k = []string{
+	"χρ", "δυσχρ", "ευχρ", "αχρ", "κοινοχρ", "παλιμψ", "σβ", "ασβ",
+	"απλ", "αειμν",
+}
+
+// the words taking back ουν after ουνε, ησουνε and ηθουνε
+var greekWords5l = []string{
+	"ρ", "στραβομουτσ", "κακομουτσ", "σπι", "ν", "εξων",
+}
+
+// the words taking back ουμ after ουμε, ησουμε and ηθουμε
+var greekWords5m = []string{
+	"ασουσ", "παρασουσ", "αλλοσουσ", "φ", "χ", "αζ", "ωριοπλ",
+}
+
+// the general endings, only removed if no other step removed a suffix
+var greekEndings = []string{
+	"υσ", "ουσ", "ασ", "εσ", "ησεσ", "ηδεσ", "ησ", "εισ", "ηθεισ", "οσ",
+	"υ", "ου", "ω", "ησω", "αω", "ηθω", "α", "ιουμα", "οσουνα",
+	"ιοσουνα", "ομουνα", "ιομουνα", "ε", "ιεσαστε", "οσαστε", "ιοσαστε",
+	"ουμαστε", "ιουμαστε", "ιεμαστε", "ουσατε", "ησατε", "αγατε",
+	"ηκατε", "ηθηκατε", "ειτε", "ηθειτε", "η", "ι", "ασαι", "εσαι",
+	"ιεσαι", "αται", "εται", "ιεται", "ουνται", "ιουνται", "ονται",
+	"ουμαι", "αμαι", "ιεμαι", "ομαι", "ει", "ησει", "αει", "ηθει", "οι",
+	"ουν", "ησουν", "οσουν", "ιοσουν", "ηθουν", "ομουν", "ιομουν", "ων",
+	"ηδων", "αν", "ουσαν", "οντουσαν", "ιοντουσαν", "ησαν", "οσασταν",
+	"ιοσασταν", "ομασταν", "ιομασταν", "ουνταν", "ιουνταν", "ονταν",
+	"ιονταν", "οταν", "ιοταν", "αγαν", "ηκαν", "ηθηκαν", "ο",
+}
+
+// the comparative and superlative suffixes
+var greekComparatives = []string{
+	"εστερ", "υτερ", "ωτερ", "οτερ", "εστατ", "υτατ", "ωτατ", "οτατ",
+}
+
+func init() {
+	Register("el", stemGreek)
+}
+
+func stemGreek(w *Word) {
+	for i, r := range w.RS {
+		if l, ok := greekLower[r]; ok {
+			w.RS[i] = l
+		}
+	}
+	if len(w.RS) < 3 {
+		return
+	}
+
+	// the general endings are only removed if no step removed a suffix
+	removed := false
+	if s := w.Suffix(keys(greekIrregular)...); s != "" {
+		w.Replace(s, greekIrregular[s])
+		removed = true
+	}
+	for _, step := range greekSteps {
+		if step(w) {
+			removed = true
+		}
+	}
+	if s := w.Suffix("ματοσ", "ματα", "ματων"); s != "" {
+		w.Replace(s, "μα")
+	}
+	if s := w.Suffix(greekEndings...); s != "" && !removed {
+		w.Delete(s)
+	}
+	if s := w.Suffix(greekComparatives...); s != "" {
+		w.Delete(s)
+	}
+}
+
+// greekIs returns true if the word is one of words.
+func greekIs(w *Word, words ...string) bool {
+	s := w.Suffix(words...)
+	return s != "" && runeLen(s) == len(w.RS)
+}
+
+// greekEndsWith returns true if the word ends with one of suffixes.
+func greekEndsWith(w *Word, suffixes ...string) bool {
+	return w.Suffix(suffixes...) != ""
+}
+
+// greekRemove removes the longest of the suffixes the word ends with,
+// and returns true if there was one.
+func greekRemove(w *Word, suffixes ...string) bool {
+	s := w.Suffix(suffixes...)
+	if s == "" {
+		return false
+	}
+	w.Delete(s)
+	return true
+}
+
+// greekAppend adds s to the end of the word.
+func greekAppend(w *Word, s string) {
+	w.RS = append(w.RS, []rune(s)...)
+}
+
+// greekAppendAfter adds s to the end of the word if its last letter is
+// one of vowels. The letter is then removed if the word before it is
+// one of words; if the last letter is not a vowel, s is added if the
+// word is one of words.
+func greekAppendAfter(w *Word, vowels, s string, words []string) {
+	if w.IsVowel(-1, vowels) {
+		v := w.At(-1)
+		greekAppend(w, s)
+		before := &Word{RS: w.RS[:len(w.RS)-runeLen(s)-1]}
+		if greekIs(before, words...) {
+			w.Replace(string(v)+s, s)
+		}
+	} else if greekIs(w, words...) {
+		greekAppend(w, s)
+	}
+}
+
+// the steps removing a suffix and restoring part of the stem for some
+// words. They return true if the general endings must then be kept,
+// which all but the steps for αδ, εδ and ουδ do when they remove a
+// suffix.
+var greekSteps = []func(w *Word) bool{
+	greekStepS1, greekStepS2, greekStepS3, greekStepS4,
+	greekStepS5, greekStepS6, greekStepS7, greekStepS8,
+	greekStepS9, greekStepS10, greekStep2a, greekStep2b,
+	greekStep2c, greekStep2d, greekStep3, greekStep4,
+	greekStep5a, greekStep5b, greekStep5c, greekStep5d,
+	greekStep5e, greekStep5f, greekStep5g, greekStep5h,
+	greekStep5j, greekStep5i, greekStep5k, greekStep5l,
+	greekStep5m,
+}
+
+func greekStepS1(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS1...) {
+		return false
+	}
+	if greekIs(w, greekWordsS1I...) {
+		greekAppend(w, "ι")
+	} else if greekIs(w, greekWordsS1Iz...) {
+		greekAppend(w, "ιζ")
+	}
+	return true
+}
+
+func greekStepS2(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS2...) {
+		return false
+	}
+	if greekIs(w, greekWordsS2...) {
+		greekAppend(w, "ων")
+	}
+	return true
+}
+
+func greekStepS3(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS3...) {
+		return false
+	}
+	if greekIs(w, "ισα") {
+		greekAppend(w, "ισ")
+	} else if greekIs(w, greekWordsS3I...) {
+		greekAppend(w, "ι")
+	} else if greekIs(w, greekWordsS3Is...) {
+		greekAppend(w, "ισ")
+	}
+	return true
+}
+
+func greekStepS4(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS4...) {
+		return false
+	}
+	if greekIs(w, greekWordsS4...) {
+		greekAppend(w, "ι")
+	}
+	return true
+}
+
+func greekStepS5(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS5...) {
+		return false
+	}
+	if greekIs(w, greekWordsS5I...) {
+		greekAppend(w, "ι")
+	} else if greekIs(w, greekWordsS5Ist...) {
+		greekAppend(w, "ιστ")
+	}
+	return true
+}
+
+func greekStepS6(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS6...) {
+		return false
+	}
+	if greekIs(w, greekWordsS6Ism...) {
+		greekAppend(w, "ισμ")
+	} else if greekIs(w, greekWordsS6I...) {
+		greekAppend(w, "ι")
+	} else if s := w.Suffix(keys(greekAdjectives)...); s != "" {
+		w.Replace(s, greekAdjectives[s])
+	}
+	return true
+}
+
+func greekStepS7(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS7...) {
+		return false
+	}
+	if greekIs(w, greekWordsS7...) {
+		greekAppend(w, "αρακ")
+	}
+	return true
+}
+
+func greekStepS8(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS8...) {
+		return false
+	}
+	if greekIs(w, greekWordsS8Ak...) {
+		greekAppend(w, "ακ")
+	} else if greekIs(w, greekWordsS8Its...) || w.HasSuffix("κορ") {
+		greekAppend(w, "ιτσ")
+	}
+	return true
+}
+
+func greekStepS9(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS9...) {
+		return false
+	}
+	if greekIs(w, greekWordsS9...) || greekEndsWith(w, greekEndingsS9...) {
+		greekAppend(w, "ιδ")
+	}
+	return true
+}
+
+func greekStepS10(w *Word) bool {
+	if !greekRemove(w, greekSuffixesS10...) {
+		return false
+	}
+	if greekIs(w, greekWordsS10...) {
+		greekAppend(w, "ισκ")
+	}
+	return true
+}
+
+func greekStep2a(w *Word) bool {
+	if greekRemove(w, "αδεσ", "αδων") && !greekEndsWith(w, greekEndings2a...) {
+		greekAppend(w, "αδ")
+	}
+	return false
+}
+
+func greekStep2b(w *Word) bool {
+	if greekRemove(w, "εδεσ", "εδων") && greekEndsWith(w, greekEndings2b...) {
+		greekAppend(w, "εδ")
+	}
+	return false
+}
+
+func greekStep2c(w *Word) bool {
+	if greekRemove(w, "ουδεσ", "ουδων") && greekEndsWith(w, greekEndings2c...) {
+		greekAppend(w, "ουδ")
+	}
+	return false
+}
+
+func greekStep2d(w *Word) bool {
+	if !greekRemove(w, "εωσ", "εων") {
+		return false
+	}
+	if greekIs(w, greekWords2d...) {
+		greekAppend(w, "ε")
+	}
+	return true
+}
+
+func greekStep3(w *Word) bool {
+	if !greekRemove(w, "ιου", "ια", "ιων") {
+		return false
+	}
+	if w.IsVowel(-1, greekVowels) {
+		greekAppend(w, "ι")
+	}
+	return true
+}
+
+func greekStep4(w *Word) bool {
+	if !greekRemove(w, "ικου", "ικα", "ικων", "ικο") {
+		return false
+	}
+	greekAppendAfter(w, greekVowels, "ικ", greekWords4)
+	return true
+}
+
+func greekStep5a(w *Word) bool {
+	if greekIs(w, "αγαμε") {
+		w.Replace("αγαμε", "αγαμ")
+	}
+	removed := greekRemove(w, greekSuffixes5a...)
+	if !greekRemove(w, "αμε") {
+		return removed
+	}
+	if greekIs(w, greekWords5a...) {
+		greekAppend(w, "αμ")
+	}
+	return true
+}
+
+func greekStep5b(w *Word) bool {
+	removed := greekRemove(w, greekSuffixes5b...)
+	if removed && greekIs(w, "τρ", "τσ") {
+		greekAppend(w, "αγαν")
+	}
+	if !greekRemove(w, "ανε") {
+		return removed
+	}
+	greekAppendAfter(w, greekVowels2, "αν", greekWords5b)
+	return true
+}
+
+func greekStep5c(w *Word) bool {
+	removed := greekRemove(w, "ησετε")
+	if !greekRemove(w, "ετε") {
+		return removed
+	}
+	// after one of the endings, it is removed if the word before it is
+	// one of the words, as after a vowel
+	s := w.Suffix(greekEndings5c...)
+	if s == "" || w.IsVowel(-1, greekVowels2) {
+		greekAppendAfter(w, greekVowels2, "ετ", greekWords5c)
+		return true
+	}
+	greekAppend(w, "ετ")
+	before := &Word{RS: w.RS[:len(w.RS)-runeLen(s)-2]}
+	if greekIs(before, greekWords5c...) {
+		w.Replace(s+"ετ", "ετ")
+	}
+	return true
+}
+
+func greekStep5d(w *Word) bool {
+	if !greekRemove(w, "ωντασ", "οντασ") {
+		return false
+	}
+	if greekIs(w, "αρχ") {
+		greekAppend(w, "οντ")
+	} else if w.HasSuffix("κρε") {
+		greekAppend(w, "ωντ")
+	}
+	return true
+}
+
+func greekStep5e(w *Word) bool {
+	if !greekRemove(w, "ομαστε", "ιομαστε") {
+		return false
+	}
+	if greekIs(w, "ον") {
+		greekAppend(w, "ομαστ")
+	}
+	return true
+}
+
+func greekStep5f(w *Word) bool {
+	removed := greekRemove(w, "ιεστε")
+	if removed && greekIs(w, greekWords5fIeste...) {
+		greekAppend(w, "ιεστ")
+	}
+	if !greekRemove(w, "εστε") {
+		return removed
+	}
+	if greekIs(w, greekWords5fEste...) {
+		greekAppend(w, "ιεστ")
+	}
+	return true
+}
+
+func greekStep5g(w *Word) bool {
+	removed := greekRemove(w, "ηθηκεσ", "ηθηκα", "ηθηκε")
+	if !greekRemove(w, "ηκεσ", "ηκα", "ηκε") {
+		return removed
+	}
+	if greekEndsWith(w, greekEndings5g...) || greekIs(w, greekWords5g...) {
+		greekAppend(w, "ηκ")
+	}
+	return true
+}
+
+func greekStep5h(w *Word) bool {
+	if !greekRemove(w, "ουσεσ", "ουσα", "ουσε") {
+		return false
+	}
+	if greekEndsWith(w, greekEndings5h...) || greekIs(w, greekWords5h...) {
+		greekAppend(w, "ουσ")
+	}
+	return true
+}
+
+func greekStep5i(w *Word) bool {
+	if !greekRemove(w, "αγεσ", "αγα", "αγε") {
+		return false
+	}
+	if w.HasSuffix("κολλ") {
+		greekAppend(w, "αγ")
+	} else if greekEndsWith(w, "ψοφ", "ναυλοχ") {
+		return true
+	} else if greekEndsWith(w, greekEndings5i...) || greekIs(w, greekWords5i...) {
+		greekAppend(w, "αγ")
+	}
+	return true
+}
+
+func greekStep5j(w *Word) bool {
+	if !greekRemove(w, "ησου", "ησα", "ησε") {
+		return false
+	}
+	if greekIs(w, greekWords5j...) {
+		greekAppend(w, "ησ")
+	}
+	return true
+}
+
+func greekStep5k(w *Word) bool {
+	if !greekRemove(w, "ηστε") {
+		return false
+	}
+	if greekIs(w, greekWords5k...) {
+		greekAppend(w, "ηστ")
+	}
+	return true
+}
+
+func greekStep5l(w *Word) bool {
+	if !greekRemove(w, "ουνε", "ησουνε", "ηθουνε") {
+		return false
+	}
+	if greekIs(w, greekWords5l...) {
+		greekAppend(w, "ουν")
+	}
+	return true
+}
+
+func greekStep5m(w *Word) bool {
+	if !greekRemove(w, "ουμε", "ησουμε", "ηθουμε") {
+		return false
+	}
+	if greekIs(w, greekWords5m...) {
+		greekAppend(w, "ουμ")
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+import (
+	"strings"
+)
+
+// HungarianName is the name of the token filter for the Hungarian
+// algorithm.
+const HungarianName = NamePrefix + "hu"
+
+const hungarianVowels = "aeiouáéíóöúüőű"
+
+var hungarianDoubles = []string{
+	"bb", "cc", "dd", "ff", "gg", "jj", "kk", "ll", "mm", "nn", "pp", "rr",
+	"ccs", "ss", "zzs", "tt", "vv", "ggy", "lly", "nny", "tty", "ssz", "zz",
+}
+
+var hungarianCases = []string{
+	"ba", "ra", "be", "re", "ig", "nak", "nek", "val", "vel", "ul", "ből",
+	"ről", "től", "nál", "nél", "ból", "ról", "tól", "ül", "n", "an", "ban",
+	"en", "ben", "képpen", "on", "ön", "képp", "kor", "t", "at", "et",
+	"ként", "anként", "enként", "onként", "ot", "ért", "öt", "hez", "hoz",
+	"höz", "vá", "vé",
+}
+
+var hungarianOwned = []string{
+	"éi", "áéi", "ééi", "é", "ké", "aké", "eké", "oké", "áké", "éké", "öké",
+	"éé",
+}
+
+var hungarianSingularOwners = []string{
+	"a", "ja", "d", "ad", "ed", "od", "ád", "éd", "öd", "e", "je", "nk",
+	"unk", "ánk", "énk", "ünk", "uk", "juk", "ájuk", "ük", "jük", "éjük",
+	"m", "am", "em", "om", "ám", "ém", "o", "á", "é",
+}
+
+var hungarianPluralOwners = []string{
+	"id", "aid", "jaid", "eid", "jeid", "áid", "éid", "i", "ai", "jai", "ei",
+	"jei", "ái", "éi", "itek", "eitek", "jeitek", "éitek", "ik", "aik",
+	"jaik", "eik", "jeik", "áik", "éik", "ink", "aink", "jaink", "eink",
+	"jeink", "áink", "éink", "aitok", "jaitok", "áitok", "im", "aim",
+	"jaim", "eim", "jeim", "áim", "éim",
+}
+
+// the consonants written with several letters
+var hungarianDigraphs = []string{"cs", "dzs", "gy", "ly", "ny", "sz", "ty", "zs"}
+
+func init() {
+	Register("hu", stemHungarian)
+}
+
+func stemHungarian(w *Word) {
+	hungarianMarkR1(w)
+
+	// instrumental
+	hungarianDoubled(w, "al", "el")
+
+	// case
+	if s := w.Suffix(hungarianCases...); s != "" && w.In(w.R1, s) {
+		w.Delete(s)
+		if v := w.Suffix("á", "é"); v != "" && w.In(w.R1, v) {
+			hungarianReplace(w, v)
+		}
+	}
+	hungarianReplaceIn(w, "én", "án", "ánként")
+	hungarianReplaceIn(w, "stul", "astul", "ástul", "stül", "estül", "éstül")
+	// factive
+	hungarianDoubled(w, "á", "é")
+
+	// owned
+	switch s := w.Suffix(hungarianOwned...); {
+	case s == "" || !w.In(w.R1, s):
+	case s == "éi" || s == "é":
+		w.Delete(s)
+	default:
+		hungarianReplace(w, s)
+	}
+
+	hungarianReplaceIn(w, hungarianSingularOwners...)
+	hungarianReplaceIn(w, hungarianPluralOwners...)
+	hungarianReplaceIn(w, "k", "ak", "ek", "ok", "ák", "ék", "ök")
+}
+
+// hungarianMarkR1 sets R1 to the region after the first consonant if the
+// word starts with a vowel, or else after the first vowel.
+func hungarianMarkR1(w *Word) {
+	w.R1 = len(w.RS)
+	if len(w.RS) == 0 {
+		return
+	}
+	if !w.isVowel(0, hungarianVowels) {
+		for i := 1; i < len(w.RS); i++ {
+			if w.isVowel(i, hungarianVowels) {
+				w.R1 = i + 1
+				return
+			}
+		}
+		return
+	}
+	for i := 1; i < len(w.RS); i++ {
+		if w.isVowel(i, hungarianVowels) {
+			continue
+		}
+		w.R1 = i + 1
+		rest := string(w.RS[i:])
+		for _, d := range hungarianDigraphs {
+			if strings.HasPrefix(rest, d) && i+runeLen(d) > w.R1 {
+				w.R1 = i + runeLen(d)
+			}
+		}
+		return
+	}
+}
+
+// hungarianReplace replaces a suffix starting with á or é with a or e,
+// and deletes the others.
+func hungarianReplace(w *Word, s string) {
+	switch {
+	case strings.HasPrefix(s, "á"):
+		w.Replace(s, "a")
+	case strings.HasPrefix(s, "é"):
+		w.Replace(s, "e")
+	default:
+		w.Delete(s)
+	}
+}
+
+// hungarianReplaceIn replaces the longest of the suffixes the word ends
+// with, as hungarianReplace does, if it lies in R1.
+func hungarianReplaceIn(w *Word, suffixes ...string) {
+	if s := w.Suffix(suffixes...); s != "" && w.In(w.R1, s) {
+		hungarianReplace(w, s)
+	}
+}
+
+// hungarianDoubled deletes the longest of the suffixes the word ends with
+// if it lies in R1 and follows a double consonant, which is then
+// undoubled.
+func hungarianDoubled(w *Word, suffixes ...string) {
+	s := w.Suffix(suffixes...)
+	if s == "" || !w.In(w.R1, s) {
+		return
+	}
+	rest := NewWord(w.RS[:w.Before(s)])
+	if rest.Suffix(hungarianDoubles...) == "" {
+		return
+	}
+	w.Delete(s)
+	w.RS = append(w.RS[:len(w.RS)-2], w.RS[len(w.RS)-1])
+}
@@ -0,0 +1,174 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// IndonesianName is the name of the token filter for the Indonesian
+// algorithm.
+const IndonesianName = NamePrefix + "id"
+
+const indonesianVowels = "aeiou"
+
+// the prefixes removed by the Indonesian algorithm, recorded to restrict
+// the suffixes removed along with them
+const (
+	indonesianPrefixNone = iota
+	indonesianPrefixDi
+	indonesianPrefixPe
+	indonesianPrefixKe
+	indonesianPrefixBe
+)
+
+func init() {
+	Register("id", stemIndonesian)
+}
+
+// stemIndonesian implements the algorithm of Tala, which removes
+// particles, possessive pronouns, derivational prefixes and suffixes
+// while the word keeps more than two vowels.
+func stemIndonesian(w *Word) {
+	measure := 0
+	for i := range w.RS {
+		if w.isVowel(i, indonesianVowels) {
+			measure++
+		}
+	}
+	if measure <= 2 {
+		return
+	}
+
+	if s := w.Suffix("kah", "lah", "pun"); s != "" {
+		w.Delete(s)
+		measure--
+	}
+	if measure <= 2 {
+		return
+	}
+	if s := w.Suffix("ku", "mu", "nya"); s != "" {
+		w.Delete(s)
+		measure--
+	}
+	if measure <= 2 {
+		return
+	}
+
+	prefix := indonesianPrefixNone
+	if removeIndonesianFirstOrderPrefix(w, &prefix, &measure) {
+		if measure > 2 && removeIndonesianSuffix(w, prefix, &measure) && measure > 2 {
+			removeIndonesianSecondOrderPrefix(w, &prefix, &measure)
+		}
+		return
+	}
+	removeIndonesianSecondOrderPrefix(w, &prefix, &measure)
+	if measure > 2 {
+		removeIndonesianSuffix(w, prefix, &measure)
+	}
+}
+
+// removeIndonesianFirstOrderPrefix removes the inflectional prefixes.
+// As in a Snowball among, the longest prefix whose condition holds is
+// removed.
+func removeIndonesianFirstOrderPrefix(w *Word, prefix, measure *int) bool {
+	for _, p := range []string{"meng", "meny", "peng", "peny", "men", "mem",
+		"pen", "pem", "ter", "di", "me", "ke"} {
+		if !w.HasPrefix(p) {
+			continue
+		}
+		switch p {
+		case "meny", "peny":
+			if !w.IsVowel(4, indonesianVowels) {
+				continue
+			}
+			w.ReplacePrefix(p, "s")
+		case "mem", "pem":
+			if w.IsVowel(3, indonesianVowels) {
+				w.ReplacePrefix(p, "p")
+			} else {
+				w.ReplacePrefix(p, "")
+			}
+		default:
+			w.ReplacePrefix(p, "")
+		}
+		switch p {
+		case "ke", "peng", "pen", "peny", "pem":
+			*prefix = indonesianPrefixKe
+		default:
+			*prefix = indonesianPrefixDi
+		}
+		*measure--
+		return true
+	}
+	return false
+}
+
+// removeIndonesianSecondOrderPrefix removes the derivational prefixes.
+func removeIndonesianSecondOrderPrefix(w *Word, prefix, measure *int) bool {
+	for _, p := range []string{"pelajar", "belajar", "per", "ber", "pe", "be"} {
+		if !w.HasPrefix(p) {
+			continue
+		}
+		switch p {
+		case "pelajar":
+			w.ReplacePrefix(p, "ajar")
+		case "belajar":
+			w.ReplacePrefix(p, "ajar")
+			*prefix = indonesianPrefixBe
+		case "per", "pe":
+			w.ReplacePrefix(p, "")
+			*prefix = indonesianPrefixPe
+		case "ber":
+			w.ReplacePrefix(p, "")
+			*prefix = indonesianPrefixBe
+		case "be":
+			// only before a consonant followed by er
+			if w.IsVowel(2, indonesianVowels) || w.At(2) == 0 ||
+				w.At(3) != 'e' || w.At(4) != 'r' {
+				continue
+			}
+			w.ReplacePrefix(p, "")
+			*prefix = indonesianPrefixBe
+		}
+		*measure--
+		return true
+	}
+	return false
+}
+
+// removeIndonesianSuffix removes the derivational suffixes which may
+// follow the prefix removed before.
+func removeIndonesianSuffix(w *Word, prefix int, measure *int) bool {
+	for _, s := range []string{"kan", "an", "i"} {
+		if !w.HasSuffix(s) {
+			continue
+		}
+		switch s {
+		case "kan":
+			if prefix == indonesianPrefixKe || prefix == indonesianPrefixPe {
+				continue
+			}
+		case "an":
+			if prefix == indonesianPrefixDi {
+				continue
+			}
+		case "i":
+			if prefix > indonesianPrefixPe || w.At(-2) == 's' {
+				continue
+			}
+		}
+		w.Delete(s)
+		*measure--
+		return true
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// ItalianName is the name of the token filter for the Italian
+// algorithm.
+const ItalianName = NamePrefix + "it"
+
+const italianVowels = "aeiouàèìòù"
+
+var italianPronouns = []string{
+	"ci", "gli", "la", "le", "li", "lo", "mi", "ne", "si", "ti", "vi",
+	"sene", "gliela", "gliele", "glieli", "glielo", "gliene", "mela",
+	"mele", "meli", "melo", "mene", "tela", "tele", "teli", "telo", "tene",
+	"cela", "cele", "celi", "celo", "cene", "vela", "vele", "veli", "velo",
+	"vene",
+}
+
+var italianStandardSuffixes = []string{
+	"anza", "anze", "ico", "ici", "ica", "ice", "iche", "ichi", "ismo",
+	"ismi", "abile", "abili", "ibile", "ibili", "ista", "iste", "isti",
+	"istà", "istè", "istì", "oso", "osi", "osa", "ose", "mente", "atrice",
+	"atrici", "ante", "anti", "azione", "azioni", "atore", "atori", "logia",
+	"logie", "uzione", "uzioni", "usione", "usioni", "enza", "enze",
+	"amento", "amenti", "imento", "imenti", "amente", "ità", "ivo", "ivi",
+	"iva", "ive",
+}
+
+var italianVerbSuffixes = []string{
+	"ammo", "ando", "ano", "are", "arono", "asse", "assero", "assi",
+	"assimo", "ata", "ate", "ati", "ato", "ava", "avamo", "avano", "avate",
+	"avi", "avo", "emmo", "enda", "ende", "endi", "endo", "erà", "erai",
+	"eranno", "ere", "erebbe", "erebbero", "erei", "eremmo", "eremo",
+	"ereste", "eresti", "erete", "erò", "erono", "essero", "ete", "eva",
+	"evamo", "evano", "evate", "evi", "evo", "iamo", "immo", "irà", "irai",
+	"iranno", "ire", "irebbe", "irebbero", "irei", "iremmo", "iremo",
+	"ireste", "iresti", "irete", "irò", "irono", "isca", "iscano", "isce",
+	"isci", "isco", "iscono", "issero", "ita", "ite", "iti", "ito", "iva",
+	"ivamo", "ivano", "ivate", "ivi", "ivo", "ar", "ir",
+}
+
+func init() {
+	Register("it", stemItalian)
+}
+
+func stemItalian(w *Word) {
+	for i, r := range w.RS {
+		switch r {
+		case 'á':
+			w.RS[i] = 'à'
+		case 'é':
+			w.RS[i] = 'è'
+		case 'í':
+			w.RS[i] = 'ì'
+		case 'ó':
+			w.RS[i] = 'ò'
+		case 'ú':
+			w.RS[i] = 'ù'
+		case 'u':
+			if i > 0 && w.RS[i-1] == 'q' {
+				w.RS[i] = 'U'
+			}
+		}
+	}
+	// u and i between vowels are consonants
+	from := 0
+	for i := 1; i < len(w.RS)-1; i++ {
+		if i-1 < from || !w.isVowel(i-1, italianVowels) ||
+			!w.isVowel(i+1, italianVowels) {
+			continue
+		}
+		switch w.RS[i] {
+		case 'u':
+			w.RS[i] = 'U'
+			from = i + 2
+		case 'i':
+			w.RS[i] = 'I'
+			from = i + 2
+		}
+	}
+	w.MarkRegions(italianVowels)
+	w.MarkRV(italianVowels)
+
+	if s := w.Suffix(italianPronouns...); s != "" {
+		rest := NewWord(w.RS[:w.Before(s)])
+		switch e := rest.Suffix("ando", "endo", "ar", "er", "ir"); {
+		case e == "" || rest.Before(e) < w.RV:
+		case e == "ando" || e == "endo":
+			w.Delete(s)
+		default:
+			w.Replace(s, "e")
+		}
+	}
+
+	if !italianStandardSuffix(w) {
+		if s := w.SuffixIn(w.RV, italianVerbSuffixes...); s != "" {
+			w.Delete(s)
+		}
+	}
+
+	switch w.At(-1) {
+	case 'a', 'e', 'i', 'o', 'à', 'è', 'ì', 'ò':
+		if len(w.RS)-1 >= w.RV {
+			w.RS = w.RS[:len(w.RS)-1]
+			if w.HasSuffix("i") && w.In(w.RV, "i") {
+				w.Delete("i")
+			}
+		}
+	}
+	if s := w.Suffix("ch", "gh"); s != "" && w.In(w.RV, s) {
+		w.Delete("h")
+	}
+
+	w.ReplaceAll('I', 'i')
+	w.ReplaceAll('U', 'u')
+}
+
+func italianStandardSuffix(w *Word) bool {
+	s := w.Suffix(italianStandardSuffixes...)
+	switch s {
+	case "":
+		return false
+	case "amento", "amenti", "imento", "imenti":
+		if !w.In(w.RV, s) {
+			return false
+		}
+		w.Delete(s)
+		return true
+	case "amente":
+		if !w.In(w.R1, s) {
+			return false
+		}
+		w.Delete(s)
+		switch p := w.Suffix("iv", "os", "ic", "abil"); {
+		case p == "" || !w.In(w.R2, p):
+		case p == "iv":
+			w.Delete(p)
+			if w.HasSuffix("at") && w.In(w.R2, "at") {
+				w.Delete("at")
+			}
+		default:
+			w.Delete(p)
+		}
+		return true
+	}
+	if !w.In(w.R2, s) {
+		return false
+	}
+	switch s {
+	case "azione", "azioni", "atore", "atori":
+		w.Delete(s)
+		if w.HasSuffix("ic") && w.In(w.R2, "ic") {
+			w.Delete("ic")
+		}
+	case "logia", "logie":
+		w.Replace(s, "log")
+	case "uzione", "uzioni", "usione", "usioni":
+		w.Replace(s, "u")
+	case "enza", "enze":
+		w.Replace(s, "ente")
+	case "ità":
+		w.Delete(s)
+		if p := w.Suffix("abil", "ic", "iv"); p != "" && w.In(w.R2, p) {
+			w.Delete(p)
+		}
+	case "ivo", "ivi", "iva", "ive":
+		w.Delete(s)
+		if w.HasSuffix("at") && w.In(w.R2, "at") {
+			w.Delete("at")
+			if w.HasSuffix("ic") && w.In(w.R2, "ic") {
+				w.Delete("ic")
+			}
+		}
+	default:
+		w.Delete(s)
+	}
+	return true
+}
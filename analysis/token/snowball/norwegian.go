@@ -0,0 +1,63 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// NorwegianName is the name of the token filter for the Norwegian
+// (Bokmål) algorithm.
+const NorwegianName = NamePrefix + "no"
+
+const norwegianVowels = "aeiouyæåø"
+
+var norwegianMainSuffixes = []string{
+	"a", "e", "ede", "ande", "ende", "ane", "ene", "hetene", "en", "heten",
+	"ar", "er", "heter", "as", "es", "edes", "endes", "enes", "hetenes",
+	"ens", "hetens", "ers", "ets", "et", "het", "ast", "s", "erte", "ert",
+}
+
+func init() {
+	Register("no", stemNorwegian)
+}
+
+func stemNorwegian(w *Word) {
+	w.MarkRegions(norwegianVowels)
+	w.MinR1(3)
+
+	switch s := w.SuffixIn(w.R1, norwegianMainSuffixes...); s {
+	case "":
+	case "s":
+		switch w.At(-2) {
+		case 'b', 'c', 'd', 'f', 'g', 'h', 'j', 'l', 'm', 'n', 'o', 'p',
+			'r', 't', 'v', 'y', 'z':
+			w.Delete(s)
+		case 'k':
+			if !w.IsVowel(-3, norwegianVowels) {
+				w.Delete(s)
+			}
+		}
+	case "erte", "ert":
+		w.Replace(s, "er")
+	default:
+		w.Delete(s)
+	}
+
+	if w.SuffixIn(w.R1, "dt", "vt") != "" {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+
+	if s := w.SuffixIn(w.R1, "leg", "eleg", "ig", "eig", "lig", "elig",
+		"els", "lov", "elov", "slov", "hetslov"); s != "" {
+		w.Delete(s)
+	}
+}
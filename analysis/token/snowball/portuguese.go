@@ -0,0 +1,172 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// PortugueseName is the name of the token filter for the Portuguese
+// algorithm.
+const PortugueseName = NamePrefix + "pt"
+
+const portugueseVowels = "aeiouáéíóúâêô"
+
+// ã and õ are written a~ and o~ while stemming
+var portugueseStandardSuffixes = []string{
+	"eza", "ezas", "ico", "ica", "icos", "icas", "ismo", "ismos", "ável",
+	"ível", "ista", "istas", "oso", "osa", "osos", "osas", "amento",
+	"amentos", "imento", "imentos", "adora", "ador", "aça~o", "adoras",
+	"adores", "aço~es", "ante", "antes", "ância", "logia", "logias", "uça~o",
+	"uço~es", "ência", "ências", "amente", "mente", "idade", "idades",
+	"iva", "ivo", "ivas", "ivos", "ira", "iras",
+}
+
+var portugueseVerbSuffixes = []string{
+	"ada", "ida", "ia", "aria", "eria", "iria", "ará", "ara", "erá", "era",
+	"irá", "ava", "asse", "esse", "isse", "aste", "este", "iste", "ei",
+	"arei", "erei", "irei", "am", "iam", "ariam", "eriam", "iriam", "aram",
+	"eram", "iram", "avam", "em", "arem", "erem", "irem", "assem", "essem",
+	"issem", "ado", "ido", "ando", "endo", "indo", "ara~o", "era~o",
+	"ira~o", "ar", "er", "ir", "as", "adas", "idas", "ias", "arias",
+	"erias", "irias", "arás", "aras", "erás", "eras", "irás", "avas", "es",
+	"ardes", "erdes", "irdes", "ares", "eres", "ires", "asses", "esses",
+	"isses", "astes", "estes", "istes", "is", "ais", "eis", "íeis",
+	"aríeis", "eríeis", "iríeis", "áreis", "areis", "éreis", "ereis",
+	"íreis", "ireis", "ásseis", "ésseis", "ísseis", "áveis", "ados", "idos",
+	"ámos", "amos", "íamos", "aríamos", "eríamos", "iríamos", "áramos",
+	"éramos", "íramos", "ávamos", "emos", "aremos", "eremos", "iremos",
+	"ássemos", "êssemos", "íssemos", "imos", "armos", "ermos", "irmos",
+	"eu", "iu", "ou", "ira", "iras",
+}
+
+func init() {
+	Register("pt", stemPortuguese)
+}
+
+func stemPortuguese(w *Word) {
+	rs := make([]rune, 0, len(w.RS))
+	for _, r := range w.RS {
+		switch r {
+		case 'ã':
+			rs = append(rs, 'a', '~')
+		case 'õ':
+			rs = append(rs, 'o', '~')
+		default:
+			rs = append(rs, r)
+		}
+	}
+	w.RS = rs
+	w.MarkRegions(portugueseVowels)
+	w.MarkRV(portugueseVowels)
+
+	if portugueseStandardSuffix(w) || portugueseVerbSuffix(w) {
+		if w.HasSuffix("ci") && w.In(w.RV, "i") {
+			w.Delete("i")
+		}
+	} else if s := w.SuffixIn(w.RV, "os", "a", "i", "o", "á", "í", "ó"); s != "" {
+		w.Delete(s)
+	}
+
+	switch s := w.Suffix("e", "é", "ê", "ç"); {
+	case s == "ç":
+		w.Replace(s, "c")
+	case s != "" && w.In(w.RV, s):
+		w.Delete(s)
+		if (w.HasSuffix("gu") || w.HasSuffix("ci")) && len(w.RS)-1 >= w.RV {
+			w.RS = w.RS[:len(w.RS)-1]
+		}
+	}
+
+	rs = w.RS[:0]
+	for _, r := range w.RS {
+		if r == '~' && len(rs) > 0 {
+			switch rs[len(rs)-1] {
+			case 'a':
+				rs[len(rs)-1] = 'ã'
+				continue
+			case 'o':
+				rs[len(rs)-1] = 'õ'
+				continue
+			}
+		}
+		rs = append(rs, r)
+	}
+	w.RS = rs
+}
+
+func portugueseStandardSuffix(w *Word) bool {
+	s := w.Suffix(portugueseStandardSuffixes...)
+	switch s {
+	case "":
+		return false
+	case "amente":
+		if !w.In(w.R1, s) {
+			return false
+		}
+		w.Delete(s)
+		switch p := w.Suffix("iv", "os", "ic", "ad"); {
+		case p == "" || !w.In(w.R2, p):
+		case p == "iv":
+			w.Delete(p)
+			if w.HasSuffix("at") && w.In(w.R2, "at") {
+				w.Delete("at")
+			}
+		default:
+			w.Delete(p)
+		}
+		return true
+	case "ira", "iras":
+		if !w.In(w.RV, s) || !w.HasSuffixBefore("e", s) {
+			return false
+		}
+		w.Replace(s, "ir")
+		return true
+	}
+	if !w.In(w.R2, s) {
+		return false
+	}
+	switch s {
+	case "logia", "logias":
+		w.Replace(s, "log")
+	case "uça~o", "uço~es":
+		w.Replace(s, "u")
+	case "ência", "ências":
+		w.Replace(s, "ente")
+	case "mente":
+		w.Delete(s)
+		if p := w.Suffix("ante", "avel", "ível"); p != "" && w.In(w.R2, p) {
+			w.Delete(p)
+		}
+	case "idade", "idades":
+		w.Delete(s)
+		if p := w.Suffix("abil", "ic", "iv"); p != "" && w.In(w.R2, p) {
+			w.Delete(p)
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		w.Delete(s)
+		if w.HasSuffix("at") && w.In(w.R2, "at") {
+			w.Delete("at")
+		}
+	default:
+		w.Delete(s)
+	}
+	return true
+}
+
+func portugueseVerbSuffix(w *Word) bool {
+	s := w.SuffixIn(w.RV, portugueseVerbSuffixes...)
+	if s == "" {
+		return false
+	}
+	w.Delete(s)
+	return true
+}
@@ -0,0 +1,178 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// RomanianName is the name of the token filter for the Romanian
+// algorithm.
+const RomanianName = NamePrefix + "ro"
+
+const romanianVowels = "aeiouâîă"
+
+// the combined suffixes, by their replacement
+var romanianComboSuffixes = map[string]string{
+	"abilitate": "abil", "abilitati": "abil", "abilităi": "abil",
+	"abilităţi": "abil",
+	"ibilitate": "ibil",
+	"ivitate":   "iv", "ivitati": "iv", "ivităi": "iv", "ivităţi": "iv",
+	"icitate": "ic", "icitati": "ic", "icităi": "ic", "icităţi": "ic",
+	"icator": "ic", "icatori": "ic", "iciv": "ic", "iciva": "ic",
+	"icive": "ic", "icivi": "ic", "icivă": "ic", "ical": "ic",
+	"icala": "ic", "icale": "ic", "icali": "ic", "icală": "ic",
+	"ativ": "at", "ativa": "at", "ative": "at", "ativi": "at", "ativă": "at",
+	"aţiune": "at", "atoare": "at", "ator": "at", "atori": "at",
+	"ătoare": "at", "ător": "at", "ători": "at",
+	"itiv": "it", "itiva": "it", "itive": "it", "itivi": "it", "itivă": "it",
+	"iţiune": "it", "itoare": "it", "itor": "it", "itori": "it",
+}
+
+var romanianStandardSuffixes = []string{
+	"ica", "abila", "ibila", "oasa", "ata", "ita", "anta", "ista", "uta",
+	"iva", "ic", "ice", "abile", "ibile", "isme", "iune", "oase", "ate",
+	"itate", "ite", "ante", "iste", "ute", "ive", "ici", "abili", "ibili",
+	"iuni", "atori", "osi", "ati", "itati", "iti", "anti", "isti", "uti",
+	"işti", "ivi", "ităi", "oşi", "ităţi", "abil", "ibil", "ism", "ator",
+	"os", "at", "it", "ant", "ist", "ut", "iv", "ică", "abilă", "ibilă",
+	"oasă", "ată", "ită", "antă", "istă", "ută", "ivă",
+}
+
+// the verb suffixes deleted after a consonant or u
+var romanianVerbSuffixes = []string{
+	"ea", "ia", "esc", "ăsc", "ind", "ând", "are", "ere", "ire", "âre",
+	"ase", "ise", "use", "âse", "eşte", "ăşte", "eze", "ai", "eai", "iai",
+	"eşti", "ăşti", "ui", "ezi", "aşi", "aseşi", "iseşi", "useşi", "âseşi",
+	"işi", "uşi", "âşi", "âi", "eaţi", "iaţi", "ară", "arăţi", "aserăţi",
+	"iserăţi", "userăţi", "âserăţi", "irăţi", "urăţi", "ârăţi", "am",
+	"eam", "iam", "asem", "isem", "usem", "âsem", "arăm", "aserăm",
+	"iserăm", "userăm", "âserăm", "irăm", "urăm", "ârăm", "au", "eau",
+	"iau", "indu", "ându", "ez", "ească", "aseră", "iseră",
+	"useră", "âseră", "iră", "ură", "âră", "ează",
+}
+
+// the verb suffixes always deleted
+var romanianOtherVerbSuffixes = []string{
+	"se", "sese", "sei", "seşi", "seseşi", "aţi", "eţi", "iţi", "serăţi",
+	"seserăţi", "âţi", "em", "sesem", "im", "ăm", "serăm", "seserăm",
+	"âm", "seră", "seseră",
+}
+
+func init() {
+	Register("ro", stemRomanian)
+}
+
+func stemRomanian(w *Word) {
+	// the letters with a comma below are written with a cedilla in the
+	// algorithm
+	w.ReplaceAll('ș', 'ş')
+	w.ReplaceAll('ț', 'ţ')
+	// u and i between vowels are consonants
+	for i := 1; i < len(w.RS)-1; i++ {
+		if !w.isVowel(i-1, romanianVowels) || !w.isVowel(i+1, romanianVowels) {
+			continue
+		}
+		switch w.RS[i] {
+		case 'u':
+			w.RS[i] = 'U'
+		case 'i':
+			w.RS[i] = 'I'
+		}
+	}
+	w.MarkRegions(romanianVowels)
+	w.MarkRV(romanianVowels)
+
+	romanianStep0(w)
+	if !romanianStandardSuffix(w) {
+		romanianVerbSuffix(w)
+	}
+	if s := w.Suffix("a", "e", "ie", "i", "ă"); s != "" && w.In(w.RV, s) {
+		w.Delete(s)
+	}
+
+	w.ReplaceAll('I', 'i')
+	w.ReplaceAll('U', 'u')
+}
+
+func romanianStep0(w *Word) {
+	s := w.Suffix("ea", "aţia", "aua", "iua", "aţie", "ele", "ile", "iile",
+		"iei", "atei", "ii", "ului", "ul", "elor", "ilor", "iilor")
+	if s == "" || !w.In(w.R1, s) {
+		return
+	}
+	switch s {
+	case "ul", "ului":
+		w.Delete(s)
+	case "aua":
+		w.Replace(s, "a")
+	case "ea", "ele", "elor":
+		w.Replace(s, "e")
+	case "ile":
+		if !w.HasSuffixBefore("ab", s) {
+			w.Replace(s, "i")
+		}
+	case "atei":
+		w.Replace(s, "at")
+	case "aţie", "aţia":
+		w.Replace(s, "aţi")
+	default:
+		w.Replace(s, "i")
+	}
+}
+
+// romanianStandardSuffix removes the combined suffixes and then a
+// standard suffix, and returns true if any was removed.
+func romanianStandardSuffix(w *Word) bool {
+	removed := false
+	for {
+		s := w.Suffix(keys(romanianComboSuffixes)...)
+		if s == "" || !w.In(w.R1, s) {
+			break
+		}
+		w.Replace(s, romanianComboSuffixes[s])
+		removed = true
+	}
+
+	switch s := w.Suffix(romanianStandardSuffixes...); {
+	case s == "" || !w.In(w.R2, s):
+		return removed
+	case s == "iune" || s == "iuni":
+		if !w.HasSuffixBefore("ţ", s) {
+			return removed
+		}
+		w.Replace("ţ"+s, "t")
+	case s == "ism" || s == "isme" || s == "ist" || s == "ista" ||
+		s == "iste" || s == "isti" || s == "istă" || s == "işti":
+		w.Replace(s, "ist")
+	default:
+		w.Delete(s)
+	}
+	return true
+}
+
+func romanianVerbSuffix(w *Word) {
+	s := w.SuffixIn(w.RV, romanianVerbSuffixes...)
+	o := w.SuffixIn(w.RV, romanianOtherVerbSuffixes...)
+	if runeLen(o) > runeLen(s) {
+		w.Delete(o)
+		return
+	}
+	if s == "" {
+		return
+	}
+	// preceded by a consonant or u in RV
+	before := w.Before(s)
+	if before-1 >= w.RV && (!w.isVowel(before-1, romanianVowels) ||
+		w.RS[before-1] == 'u') {
+		w.Delete(s)
+	}
+}
@@ -0,0 +1,121 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// RussianName is the name of the token filter for the Russian
+// algorithm.
+const RussianName = NamePrefix + "ru"
+
+const russianVowels = "аеиоуыэюя"
+
+var russianPerfectiveGerund1 = []string{"в", "вши", "вшись"}
+var russianPerfectiveGerund2 = []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}
+
+var russianAdjective = []string{
+	"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им",
+	"ым", "ом", "его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая",
+	"яя", "ою", "ею",
+}
+
+var russianParticiple1 = []string{"ем", "нн", "вш", "ющ", "щ"}
+var russianParticiple2 = []string{"ивш", "ывш", "ующ"}
+
+var russianVerb1 = []string{
+	"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет",
+	"ют", "ны", "ть", "ешь", "нно",
+}
+var russianVerb2 = []string{
+	"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй",
+	"ил", "ыл", "им", "ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют",
+	"ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+}
+
+var russianNoun = []string{
+	"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и",
+	"ией", "ей", "ой", "ий", "й", "иям", "ям", "ием", "ем", "ам", "ом", "о",
+	"у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+}
+
+func init() {
+	Register("ru", stemRussian)
+}
+
+func stemRussian(w *Word) {
+	w.ReplaceAll('ё', 'е')
+	w.RV = len(w.RS)
+	for i := range w.RS {
+		if w.isVowel(i, russianVowels) {
+			w.RV = i + 1
+			break
+		}
+	}
+	w.MarkRegions(russianVowels)
+
+	if !russianEnding(w, russianPerfectiveGerund1, russianPerfectiveGerund2) {
+		if s := w.SuffixIn(w.RV, "ся", "сь"); s != "" {
+			w.Delete(s)
+		}
+		if russianEnding(w, nil, russianAdjective) {
+			russianEnding(w, russianParticiple1, russianParticiple2)
+		} else if !russianEnding(w, russianVerb1, russianVerb2) {
+			russianEnding(w, nil, russianNoun)
+		}
+	}
+
+	if w.HasSuffix("и") && w.In(w.RV, "и") {
+		w.Delete("и")
+	}
+
+	if s := w.SuffixIn(w.RV, "ост", "ость"); s != "" && w.In(w.R2, s) {
+		w.Delete(s)
+	}
+
+	switch s := w.SuffixIn(w.RV, "ейш", "ейше", "н", "ь"); s {
+	case "ейш", "ейше":
+		w.Delete(s)
+		if w.HasSuffix("нн") && w.In(w.RV, "нн") {
+			w.Delete("н")
+		}
+	case "н":
+		if w.HasSuffix("нн") && w.In(w.RV, "нн") {
+			w.Delete("н")
+		}
+	case "ь":
+		w.Delete(s)
+	}
+}
+
+// russianEnding removes the longest of the endings in RV, where endings
+// of group1 must be preceded by а or я, and returns true if it did.
+func russianEnding(w *Word, group1, group2 []string) bool {
+	endings := make([]string, 0, len(group1)+len(group2))
+	endings = append(endings, group1...)
+	endings = append(endings, group2...)
+	s := w.SuffixIn(w.RV, endings...)
+	if s == "" {
+		return false
+	}
+	for _, e := range group1 {
+		if e == s {
+			// a longer ending of group2 would have been selected
+			p := w.Before(s) - 1
+			if p < w.RV || (w.RS[p] != 'а' && w.RS[p] != 'я') {
+				return false
+			}
+		}
+	}
+	w.Delete(s)
+	return true
+}
@@ -0,0 +1,338 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snowball implements some of the Snowball stemming algorithms,
+// see http://snowball.tartarus.org, translated by hand to Go. Each
+// algorithm is registered as a token filter named stemmer_snowball_<lang>,
+// where lang is the two letter code of the language.
+//
+// The algorithms available are Armenian, Basque, Danish, Dutch, English,
+// Finnish, French, German, Greek, Hungarian, Indonesian, Italian,
+// Norwegian, Portuguese, Romanian, Russian, Spanish, Swedish and Turkish.
+// Snowball has no algorithms for Bulgarian, Czech and Galician.
+// Algorithms written in the Snowball language cannot be loaded, each has
+// to be translated to Go and registered with Register.
+//
+// Algorithms operate on a Word, which keeps the region marks (R1, R2 and
+// RV) the Snowball descriptions refer to, and provides the suffix
+// operations they are written with. Suffix searches follow the Snowball
+// "among" semantics: the longest matching suffix is selected, and if its
+// conditions do not hold no shorter suffix is tried.
+package snowball
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// NamePrefix prefixes the language code in the names of the registered
+// token filters.
+const NamePrefix = "stemmer_snowball_"
+
+// Name returns the name of the token filter registered for the
+// language.
+func Name(lang string) string {
+	return NamePrefix + lang
+}
+
+// An Algorithm stems the lower case word in place.
+type Algorithm func(w *Word)
+
+var algorithms = make(map[string]Algorithm)
+
+// Register registers the algorithm for the language, along with its
+// token filter. It panics if an algorithm is already registered for the
+// language.
+func Register(lang string, algorithm Algorithm) {
+	if _, exists := algorithms[lang]; exists {
+		panic(fmt.Errorf("attempted to register duplicate snowball algorithm '%s'", lang))
+	}
+	algorithms[lang] = algorithm
+	registry.RegisterTokenFilter(Name(lang), func(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+		return NewSnowballStemmerFilter(lang)
+	})
+}
+
+// Languages returns the sorted codes of the languages with a registered
+// algorithm.
+func Languages() []string {
+	rv := make([]string, 0, len(algorithms))
+	for lang := range algorithms {
+		rv = append(rv, lang)
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// Stem returns the stem of the lower case word in the language.
+func Stem(lang, word string) (string, error) {
+	algorithm, ok := algorithms[lang]
+	if !ok {
+		return "", fmt.Errorf("no snowball algorithm for language '%s'", lang)
+	}
+	w := NewWord([]rune(word))
+	algorithm(w)
+	return w.String(), nil
+}
+
+// SnowballStemmerFilter stems the terms of the tokens which are not
+// keywords. Terms are expected to be lower case.
+type SnowballStemmerFilter struct {
+	algorithm Algorithm
+}
+
+func NewSnowballStemmerFilter(lang string) (*SnowballStemmerFilter, error) {
+	algorithm, ok := algorithms[lang]
+	if !ok {
+		return nil, fmt.Errorf("no snowball algorithm for language '%s'", lang)
+	}
+	return &SnowballStemmerFilter{
+		algorithm: algorithm,
+	}, nil
+}
+
+func (s *SnowballStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		// if it is not a protected keyword, stem it
+		if !token.KeyWord {
+			w := NewWord(bytes.Runes(token.Term))
+			s.algorithm(w)
+			token.Term = analysis.BuildTermFromRunes(w.RS)
+		}
+	}
+	return input
+}
+
+// A Word is a word being stemmed, along with the start of its regions.
+// Regions are marked once, before the word is modified, and keep their
+// start when suffixes are replaced, as in Snowball.
+type Word struct {
+	RS []rune
+	R1 int
+	R2 int
+	RV int
+}
+
+// NewWord creates a Word with empty regions.
+func NewWord(rs []rune) *Word {
+	return &Word{
+		RS: rs,
+		R1: len(rs),
+		R2: len(rs),
+		RV: len(rs),
+	}
+}
+
+func (w *Word) String() string {
+	return string(w.RS)
+}
+
+// MarkRegions sets R1 to the region after the first non-vowel following
+// a vowel, and R2 to the same region within R1. Regions not found are
+// empty.
+func (w *Word) MarkRegions(vowels string) {
+	w.R1 = w.regionAfter(0, vowels)
+	w.R2 = w.regionAfter(w.R1, vowels)
+}
+
+// regionAfter returns the position after the first non-vowel following
+// a vowel, starting at start.
+func (w *Word) regionAfter(start int, vowels string) int {
+	for i := start + 1; i < len(w.RS); i++ {
+		if !w.isVowel(i, vowels) && w.isVowel(i-1, vowels) {
+			return i + 1
+		}
+	}
+	return len(w.RS)
+}
+
+// MarkRV sets RV as in the romance languages: if the second letter is a
+// consonant, RV is the region after the next vowel, if the first two
+// letters are vowels, it is the region after the next consonant, and
+// otherwise it is the region after the third letter.
+func (w *Word) MarkRV(vowels string) {
+	w.RV = len(w.RS)
+	if len(w.RS) < 2 {
+		return
+	}
+	if !w.isVowel(1, vowels) {
+		for i := 2; i < len(w.RS); i++ {
+			if w.isVowel(i, vowels) {
+				w.RV = i + 1
+				return
+			}
+		}
+	} else if w.isVowel(0, vowels) {
+		for i := 2; i < len(w.RS); i++ {
+			if !w.isVowel(i, vowels) {
+				w.RV = i + 1
+				return
+			}
+		}
+	} else if len(w.RS) >= 3 {
+		w.RV = 3
+	}
+}
+
+// MinR1 ensures the region before R1 contains at least n letters.
+func (w *Word) MinR1(n int) {
+	if w.R1 < n {
+		w.R1 = n
+	}
+	if w.R1 > len(w.RS) {
+		w.R1 = len(w.RS)
+	}
+}
+
+func (w *Word) isVowel(i int, vowels string) bool {
+	return strings.ContainsRune(vowels, w.RS[i])
+}
+
+// IsVowel returns true if the letter at i, which may be negative to
+// count from the end of the word, is one of vowels. It returns false if
+// there is no such letter.
+func (w *Word) IsVowel(i int, vowels string) bool {
+	if i < 0 {
+		i += len(w.RS)
+	}
+	if i < 0 || i >= len(w.RS) {
+		return false
+	}
+	return w.isVowel(i, vowels)
+}
+
+// At returns the letter at i, which may be negative to count from the
+// end of the word, or 0 if there is no such letter.
+func (w *Word) At(i int) rune {
+	if i < 0 {
+		i += len(w.RS)
+	}
+	if i < 0 || i >= len(w.RS) {
+		return 0
+	}
+	return w.RS[i]
+}
+
+// HasSuffix returns true if the word ends with suffix.
+func (w *Word) HasSuffix(suffix string) bool {
+	return analysis.RunesEndsWith(w.RS, suffix)
+}
+
+// HasSuffixBefore returns true if the word ends with s followed by
+// suffix.
+func (w *Word) HasSuffixBefore(s, suffix string) bool {
+	n := len(w.RS) - runeLen(suffix)
+	return n >= 0 && analysis.RunesEndsWith(w.RS[:n], s)
+}
+
+// HasPrefix returns true if the word starts with prefix.
+func (w *Word) HasPrefix(prefix string) bool {
+	rs := []rune(prefix)
+	if len(rs) > len(w.RS) {
+		return false
+	}
+	for i, r := range rs {
+		if w.RS[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// ReplacePrefix replaces prefix, which the word starts with, with repl.
+// The regions are not moved, so it is meant for the algorithms which do
+// not use them.
+func (w *Word) ReplacePrefix(prefix, repl string) {
+	w.RS = append([]rune(repl), w.RS[runeLen(prefix):]...)
+}
+
+// Suffix returns the longest of the suffixes the word ends with, or ""
+// if none.
+func (w *Word) Suffix(suffixes ...string) string {
+	return w.SuffixIn(0, suffixes...)
+}
+
+// SuffixIn returns the longest of the suffixes the word ends with which
+// lie in the region starting at start, or "" if none.
+func (w *Word) SuffixIn(start int, suffixes ...string) string {
+	rv := ""
+	rvLen := -1
+	for _, suffix := range suffixes {
+		l := runeLen(suffix)
+		if l > rvLen && len(w.RS)-l >= start && analysis.RunesEndsWith(w.RS, suffix) {
+			rv = suffix
+			rvLen = l
+		}
+	}
+	return rv
+}
+
+// In returns true if suffix, which the word ends with, lies in the
+// region starting at start.
+func (w *Word) In(start int, suffix string) bool {
+	return len(w.RS)-runeLen(suffix) >= start
+}
+
+// Before returns the position of suffix, which the word ends with.
+func (w *Word) Before(suffix string) int {
+	return len(w.RS) - runeLen(suffix)
+}
+
+// Replace replaces suffix, which the word ends with, with repl.
+func (w *Word) Replace(suffix, repl string) {
+	w.RS = append(w.RS[:len(w.RS)-runeLen(suffix)], []rune(repl)...)
+}
+
+// Delete removes suffix, which the word ends with.
+func (w *Word) Delete(suffix string) {
+	w.RS = w.RS[:len(w.RS)-runeLen(suffix)]
+}
+
+// ReplaceAll replaces all the letters old with new.
+func (w *Word) ReplaceAll(old, new rune) {
+	for i, r := range w.RS {
+		if r == old {
+			w.RS[i] = new
+		}
+	}
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+// ContainsVowel returns true if one of the letters before end is a
+// vowel.
+func (w *Word) ContainsVowel(end int, vowels string) bool {
+	for i := 0; i < end && i < len(w.RS); i++ {
+		if w.isVowel(i, vowels) {
+			return true
+		}
+	}
+	return false
+}
+
+// keys returns the keys of a suffix table, for use with Suffix.
+func keys(m map[string]string) []string {
+	rv := make([]string, 0, len(m))
+	for k := range m {
+		rv = append(rv, k)
+	}
+	return rv
+}
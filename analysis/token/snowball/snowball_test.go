@@ -0,0 +1,289 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func TestSnowballStemmerFilter(t *testing.T) {
+
+	inputTokenStream := analysis.TokenStream{
+		&analysis.Token{
+			Term: []byte("consignment"),
+		},
+		&analysis.Token{
+			Term: []byte("generously"),
+		},
+		&analysis.Token{
+			Term:    []byte("protected"),
+			KeyWord: true,
+		},
+		&analysis.Token{
+			Term: []byte("knives"),
+		},
+	}
+
+	expectedTokenStream := analysis.TokenStream{
+		&analysis.Token{
+			Term: []byte("consign"),
+		},
+		&analysis.Token{
+			Term: []byte("generous"),
+		},
+		&analysis.Token{
+			Term:    []byte("protected"),
+			KeyWord: true,
+		},
+		&analysis.Token{
+			Term: []byte("knive"),
+		},
+	}
+
+	cache := registry.NewCache()
+	filter, err := cache.TokenFilterNamed(EnglishName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ouputTokenStream := filter.Filter(inputTokenStream)
+	if !reflect.DeepEqual(ouputTokenStream, expectedTokenStream) {
+		t.Errorf("expected %#v got %#v", expectedTokenStream, ouputTokenStream)
+	}
+
+	_, err = NewSnowballStemmerFilter("xx")
+	if err == nil {
+		t.Errorf("expected error for unknown language")
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	expected := []string{"da", "de", "el", "en", "es", "eu", "fi", "fr", "hu", "hy", "id",
+		"it", "nl", "no", "pt", "ro", "ru", "sv", "tr"}
+	actual := Languages()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := map[string]map[string]string{
+		"da": {
+			"indtage":         "indtag",
+			"indtagelse":      "indtag",
+			"indtager":        "indtag",
+			"undersøgelserne": "undersøg",
+			"venligst":        "ven",
+			"hestene":         "hest",
+		},
+		"de": {
+			"aufeinanderfolgenden": "aufeinanderfolg",
+			"häuser":               "haus",
+			"häusern":              "haus",
+			"kategorien":           "kategori",
+			"ergebnissen":          "ergebnis",
+			"fußball":              "fussball",
+			"kenntnisse":           "kenntnis",
+			"aufzeichnungen":       "aufzeichn",
+		},
+		"el": {
+			"ανθρώπους": "ανθρωπ",
+			"καλύτερος": "καλ",
+			"γράφοντας": "γραφ",
+			"αγαπημένη": "αγαπημεν",
+			"πολιτικής": "πολιτικ",
+			"κυβέρνηση": "κυβερνησ",
+			"ελληνικά":  "ελλην",
+		},
+		"en": {
+			"caresses":     "caress",
+			"cries":        "cri",
+			"ties":         "tie",
+			"agreed":       "agre",
+			"hoped":        "hope",
+			"hopping":      "hop",
+			"kiwis":        "kiwi",
+			"gas":          "gas",
+			"itemization":  "item",
+			"sensational":  "sensat",
+			"happily":      "happili",
+			"knightly":     "knight",
+			"consolatory":  "consolatori",
+			"skies":        "sky",
+			"proceeding":   "proceed",
+			"succeed":      "succeed",
+			"communism":    "communism",
+			"hopefulness":  "hope",
+			"conspiracies": "conspiraci",
+		},
+		"es": {
+			"cheque":           "chequ",
+			"chicas":           "chic",
+			"lentamente":       "lent",
+			"preocupación":     "preocup",
+			"abarcándolos":     "abarc",
+			"correspondencias": "correspondent",
+			"generosidades":    "gener",
+			"arguyendo":        "argu",
+		},
+		"eu": {
+			"etxeetan":   "etxe",
+			"mendiko":    "mendi",
+			"liburuak":   "liburu",
+			"ikastolako": "ikast",
+			"esaten":     "esa",
+		},
+		"fi": {
+			"kirjoissa":    "kirj",
+			"taloissa":     "talo",
+			"kaupungeista": "kaupung",
+			"tyttöjen":     "tyttöj",
+			"vaikeimmat":   "vaikeim",
+			"kahvia":       "kahv",
+		},
+		"fr": {
+			"continuait":      "continu",
+			"continuation":    "continu",
+			"continuellement": "continuel",
+			"continuité":      "continu",
+			"nationaux":       "national",
+			"châteaux":        "château",
+			"heureusement":    "heureux",
+			"intelligemment":  "intelligent",
+			"premières":       "premi",
+			"finissons":       "fin",
+			"généreuse":       "géner",
+		},
+		"hu": {
+			"házakban":      "ház",
+			"embereknek":    "ember",
+			"kertjeitekben": "kert",
+			"almákat":       "alma",
+			"városokban":    "város",
+			"könyvekről":    "könyv",
+		},
+		"hy": {
+			"տներում":    "տներ",
+			"մարդկանց":   "մարդկ",
+			"երեխաներին": "երեխ",
+			"քաղաքից":    "քաղ",
+			"սիրելի":     "սիր",
+		},
+		"id": {
+			"kamilah":    "kami",
+			"bukunya":    "buku",
+			"menyapu":    "sapu",
+			"mempunyai":  "punya",
+			"dibukukan":  "buku",
+			"makanan":    "makan",
+			"pelajaran":  "ajar",
+			"bermain":    "main",
+			"bekerja":    "kerja",
+			"pendidikan": "didik",
+			"rumah":      "rumah",
+		},
+		"it": {
+			"abbandonata":  "abbandon",
+			"abbandonerà":  "abbandon",
+			"abbassamento": "abbass",
+			"abitazione":   "abit",
+			"attivamente":  "attiv",
+			"chiamarlo":    "chiam",
+			"chiedendogli": "chied",
+			"amiche":       "amic",
+			"felicità":     "felic",
+		},
+		"nl": {
+			"lichamelijke":  "licham",
+			"opgehouden":    "opgehoud",
+			"mogelijkheden": "mogelijk",
+			"kinderen":      "kinder",
+			"maan":          "man",
+			"brood":         "brod",
+			"zeehonden":     "zeehond",
+		},
+		"no": {
+			"havnedistriktene": "havnedistrikt",
+			"havnene":          "havn",
+			"guttene":          "gutt",
+			"kjærligheten":     "kjær",
+			"bilene":           "bil",
+		},
+		"pt": {
+			"quilométricas":   "quilométr",
+			"quilômetros":     "quilômetr",
+			"felicidade":      "felic",
+			"informação":      "inform",
+			"nações":          "naçõ",
+			"correspondência": "correspondent",
+			"cantaríamos":     "cant",
+		},
+		"ro": {
+			"copiilor":     "cop",
+			"frumoasele":   "frumoas",
+			"cărţilor":     "cărţ",
+			"îndrăgostiţi": "îndrăgost",
+			"luminoasă":    "lumin",
+			"oamenii":      "oamen",
+		},
+		"ru": {
+			"вагонами":       "вагон",
+			"важная":         "важн",
+			"важнейшие":      "важн",
+			"взволновавшись": "взволнова",
+			"бесконечности":  "бесконечн",
+			"благороднейший": "благородн",
+			"длинный":        "длин",
+			"лёгкий":         "легк",
+		},
+		"sv": {
+			"jaktkarlarne":  "jaktkarl",
+			"jaktkarlens":   "jaktkarl",
+			"kärlekens":     "kärlek",
+			"möjligheterna": "möj",
+			"bilarna":       "bil",
+			"flickorna":     "flick",
+			"hästarnas":     "häst",
+		},
+		"tr": {
+			"kitaplarımızdan": "kitap",
+			"evlerde":         "ev",
+			"çocukların":      "çocuk",
+			"arkadaşlarımla":  "arkadaş",
+			"kitabı":          "kitap",
+			"ağacı":           "ağaç",
+		},
+	}
+
+	for lang, words := range tests {
+		for word, expected := range words {
+			actual, err := Stem(lang, word)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != expected {
+				t.Errorf("%s: expected %s to stem to %s, got %s", lang, word, expected, actual)
+			}
+		}
+	}
+
+	_, err := Stem("xx", "word")
+	if err == nil {
+		t.Errorf("expected error for unknown language")
+	}
+}
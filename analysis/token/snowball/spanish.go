@@ -0,0 +1,214 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// SpanishName is the name of the token filter for the Spanish
+// algorithm.
+const SpanishName = NamePrefix + "es"
+
+const spanishVowels = "aeiouáéíóúü"
+
+var spanishPronouns = []string{
+	"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo", "las",
+	"les", "los", "nos",
+}
+
+var spanishStandardSuffixes = []string{
+	"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos", "able",
+	"ables", "ible", "ibles", "ista", "istas", "oso", "osa", "osos", "osas",
+	"amiento", "amientos", "imiento", "imientos", "adora", "ador", "ación",
+	"adoras", "adores", "aciones", "ante", "antes", "ancia", "ancias",
+	"logía", "logías", "ución", "uciones", "encia", "encias", "amente",
+	"mente", "idad", "idades", "iva", "ivo", "ivas", "ivos",
+}
+
+var spanishYVerbSuffixes = []string{
+	"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes",
+	"yais", "yamos",
+}
+
+var spanishVerbSuffixes = []string{
+	"en", "es", "éis", "emos", "arían", "arías", "arán", "arás", "aríais",
+	"aría", "aréis", "aríamos", "aremos", "ará", "aré", "erían", "erías",
+	"erán", "erás", "eríais", "ería", "eréis", "eríamos", "eremos", "erá",
+	"eré", "irían", "irías", "irán", "irás", "iríais", "iría", "iréis",
+	"iríamos", "iremos", "irá", "iré", "aba", "ada", "ida", "ía", "ara",
+	"iera", "ad", "ed", "id", "ase", "iese", "aste", "iste", "an", "aban",
+	"ían", "aran", "ieran", "asen", "iesen", "aron", "ieron", "ado", "ido",
+	"ando", "iendo", "ió", "ar", "er", "ir", "as", "abas", "adas", "idas",
+	"ías", "aras", "ieras", "ases", "ieses", "ís", "áis", "abais", "íais",
+	"arais", "ierais", "aseis", "ieseis", "asteis", "isteis", "ados",
+	"idos", "amos", "ábamos", "íamos", "imos", "áramos", "iéramos",
+	"iésemos", "ásemos",
+}
+
+func init() {
+	Register("es", stemSpanish)
+}
+
+func stemSpanish(w *Word) {
+	w.MarkRegions(spanishVowels)
+	w.MarkRV(spanishVowels)
+
+	spanishAttachedPronoun(w)
+	if !spanishStandardSuffix(w) && !spanishYVerbSuffix(w) {
+		spanishVerbSuffix(w)
+	}
+
+	switch s := w.SuffixIn(w.RV, "os", "a", "o", "á", "í", "ó", "e", "é"); s {
+	case "os", "a", "o", "á", "í", "ó":
+		w.Delete(s)
+	case "e", "é":
+		w.Delete(s)
+		if w.HasSuffix("gu") && w.In(w.RV, "u") {
+			w.Delete("u")
+		}
+	}
+
+	for i, r := range w.RS {
+		switch r {
+		case 'á':
+			w.RS[i] = 'a'
+		case 'é':
+			w.RS[i] = 'e'
+		case 'í':
+			w.RS[i] = 'i'
+		case 'ó':
+			w.RS[i] = 'o'
+		case 'ú':
+			w.RS[i] = 'u'
+		}
+	}
+}
+
+func spanishAttachedPronoun(w *Word) {
+	s := w.Suffix(spanishPronouns...)
+	if s == "" {
+		return
+	}
+	rest := NewWord(w.RS[:w.Before(s)])
+	switch e := rest.Suffix("iéndo", "ándo", "ár", "ér", "ír", "ando", "iendo", "ar", "er", "ir", "yendo"); {
+	case e == "" || rest.Before(e) < w.RV:
+	case e == "yendo":
+		if rest.HasSuffixBefore("u", e) {
+			w.Delete(s)
+		}
+	case e == "iéndo" || e == "ándo" || e == "ár" || e == "ér" || e == "ír":
+		w.Delete(s)
+		w.Replace(e, spanishUnaccent(e))
+	default:
+		w.Delete(s)
+	}
+}
+
+func spanishUnaccent(s string) string {
+	rs := []rune(s)
+	for i, r := range rs {
+		switch r {
+		case 'á':
+			rs[i] = 'a'
+		case 'é':
+			rs[i] = 'e'
+		case 'í':
+			rs[i] = 'i'
+		case 'ó':
+			rs[i] = 'o'
+		case 'ú':
+			rs[i] = 'u'
+		}
+	}
+	return string(rs)
+}
+
+func spanishStandardSuffix(w *Word) bool {
+	s := w.Suffix(spanishStandardSuffixes...)
+	switch s {
+	case "":
+		return false
+	case "amente":
+		if !w.In(w.R1, s) {
+			return false
+		}
+		w.Delete(s)
+		switch p := w.Suffix("iv", "os", "ic", "ad"); {
+		case p == "" || !w.In(w.R2, p):
+		case p == "iv":
+			w.Delete(p)
+			if w.HasSuffix("at") && w.In(w.R2, "at") {
+				w.Delete("at")
+			}
+		default:
+			w.Delete(p)
+		}
+		return true
+	}
+	if !w.In(w.R2, s) {
+		return false
+	}
+	switch s {
+	case "adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+		"antes", "ancia", "ancias":
+		w.Delete(s)
+		if w.HasSuffix("ic") && w.In(w.R2, "ic") {
+			w.Delete("ic")
+		}
+	case "logía", "logías":
+		w.Replace(s, "log")
+	case "ución", "uciones":
+		w.Replace(s, "u")
+	case "encia", "encias":
+		w.Replace(s, "ente")
+	case "mente":
+		w.Delete(s)
+		if p := w.Suffix("ante", "able", "ible"); p != "" && w.In(w.R2, p) {
+			w.Delete(p)
+		}
+	case "idad", "idades":
+		w.Delete(s)
+		if p := w.Suffix("abil", "ic", "iv"); p != "" && w.In(w.R2, p) {
+			w.Delete(p)
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		w.Delete(s)
+		if w.HasSuffix("at") && w.In(w.R2, "at") {
+			w.Delete("at")
+		}
+	default:
+		w.Delete(s)
+	}
+	return true
+}
+
+func spanishYVerbSuffix(w *Word) bool {
+	s := w.SuffixIn(w.RV, spanishYVerbSuffixes...)
+	if s == "" || !w.HasSuffixBefore("u", s) {
+		return false
+	}
+	w.Delete(s)
+	return true
+}
+
+func spanishVerbSuffix(w *Word) {
+	switch s := w.SuffixIn(w.RV, spanishVerbSuffixes...); s {
+	case "":
+	case "en", "es", "éis", "emos":
+		w.Delete(s)
+		if w.HasSuffix("gu") {
+			w.Delete("u")
+		}
+	default:
+		w.Delete(s)
+	}
+}
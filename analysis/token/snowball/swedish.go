@@ -0,0 +1,64 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+// SwedishName is the name of the token filter for the Swedish
+// algorithm.
+const SwedishName = NamePrefix + "sv"
+
+const swedishVowels = "aeiouyäåö"
+
+var swedishMainSuffixes = []string{
+	"a", "arna", "erna", "heterna", "orna", "ad", "e", "ade", "ande",
+	"arne", "are", "aste", "en", "anden", "aren", "heten", "ern", "ar",
+	"er", "heter", "or", "as", "arnas", "ernas", "ornas", "es", "ades",
+	"andes", "ens", "arens", "hetens", "erns", "at", "andet", "het", "ast",
+	"s",
+}
+
+func init() {
+	Register("sv", stemSwedish)
+}
+
+func stemSwedish(w *Word) {
+	w.MarkRegions(swedishVowels)
+	w.MinR1(3)
+
+	switch s := w.SuffixIn(w.R1, swedishMainSuffixes...); s {
+	case "":
+	case "s":
+		switch w.At(-2) {
+		case 'b', 'c', 'd', 'f', 'g', 'h', 'j', 'k', 'l', 'm', 'n', 'o',
+			'p', 'r', 't', 'v', 'y':
+			w.Delete(s)
+		}
+	default:
+		w.Delete(s)
+	}
+
+	if w.SuffixIn(w.R1, "dd", "gd", "nn", "dt", "gt", "kt", "tt") != "" {
+		w.RS = w.RS[:len(w.RS)-1]
+	}
+
+	switch s := w.SuffixIn(w.R1, "lig", "ig", "els", "löst", "fullt"); s {
+	case "":
+	case "löst":
+		w.Replace(s, "lös")
+	case "fullt":
+		w.Replace(s, "full")
+	default:
+		w.Delete(s)
+	}
+}
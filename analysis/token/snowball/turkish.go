@@ -0,0 +1,568 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowball
+
+import (
+	"strings"
+
+	"github.com/edwindvinas/bleve/analysis"
+)
+
+// TurkishName is the name of the token filter for the Turkish
+// algorithm.
+const TurkishName = NamePrefix + "tr"
+
+const turkishVowels = "aeıioöuü"
+
+// the vowels a suffix vowel written U may be
+const turkishU = "ıiuü"
+
+// the vowels which must precede a last vowel for the vowel harmony to
+// hold
+var turkishHarmony = map[rune]string{
+	'a': "aıou",
+	'e': "eiöü",
+	'ı': "aı",
+	'i': "ei",
+	'o': "ou",
+	'ö': "öü",
+	'u': "ou",
+	'ü': "öü",
+}
+
+// A turkishSuffix is one of the suffixes the Turkish algorithm marks,
+// in all its forms.
+type turkishSuffix struct {
+	// harmony is true if the vowel harmony must hold before the suffix
+	harmony bool
+	forms   []string
+	// optional is the consonant which may separate the suffix from a
+	// preceding vowel, or U if a vowel may separate it from a preceding
+	// consonant
+	optional rune
+}
+
+var (
+	turkishPossessives = turkishSuffix{false, []string{"m", "n", "miz", "niz", "muz", "nuz", "mız", "nız", "müz", "nüz"}, 'U'}
+	turkishSU          = turkishSuffix{true, []string{"i", "u", "ı", "ü"}, 's'}
+	turkishLArI        = turkishSuffix{false, []string{"leri", "ları"}, 0}
+	turkishYU          = turkishSuffix{true, []string{"i", "u", "ı", "ü"}, 'y'}
+	turkishNU          = turkishSuffix{true, []string{"ni", "nu", "nı", "nü"}, 0}
+	turkishNUn         = turkishSuffix{true, []string{"in", "un", "ın", "ün"}, 'n'}
+	turkishYA          = turkishSuffix{true, []string{"a", "e"}, 'y'}
+	turkishNA          = turkishSuffix{true, []string{"na", "ne"}, 0}
+	turkishDA          = turkishSuffix{true, []string{"da", "ta", "de", "te"}, 0}
+	turkishNdA         = turkishSuffix{true, []string{"nda", "nde"}, 0}
+	turkishDAn         = turkishSuffix{true, []string{"dan", "tan", "den", "ten"}, 0}
+	turkishNdAn        = turkishSuffix{true, []string{"ndan", "nden"}, 0}
+	turkishYlA         = turkishSuffix{true, []string{"la", "le"}, 'y'}
+	turkishKi          = turkishSuffix{false, []string{"ki"}, 0}
+	turkishNcA         = turkishSuffix{true, []string{"ca", "ce"}, 'n'}
+	turkishYUm         = turkishSuffix{true, []string{"im", "um", "ım", "üm"}, 'y'}
+	turkishSUn         = turkishSuffix{true, []string{"sin", "sun", "sın", "sün"}, 0}
+	turkishYUz         = turkishSuffix{true, []string{"iz", "uz", "ız", "üz"}, 'y'}
+	turkishSUnUz       = turkishSuffix{false, []string{"siniz", "sunuz", "sınız", "sünüz"}, 0}
+	turkishLAr         = turkishSuffix{true, []string{"lar", "ler"}, 0}
+	turkishNUz         = turkishSuffix{true, []string{"niz", "nuz", "nız", "nüz"}, 0}
+	turkishDUr         = turkishSuffix{true, []string{"dir", "tir", "dur", "tur", "dır", "tır", "dür", "tür"}, 0}
+	turkishCAsInA      = turkishSuffix{false, []string{"casına", "cesine"}, 0}
+	turkishYDU         = turkishSuffix{true, []string{
+		"di", "ti", "dik", "tik", "duk", "tuk", "dık", "tık", "dük", "tük",
+		"dim", "tim", "dum", "tum", "dım", "tım", "düm", "tüm", "din", "tin",
+		"dun", "tun", "dın", "tın", "dün", "tün", "du", "tu", "dı", "tı",
+		"dü", "tü"}, 'y'}
+	turkishYsA   = turkishSuffix{false, []string{"sa", "se", "sak", "sek", "sam", "sem", "san", "sen"}, 'y'}
+	turkishYmUs  = turkishSuffix{true, []string{"miş", "muş", "mış", "müş"}, 'y'}
+	turkishYken  = turkishSuffix{false, []string{"ken"}, 'y'}
+	turkishFinal = map[rune]rune{'b': 'p', 'c': 'ç', 'd': 't', 'ğ': 'k'}
+)
+
+func init() {
+	Register("tr", stemTurkish)
+}
+
+// turkishStemmer removes the chains of suffixes the Turkish algorithm
+// describes. Unlike the other algorithms, which only look at the end of
+// the word, it marks suffixes one after the other going backwards from
+// a cursor, and removes the letters between the cursor and a mark (the
+// Snowball ket) once a chain is recognized. Positions saved to
+// backtrack are relative to the end of the word, so they stay valid
+// when a suffix is removed.
+type turkishStemmer struct {
+	w   *Word
+	c   int
+	ket int
+	// noun is false if the noun suffixes are not to be removed
+	noun bool
+}
+
+func stemTurkish(w *Word) {
+	vowels := 0
+	for i := range w.RS {
+		if w.isVowel(i, turkishVowels) {
+			vowels++
+		}
+	}
+	if vowels < 2 {
+		return
+	}
+
+	t := &turkishStemmer{w: w, c: len(w.RS)}
+	t.try(t.nominalVerbSuffixes)
+	if !t.noun {
+		return
+	}
+	t.try(t.nounSuffixes)
+
+	if s := w.String(); s == "ad" || s == "soyad" {
+		return
+	}
+	if r := w.At(-1); r == 'd' || r == 'g' {
+		for i := len(w.RS) - 1; i >= 0; i-- {
+			if w.isVowel(i, turkishVowels) {
+				w.RS = append(w.RS, turkishAppended(w.RS[i]))
+				break
+			}
+		}
+	}
+	if r, ok := turkishFinal[w.At(-1)]; ok {
+		w.RS[len(w.RS)-1] = r
+	}
+}
+
+// turkishAppended returns the vowel appended to a stem ending with d or
+// g whose last vowel is v.
+func turkishAppended(v rune) rune {
+	switch v {
+	case 'a', 'ı':
+		return 'ı'
+	case 'e', 'i':
+		return 'i'
+	case 'o', 'u':
+		return 'u'
+	}
+	return 'ü'
+}
+
+func (t *turkishStemmer) save() int {
+	return len(t.w.RS) - t.c
+}
+
+func (t *turkishStemmer) restore(saved int) {
+	t.c = len(t.w.RS) - saved
+}
+
+// try runs f, and moves the cursor back if it fails.
+func (t *turkishStemmer) try(f func() bool) {
+	saved := t.save()
+	if !f() {
+		t.restore(saved)
+	}
+}
+
+// or runs each of fs until one succeeds, moving the cursor back after
+// each failure.
+func (t *turkishStemmer) or(fs ...func() bool) bool {
+	saved := t.save()
+	for _, f := range fs {
+		if f() {
+			return true
+		}
+		t.restore(saved)
+	}
+	return false
+}
+
+// remove removes the letters from the cursor to the ket.
+func (t *turkishStemmer) remove() {
+	t.w.RS = append(t.w.RS[:t.c], t.w.RS[t.ket:]...)
+}
+
+func (t *turkishStemmer) vowelAt(i int) bool {
+	return i >= 0 && t.w.isVowel(i, turkishVowels)
+}
+
+// harmony returns true if the last vowel before the cursor is preceded
+// by a vowel it harmonizes with.
+func (t *turkishStemmer) harmony() bool {
+	i := t.c - 1
+	for i >= 0 && !t.vowelAt(i) {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	harmony := turkishHarmony[t.w.RS[i]]
+	for i--; i >= 0; i-- {
+		if strings.ContainsRune(harmony, t.w.RS[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// mark moves the cursor before the suffix if the word has it before the
+// cursor.
+func (t *turkishStemmer) mark(s turkishSuffix) bool {
+	if s.harmony && !t.harmony() {
+		return false
+	}
+	form := ""
+	for _, f := range s.forms {
+		if runeLen(f) > runeLen(form) && analysis.RunesEndsWith(t.w.RS[:t.c], f) {
+			form = f
+		}
+	}
+	if form == "" {
+		return false
+	}
+	c := t.c - runeLen(form)
+	switch {
+	case s.optional == 0:
+	case c < 1:
+		return false
+	case s.optional == 'U':
+		// a consonant before the suffix, or before a U vowel preceding it
+		u := strings.ContainsRune(turkishU, t.w.RS[c-1])
+		if c < 2 || t.vowelAt(c-2) {
+			return false
+		}
+		if u {
+			c--
+		}
+	case t.w.RS[c-1] == s.optional:
+		// the consonant after a vowel
+		if !t.vowelAt(c - 2) {
+			return false
+		}
+		c--
+	case !t.vowelAt(c - 2):
+		// no consonant, and a vowel before the last letter
+		return false
+	}
+	t.c = c
+	return true
+}
+
+func (t *turkishStemmer) nominalVerbSuffixes() bool {
+	t.ket = t.c
+	t.noun = true
+	if !t.or(
+		func() bool {
+			return t.mark(turkishYmUs) || t.mark(turkishYDU) ||
+				t.mark(turkishYsA) || t.mark(turkishYken)
+		},
+		func() bool {
+			if !t.mark(turkishCAsInA) {
+				return false
+			}
+			_ = t.mark(turkishSUnUz) || t.mark(turkishLAr) ||
+				t.mark(turkishYUm) || t.mark(turkishSUn) || t.mark(turkishYUz)
+			return t.mark(turkishYmUs)
+		},
+		func() bool {
+			if !t.mark(turkishLAr) {
+				return false
+			}
+			t.remove()
+			t.try(func() bool {
+				t.ket = t.c
+				return t.mark(turkishDUr) || t.mark(turkishYDU) ||
+					t.mark(turkishYsA) || t.mark(turkishYmUs)
+			})
+			t.noun = false
+			return true
+		},
+		func() bool {
+			return t.mark(turkishNUz) &&
+				(t.mark(turkishYDU) || t.mark(turkishYsA))
+		},
+		func() bool {
+			if !t.mark(turkishSUnUz) && !t.mark(turkishYUz) &&
+				!t.mark(turkishSUn) && !t.mark(turkishYUm) {
+				return false
+			}
+			t.remove()
+			t.try(func() bool {
+				t.ket = t.c
+				return t.mark(turkishYmUs)
+			})
+			return true
+		},
+		func() bool {
+			if !t.mark(turkishDUr) {
+				return false
+			}
+			t.remove()
+			t.try(func() bool {
+				t.ket = t.c
+				_ = t.mark(turkishSUnUz) || t.mark(turkishLAr) ||
+					t.mark(turkishYUm) || t.mark(turkishSUn) || t.mark(turkishYUz)
+				return t.mark(turkishYmUs)
+			})
+			return true
+		},
+	) {
+		return false
+	}
+	t.remove()
+	return true
+}
+
+// removeMarked removes the suffix if the word has it before the cursor.
+func (t *turkishStemmer) removeMarked(s turkishSuffix) bool {
+	if !t.mark(s) {
+		return false
+	}
+	t.remove()
+	return true
+}
+
+// removeOwned removes a possessive or sU suffix, and then a lAr suffix
+// followed by a suffix chain before ki.
+func (t *turkishStemmer) removeOwned() bool {
+	t.ket = t.c
+	if !t.mark(turkishPossessives) && !t.mark(turkishSU) {
+		return false
+	}
+	t.remove()
+	t.try(t.pluralChainBeforeKi)
+	return true
+}
+
+// pluralChainBeforeKi removes a lAr suffix followed by a suffix chain
+// before ki.
+func (t *turkishStemmer) pluralChainBeforeKi() bool {
+	t.ket = t.c
+	if !t.removeMarked(turkishLAr) {
+		return false
+	}
+	return t.chainBeforeKi()
+}
+
+// chainBeforeKi removes the suffixes before and including ki.
+func (t *turkishStemmer) chainBeforeKi() bool {
+	t.ket = t.c
+	if !t.mark(turkishKi) {
+		return false
+	}
+	return t.or(
+		func() bool {
+			if !t.removeMarked(turkishDA) {
+				return false
+			}
+			t.try(func() bool {
+				t.ket = t.c
+				return t.or(
+					func() bool {
+						if !t.removeMarked(turkishLAr) {
+							return false
+						}
+						t.try(t.chainBeforeKi)
+						return true
+					},
+					func() bool {
+						if !t.removeMarked(turkishPossessives) {
+							return false
+						}
+						t.try(t.pluralChainBeforeKi)
+						return true
+					},
+				)
+			})
+			return true
+		},
+		func() bool {
+			if !t.removeMarked(turkishNUn) {
+				return false
+			}
+			t.try(func() bool {
+				t.ket = t.c
+				return t.or(
+					func() bool {
+						return t.removeMarked(turkishLArI)
+					},
+					t.removeOwned,
+					t.chainBeforeKi,
+				)
+			})
+			return true
+		},
+		func() bool {
+			if !t.mark(turkishNdA) {
+				return false
+			}
+			return t.or(
+				func() bool {
+					return t.removeMarked(turkishLArI)
+				},
+				func() bool {
+					if !t.removeMarked(turkishSU) {
+						return false
+					}
+					t.try(t.pluralChainBeforeKi)
+					return true
+				},
+				t.chainBeforeKi,
+			)
+		},
+	)
+}
+
+func (t *turkishStemmer) nounSuffixes() bool {
+	return t.or(
+		func() bool {
+			t.ket = t.c
+			if !t.removeMarked(turkishLAr) {
+				return false
+			}
+			t.try(t.chainBeforeKi)
+			return true
+		},
+		func() bool {
+			t.ket = t.c
+			if !t.removeMarked(turkishNcA) {
+				return false
+			}
+			t.try(func() bool {
+				return t.or(
+					func() bool {
+						t.ket = t.c
+						return t.removeMarked(turkishLArI)
+					},
+					t.removeOwned,
+					t.pluralChainBeforeKi,
+				)
+			})
+			return true
+		},
+		func() bool {
+			t.ket = t.c
+			if !t.mark(turkishNdA) && !t.mark(turkishNA) {
+				return false
+			}
+			return t.or(
+				func() bool {
+					return t.removeMarked(turkishLArI)
+				},
+				func() bool {
+					if !t.removeMarked(turkishSU) {
+						return false
+					}
+					t.try(t.pluralChainBeforeKi)
+					return true
+				},
+				t.chainBeforeKi,
+			)
+		},
+		func() bool {
+			t.ket = t.c
+			if !t.mark(turkishNdAn) && !t.mark(turkishNU) {
+				return false
+			}
+			return t.or(
+				func() bool {
+					if !t.removeMarked(turkishSU) {
+						return false
+					}
+					t.try(t.pluralChainBeforeKi)
+					return true
+				},
+				// lArI is marked but, as in Snowball, not removed
+				func() bool {
+					return t.mark(turkishLArI)
+				},
+			)
+		},
+		func() bool {
+			t.ket = t.c
+			if !t.removeMarked(turkishDAn) {
+				return false
+			}
+			t.try(func() bool {
+				t.ket = t.c
+				return t.or(
+					func() bool {
+						if !t.removeMarked(turkishPossessives) {
+							return false
+						}
+						t.try(t.pluralChainBeforeKi)
+						return true
+					},
+					func() bool {
+						if !t.removeMarked(turkishLAr) {
+							return false
+						}
+						t.try(t.chainBeforeKi)
+						return true
+					},
+					t.chainBeforeKi,
+				)
+			})
+			return true
+		},
+		func() bool {
+			t.ket = t.c
+			if !t.mark(turkishNUn) && !t.mark(turkishYlA) {
+				return false
+			}
+			t.remove()
+			t.try(func() bool {
+				return t.or(
+					t.pluralChainBeforeKi,
+					t.removeOwned,
+					t.chainBeforeKi,
+				)
+			})
+			return true
+		},
+		func() bool {
+			t.ket = t.c
+			return t.removeMarked(turkishLArI)
+		},
+		t.chainBeforeKi,
+		func() bool {
+			t.ket = t.c
+			if !t.mark(turkishDA) && !t.mark(turkishYU) && !t.mark(turkishYA) {
+				return false
+			}
+			t.remove()
+			t.try(func() bool {
+				t.ket = t.c
+				if !t.or(
+					func() bool {
+						if !t.removeMarked(turkishPossessives) {
+							return false
+						}
+						t.try(func() bool {
+							t.ket = t.c
+							return t.mark(turkishLAr)
+						})
+						return true
+					},
+					func() bool {
+						return t.mark(turkishLAr)
+					},
+				) {
+					return false
+				}
+				t.remove()
+				return t.chainBeforeKi()
+			})
+			return true
+		},
+		t.removeOwned,
+	)
+}
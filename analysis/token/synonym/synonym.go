@@ -0,0 +1,150 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package synonym implements a TokenFilter expanding token sequences found
+// in a SynonymMap into their synonyms.
+//
+// Its constructor takes the following arguments:
+//
+// "synonym_map" (string): the name of the synonym map describing the
+// synonyms.
+//
+// The longest matching sequence of consecutive tokens is replaced by each
+// of its synonyms. The tokens of a synonym start at the position of the
+// first matched token and occupy consecutive positions, and the tokens
+// following a synonym longer than the matched sequence are moved along,
+// so phrase queries work for both the original and the synonym tokens.
+// The original tokens are only kept when the synonym map expands them to
+// themselves, as it does for lists of equivalent synonyms.
+package synonym
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const Name = "synonym"
+
+type SynonymFilter struct {
+	synonyms  analysis.SynonymMap
+	maxLength int
+}
+
+func NewSynonymFilter(synonyms analysis.SynonymMap) *SynonymFilter {
+	return &SynonymFilter{
+		synonyms:  synonyms,
+		maxLength: synonyms.MaxLength(),
+	}
+}
+
+func (f *SynonymFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input))
+	// shift is how far the tokens are moved along by the synonyms
+	// longer than the sequences they replaced
+	shift := 0
+	for i := 0; i < len(input); {
+		source, n := f.longestMatch(input, i)
+		if n == 0 {
+			input[i].Position += shift
+			rv = append(rv, input[i])
+			i++
+			continue
+		}
+		matched := input[i : i+n]
+		for _, token := range matched {
+			token.Position += shift
+		}
+		span := 0
+		for _, target := range f.synonyms[source] {
+			if target == source {
+				rv = append(rv, matched...)
+				if n > span {
+					span = n
+				}
+			} else {
+				tokens := synonymTokens(matched, target)
+				rv = append(rv, tokens...)
+				if len(tokens) > span {
+					span = len(tokens)
+				}
+			}
+		}
+		if span > n {
+			shift += span - n
+		}
+		i += n
+	}
+	return rv
+}
+
+// longestMatch finds the longest sequence of tokens with consecutive
+// positions starting at input[i] which has synonyms, returning the
+// sequence and the number of tokens in it.
+func (f *SynonymFilter) longestMatch(input analysis.TokenStream, i int) (string, int) {
+	var source string
+	var rv int
+	var buf bytes.Buffer
+	for n := 1; n <= f.maxLength && i+n <= len(input); n++ {
+		token := input[i+n-1]
+		if n > 1 {
+			if token.Position != input[i+n-2].Position+1 {
+				break
+			}
+			buf.WriteByte(' ')
+		}
+		buf.Write(token.Term)
+		if _, ok := f.synonyms[buf.String()]; ok {
+			source = buf.String()
+			rv = n
+		}
+	}
+	return source, rv
+}
+
+// synonymTokens returns the tokens of synonym, starting at the position of
+// the first matched token, which has already been shifted.
+func synonymTokens(matched analysis.TokenStream, synonym string) analysis.TokenStream {
+	terms := strings.Split(synonym, " ")
+	rv := make(analysis.TokenStream, len(terms))
+	for j, term := range terms {
+		rv[j] = &analysis.Token{
+			Term:     []byte(term),
+			Position: matched[0].Position + j,
+			Start:    matched[0].Start,
+			End:      matched[len(matched)-1].End,
+			Type:     analysis.Synonym,
+		}
+	}
+	return rv
+}
+
+func SynonymFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	synonymMapName, ok := config["synonym_map"].(string)
+	if !ok {
+		return nil, fmt.Errorf("must specify synonym_map")
+	}
+	synonymMap, err := cache.SynonymMapNamed(synonymMapName)
+	if err != nil {
+		return nil, fmt.Errorf("error building synonym filter: %v", err)
+	}
+	return NewSynonymFilter(synonymMap), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(Name, SynonymFilterConstructor)
+}
@@ -0,0 +1,134 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synonym
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/analysis/synonymmap"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func tokenStream(terms ...string) analysis.TokenStream {
+	rv := make(analysis.TokenStream, len(terms))
+	start := 0
+	for i, term := range terms {
+		rv[i] = &analysis.Token{
+			Term:     []byte(term),
+			Position: i + 1,
+			Start:    start,
+			End:      start + len(term),
+			Type:     analysis.AlphaNumeric,
+		}
+		start += len(term) + 1
+	}
+	return rv
+}
+
+func TestSynonymFilter(t *testing.T) {
+	cache := registry.NewCache()
+	synonymMapConfig := map[string]interface{}{
+		"type": synonymmap.Name,
+		"synonyms": []interface{}{
+			"tv, television",
+			"usa => united states of america",
+			"new york city, nyc",
+			"new york => ny",
+		},
+	}
+	_, err := cache.DefineSynonymMap("synonym_test", synonymMapConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	synonymConfig := map[string]interface{}{
+		"type":        Name,
+		"synonym_map": "synonym_test",
+	}
+	synonymFilter, err := cache.DefineTokenFilter("synonym_test", synonymConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input  analysis.TokenStream
+		output analysis.TokenStream
+	}{
+		// single token equivalent synonyms, original is kept
+		{
+			input: tokenStream("cheap", "tv"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("cheap"), Position: 1, Start: 0, End: 5, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("tv"), Position: 2, Start: 6, End: 8, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("television"), Position: 2, Start: 6, End: 8, Type: analysis.Synonym},
+			},
+		},
+		// explicit mapping to multiple tokens, original is replaced
+		{
+			input: tokenStream("usa", "today"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("united"), Position: 1, Start: 0, End: 3, Type: analysis.Synonym},
+				&analysis.Token{Term: []byte("states"), Position: 2, Start: 0, End: 3, Type: analysis.Synonym},
+				&analysis.Token{Term: []byte("of"), Position: 3, Start: 0, End: 3, Type: analysis.Synonym},
+				&analysis.Token{Term: []byte("america"), Position: 4, Start: 0, End: 3, Type: analysis.Synonym},
+				&analysis.Token{Term: []byte("today"), Position: 5, Start: 4, End: 9, Type: analysis.AlphaNumeric},
+			},
+		},
+		// longest match wins
+		{
+			input: tokenStream("new", "york", "city"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("new"), Position: 1, Start: 0, End: 3, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("york"), Position: 2, Start: 4, End: 8, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("city"), Position: 3, Start: 9, End: 13, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("nyc"), Position: 1, Start: 0, End: 13, Type: analysis.Synonym},
+			},
+		},
+		{
+			input: tokenStream("new", "york", "state"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("ny"), Position: 1, Start: 0, End: 8, Type: analysis.Synonym},
+				&analysis.Token{Term: []byte("state"), Position: 3, Start: 9, End: 14, Type: analysis.AlphaNumeric},
+			},
+		},
+		// no synonyms
+		{
+			input:  tokenStream("new", "car"),
+			output: tokenStream("new", "car"),
+		},
+	}
+
+	for _, test := range tests {
+		actual := synonymFilter.Filter(test.input)
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %s, got %s", test.output, actual)
+		}
+	}
+}
+
+func TestSynonymFilterNonConsecutive(t *testing.T) {
+	synonymMap := analysis.NewSynonymMap()
+	synonymMap.LoadLine("new york => ny")
+	filter := NewSynonymFilter(synonymMap)
+
+	input := tokenStream("new", "york")
+	input[1].Position = 3
+	actual := filter.Filter(input)
+	if len(actual) != 2 || string(actual[0].Term) != "new" || string(actual[1].Term) != "york" {
+		t.Errorf("expected tokens with a gap to be left alone, got %s", actual)
+	}
+}
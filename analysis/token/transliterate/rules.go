@@ -0,0 +1,44 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transliterate
+
+// cyrillicLatinRules romanize Russian in the style of BGN/PCGN, without
+// diacritics, along with the letters of the other Cyrillic alphabets
+var cyrillicLatinRules = []string{
+	"а > a", "б > b", "в > v", "г > g", "д > d", "е > e", "ё > e",
+	"ж > zh", "з > z", "и > i", "й > y", "к > k", "л > l", "м > m",
+	"н > n", "о > o", "п > p", "р > r", "с > s", "т > t", "у > u",
+	"ф > f", "х > kh", "ц > ts", "ч > ch", "ш > sh", "щ > shch", "ъ >",
+	"ы > y", "ь >", "э > e", "ю > yu", "я > ya",
+	// Ukrainian and Belarusian
+	"ґ > g", "є > ye", "і > i", "ї > yi", "ў > w",
+	// Serbian and Macedonian
+	"ђ > dj", "ѓ > gj", "ѕ > dz", "ј > j", "љ > lj", "њ > nj",
+	"ћ > c", "ќ > kj", "џ > dzh",
+}
+
+// greekLatinRules romanize modern Greek close to ELOT 743, without
+// diacritics
+var greekLatinRules = []string{
+	"α > a", "β > v", "γ > g", "δ > d", "ε > e", "ζ > z", "η > i",
+	"θ > th", "ι > i", "κ > k", "λ > l", "μ > m", "ν > n", "ξ > x",
+	"ο > o", "π > p", "ρ > r", "σ > s", "ς > s", "τ > t", "υ > y",
+	"φ > f", "χ > ch", "ψ > ps", "ω > o",
+	"ά > a", "έ > e", "ή > i", "ί > i", "ό > o", "ύ > y", "ώ > o",
+	"ϊ > i", "ϋ > y", "ΐ > i", "ΰ > y",
+	// digraphs
+	"ου > ou", "ού > ou", "γγ > ng", "γκ > gk", "γξ > nx", "γχ > nch",
+	"αυ > av", "αύ > av", "ευ > ev", "εύ > ev", "ηυ > iv", "ηύ > iv",
+}
@@ -0,0 +1,232 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transliterate implements a TokenFilter converting terms from one
+// script to another using a set of rules.
+//
+// A rule has the form "source > target", an empty target removes the
+// source. At each position of a term the longest matching source is
+// replaced. Rules written in lower case apply to upper case sources as
+// well, the target being capitalized or upper cased to match: with the
+// rule "ж > zh", "Жук" becomes "Zhuk" and "ЖЖ" becomes "ZHZH".
+//
+// The filter is configured with the name of a built in transliterator,
+// a list of rules, or both, the rules taking precedence:
+//
+//	{
+//		"type": "transliterate",
+//		"transliterator": "cyrillic_latin",
+//		"rules": ["щ > sch", "ъ > '"]
+//	}
+//
+// The built in transliterators are also registered as token filters named
+// "transliterate_" followed by their name.
+package transliterate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const Name = "transliterate"
+const NamePrefix = Name + "_"
+
+const CyrillicLatin = "cyrillic_latin"
+const GreekLatin = "greek_latin"
+
+var transliterators = map[string][]string{
+	CyrillicLatin: cyrillicLatinRules,
+	GreekLatin:    greekLatinRules,
+}
+
+type Transliterator struct {
+	rules  map[string]string
+	maxLen int
+}
+
+func NewTransliterator() *Transliterator {
+	return &Transliterator{
+		rules: make(map[string]string),
+	}
+}
+
+// NewTransliteratorFromRules returns a Transliterator with the given rules.
+func NewTransliteratorFromRules(rules []string) (*Transliterator, error) {
+	rv := NewTransliterator()
+	err := rv.AddRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// Add adds or replaces the rule for source.
+func (t *Transliterator) Add(source, target string) {
+	t.rules[source] = target
+	if n := utf8.RuneCountInString(source); n > t.maxLen {
+		t.maxLen = n
+	}
+}
+
+// AddRules parses and adds rules of the form "source > target".
+func (t *Transliterator) AddRules(rules []string) error {
+	for _, rule := range rules {
+		i := strings.Index(rule, ">")
+		if i < 0 {
+			return fmt.Errorf("invalid transliteration rule '%s', expected 'source > target'", rule)
+		}
+		source := strings.TrimSpace(rule[:i])
+		if source == "" {
+			return fmt.Errorf("invalid transliteration rule '%s', empty source", rule)
+		}
+		t.Add(source, strings.TrimSpace(rule[i+1:]))
+	}
+	return nil
+}
+
+func (t *Transliterator) Transliterate(input []byte) []byte {
+	runes := bytes.Runes(input)
+	rv := make([]byte, 0, len(input))
+	for i := 0; i < len(runes); {
+		n, target := t.match(runes, i)
+		if n == 0 {
+			rv = append(rv, string(runes[i])...)
+			i++
+			continue
+		}
+		rv = append(rv, target...)
+		i += n
+	}
+	return rv
+}
+
+// match returns the length of the longest source matching runes at i and
+// its target, or 0 if none matches
+func (t *Transliterator) match(runes []rune, i int) (int, string) {
+	n := t.maxLen
+	if n > len(runes)-i {
+		n = len(runes) - i
+	}
+	for ; n > 0; n-- {
+		source := string(runes[i : i+n])
+		if target, ok := t.rules[source]; ok {
+			return n, target
+		}
+		lower := strings.ToLower(source)
+		if lower == source {
+			continue
+		}
+		if target, ok := t.rules[lower]; ok {
+			return n, recase(runes, i, n, target)
+		}
+	}
+	return 0, ""
+}
+
+// recase returns target for the source runes[i:i+n] which matched in lower
+// case, upper cased if the source is upper cased text, and capitalized
+// otherwise
+func recase(runes []rune, i, n int, target string) string {
+	upper := true
+	for _, r := range runes[i : i+n] {
+		if !unicode.IsUpper(r) {
+			upper = false
+		}
+	}
+	if upper && (n > 1 ||
+		(i > 0 && unicode.IsUpper(runes[i-1])) ||
+		(i+n < len(runes) && unicode.IsUpper(runes[i+n]))) {
+		return strings.ToUpper(target)
+	}
+	r, size := utf8.DecodeRuneInString(target)
+	return string(unicode.ToUpper(r)) + target[size:]
+}
+
+type TransliterateFilter struct {
+	transliterator *Transliterator
+}
+
+func NewTransliterateFilter(transliterator *Transliterator) *TransliterateFilter {
+	return &TransliterateFilter{
+		transliterator: transliterator,
+	}
+}
+
+func (f *TransliterateFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = f.transliterator.Transliterate(token.Term)
+	}
+	return input
+}
+
+func TransliterateFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	transliterator := NewTransliterator()
+	configured := false
+
+	if name, ok := config["transliterator"].(string); ok {
+		rules, ok := transliterators[name]
+		if !ok {
+			return nil, fmt.Errorf("no transliterator named '%s'", name)
+		}
+		err := transliterator.AddRules(rules)
+		if err != nil {
+			return nil, err
+		}
+		configured = true
+	}
+
+	if rulesVal, ok := config["rules"].([]interface{}); ok {
+		rules := make([]string, 0, len(rulesVal))
+		for _, ruleVal := range rulesVal {
+			rule, ok := ruleVal.(string)
+			if !ok {
+				return nil, fmt.Errorf("transliteration rules must be strings")
+			}
+			rules = append(rules, rule)
+		}
+		err := transliterator.AddRules(rules)
+		if err != nil {
+			return nil, err
+		}
+		configured = true
+	}
+
+	if !configured {
+		return nil, fmt.Errorf("must specify transliterator or rules")
+	}
+	return NewTransliterateFilter(transliterator), nil
+}
+
+func builtinConstructor(name string) registry.TokenFilterConstructor {
+	return func(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+		transliterator, err := NewTransliteratorFromRules(transliterators[name])
+		if err != nil {
+			return nil, err
+		}
+		return NewTransliterateFilter(transliterator), nil
+	}
+}
+
+func init() {
+	registry.RegisterTokenFilter(Name, TransliterateFilterConstructor)
+	for name := range transliterators {
+		registry.RegisterTokenFilter(NamePrefix+name, builtinConstructor(name))
+	}
+}
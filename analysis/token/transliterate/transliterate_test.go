@@ -0,0 +1,118 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transliterate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		transliterator string
+		input          string
+		output         string
+	}{
+		{CyrillicLatin, "москва", "moskva"},
+		{CyrillicLatin, "Щукин", "Shchukin"},
+		{CyrillicLatin, "ЖУК", "ZHUK"},
+		{CyrillicLatin, "Ж", "Zh"},
+		{CyrillicLatin, "объект", "obekt"},
+		{CyrillicLatin, "Київ", "Kiyiv"},
+		{CyrillicLatin, "Београд", "Beograd"},
+		{CyrillicLatin, "tokyo", "tokyo"},
+		{GreekLatin, "Αθήνα", "Athina"},
+		{GreekLatin, "ουρανός", "ouranos"},
+		{GreekLatin, "Άγγελος", "Angelos"},
+		{GreekLatin, "ΕΛΛΑΔΑ", "ELLADA"},
+		{GreekLatin, "Ευρώπη", "Evropi"},
+	}
+
+	for _, test := range tests {
+		transliterator, err := NewTransliteratorFromRules(transliterators[test.transliterator])
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := string(transliterator.Transliterate([]byte(test.input)))
+		if actual != test.output {
+			t.Errorf("expected %s to transliterate to %s, got %s", test.input, test.output, actual)
+		}
+	}
+}
+
+func TestTransliteratorRules(t *testing.T) {
+	_, err := NewTransliteratorFromRules([]string{"a = b"})
+	if err == nil {
+		t.Errorf("expected error for rule without '>'")
+	}
+	_, err = NewTransliteratorFromRules([]string{" > b"})
+	if err == nil {
+		t.Errorf("expected error for rule without source")
+	}
+}
+
+func TestTransliterateFilter(t *testing.T) {
+	cache := registry.NewCache()
+	filter, err := cache.DefineTokenFilter("transliterate_test", map[string]interface{}{
+		"type":           Name,
+		"transliterator": CyrillicLatin,
+		"rules": []interface{}{
+			"щ > sch",
+			"ъ > '",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputTokenStream := analysis.TokenStream{
+		&analysis.Token{Term: []byte("борщ"), Position: 1, Start: 0, End: 8},
+		&analysis.Token{Term: []byte("объём"), Position: 2, Start: 9, End: 19},
+	}
+	expectedTokenStream := analysis.TokenStream{
+		&analysis.Token{Term: []byte("borsch"), Position: 1, Start: 0, End: 8},
+		&analysis.Token{Term: []byte("ob'em"), Position: 2, Start: 9, End: 19},
+	}
+	actual := filter.Filter(inputTokenStream)
+	if !reflect.DeepEqual(actual, expectedTokenStream) {
+		t.Errorf("expected %s, got %s", expectedTokenStream, actual)
+	}
+
+	builtin, err := cache.TokenFilterNamed(NamePrefix + GreekLatin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = builtin.Filter(analysis.TokenStream{&analysis.Token{Term: []byte("θάλασσα")}})
+	if string(actual[0].Term) != "thalassa" {
+		t.Errorf("expected thalassa, got %s", actual[0].Term)
+	}
+
+	_, err = cache.DefineTokenFilter("transliterate_unknown", map[string]interface{}{
+		"type":           Name,
+		"transliterator": "klingon_latin",
+	})
+	if err == nil {
+		t.Errorf("expected error for unknown transliterator")
+	}
+	_, err = cache.DefineTokenFilter("transliterate_empty", map[string]interface{}{
+		"type": Name,
+	})
+	if err == nil {
+		t.Errorf("expected error without transliterator or rules")
+	}
+}
@@ -0,0 +1,326 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package worddelimiter implements a TokenFilter splitting tokens such as
+// product codes and identifiers into their word and number parts.
+//
+// Tokens are split on intra-word delimiters (any rune which is not a letter
+// or a digit), on case changes ("PowerShot" -> "Power", "Shot") and on
+// letter/digit boundaries ("SD500" -> "SD", "500"). A trailing English
+// possessive is removed ("O'Neil's" -> "O", "Neil").
+//
+// Each part is placed at its own position, and tokens following a split
+// token are moved along, so phrase queries analyzed the same way keep
+// matching. Catenated parts and the original token are placed at the
+// position of the first part they cover.
+package worddelimiter
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+const Name = "word_delimiter"
+
+// Options controls which tokens the WordDelimiterFilter emits and where
+// it splits.
+type Options struct {
+	// GenerateWordParts emits the letter parts: "PowerShot" -> "Power", "Shot".
+	GenerateWordParts bool
+	// GenerateNumberParts emits the digit parts: "500-42" -> "500", "42".
+	GenerateNumberParts bool
+	// CatenateWords joins runs of adjacent letter parts: "wi-fi" -> "wifi".
+	CatenateWords bool
+	// CatenateNumbers joins runs of adjacent digit parts: "500-42" -> "50042".
+	CatenateNumbers bool
+	// CatenateAll joins all the parts: "wi-fi-4000" -> "wifi4000".
+	CatenateAll bool
+	// SplitOnCaseChange splits where lower case is followed by upper case,
+	// and before the last upper case letter of an upper case run followed
+	// by lower case: "XMLHttp" -> "XML", "Http".
+	SplitOnCaseChange bool
+	// SplitOnNumerics splits on letter/digit boundaries: "j2se" -> "j", "2", "se".
+	SplitOnNumerics bool
+	// StemEnglishPossessive removes a trailing "'s" or "’s".
+	StemEnglishPossessive bool
+	// PreserveOriginal emits the original token as well as its parts.
+	PreserveOriginal bool
+}
+
+// DefaultOptions splits on everything and emits the word and number parts
+// only.
+var DefaultOptions = Options{
+	GenerateWordParts:     true,
+	GenerateNumberParts:   true,
+	SplitOnCaseChange:     true,
+	SplitOnNumerics:       true,
+	StemEnglishPossessive: true,
+}
+
+type WordDelimiterFilter struct {
+	options Options
+}
+
+func NewWordDelimiterFilter(options Options) *WordDelimiterFilter {
+	return &WordDelimiterFilter{
+		options: options,
+	}
+}
+
+func (f *WordDelimiterFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input))
+
+	// shift is how far tokens at the current input position are moved
+	// along by the parts emitted for earlier positions, span is the
+	// largest number of positions taken by a token at the current position
+	shift := 0
+	span := 1
+	lastPosition := 0
+	for _, token := range input {
+		if token.Position > lastPosition {
+			shift += span - 1
+			span = 1
+			lastPosition = token.Position
+		}
+		position := token.Position + shift
+
+		if token.KeyWord {
+			token.Position = position
+			rv = append(rv, token)
+			continue
+		}
+
+		tokens, n := f.split(token, position)
+		rv = append(rv, tokens...)
+		if n > span {
+			span = n
+		}
+	}
+
+	return rv
+}
+
+const (
+	partWord = iota
+	partNumber
+)
+
+// part is a run of runes [start, end) of a token
+type part struct {
+	start, end int
+	kind       int
+}
+
+const (
+	runeDelimiter = iota
+	runeLower
+	runeUpper
+	runeLetter
+	runeDigit
+)
+
+func runeType(r rune) int {
+	switch {
+	case unicode.IsLower(r):
+		return runeLower
+	case unicode.IsUpper(r), unicode.IsTitle(r):
+		return runeUpper
+	case unicode.IsLetter(r), unicode.IsMark(r):
+		return runeLetter
+	case unicode.IsDigit(r):
+		return runeDigit
+	}
+	return runeDelimiter
+}
+
+func isBreak(prev, curr, next int, o *Options) bool {
+	if (prev == runeDigit) != (curr == runeDigit) {
+		return o.SplitOnNumerics
+	}
+	if !o.SplitOnCaseChange {
+		return false
+	}
+	if prev == runeLower && curr == runeUpper {
+		return true
+	}
+	return prev == runeUpper && curr == runeUpper && next == runeLower
+}
+
+// parts splits runes into word and number parts
+func (f *WordDelimiterFilter) parts(runes []rune) []part {
+	end := len(runes)
+	if f.options.StemEnglishPossessive && end > 2 &&
+		(runes[end-1] == 's' || runes[end-1] == 'S') &&
+		(runes[end-2] == '\'' || runes[end-2] == '’') {
+		end -= 2
+	}
+
+	var rv []part
+	start := -1
+	for i := 0; i < end; i++ {
+		curr := runeType(runes[i])
+		if curr == runeDelimiter {
+			if start >= 0 {
+				rv = append(rv, newPart(runes, start, i))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		next := runeDelimiter
+		if i+1 < end {
+			next = runeType(runes[i+1])
+		}
+		if isBreak(runeType(runes[i-1]), curr, next, &f.options) {
+			rv = append(rv, newPart(runes, start, i))
+			start = i
+		}
+	}
+	if start >= 0 {
+		rv = append(rv, newPart(runes, start, end))
+	}
+	return rv
+}
+
+func newPart(runes []rune, start, end int) part {
+	kind := partWord
+	if runeType(runes[start]) == runeDigit {
+		kind = partNumber
+	}
+	return part{start: start, end: end, kind: kind}
+}
+
+// split returns the tokens emitted for token at the given position, along
+// with the number of positions they take
+func (f *WordDelimiterFilter) split(token *analysis.Token, position int) (analysis.TokenStream, int) {
+	runes := bytes.Runes(token.Term)
+	parts := f.parts(runes)
+
+	if len(parts) == 1 && parts[0].start == 0 && parts[0].end == len(runes) {
+		// nothing to split, pass the token through
+		token.Position = position
+		return analysis.TokenStream{token}, 1
+	}
+
+	// byte offsets of the runes, when the term still matches the input
+	var offsets []int
+	if len(token.Term) == token.End-token.Start {
+		offsets = make([]int, len(runes)+1)
+		for i, r := range runes {
+			offsets[i+1] = offsets[i] + utf8.RuneLen(r)
+		}
+	}
+
+	rv := make(analysis.TokenStream, 0, len(parts)+1)
+	emit := func(from, to, pos int, typ analysis.TokenType) {
+		term := make([]byte, 0, len(token.Term))
+		for _, p := range parts[from:to] {
+			term = append(term, string(runes[p.start:p.end])...)
+		}
+		for _, t := range rv {
+			if t.Position == pos && bytes.Equal(t.Term, term) {
+				return
+			}
+		}
+		start, end := token.Start, token.End
+		if offsets != nil {
+			start = token.Start + offsets[parts[from].start]
+			end = token.Start + offsets[parts[to-1].end]
+		}
+		rv = append(rv, &analysis.Token{
+			Term:     term,
+			Start:    start,
+			End:      end,
+			Position: pos,
+			Type:     typ,
+		})
+	}
+
+	if f.options.PreserveOriginal {
+		token.Position = position
+		rv = append(rv, token)
+	}
+
+	n := 0
+	for i, p := range parts {
+		pos := position + n
+		emitted := len(rv)
+
+		if p.kind == partWord && f.options.GenerateWordParts {
+			emit(i, i+1, pos, token.Type)
+		} else if p.kind == partNumber && f.options.GenerateNumberParts {
+			emit(i, i+1, pos, analysis.Numeric)
+		}
+
+		// catenate the run of parts of the same kind starting here
+		if i == 0 || parts[i-1].kind != p.kind {
+			j := i + 1
+			for j < len(parts) && parts[j].kind == p.kind {
+				j++
+			}
+			if j-i > 1 {
+				if p.kind == partWord && f.options.CatenateWords {
+					emit(i, j, pos, token.Type)
+				} else if p.kind == partNumber && f.options.CatenateNumbers {
+					emit(i, j, pos, analysis.Numeric)
+				}
+			}
+		}
+
+		if i == 0 && f.options.CatenateAll && len(parts) > 1 {
+			emit(0, len(parts), pos, token.Type)
+		}
+
+		if len(rv) > emitted {
+			n++
+		}
+	}
+
+	if n == 0 {
+		n = 1
+	}
+	return rv, n
+}
+
+func WordDelimiterFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	options := DefaultOptions
+	flags := map[string]*bool{
+		"generate_word_parts":     &options.GenerateWordParts,
+		"generate_number_parts":   &options.GenerateNumberParts,
+		"catenate_words":          &options.CatenateWords,
+		"catenate_numbers":        &options.CatenateNumbers,
+		"catenate_all":            &options.CatenateAll,
+		"split_on_case_change":    &options.SplitOnCaseChange,
+		"split_on_numerics":       &options.SplitOnNumerics,
+		"stem_english_possessive": &options.StemEnglishPossessive,
+		"preserve_original":       &options.PreserveOriginal,
+	}
+	for key, flag := range flags {
+		if val, ok := config[key].(bool); ok {
+			*flag = val
+		}
+	}
+	return NewWordDelimiterFilter(options), nil
+}
+
+func init() {
+	registry.RegisterTokenFilter(Name, WordDelimiterFilterConstructor)
+}
@@ -0,0 +1,221 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worddelimiter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+func tokenStream(terms ...string) analysis.TokenStream {
+	rv := make(analysis.TokenStream, len(terms))
+	start := 0
+	for i, term := range terms {
+		rv[i] = &analysis.Token{
+			Term:     []byte(term),
+			Position: i + 1,
+			Start:    start,
+			End:      start + len(term),
+			Type:     analysis.AlphaNumeric,
+		}
+		start += len(term) + 1
+	}
+	return rv
+}
+
+func TestWordDelimiterFilter(t *testing.T) {
+	catenate := DefaultOptions
+	catenate.CatenateWords = true
+	catenate.CatenateNumbers = true
+	catenate.CatenateAll = true
+
+	original := DefaultOptions
+	original.PreserveOriginal = true
+
+	noSplit := DefaultOptions
+	noSplit.SplitOnCaseChange = false
+	noSplit.SplitOnNumerics = false
+
+	tests := []struct {
+		options Options
+		input   analysis.TokenStream
+		output  analysis.TokenStream
+	}{
+		{
+			options: DefaultOptions,
+			input:   tokenStream("Wi-Fi"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("Wi"), Position: 1, Start: 0, End: 2, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("Fi"), Position: 2, Start: 3, End: 5, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("PowerShot500", "camera"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("Power"), Position: 1, Start: 0, End: 5, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("Shot"), Position: 2, Start: 5, End: 9, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("500"), Position: 3, Start: 9, End: 12, Type: analysis.Numeric},
+				&analysis.Token{Term: []byte("camera"), Position: 4, Start: 13, End: 19, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("foo_bar.baz"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("foo"), Position: 1, Start: 0, End: 3, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("bar"), Position: 2, Start: 4, End: 7, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("baz"), Position: 3, Start: 8, End: 11, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("XMLHttpRequest"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("XML"), Position: 1, Start: 0, End: 3, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("Http"), Position: 2, Start: 3, End: 7, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("Request"), Position: 3, Start: 7, End: 14, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("O'Neil's"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("O"), Position: 1, Start: 0, End: 1, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("Neil"), Position: 2, Start: 2, End: 6, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("Neil’s"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("Neil"), Position: 1, Start: 0, End: 4, Type: analysis.AlphaNumeric},
+			},
+		},
+		// only an apostrophe introduces a possessive
+		{
+			options: DefaultOptions,
+			input:   tokenStream("XL-S"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("XL"), Position: 1, Start: 0, End: 2, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("S"), Position: 2, Start: 3, End: 4, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("size_s"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("size"), Position: 1, Start: 0, End: 4, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("s"), Position: 2, Start: 5, End: 6, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: DefaultOptions,
+			input:   tokenStream("hello", "--", "world"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("hello"), Position: 1, Start: 0, End: 5, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("world"), Position: 3, Start: 9, End: 14, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: catenate,
+			input:   tokenStream("wi-fi-4000-12"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("wi"), Position: 1, Start: 0, End: 2, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("wifi"), Position: 1, Start: 0, End: 5, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("wifi400012"), Position: 1, Start: 0, End: 13, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("fi"), Position: 2, Start: 3, End: 5, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("4000"), Position: 3, Start: 6, End: 10, Type: analysis.Numeric},
+				&analysis.Token{Term: []byte("400012"), Position: 3, Start: 6, End: 13, Type: analysis.Numeric},
+				&analysis.Token{Term: []byte("12"), Position: 4, Start: 11, End: 13, Type: analysis.Numeric},
+			},
+		},
+		{
+			options: original,
+			input:   tokenStream("SD500", "zoom"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("SD500"), Position: 1, Start: 0, End: 5, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("SD"), Position: 1, Start: 0, End: 2, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("500"), Position: 2, Start: 2, End: 5, Type: analysis.Numeric},
+				&analysis.Token{Term: []byte("zoom"), Position: 3, Start: 6, End: 10, Type: analysis.AlphaNumeric},
+			},
+		},
+		{
+			options: noSplit,
+			input:   tokenStream("PowerShot500-x"),
+			output: analysis.TokenStream{
+				&analysis.Token{Term: []byte("PowerShot500"), Position: 1, Start: 0, End: 12, Type: analysis.AlphaNumeric},
+				&analysis.Token{Term: []byte("x"), Position: 2, Start: 13, End: 14, Type: analysis.AlphaNumeric},
+			},
+		},
+		// nothing to split
+		{
+			options: DefaultOptions,
+			input:   tokenStream("plain", "words"),
+			output:  tokenStream("plain", "words"),
+		},
+	}
+
+	for _, test := range tests {
+		filter := NewWordDelimiterFilter(test.options)
+		actual := filter.Filter(test.input)
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %s, got %s", test.output, actual)
+		}
+	}
+}
+
+func TestWordDelimiterFilterSamePosition(t *testing.T) {
+	input := tokenStream("wi-fi", "wireless", "router")
+	input[1].Position = 1
+	input[2].Position = 2
+
+	filter := NewWordDelimiterFilter(DefaultOptions)
+	actual := filter.Filter(input)
+	expected := []int{1, 2, 1, 3}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d tokens, got %s", len(expected), actual)
+	}
+	for i, token := range actual {
+		if token.Position != expected[i] {
+			t.Errorf("expected %s at position %d, got %d", token.Term, expected[i], token.Position)
+		}
+	}
+}
+
+func TestWordDelimiterFilterConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	filter, err := cache.DefineTokenFilter("word_delimiter_test", map[string]interface{}{
+		"type":              Name,
+		"catenate_words":    true,
+		"preserve_original": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := filter.Filter(tokenStream("Wi-Fi"))
+	var terms []string
+	for _, token := range actual {
+		terms = append(terms, string(token.Term))
+	}
+	expected := []string{"Wi-Fi", "Wi", "WiFi", "Fi"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected %v, got %v", expected, terms)
+	}
+}
@@ -0,0 +1,110 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lattice
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DictionaryPaths returns the paths of the "dictionary" property of a
+// tokenizer config, which is either a single path or a list of paths.
+func DictionaryPaths(config map[string]interface{}) []string {
+	var rv []string
+	switch v := config["dictionary"].(type) {
+	case string:
+		rv = []string{v}
+	case []string:
+		rv = v
+	case []interface{}:
+		for _, path := range v {
+			if path, ok := path.(string); ok {
+				rv = append(rv, path)
+			}
+		}
+	}
+	return rv
+}
+
+// ReadDictionaryFiles calls add with each line of the files, in order.
+// Errors returned by add are reported with the path and line number.
+func ReadDictionaryFiles(paths []string, add func(line string) error) error {
+	for _, path := range paths {
+		err := readDictionaryFile(path, add)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDictionaryFile(path string, add func(line string) error) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening dictionary: %v", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	n := 0
+	for scanner.Scan() {
+		n++
+		err = add(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if err != nil {
+			return fmt.Errorf("dictionary %s line %d: %v", path, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// A DictionaryCache loads the dictionaries read from files once, so
+// that the indexes using the same files share them.
+type DictionaryCache struct {
+	load  func(paths []string) (*Dictionary, error)
+	mutex sync.Mutex
+	dicts map[string]*Dictionary
+}
+
+// NewDictionaryCache returns a cache of the dictionaries built by load.
+func NewDictionaryCache(load func(paths []string) (*Dictionary, error)) *DictionaryCache {
+	return &DictionaryCache{
+		load:  load,
+		dicts: make(map[string]*Dictionary),
+	}
+}
+
+// Dictionary returns the dictionary read from the files, loading it
+// unless it already was.
+func (c *DictionaryCache) Dictionary(paths []string) (*Dictionary, error) {
+	key := strings.Join(paths, "\x00")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if dict, ok := c.dicts[key]; ok {
+		return dict, nil
+	}
+	dict, err := c.load(paths)
+	if err != nil {
+		return nil, err
+	}
+	c.dicts[key] = dict
+	return dict, nil
+}
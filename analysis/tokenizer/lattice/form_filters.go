@@ -0,0 +1,70 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lattice
+
+import (
+	"github.com/edwindvinas/bleve/analysis"
+)
+
+// BaseFormFilter replaces the terms of inflected words with their base
+// form. The terms are looked up in the dictionary with their type, so
+// the filter must come before any filter altering them.
+type BaseFormFilter struct {
+	dict *Dictionary
+}
+
+func NewBaseFormFilter(dict *Dictionary) *BaseFormFilter {
+	return &BaseFormFilter{
+		dict: dict,
+	}
+}
+
+func (f *BaseFormFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord {
+			continue
+		}
+		if e := f.dict.Find(string(token.Term), token.Type); e != nil && e.Base != "" {
+			token.Term = []byte(e.Base)
+		}
+	}
+	return input
+}
+
+// ReadingFormFilter replaces the terms of words with their reading. The
+// terms are looked up in the dictionary with their type, so the filter
+// must come before any filter altering them. Terms without a reading are
+// left as they are.
+type ReadingFormFilter struct {
+	dict *Dictionary
+}
+
+func NewReadingFormFilter(dict *Dictionary) *ReadingFormFilter {
+	return &ReadingFormFilter{
+		dict: dict,
+	}
+}
+
+func (f *ReadingFormFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord {
+			continue
+		}
+		if e := f.dict.Find(string(token.Term), token.Type); e != nil && e.Reading != "" {
+			token.Term = []byte(e.Reading)
+		}
+	}
+	return input
+}
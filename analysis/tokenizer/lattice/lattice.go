@@ -0,0 +1,342 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lattice implements tokenizers segmenting text with a
+// dictionary, for languages written without spaces between words.
+//
+// The input is split into runs of letters and numbers, and each run is
+// segmented into the sequence of dictionary entries with the lowest total
+// cost, found with the Viterbi algorithm over the lattice of the entries
+// matching the run. The cost of a sequence is the sum of the costs of its
+// entries, plus the connection costs between consecutive entries, looked
+// up in the Matrix of the dictionary by the context ids of the entries
+// when it has one, or else given by its Connection between their types.
+// The parts of a run no entry matches are covered by the unknown
+// word entries the dictionary generates.
+//
+// The tokens are typed with the part of speech of their entry, and the
+// BaseFormFilter and ReadingFormFilter replace their terms with the base
+// form and the reading of the entry.
+package lattice
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// An Entry is a word of a Dictionary.
+type Entry struct {
+	// Surface is the text of the word.
+	Surface string
+	// Base is the uninflected form of the word, empty when it is the
+	// surface.
+	Base string
+	// Reading is the pronunciation of the word, empty if unknown.
+	Reading string
+	// POS is the detailed part of speech of the word.
+	POS  string
+	Type analysis.TokenType
+	Cost int
+	// LeftID and RightID are the context ids of the word, connecting it
+	// to the previous and the next word in the Matrix of the dictionary.
+	LeftID  int
+	RightID int
+	// Segments, if not empty, are the tokens the word is split into.
+	Segments []*Entry
+}
+
+// BaseForm returns the base form of the word.
+func (e *Entry) BaseForm() string {
+	if e.Base != "" {
+		return e.Base
+	}
+	return e.Surface
+}
+
+// A Dictionary holds the entries used to segment text. It must not be
+// modified once in use by a tokenizer.
+type Dictionary struct {
+	entries map[string][]*Entry
+	// segments of the user entries, which are only looked up by Find
+	segments map[string][]*Entry
+	maxLen   int
+
+	// Unknown returns the entries for the unknown words starting at i in
+	// rs. It must return at least one entry. The default returns the
+	// letter at i as an Ideographic word with a high cost.
+	Unknown func(rs []rune, i int) []*Entry
+	// Matrix, if set, holds the connection costs between the context ids
+	// of the entries, which must be within its size. The start and the
+	// end of a run have the context id 0.
+	Matrix *Matrix
+	// Connection returns the additional cost of an entry of type right
+	// following one of type left, when there is no Matrix. The default
+	// costs nothing.
+	Connection func(left, right analysis.TokenType) int
+	// TypeOf returns the type of the entries of a user dictionary with
+	// the part of speech pos. The default returns Noun.
+	TypeOf func(pos string) analysis.TokenType
+	// Context returns the context ids of the entries of a user
+	// dictionary with the part of speech pos. The default returns 0.
+	Context func(pos string) (left, right int)
+}
+
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		entries:  make(map[string][]*Entry),
+		segments: make(map[string][]*Entry),
+	}
+}
+
+// Add adds an entry to the dictionary.
+func (d *Dictionary) Add(e *Entry) {
+	d.entries[e.Surface] = append(d.entries[e.Surface], e)
+	if l := utf8.RuneCountInString(e.Surface); l > d.maxLen {
+		d.maxLen = l
+	}
+}
+
+// Lookup returns the entries with the surface.
+func (d *Dictionary) Lookup(surface string) []*Entry {
+	return d.entries[surface]
+}
+
+// Find returns the entry with the surface and type with the lowest cost,
+// including the segments of user entries, or nil if there is none.
+func (d *Dictionary) Find(surface string, typ analysis.TokenType) *Entry {
+	var rv *Entry
+	for _, entries := range [][]*Entry{d.entries[surface], d.segments[surface]} {
+		for _, e := range entries {
+			if e.Type == typ && (rv == nil || e.Cost < rv.Cost) {
+				rv = e
+			}
+		}
+	}
+	return rv
+}
+
+// Copy returns a copy of the dictionary, which can be modified without
+// altering the original.
+func (d *Dictionary) Copy() *Dictionary {
+	rv := *d
+	rv.entries = make(map[string][]*Entry, len(d.entries))
+	for k, v := range d.entries {
+		rv.entries[k] = v[:len(v):len(v)]
+	}
+	rv.segments = make(map[string][]*Entry, len(d.segments))
+	for k, v := range d.segments {
+		rv.segments[k] = v[:len(v):len(v)]
+	}
+	return &rv
+}
+
+func (d *Dictionary) unknown(rs []rune, i int) []*Entry {
+	if d.Unknown != nil {
+		return d.Unknown(rs, i)
+	}
+	return []*Entry{
+		&Entry{
+			Surface: string(rs[i]),
+			Type:    analysis.Ideographic,
+			Cost:    10000,
+		},
+	}
+}
+
+// a node of the lattice, the best path ending with entry at some position
+type node struct {
+	cost  int
+	entry *Entry
+	start int
+	prev  *node
+	// the state the next entry connects to, the right context id of
+	// entry, or its type when the dictionary has no Matrix
+	state int
+}
+
+// connection returns the cost of e following the entry of prev, nil at
+// the start of the run, or of the end of the run following prev when e
+// is nil.
+func (d *Dictionary) connection(prev *node, e *Entry) int {
+	if d.Matrix != nil {
+		right, left := 0, 0
+		if prev.entry != nil {
+			right = prev.entry.RightID
+		}
+		if e != nil {
+			left = e.LeftID
+		}
+		return d.Matrix.Cost(right, left)
+	}
+	if prev.entry == nil || e == nil || d.Connection == nil {
+		return 0
+	}
+	return d.Connection(prev.entry.Type, e.Type)
+}
+
+// segment returns the entries of the lowest cost path through rs, along
+// with their starting positions.
+func (d *Dictionary) segment(rs []rune) ([]*Entry, []int) {
+	// the best paths ending at each position, one per state
+	best := make([][]*node, len(rs)+1)
+	best[0] = []*node{&node{}}
+	for i := 0; i < len(rs); i++ {
+		if best[i] == nil {
+			continue
+		}
+		candidates := make([]*Entry, 0, 8)
+		for l := 1; l <= d.maxLen && i+l <= len(rs); l++ {
+			candidates = append(candidates, d.entries[string(rs[i:i+l])]...)
+		}
+		candidates = append(candidates, d.unknown(rs, i)...)
+		for _, e := range candidates {
+			j := i + utf8.RuneCountInString(e.Surface)
+			state := int(e.Type)
+			if d.Matrix != nil {
+				state = e.RightID
+			}
+			var n *node
+			for _, m := range best[j] {
+				if m.state == state {
+					n = m
+					break
+				}
+			}
+			for _, prev := range best[i] {
+				cost := prev.cost + e.Cost + d.connection(prev, e)
+				if n == nil {
+					n = &node{state: state}
+					best[j] = append(best[j], n)
+				} else if cost >= n.cost {
+					continue
+				}
+				n.cost = cost
+				n.entry = e
+				n.start = i
+				n.prev = prev
+			}
+		}
+	}
+
+	var last *node
+	lastCost := 0
+	for _, n := range best[len(rs)] {
+		cost := n.cost + d.connection(n, nil)
+		if last == nil || cost < lastCost {
+			last = n
+			lastCost = cost
+		}
+	}
+	var entries []*Entry
+	var starts []int
+	for n := last; n != nil && n.entry != nil; n = n.prev {
+		entries = append(entries, n.entry)
+		starts = append(starts, n.start)
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+		starts[i], starts[j] = starts[j], starts[i]
+	}
+	return entries, starts
+}
+
+// DictionaryTokenizer segments runs of letters and numbers with a
+// Dictionary. Other characters separate the runs and are discarded.
+type DictionaryTokenizer struct {
+	dict *Dictionary
+}
+
+func NewDictionaryTokenizer(dict *Dictionary) *DictionaryTokenizer {
+	return &DictionaryTokenizer{
+		dict: dict,
+	}
+}
+
+// Dictionary returns the dictionary of the tokenizer.
+func (t *DictionaryTokenizer) Dictionary() *Dictionary {
+	return t.dict
+}
+
+func (t *DictionaryTokenizer) Tokenize(input []byte) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0)
+
+	// the letters of the input, and their byte offsets
+	rs := make([]rune, 0, len(input))
+	offsets := make([]int, 0, len(input)+1)
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRune(input[i:])
+		rs = append(rs, r)
+		offsets = append(offsets, i)
+		i += size
+	}
+	offsets = append(offsets, len(input))
+
+	pos := 1
+	for start := 0; start < len(rs); {
+		if !isWordRune(rs[start]) {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < len(rs) && isWordRune(rs[end]) {
+			end++
+		}
+		entries, starts := t.dict.segment(rs[start:end])
+		for i, e := range entries {
+			segments := e.Segments
+			if len(segments) == 0 {
+				segments = []*Entry{e}
+			}
+			s := start + starts[i]
+			for _, segment := range segments {
+				l := utf8.RuneCountInString(segment.Surface)
+				token := &analysis.Token{
+					Term:     input[offsets[s]:offsets[s+l]],
+					Start:    offsets[s],
+					End:      offsets[s+l],
+					Position: pos,
+					Type:     segment.Type,
+				}
+				rv = append(rv, token)
+				pos++
+				s += l
+			}
+		}
+		start = end
+	}
+
+	return rv
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)
+}
+
+// TokenizerDictionary returns the dictionary of the named tokenizer,
+// which must be a DictionaryTokenizer.
+func TokenizerDictionary(cache *registry.Cache, name string) (*Dictionary, error) {
+	tokenizer, err := cache.TokenizerNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	dt, ok := tokenizer.(*DictionaryTokenizer)
+	if !ok {
+		return nil, fmt.Errorf("tokenizer '%s' is not a dictionary tokenizer", name)
+	}
+	return dt.Dictionary(), nil
+}
@@ -0,0 +1,269 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lattice
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/analysis"
+)
+
+func TestDictionaryTokenizer(t *testing.T) {
+	dict := NewDictionary()
+	dict.Add(&Entry{Surface: "the", Type: analysis.Noun, Cost: 10})
+	dict.Add(&Entry{Surface: "them", Type: analysis.Pronoun, Cost: 10})
+	dict.Add(&Entry{Surface: "men", Type: analysis.Noun, Cost: 10})
+	dict.Add(&Entry{Surface: "end", Type: analysis.Verb, Cost: 10})
+	dict.Add(&Entry{Surface: "ends", Type: analysis.Verb, Cost: 20, Base: "end"})
+	dict.Add(&Entry{Surface: "mend", Type: analysis.Verb, Cost: 10})
+	dict.Add(&Entry{Surface: "s", Type: analysis.Suffix, Cost: 5})
+
+	tests := []struct {
+		input  []byte
+		output analysis.TokenStream
+	}{
+		// the, mend, s is cheaper than them, ends
+		{
+			input: []byte("themends"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("the"),
+					Type:     analysis.Noun,
+					Position: 1,
+					Start:    0,
+					End:      3,
+				},
+				&analysis.Token{
+					Term:     []byte("mend"),
+					Type:     analysis.Verb,
+					Position: 2,
+					Start:    3,
+					End:      7,
+				},
+				&analysis.Token{
+					Term:     []byte("s"),
+					Type:     analysis.Suffix,
+					Position: 3,
+					Start:    7,
+					End:      8,
+				},
+			},
+		},
+		// unknown letters, and punctuation separating the runs
+		{
+			input: []byte("xmen, them"),
+			output: analysis.TokenStream{
+				&analysis.Token{
+					Term:     []byte("x"),
+					Type:     analysis.Ideographic,
+					Position: 1,
+					Start:    0,
+					End:      1,
+				},
+				&analysis.Token{
+					Term:     []byte("men"),
+					Type:     analysis.Noun,
+					Position: 2,
+					Start:    1,
+					End:      4,
+				},
+				&analysis.Token{
+					Term:     []byte("them"),
+					Type:     analysis.Pronoun,
+					Position: 3,
+					Start:    6,
+					End:      10,
+				},
+			},
+		},
+	}
+
+	tokenizer := NewDictionaryTokenizer(dict)
+	for _, test := range tests {
+		actual := tokenizer.Tokenize(test.input)
+		if !reflect.DeepEqual(actual, test.output) {
+			t.Errorf("expected %v, got %v", test.output, actual)
+		}
+	}
+
+	// a verb followed by a suffix costs more, so them, ends is preferred
+	dict.Connection = func(left, right analysis.TokenType) int {
+		if left == analysis.Verb && right == analysis.Suffix {
+			return 100
+		}
+		return 0
+	}
+	var terms []string
+	for _, token := range tokenizer.Tokenize([]byte("themends")) {
+		terms = append(terms, string(token.Term))
+	}
+	expected := []string{"them", "ends"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected %v, got %v", expected, terms)
+	}
+
+	filter := NewBaseFormFilter(dict)
+	terms = nil
+	for _, token := range filter.Filter(tokenizer.Tokenize([]byte("themends"))) {
+		terms = append(terms, string(token.Term))
+	}
+	expected = []string{"them", "end"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected %v, got %v", expected, terms)
+	}
+}
+
+func TestDictionaryMatrix(t *testing.T) {
+	dict := NewDictionary()
+	dict.Add(&Entry{Surface: "the", Type: analysis.Noun, Cost: 10, LeftID: 1, RightID: 1})
+	dict.Add(&Entry{Surface: "them", Type: analysis.Pronoun, Cost: 10, LeftID: 2, RightID: 2})
+	dict.Add(&Entry{Surface: "mend", Type: analysis.Verb, Cost: 10, LeftID: 3, RightID: 3})
+	dict.Add(&Entry{Surface: "ends", Type: analysis.Verb, Cost: 20, LeftID: 4, RightID: 4})
+	dict.Add(&Entry{Surface: "s", Type: analysis.Suffix, Cost: 5, LeftID: 5, RightID: 5})
+
+	f, err := ioutil.TempFile("", "matrix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	// mend followed by s costs more, and so does ends at the end of a run
+	_, err = f.WriteString("6 6\n3 5 100\n4 0 200\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict.Matrix, err = LoadMatrix(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the connection costs between types are not used with a matrix
+	dict.Connection = func(left, right analysis.TokenType) int {
+		return 1000
+	}
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			input:    "themends",
+			expected: []string{"the", "mend", "s"},
+		},
+		{
+			input:    "themends them",
+			expected: []string{"the", "mend", "s", "them"},
+		},
+	}
+	tokenizer := NewDictionaryTokenizer(dict)
+	for _, test := range tests {
+		var terms []string
+		for _, token := range tokenizer.Tokenize([]byte(test.input)) {
+			terms = append(terms, string(token.Term))
+		}
+		if !reflect.DeepEqual(terms, test.expected) {
+			t.Errorf("expected %v, got %v", test.expected, terms)
+		}
+	}
+
+	dict.Matrix.Set(4, 0, 0)
+	var terms []string
+	for _, token := range tokenizer.Tokenize([]byte("themends")) {
+		terms = append(terms, string(token.Term))
+	}
+	expected := []string{"them", "ends"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected %v, got %v", expected, terms)
+	}
+}
+
+func TestLoadMatrix(t *testing.T) {
+	invalid := []string{
+		"",
+		"6\n",
+		"6 6\n6 0 100\n",
+		"6 6\n0 6 100\n",
+		"6 6\n0 0 40000\n",
+		"6 6\n0 0\n",
+	}
+	for _, data := range invalid {
+		f, err := ioutil.TempFile("", "matrix")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = f.WriteString(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = LoadMatrix(f.Name())
+		if err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+		_ = os.Remove(f.Name())
+	}
+}
+
+func TestAddUserDictionary(t *testing.T) {
+	dict := NewDictionary()
+	dict.Add(&Entry{Surface: "new", Type: analysis.Adjective, Cost: 10})
+	dict.Add(&Entry{Surface: "york", Type: analysis.Noun, Cost: 10})
+
+	user := dict.Copy()
+	err := user.AddUserDictionary([]string{
+		"",
+		"# comment",
+		"newyorkcity,newyork city,nuyork siti,place",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Lookup("newyorkcity") != nil {
+		t.Errorf("expected copy not to alter the original dictionary")
+	}
+
+	var terms []string
+	for _, token := range NewDictionaryTokenizer(user).Tokenize([]byte("newyorkcity")) {
+		terms = append(terms, string(token.Term))
+	}
+	expected := []string{"newyork", "city"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected %v, got %v", expected, terms)
+	}
+	if e := user.Find("city", analysis.Noun); e == nil || e.Reading != "siti" {
+		t.Errorf("expected to find segment city with its reading, got %v", e)
+	}
+
+	invalid := [][]string{
+		{"newyork,new york"},
+		{"newyork,new yrk,nu york,place"},
+		{"newyork,new york,nuyork,place"},
+		{",,,place"},
+	}
+	for _, lines := range invalid {
+		err := dict.Copy().AddUserDictionary(lines)
+		if err == nil {
+			t.Errorf("expected error for %v", lines)
+		}
+	}
+}
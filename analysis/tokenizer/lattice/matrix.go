@@ -0,0 +1,113 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lattice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Matrix holds the connection costs between the right context ids of
+// entries and the left context ids of the entries following them.
+type Matrix struct {
+	rightSize int
+	leftSize  int
+	costs     []int16
+}
+
+// NewMatrix returns a matrix of the given numbers of right and left ids,
+// where all the connection costs are 0.
+func NewMatrix(rightSize, leftSize int) *Matrix {
+	return &Matrix{
+		rightSize: rightSize,
+		leftSize:  leftSize,
+		costs:     make([]int16, rightSize*leftSize),
+	}
+}
+
+// Size returns the numbers of right and left ids of the matrix.
+func (m *Matrix) Size() (rightSize, leftSize int) {
+	return m.rightSize, m.leftSize
+}
+
+// Set sets the cost of an entry of left id left following one of right
+// id right.
+func (m *Matrix) Set(right, left int, cost int16) {
+	m.costs[right+m.rightSize*left] = cost
+}
+
+// Cost returns the cost of an entry of left id left following one of
+// right id right.
+func (m *Matrix) Cost(right, left int) int {
+	return int(m.costs[right+m.rightSize*left])
+}
+
+// LoadMatrix reads a matrix from a file in the format of the matrix.def
+// file of MeCab dictionaries: the numbers of right and left ids on the
+// first line, followed by one connection cost per line, after the right
+// id of the previous entry and the left id of the next one:
+//
+//	1316 1316
+//	0 0 -434
+//	0 1 1
+func LoadMatrix(path string) (*Matrix, error) {
+	var rv *Matrix
+	err := readDictionaryFile(path, func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return nil
+		}
+		if rv == nil {
+			if len(fields) != 2 {
+				return fmt.Errorf("expected 2 sizes, got %d fields", len(fields))
+			}
+			rightSize, err := strconv.Atoi(fields[0])
+			if err != nil || rightSize < 1 {
+				return fmt.Errorf("invalid size '%s'", fields[0])
+			}
+			leftSize, err := strconv.Atoi(fields[1])
+			if err != nil || leftSize < 1 {
+				return fmt.Errorf("invalid size '%s'", fields[1])
+			}
+			rv = NewMatrix(rightSize, leftSize)
+			return nil
+		}
+		if len(fields) != 3 {
+			return fmt.Errorf("expected 3 fields, got %d", len(fields))
+		}
+		right, err := strconv.Atoi(fields[0])
+		if err != nil || right < 0 || right >= rv.rightSize {
+			return fmt.Errorf("invalid right id '%s'", fields[0])
+		}
+		left, err := strconv.Atoi(fields[1])
+		if err != nil || left < 0 || left >= rv.leftSize {
+			return fmt.Errorf("invalid left id '%s'", fields[1])
+		}
+		cost, err := strconv.ParseInt(fields[2], 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid cost '%s'", fields[2])
+		}
+		rv.Set(right, left, int16(cost))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rv == nil {
+		return nil, fmt.Errorf("matrix %s is empty", path)
+	}
+	return rv, nil
+}
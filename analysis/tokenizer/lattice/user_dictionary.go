@@ -0,0 +1,121 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lattice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edwindvinas/bleve/analysis"
+)
+
+// UserCost is the cost of the user dictionary entries, low enough for
+// them to take precedence over the system dictionary.
+const UserCost = -100000
+
+// AddUserDictionary adds the entries of a user dictionary, one per line
+// in the format:
+//
+//	surface,segmentation,readings,part of speech
+//
+// where segmentation splits the surface in tokens separated by spaces,
+// and readings holds the reading of each token, also separated by
+// spaces, or is empty. Empty lines and lines starting with # are
+// ignored. For example:
+//
+//	関西国際空港,関西 国際 空港,カンサイ コクサイ クウコウ,カスタム名詞
+func (d *Dictionary) AddUserDictionary(lines []string) error {
+	for n, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 {
+			return fmt.Errorf("user dictionary line %d: expected 4 fields, got %d", n+1, len(fields))
+		}
+		surface := strings.TrimSpace(fields[0])
+		segments := strings.Fields(fields[1])
+		readings := strings.Fields(fields[2])
+		pos := strings.TrimSpace(fields[3])
+		if surface == "" {
+			return fmt.Errorf("user dictionary line %d: empty surface", n+1)
+		}
+		if strings.Join(segments, "") != surface {
+			return fmt.Errorf("user dictionary line %d: segmentation does not match surface '%s'", n+1, surface)
+		}
+		if len(readings) == 0 {
+			readings = make([]string, len(segments))
+		}
+		if len(readings) != len(segments) {
+			return fmt.Errorf("user dictionary line %d: expected %d readings, got %d", n+1, len(segments), len(readings))
+		}
+		typ := analysis.Noun
+		if d.TypeOf != nil {
+			typ = d.TypeOf(pos)
+		}
+		left, right := 0, 0
+		if d.Context != nil {
+			left, right = d.Context(pos)
+		}
+		e := &Entry{
+			Surface: surface,
+			POS:     pos,
+			Type:    typ,
+			Cost:    UserCost,
+			LeftID:  left,
+			RightID: right,
+		}
+		if len(segments) == 1 {
+			e.Reading = readings[0]
+		} else {
+			for i, segment := range segments {
+				s := &Entry{
+					Surface: segment,
+					Reading: readings[i],
+					POS:     pos,
+					Type:    typ,
+					Cost:    UserCost,
+					LeftID:  left,
+					RightID: right,
+				}
+				e.Segments = append(e.Segments, s)
+				d.segments[segment] = append(d.segments[segment], s)
+			}
+		}
+		d.Add(e)
+	}
+	return nil
+}
+
+// UserDictionaryLines returns the lines of the "user_dictionary"
+// property of a tokenizer config, which is either a list of lines or a
+// single string.
+func UserDictionaryLines(config map[string]interface{}) []string {
+	var rv []string
+	switch v := config["user_dictionary"].(type) {
+	case string:
+		rv = strings.Split(v, "\n")
+	case []string:
+		rv = v
+	case []interface{}:
+		for _, line := range v {
+			if line, ok := line.(string); ok {
+				rv = append(rv, line)
+			}
+		}
+	}
+	return rv
+}
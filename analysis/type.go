@@ -34,6 +34,21 @@ const (
 	Single
 	Double
 	Boolean
+	Synonym
+
+	// part of speech types, set by the dictionary based tokenizers
+	Noun
+	Pronoun
+	Verb
+	Adjective
+	Adverb
+	Particle
+	AuxiliaryVerb
+	Conjunction
+	Interjection
+	Prefix
+	Suffix
+	Symbol
 )
 
 // Token represents one occurrence of a term at a particular location in a
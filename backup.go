@@ -0,0 +1,337 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"archive/tar"
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/edwindvinas/bleve/index/store"
+	"github.com/edwindvinas/bleve/registry"
+)
+
+// backupKVFilename is the name of the tar entry holding the
+// key/value pairs of the store
+const backupKVFilename = "store.kv"
+
+// RestoreBatchSize is the number of key/value pairs
+// written in each batch while restoring a backup
+var RestoreBatchSize = 1000
+
+// runtime store config which is not persisted in the index metadata
+var runtimeStoreConfigKeys = []string{"path", "create_if_missing", "error_if_exists"}
+
+// Backup writes a tar stream containing the index metadata and every
+// key/value pair of the underlying store, read from a consistent
+// snapshot of the store. Writes to the index may continue while the
+// backup is taken, they are not included in the backup.
+func (i *indexImpl) Backup(w io.Writer) (err error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return ErrorIndexClosed
+	}
+
+	kvstore, err := i.i.Advanced()
+	if err != nil {
+		return err
+	}
+	kvreader, err := kvstore.Reader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := kvreader.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	meta := newIndexMeta(i.meta.IndexType, i.meta.Storage, nil)
+	if i.meta.Config != nil {
+		meta.Config = make(map[string]interface{}, len(i.meta.Config))
+		for k, v := range i.meta.Config {
+			meta.Config[k] = v
+		}
+		for _, k := range runtimeStoreConfigKeys {
+			delete(meta.Config, k)
+		}
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tw := tar.NewWriter(w)
+	err = tw.WriteHeader(&tar.Header{
+		Name:    metaFilename,
+		Mode:    0600,
+		Size:    int64(len(metaBytes)),
+		ModTime: now,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(metaBytes)
+	if err != nil {
+		return err
+	}
+
+	// tar needs the size up front, so spool the pairs to a temporary
+	// file rather than reading the whole store twice
+	spool, err := ioutil.TempFile("", "bleve-backup")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := spool.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+		if rerr := os.Remove(spool.Name()); err == nil && rerr != nil {
+			err = rerr
+		}
+	}()
+	bw := bufio.NewWriter(spool)
+	size, err := writeKVPairs(bw, kvreader)
+	if err != nil {
+		return err
+	}
+	err = bw.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = spool.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    backupKVFilename,
+		Mode:    0600,
+		Size:    size,
+		ModTime: now,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, spool)
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// writeKVPairs writes all the key/value pairs visible to the reader,
+// each as the uvarint length of the key, the key, the uvarint length
+// of the value and the value, returning the number of bytes written
+func writeKVPairs(w io.Writer, kvreader store.KVReader) (n int64, err error) {
+	it := kvreader.RangeIterator(nil, nil)
+	defer func() {
+		if cerr := it.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	writeBytes := func(b []byte) error {
+		lenSize := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+		wn, err := w.Write(lenBuf[:lenSize])
+		n += int64(wn)
+		if err != nil {
+			return err
+		}
+		wn, err = w.Write(b)
+		n += int64(wn)
+		return err
+	}
+
+	key, val, valid := it.Current()
+	for valid {
+		err = writeBytes(key)
+		if err != nil {
+			return n, err
+		}
+		err = writeBytes(val)
+		if err != nil {
+			return n, err
+		}
+		it.Next()
+		key, val, valid = it.Current()
+	}
+	return n, nil
+}
+
+// Restore creates a new index at the specified path, which must not
+// already exist, from a backup written by Index.Backup.
+// The index is restored using the kvstore and kvconfig provided, which
+// need not match the store of the backed up index. If kvstore is empty
+// the store type and config recorded in the backup are used.
+// If the restore fails, the partially restored index is removed.
+func Restore(r io.Reader, path string, kvstore string, kvconfig map[string]interface{}) (Index, error) {
+	if path == "" {
+		return nil, fmt.Errorf("restore requires an index path")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, ErrorIndexPathExists
+	}
+
+	idx, err := restore(r, path, kvstore, kvconfig)
+	if err != nil {
+		_ = os.RemoveAll(path)
+		return nil, err
+	}
+	return idx, nil
+}
+
+func restore(r io.Reader, path string, kvstore string, kvconfig map[string]interface{}) (Index, error) {
+
+	tr := tar.NewReader(r)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup: %v", err)
+	}
+	if hdr.Name != metaFilename {
+		return nil, fmt.Errorf("backup must start with %s, found %s", metaFilename, hdr.Name)
+	}
+	var meta indexMeta
+	err = json.NewDecoder(tr).Decode(&meta)
+	if err != nil {
+		return nil, ErrorIndexMetaCorrupt
+	}
+	if kvstore != "" {
+		meta.Storage = kvstore
+		meta.Config = kvconfig
+	}
+
+	hdr, err = tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup: %v", err)
+	}
+	if hdr.Name != backupKVFilename {
+		return nil, fmt.Errorf("backup missing %s, found %s", backupKVFilename, hdr.Name)
+	}
+
+	err = meta.Save(path)
+	if err != nil {
+		return nil, err
+	}
+
+	storeConfig := map[string]interface{}{}
+	for k, v := range meta.Config {
+		storeConfig[k] = v
+	}
+	storeConfig["create_if_missing"] = true
+	storeConfig["error_if_exists"] = true
+	storeConfig["path"] = indexStorePath(path)
+
+	indexTypeConstructor := registry.IndexTypeConstructorByName(meta.IndexType)
+	if indexTypeConstructor == nil {
+		return nil, ErrorUnknownIndexType
+	}
+	idx, err := indexTypeConstructor(meta.Storage, storeConfig, Config.analysisQueue)
+	if err != nil {
+		return nil, err
+	}
+	err = idx.Open()
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := idx.Advanced()
+	if err != nil {
+		_ = idx.Close()
+		return nil, err
+	}
+	err = readKVPairs(bufio.NewReader(tr), kvs)
+	if err != nil {
+		_ = idx.Close()
+		return nil, err
+	}
+	// close and reopen, so the index state is loaded from the restored rows
+	err = idx.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return openIndexUsing(path, nil)
+}
+
+// readKVPairs reads key/value pairs written by writeKVPairs
+// and writes them to the store
+func readKVPairs(r *bufio.Reader, kvs store.KVStore) (err error) {
+	kvwriter, err := kvs.Writer()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := kvwriter.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	readBytes := func() ([]byte, error) {
+		l, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		rv := make([]byte, l)
+		_, err = io.ReadFull(r, rv)
+		return rv, err
+	}
+
+	batch := kvwriter.NewBatch()
+	defer func() {
+		if cerr := batch.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+	batchCount := 0
+	for {
+		key, err := readBytes()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading backup key: %v", err)
+		}
+		val, err := readBytes()
+		if err != nil {
+			return fmt.Errorf("error reading backup value: %v", err)
+		}
+		batch.Set(key, val)
+		batchCount++
+		if batchCount >= RestoreBatchSize {
+			err = kvwriter.ExecuteBatch(batch)
+			if err != nil {
+				return err
+			}
+			batch.Reset()
+			batchCount = 0
+		}
+	}
+	if batchCount > 0 {
+		return kvwriter.ExecuteBatch(batch)
+	}
+	return nil
+}
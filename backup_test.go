@@ -0,0 +1,142 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/edwindvinas/bleve/index/store/boltdb"
+	"github.com/edwindvinas/bleve/index/store/goleveldb"
+	"github.com/edwindvinas/bleve/index/upsidedown"
+)
+
+func TestBackupRestore(t *testing.T) {
+	defer func() {
+		err := os.RemoveAll("testidx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.RemoveAll("testidx-restored")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	index, err := NewUsing("testidx", NewIndexMapping(), upsidedown.Name, boltdb.Name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := map[string]interface{}{
+		"a": map[string]interface{}{"name": "marty", "desc": "couchbase developer"},
+		"b": map[string]interface{}{"name": "steve", "desc": "couchbase architect"},
+		"c": map[string]interface{}{"name": "mike", "desc": "bleve developer"},
+	}
+	for id, doc := range docs {
+		err = index.Index(id, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	err = index.Backup(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// changes after the backup are not included
+	err = index.Delete("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = index.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := Restore(bytes.NewReader(buf.Bytes()), "testidx-restored", goleveldb.Name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := restored.DocCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 docs in restored index, got %d", count)
+	}
+
+	res, err := restored.Search(NewSearchRequest(NewMatchQuery("developer")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 2 {
+		t.Errorf("expected 2 hits, got %d", res.Total)
+	}
+
+	doc, err := restored.Document("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc == nil {
+		t.Errorf("expected document a in restored index")
+	}
+
+	err = restored.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the restored index records its new storage type
+	restored, err = Open("testidx-restored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, kvstore, err := restored.Advanced()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kvstore.(*goleveldb.Store); !ok {
+		t.Errorf("expected goleveldb store, got %T", kvstore)
+	}
+	err = restored.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// restoring over an existing index fails, and leaves it alone
+	_, err = Restore(bytes.NewReader(buf.Bytes()), "testidx-restored", "", nil)
+	if err != ErrorIndexPathExists {
+		t.Errorf("expected index path exists error, got %v", err)
+	}
+	if _, err = os.Stat("testidx-restored"); err != nil {
+		t.Errorf("expected existing index to be kept, got %v", err)
+	}
+
+	// a failed restore removes the partially restored index
+	truncated := buf.Bytes()[:buf.Len()/2]
+	_, err = Restore(bytes.NewReader(truncated), "testidx-truncated", "", nil)
+	if err == nil {
+		t.Errorf("expected error restoring truncated backup")
+	}
+	if _, err = os.Stat("testidx-truncated"); !os.IsNotExist(err) {
+		t.Errorf("expected partially restored index to be removed, got %v", err)
+		_ = os.RemoveAll("testidx-truncated")
+	}
+}
@@ -0,0 +1,56 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup [index path] [backup file]",
+	Short: "backs up the index",
+	Long: `The backup command writes a consistent copy of the index to a tar file,
+or to stdout if the backup file is omitted or is "-".`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var w io.Writer = os.Stdout
+		if len(args) > 1 && args[1] != "-" {
+			var f *os.File
+			f, err = os.OpenFile(args[1], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return fmt.Errorf("error creating backup file: %v", err)
+			}
+			defer func() {
+				if cerr := f.Close(); err == nil && cerr != nil {
+					err = fmt.Errorf("error closing backup file: %v", cerr)
+				}
+			}()
+			w = f
+		}
+		err = idx.Backup(w)
+		if err != nil {
+			return fmt.Errorf("error backing up index: %v", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(backupCmd)
+}
@@ -45,6 +45,9 @@ var registryCmd = &cobra.Command{
 		types, instances = registry.TokenMapTypesAndInstances()
 		printType("Token Map", types, instances)
 
+		types, instances = registry.SynonymMapTypesAndInstances()
+		printType("Synonym Map", types, instances)
+
 		types, instances = registry.TokenFilterTypesAndInstances()
 		printType("Token Filter", types, instances)
 
@@ -65,6 +68,9 @@ var registryCmd = &cobra.Command{
 
 		types, instances = registry.HighlighterTypesAndInstances()
 		printType("Highlighter", types, instances)
+
+		types, instances = registry.SimilarityTypesAndInstances()
+		printType("Similarity", types, instances)
 	},
 }
 
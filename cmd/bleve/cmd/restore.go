@@ -0,0 +1,70 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/edwindvinas/bleve"
+	"github.com/spf13/cobra"
+)
+
+var restoreStoreType string
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [index path] [backup file]",
+	Short: "restores an index from a backup",
+	Long: `The restore command creates a new index from a backup written by the backup
+command, reading from stdin if the backup file is omitted or is "-".`,
+	Annotations: map[string]string{
+		canMutateBleveIndex: "true",
+	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// override RootCmd version which opens existing index
+		if len(args) < 1 {
+			return fmt.Errorf("must specify path to index")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = os.Stdin
+		if len(args) > 1 && args[1] != "-" {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return fmt.Errorf("error opening backup file: %v", err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			r = f
+		}
+		var err error
+		idx, err = bleve.Restore(r, args[0], restoreStoreType, nil)
+		if err != nil {
+			return fmt.Errorf("error restoring index: %v", err)
+		}
+		// the inheritted Post action will close the index
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().StringVarP(&restoreStoreType, "store", "s", "", "The bleve storage type to restore into, defaults to the storage type of the backup.")
+}
@@ -0,0 +1,362 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search/completion"
+)
+
+// DefaultCompletionSize is the number of completions returned when the
+// CompletionRequest does not specify a size.
+const DefaultCompletionSize = 10
+
+// A CompletionRequest asks for the highest weighted completions of
+// Prefix, from the inputs indexed in Field, which must be mapped with a
+// completion field mapping.
+// Fuzziness allows the prefix to be up to that many edits away from the
+// start of the completions, to tolerate typing mistakes.
+type CompletionRequest struct {
+	Prefix    string `json:"prefix"`
+	Field     string `json:"field"`
+	Size      int    `json:"size"`
+	Fuzziness int    `json:"fuzziness,omitempty"`
+}
+
+// NewCompletionRequest creates a CompletionRequest for the prefix using
+// the completion field, with default values for all other parameters.
+func NewCompletionRequest(prefix, field string) *CompletionRequest {
+	return &CompletionRequest{
+		Prefix: prefix,
+		Field:  field,
+		Size:   DefaultCompletionSize,
+	}
+}
+
+func (r *CompletionRequest) Validate() error {
+	if r.Field == "" {
+		return fmt.Errorf("completion field must be specified")
+	}
+	if r.Size < 0 {
+		return fmt.Errorf("completion size must not be negative")
+	}
+	if r.Fuzziness < 0 {
+		return fmt.Errorf("completion fuzziness must not be negative")
+	}
+	return nil
+}
+
+func (r *CompletionRequest) size() int {
+	if r.Size == 0 {
+		return DefaultCompletionSize
+	}
+	return r.Size
+}
+
+// A CompletionResult describes the results of executing
+// a CompletionRequest.
+type CompletionResult struct {
+	Request     *CompletionRequest     `json:"request"`
+	Completions completion.Completions `json:"completions"`
+	Took        time.Duration          `json:"took"`
+}
+
+// Complete returns the highest weighted completions of the requested
+// prefix. The completions of each field are kept in memory, loaded from
+// the internal storage when first requested, and then updated along with
+// it by the batches changing them.
+func (i *indexImpl) Complete(req *CompletionRequest) (*CompletionResult, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	start := time.Now()
+
+	if !i.open {
+		return nil, ErrorIndexClosed
+	}
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	indexReader, err := i.i.Reader()
+	if err != nil {
+		return nil, err
+	}
+	trie, err := i.completionTrie(indexReader, req.Field)
+	if cerr := indexReader.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the trie is updated in place when the index changes
+	i.completionMutex.RLock()
+	completions := trie.FuzzyTopK(strings.ToLower(req.Prefix),
+		req.Fuzziness, req.size())
+	i.completionMutex.RUnlock()
+
+	return &CompletionResult{
+		Request:     req,
+		Completions: completions,
+		Took:        time.Since(start),
+	}, nil
+}
+
+// The completions are kept out of the term dictionary.  The trie of each
+// completion field is kept in the internal storage, along with the
+// completions of each document, so that they can be removed when the
+// document is updated or deleted.
+var (
+	completionTrieInternalPrefix = []byte("_completion_trie/")
+	completionDocInternalPrefix  = []byte("_completion_doc/")
+)
+
+func completionTrieInternalKey(field string) []byte {
+	return append(append([]byte(nil), completionTrieInternalPrefix...), field...)
+}
+
+func completionDocInternalKey(id string) []byte {
+	return append(append([]byte(nil), completionDocInternalPrefix...), id...)
+}
+
+type completionIndexMapping interface {
+	HasCompletion() bool
+}
+
+// hasCompletion returns true if the mapping can create completion fields.
+func hasCompletion(m mapping.IndexMapping) bool {
+	if cm, ok := m.(completionIndexMapping); ok {
+		return cm.HasCompletion()
+	}
+	return false
+}
+
+// completionTrie returns the trie of the field, loading it from the
+// internal storage seen by the reader when it is not in memory yet.
+func (i *indexImpl) completionTrie(indexReader index.IndexReader, field string) (*completion.Trie, error) {
+	i.completionMutex.RLock()
+	trie := i.completionTries[field]
+	i.completionMutex.RUnlock()
+	if trie != nil {
+		return trie, nil
+	}
+
+	val, err := indexReader.GetInternal(completionTrieInternalKey(field))
+	if err != nil {
+		return nil, err
+	}
+	trie = completion.NewTrie()
+	if len(val) > 0 {
+		err = trie.UnmarshalBinary(val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	i.completionMutex.Lock()
+	defer i.completionMutex.Unlock()
+	// batches load the trie before changing it, keep theirs
+	if existing := i.completionTries[field]; existing != nil {
+		return existing, nil
+	}
+	if i.completionTries == nil {
+		i.completionTries = make(map[string]*completion.Trie)
+	}
+	i.completionTries[field] = trie
+	return trie, nil
+}
+
+// completionEntry is an input of a completion field with its weight.
+type completionEntry struct {
+	field  string
+	input  string
+	weight uint64
+}
+
+func (e *completionEntry) key() string {
+	return strings.ToLower(e.input)
+}
+
+// encodeCompletionEntries encodes the completions of a document.
+func encodeCompletionEntries(entries []completionEntry) []byte {
+	var rv []byte
+	var tmp [binary.MaxVarintLen64]byte
+	for _, e := range entries {
+		for _, s := range []string{e.field, e.input} {
+			l := binary.PutUvarint(tmp[:], uint64(len(s)))
+			rv = append(rv, tmp[:l]...)
+			rv = append(rv, s...)
+		}
+		l := binary.PutUvarint(tmp[:], e.weight)
+		rv = append(rv, tmp[:l]...)
+	}
+	return rv
+}
+
+func decodeCompletionEntries(val []byte) ([]completionEntry, error) {
+	var rv []completionEntry
+	next := func() (uint64, error) {
+		v, l := binary.Uvarint(val)
+		if l <= 0 {
+			return 0, fmt.Errorf("invalid document completions")
+		}
+		val = val[l:]
+		return v, nil
+	}
+	for len(val) > 0 {
+		var parts [2]string
+		for j := range parts {
+			n, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if n > uint64(len(val)) {
+				return nil, fmt.Errorf("invalid document completions")
+			}
+			parts[j] = string(val[:n])
+			val = val[n:]
+		}
+		weight, err := next()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, completionEntry{field: parts[0], input: parts[1], weight: weight})
+	}
+	return rv, nil
+}
+
+// documentCompletions returns the completions of the fields of doc.
+func documentCompletions(doc *document.Document) []completionEntry {
+	var rv []completionEntry
+	for _, f := range doc.Fields {
+		if cf, ok := f.(*document.CompletionField); ok {
+			_, input, weight, err := document.DecodeCompletionTerm(cf.Value())
+			if err == nil {
+				rv = append(rv, completionEntry{field: cf.Name(), input: input, weight: weight})
+			}
+		}
+	}
+	return rv
+}
+
+// batchCompletion executes the batch, along with the updates of the
+// completions of its documents, in memory and in the internal storage.
+func (i *indexImpl) batchCompletion(b *index.Batch) error {
+	// the completions of the documents are read before the batch is
+	// executed, and the tries are changed in place
+	i.completionBatchMutex.Lock()
+	defer i.completionBatchMutex.Unlock()
+
+	indexReader, err := i.i.Reader()
+	if err != nil {
+		return err
+	}
+	removed, added, err := i.changeCompletions(indexReader, b)
+	if cerr := indexReader.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = i.i.Batch(b)
+	if err != nil {
+		// undo the changes of the tries
+		i.applyCompletions(added, removed)
+	}
+	return err
+}
+
+// changeCompletions adds the changes of the stored completions to the
+// batch, and applies them to the tries, returning the completions
+// removed and added.
+func (i *indexImpl) changeCompletions(indexReader index.IndexReader, b *index.Batch) (removed, added []completionEntry, err error) {
+	for id, doc := range b.IndexOps {
+		docKey := completionDocInternalKey(id)
+		val, err := indexReader.GetInternal(docKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		old, err := decodeCompletionEntries(val)
+		if err != nil {
+			return nil, nil, err
+		}
+		var entries []completionEntry
+		if doc != nil {
+			entries = documentCompletions(doc)
+		}
+		if len(entries) > 0 {
+			b.SetInternal(docKey, encodeCompletionEntries(entries))
+		} else if len(old) > 0 {
+			b.DeleteInternal(docKey)
+		}
+		removed = append(removed, old...)
+		added = append(added, entries...)
+	}
+
+	fields := make(map[string]*completion.Trie)
+	for _, entries := range [][]completionEntry{removed, added} {
+		for _, e := range entries {
+			if _, ok := fields[e.field]; ok {
+				continue
+			}
+			fields[e.field], err = i.completionTrie(indexReader, e.field)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	i.applyCompletions(removed, added)
+
+	i.completionMutex.RLock()
+	for field, trie := range fields {
+		var val []byte
+		val, err = trie.MarshalBinary()
+		if err != nil {
+			break
+		}
+		b.SetInternal(completionTrieInternalKey(field), val)
+	}
+	i.completionMutex.RUnlock()
+	if err != nil {
+		i.applyCompletions(added, removed)
+		return nil, nil, err
+	}
+	return removed, added, nil
+}
+
+// applyCompletions removes and adds completions to the tries, which
+// must have been loaded.
+func (i *indexImpl) applyCompletions(removed, added []completionEntry) {
+	i.completionMutex.Lock()
+	defer i.completionMutex.Unlock()
+	for _, e := range removed {
+		i.completionTries[e.field].Remove(e.key(), e.input, e.weight)
+	}
+	for _, e := range added {
+		i.completionTries[e.field].Insert(e.key(), e.input, e.weight)
+	}
+}
@@ -25,6 +25,10 @@ import (
 	"github.com/edwindvinas/bleve/index/upsidedown"
 	"github.com/edwindvinas/bleve/registry"
 	"github.com/edwindvinas/bleve/search/highlight/highlighter/html"
+
+	// built-in similarities
+	_ "github.com/edwindvinas/bleve/search/similarity/bm25"
+	_ "github.com/edwindvinas/bleve/search/similarity/tfidf"
 )
 
 var bleveExpVar = expvar.NewMap("bleve")
@@ -18,6 +18,9 @@ import (
 	// token maps
 	_ "github.com/edwindvinas/bleve/analysis/tokenmap"
 
+	// synonym maps
+	_ "github.com/edwindvinas/bleve/analysis/synonymmap"
+
 	// fragment formatters
 	_ "github.com/edwindvinas/bleve/search/highlight/format/ansi"
 	_ "github.com/edwindvinas/bleve/search/highlight/format/html"
@@ -30,6 +33,10 @@ import (
 	_ "github.com/edwindvinas/bleve/search/highlight/highlighter/html"
 	_ "github.com/edwindvinas/bleve/search/highlight/highlighter/simple"
 
+	// similarities
+	_ "github.com/edwindvinas/bleve/search/similarity/bm25"
+	_ "github.com/edwindvinas/bleve/search/similarity/tfidf"
+
 	// char filters
 	_ "github.com/edwindvinas/bleve/analysis/char/html"
 	_ "github.com/edwindvinas/bleve/analysis/char/regexp"
@@ -47,14 +54,19 @@ import (
 	_ "github.com/edwindvinas/bleve/analysis/token/compound"
 	_ "github.com/edwindvinas/bleve/analysis/token/edgengram"
 	_ "github.com/edwindvinas/bleve/analysis/token/elision"
+	_ "github.com/edwindvinas/bleve/analysis/token/folding"
 	_ "github.com/edwindvinas/bleve/analysis/token/keyword"
 	_ "github.com/edwindvinas/bleve/analysis/token/length"
 	_ "github.com/edwindvinas/bleve/analysis/token/lowercase"
 	_ "github.com/edwindvinas/bleve/analysis/token/ngram"
 	_ "github.com/edwindvinas/bleve/analysis/token/shingle"
+	_ "github.com/edwindvinas/bleve/analysis/token/snowball"
 	_ "github.com/edwindvinas/bleve/analysis/token/stop"
+	_ "github.com/edwindvinas/bleve/analysis/token/synonym"
+	_ "github.com/edwindvinas/bleve/analysis/token/transliterate"
 	_ "github.com/edwindvinas/bleve/analysis/token/truncate"
 	_ "github.com/edwindvinas/bleve/analysis/token/unicodenorm"
+	_ "github.com/edwindvinas/bleve/analysis/token/worddelimiter"
 
 	// tokenizers
 	_ "github.com/edwindvinas/bleve/analysis/tokenizer/exception"
@@ -75,8 +87,10 @@ import (
 	_ "github.com/edwindvinas/bleve/analysis/lang/cjk"
 	_ "github.com/edwindvinas/bleve/analysis/lang/ckb"
 	_ "github.com/edwindvinas/bleve/analysis/lang/cs"
+	_ "github.com/edwindvinas/bleve/analysis/lang/de"
 	_ "github.com/edwindvinas/bleve/analysis/lang/el"
 	_ "github.com/edwindvinas/bleve/analysis/lang/en"
+	_ "github.com/edwindvinas/bleve/analysis/lang/es"
 	_ "github.com/edwindvinas/bleve/analysis/lang/eu"
 	_ "github.com/edwindvinas/bleve/analysis/lang/fa"
 	_ "github.com/edwindvinas/bleve/analysis/lang/fr"
@@ -87,7 +101,12 @@ import (
 	_ "github.com/edwindvinas/bleve/analysis/lang/id"
 	_ "github.com/edwindvinas/bleve/analysis/lang/in"
 	_ "github.com/edwindvinas/bleve/analysis/lang/it"
+	_ "github.com/edwindvinas/bleve/analysis/lang/ja"
+	_ "github.com/edwindvinas/bleve/analysis/lang/nl"
 	_ "github.com/edwindvinas/bleve/analysis/lang/pt"
+	_ "github.com/edwindvinas/bleve/analysis/lang/ru"
+	_ "github.com/edwindvinas/bleve/analysis/lang/sv"
+	_ "github.com/edwindvinas/bleve/analysis/lang/zh"
 
 	// kv stores
 	_ "github.com/edwindvinas/bleve/index/store/boltdb"
@@ -21,6 +21,10 @@ type Document struct {
 	Fields          []Field `json:"fields"`
 	CompositeFields []*CompositeField
 	Number          uint64 `json:"-"`
+
+	// Nested holds the documents created for the elements of nested
+	// sub-documents, indexed alongside this one
+	Nested []*Document `json:"-"`
 }
 
 func NewDocument(id string) *Document {
@@ -0,0 +1,140 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/edwindvinas/bleve/analysis"
+)
+
+const DefaultCompletionIndexingOptions = IndexField
+
+// CompletionSeparator separates the parts of an encoded completion,
+// it can never occur in valid UTF-8 text.
+const CompletionSeparator = 0xff
+
+// CompletionField holds one input of a completion (type-ahead) field,
+// along with its weight. Its value is made of the lower cased input,
+// the input itself and the weight.  It has no terms, the completions
+// are kept in a separate prefix structure instead of the term
+// dictionary.
+type CompletionField struct {
+	name              string
+	arrayPositions    []uint64
+	options           IndexingOptions
+	value             []byte
+	numPlainTextBytes uint64
+}
+
+func (c *CompletionField) Name() string {
+	return c.name
+}
+
+func (c *CompletionField) ArrayPositions() []uint64 {
+	return c.arrayPositions
+}
+
+func (c *CompletionField) Options() IndexingOptions {
+	return c.options
+}
+
+func (c *CompletionField) Analyze() (int, analysis.TokenFrequencies) {
+	return 0, analysis.TokenFrequencies{}
+}
+
+func (c *CompletionField) Value() []byte {
+	return c.value
+}
+
+// Input returns the text which is suggested as a completion.
+func (c *CompletionField) Input() (string, error) {
+	_, input, _, err := DecodeCompletionTerm(c.value)
+	return input, err
+}
+
+// Weight returns the weight used to rank this completion.
+func (c *CompletionField) Weight() (uint64, error) {
+	_, _, weight, err := DecodeCompletionTerm(c.value)
+	return weight, err
+}
+
+func (c *CompletionField) GoString() string {
+	key, input, weight, err := DecodeCompletionTerm(c.value)
+	if err != nil {
+		return fmt.Sprintf("&document.CompletionField{Name:%s, Options: %s, Value: %q}", c.name, c.options, c.value)
+	}
+	return fmt.Sprintf("&document.CompletionField{Name:%s, Options: %s, Key: %s, Input: %s, Weight: %d}", c.name, c.options, key, input, weight)
+}
+
+func (c *CompletionField) NumPlainTextBytes() uint64 {
+	return c.numPlainTextBytes
+}
+
+// EncodeCompletionTerm builds the value of a completion for the input and
+// weight.
+func EncodeCompletionTerm(input string, weight uint64) []byte {
+	key := strings.ToLower(input)
+	rv := make([]byte, 0, len(key)+len(input)+10)
+	rv = append(rv, key...)
+	rv = append(rv, CompletionSeparator)
+	rv = append(rv, input...)
+	rv = append(rv, CompletionSeparator)
+	var weightBytes [8]byte
+	binary.BigEndian.PutUint64(weightBytes[:], weight)
+	return append(rv, weightBytes[:]...)
+}
+
+// DecodeCompletionTerm returns the lower cased key, the input and the
+// weight of an encoded completion.
+func DecodeCompletionTerm(term []byte) (key, input string, weight uint64, err error) {
+	if len(term) < 10 || term[len(term)-9] != CompletionSeparator {
+		return "", "", 0, fmt.Errorf("invalid completion %q", term)
+	}
+	weight = binary.BigEndian.Uint64(term[len(term)-8:])
+	rest := term[:len(term)-9]
+	sep := bytes.IndexByte(rest, CompletionSeparator)
+	if sep < 0 {
+		return "", "", 0, fmt.Errorf("invalid completion %q", term)
+	}
+	return string(rest[:sep]), string(rest[sep+1:]), weight, nil
+}
+
+func NewCompletionFieldFromBytes(name string, arrayPositions []uint64, value []byte) *CompletionField {
+	return &CompletionField{
+		name:              name,
+		arrayPositions:    arrayPositions,
+		value:             value,
+		options:           DefaultCompletionIndexingOptions,
+		numPlainTextBytes: uint64(len(value)),
+	}
+}
+
+func NewCompletionField(name string, arrayPositions []uint64, input string, weight uint64) *CompletionField {
+	return NewCompletionFieldWithIndexingOptions(name, arrayPositions, input, weight, DefaultCompletionIndexingOptions)
+}
+
+func NewCompletionFieldWithIndexingOptions(name string, arrayPositions []uint64, input string, weight uint64, options IndexingOptions) *CompletionField {
+	return &CompletionField{
+		name:              name,
+		arrayPositions:    arrayPositions,
+		value:             EncodeCompletionTerm(input, weight),
+		options:           options,
+		numPlainTextBytes: uint64(len(input)),
+	}
+}
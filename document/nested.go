@@ -0,0 +1,54 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NestedSeparator separates the identifier of a document from the
+// suffix identifying one of its nested documents. Nested documents
+// thereby sort right after their parent, and the identifiers of
+// documents with nested documents must not contain it.
+const NestedSeparator = "\x00"
+
+// NestedPathField is the field holding the path of the nested mapping
+// a nested document was created for.
+const NestedPathField = "_nested"
+
+// NewNestedDocument creates the n-th nested document of the parent, for
+// an element found at the path.
+func NewNestedDocument(parent *Document, path string, n int) *Document {
+	id := parent.ID + NestedSeparator + path + NestedSeparator + strconv.Itoa(n)
+	rv := NewDocument(id)
+	rv.AddField(NewTextFieldWithIndexingOptions(NestedPathField, nil, []byte(path), IndexField))
+	return rv
+}
+
+// IsNestedID returns true when id identifies a nested document.
+func IsNestedID(id string) bool {
+	return strings.Contains(id, NestedSeparator)
+}
+
+// NestedParentID returns the identifier of the top level document
+// holding the nested document identified by id, or id itself if it
+// does not identify a nested document.
+func NestedParentID(id string) string {
+	if i := strings.Index(id, NestedSeparator); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
@@ -27,6 +27,8 @@ const (
 	ErrorUnknownIndexType
 	ErrorEmptyID
 	ErrorIndexReadInconsistency
+	ErrorSnapshotNotFound
+	ErrorNestedID
 )
 
 // Error represents a more strongly typed bleve error for detecting
@@ -49,4 +51,6 @@ var errorMessages = map[Error]string{
 	ErrorUnknownIndexType:       "unknown index type",
 	ErrorEmptyID:                "document ID cannot be empty",
 	ErrorIndexReadInconsistency: "index read inconsistency detected",
+	ErrorSnapshotNotFound:       "snapshot not found, it may have expired",
+	ErrorNestedID:               "document ID cannot contain the nested document separator",
 }
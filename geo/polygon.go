@@ -0,0 +1,71 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+// PolygonContains checks whether the lon/lat point is within the
+// polygon described by the ring of [lon, lat] vertices. The ring may
+// be open or closed (first vertex repeated as the last), edges are
+// treated as straight lines in lon/lat space.
+func PolygonContains(lon, lat float64, ring [][]float64) bool {
+	inside := false
+	j := len(ring) - 1
+	for i := 0; i < len(ring); i++ {
+		iLon, iLat := ring[i][0], ring[i][1]
+		jLon, jLat := ring[j][0], ring[j][1]
+		// count the edges crossed by a ray extending east of the point
+		if (iLat > lat) != (jLat > lat) &&
+			lon < (jLon-iLon)*(lat-iLat)/(jLat-iLat)+iLon {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
+
+// PolygonWithHolesContains checks whether the lon/lat point is within
+// the outer ring of the polygon, and not within any of its holes.
+func PolygonWithHolesContains(lon, lat float64, outer [][]float64,
+	holes [][][]float64) bool {
+	if !PolygonContains(lon, lat, outer) {
+		return false
+	}
+	for _, hole := range holes {
+		if PolygonContains(lon, lat, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+// PolygonBoundingBox computes the bounding box of the ring of
+// [lon, lat] vertices.
+func PolygonBoundingBox(ring [][]float64) (minLon, minLat,
+	maxLon, maxLat float64) {
+	for i, point := range ring {
+		if i == 0 || point[0] < minLon {
+			minLon = point[0]
+		}
+		if i == 0 || point[0] > maxLon {
+			maxLon = point[0]
+		}
+		if i == 0 || point[1] < minLat {
+			minLat = point[1]
+		}
+		if i == 0 || point[1] > maxLat {
+			maxLat = point[1]
+		}
+	}
+	return
+}
@@ -0,0 +1,60 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import "testing"
+
+func TestPolygonContains(t *testing.T) {
+	square := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	closedSquare := [][]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	// concave "L" shape
+	ell := [][]float64{{0, 0}, {10, 0}, {10, 3}, {3, 3}, {3, 10}, {0, 10}}
+	hole := [][]float64{{4, 4}, {6, 4}, {6, 6}, {4, 6}}
+
+	tests := []struct {
+		lon, lat float64
+		ring     [][]float64
+		holes    [][][]float64
+		want     bool
+	}{
+		{5, 5, square, nil, true},
+		{5, 5, closedSquare, nil, true},
+		{-1, 5, square, nil, false},
+		{5, 11, square, nil, false},
+		{1, 1, ell, nil, true},
+		{8, 1, ell, nil, true},
+		{1, 8, ell, nil, true},
+		{8, 8, ell, nil, false},
+		{5, 5, square, [][][]float64{hole}, false},
+		{2, 2, square, [][][]float64{hole}, true},
+		{-122.4, 37.8, [][]float64{{-123, 37}, {-122, 37}, {-122, 38}, {-123, 38}}, nil, true},
+	}
+
+	for _, test := range tests {
+		got := PolygonWithHolesContains(test.lon, test.lat, test.ring, test.holes)
+		if got != test.want {
+			t.Errorf("expected %t for point %f,%f in %v holes %v, got %t",
+				test.want, test.lon, test.lat, test.ring, test.holes, got)
+		}
+	}
+}
+
+func TestPolygonBoundingBox(t *testing.T) {
+	minLon, minLat, maxLon, maxLat := PolygonBoundingBox(
+		[][]float64{{-5, 2}, {3, -1}, {7, 4}, {1, 9}})
+	if minLon != -5 || minLat != -1 || maxLon != 7 || maxLat != 9 {
+		t.Errorf("expected -5,-1,7,9 got %f,%f,%f,%f", minLon, minLat, maxLon, maxLat)
+	}
+}
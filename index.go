@@ -15,6 +15,9 @@
 package bleve
 
 import (
+	"io"
+	"time"
+
 	"github.com/edwindvinas/bleve/document"
 	"github.com/edwindvinas/bleve/index"
 	"github.com/edwindvinas/bleve/index/store"
@@ -40,8 +43,12 @@ func (b *Batch) Index(id string, data interface{}) error {
 	if id == "" {
 		return ErrorEmptyID
 	}
+	err := checkNestedID(b.index.Mapping(), id)
+	if err != nil {
+		return err
+	}
 	doc := document.NewDocument(id)
-	err := b.index.Mapping().MapDocument(doc, data)
+	err = b.index.Mapping().MapDocument(doc, data)
 	if err != nil {
 		return err
 	}
@@ -171,6 +178,23 @@ type Index interface {
 	Search(req *SearchRequest) (*SearchResult, error)
 	SearchInContext(ctx context.Context, req *SearchRequest) (*SearchResult, error)
 
+	// Complete returns the highest weighted completions of a prefix,
+	// using the inputs of a field mapped with a completion field mapping.
+	Complete(req *CompletionRequest) (*CompletionResult, error)
+
+	// OpenSnapshot pins the current state of the index, returning an ID
+	// which SearchRequest.Snapshot and DocumentInSnapshot use to read a
+	// consistent view of the index across requests. The snapshot is
+	// released after it goes unused for the keep alive duration.
+	OpenSnapshot(keepAlive time.Duration) (string, error)
+	// RenewSnapshot extends the life of the snapshot.
+	RenewSnapshot(id string, keepAlive time.Duration) error
+	// CloseSnapshot releases the snapshot.
+	CloseSnapshot(id string) error
+	// DocumentInSnapshot returns the specified document as it was when
+	// the snapshot was opened.
+	DocumentInSnapshot(snapshotID, id string) (*document.Document, error)
+
 	Fields() ([]string, error)
 
 	FieldDict(field string) (index.FieldDict, error)
@@ -193,6 +217,10 @@ type Index interface {
 	// SetName lets you assign your own logical name to this index
 	SetName(string)
 
+	// Backup writes a consistent copy of the index to w, which can be
+	// restored using Restore.
+	Backup(w io.Writer) error
+
 	// Advanced returns the indexer and data store, exposing lower level
 	// methods to enumerate records and access data.
 	Advanced() (index.Index, store.KVStore, error)
@@ -84,6 +84,30 @@ type IndexReader interface {
 	Close() error
 }
 
+// FieldStats describes the lengths of an indexed field across all of
+// the documents containing it
+type FieldStats struct {
+	DocCount    uint64
+	TotalLength uint64
+}
+
+// AvgLength returns the average length of the field, or 0 when no
+// documents contain it
+func (fs *FieldStats) AvgLength() float64 {
+	if fs == nil || fs.DocCount == 0 {
+		return 0
+	}
+	return float64(fs.TotalLength) / float64(fs.DocCount)
+}
+
+// IndexReaderFieldStats is an optional interface implemented by
+// IndexReaders which track per field length statistics
+type IndexReaderFieldStats interface {
+	// FieldStats returns the statistics for the named field, or nil
+	// if none are available
+	FieldStats(field string) (*FieldStats, error)
+}
+
 // FieldTerms contains the terms used by a document, keyed by field
 type FieldTerms map[string][]string
 
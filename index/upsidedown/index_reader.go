@@ -177,6 +177,30 @@ func (i *IndexReader) DocCount() (uint64, error) {
 	return i.docCount, nil
 }
 
+func (i *IndexReader) FieldStats(fieldName string) (*index.FieldStats, error) {
+	fieldIndex, fieldExists := i.index.fieldCache.FieldNamed(fieldName, false)
+	if !fieldExists {
+		return nil, nil
+	}
+	var keyBuf [3]byte
+	keySize := fieldStatsRowKeyTo(keyBuf[:], uint16(fieldIndex))
+	val, err := i.kvreader.Get(keyBuf[:keySize])
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+	row, err := NewFieldStatsRowKV(keyBuf[:keySize], val)
+	if err != nil {
+		return nil, err
+	}
+	return &index.FieldStats{
+		DocCount:    row.docCount,
+		TotalLength: row.totalLength,
+	}, nil
+}
+
 func (i *IndexReader) Close() error {
 	return i.kvreader.Close()
 }
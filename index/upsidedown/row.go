@@ -54,6 +54,8 @@ func ParseFromKeyValue(key, value []byte) (UpsideDownCouchRow, error) {
 			return NewStoredRowKV(key, value)
 		case 'i':
 			return NewInternalRowKV(key, value)
+		case 'l':
+			return NewFieldStatsRowKV(key, value)
 		}
 		return nil, fmt.Errorf("Unknown field type '%s'", string(key[0]))
 	}
@@ -237,6 +239,102 @@ func NewFieldRowKV(key, value []byte) (*FieldRow, error) {
 	return &rv, nil
 }
 
+// FIELD STATS
+
+const FieldStatsRowMaxValueSize = 2 * binary.MaxVarintLen64
+
+// FieldStatsRow tracks the number of documents containing a field and the
+// sum of the lengths of that field across those documents.
+type FieldStatsRow struct {
+	field       uint16
+	docCount    uint64
+	totalLength uint64
+}
+
+func (fs *FieldStatsRow) Key() []byte {
+	buf := make([]byte, fs.KeySize())
+	size, _ := fs.KeyTo(buf)
+	return buf[:size]
+}
+
+func (fs *FieldStatsRow) KeySize() int {
+	return 3
+}
+
+func (fs *FieldStatsRow) KeyTo(buf []byte) (int, error) {
+	return fieldStatsRowKeyTo(buf, fs.field), nil
+}
+
+func fieldStatsRowKeyTo(buf []byte, field uint16) int {
+	buf[0] = 'l'
+	binary.LittleEndian.PutUint16(buf[1:3], field)
+	return 3
+}
+
+func (fs *FieldStatsRow) Value() []byte {
+	buf := make([]byte, fs.ValueSize())
+	size, _ := fs.ValueTo(buf)
+	return buf[:size]
+}
+
+func (fs *FieldStatsRow) ValueSize() int {
+	return FieldStatsRowMaxValueSize
+}
+
+func (fs *FieldStatsRow) ValueTo(buf []byte) (int, error) {
+	used := binary.PutUvarint(buf, fs.docCount)
+	used += binary.PutUvarint(buf[used:], fs.totalLength)
+	return used, nil
+}
+
+func (fs *FieldStatsRow) String() string {
+	return fmt.Sprintf("Field Stats: %d DocCount: %d TotalLength: %d", fs.field, fs.docCount, fs.totalLength)
+}
+
+func NewFieldStatsRow(field uint16, docCount, totalLength uint64) *FieldStatsRow {
+	return &FieldStatsRow{
+		field:       field,
+		docCount:    docCount,
+		totalLength: totalLength,
+	}
+}
+
+func NewFieldStatsRowKV(key, value []byte) (*FieldStatsRow, error) {
+	rv, err := NewFieldStatsRowK(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rv.parseFieldStatsV(value)
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func NewFieldStatsRowK(key []byte) (*FieldStatsRow, error) {
+	if len(key) < 3 {
+		return nil, fmt.Errorf("invalid field stats row key")
+	}
+	return &FieldStatsRow{
+		field: binary.LittleEndian.Uint16(key[1:3]),
+	}, nil
+}
+
+func (fs *FieldStatsRow) parseFieldStatsV(value []byte) error {
+	docCount, nread := binary.Uvarint(value)
+	if nread <= 0 {
+		return fmt.Errorf("FieldStatsRow parse Uvarint error, nread: %d", nread)
+	}
+	totalLength, nread2 := binary.Uvarint(value[nread:])
+	if nread2 <= 0 {
+		return fmt.Errorf("FieldStatsRow parse Uvarint error, nread: %d", nread2)
+	}
+	fs.docCount = docCount
+	fs.totalLength = totalLength
+	return nil
+}
+
 // DICTIONARY
 
 const DictionaryRowMaxValueSize = binary.MaxVarintLen64
@@ -553,6 +651,20 @@ func (tfr *TermFrequencyRow) parseK(key []byte) error {
 	return nil
 }
 
+// newTermFrequencyRowKDoc parses the key of a term frequency row of the
+// document doc, whose term may itself contain the byte separator.
+func newTermFrequencyRowKDoc(key, doc []byte) (*TermFrequencyRow, error) {
+	if len(key) < 3+1+len(doc) || !bytes.HasSuffix(key, doc) ||
+		key[len(key)-len(doc)-1] != ByteSeparator {
+		return nil, fmt.Errorf("invalid term frequency key for doc %q", doc)
+	}
+	return &TermFrequencyRow{
+		field: binary.LittleEndian.Uint16(key[1:3]),
+		term:  key[3 : len(key)-len(doc)-1],
+		doc:   doc,
+	}, nil
+}
+
 func (tfr *TermFrequencyRow) parseKDoc(key []byte, term []byte) error {
 	tfr.doc = key[3+len(term)+1:]
 	if len(tfr.doc) <= 0 {
@@ -34,6 +34,10 @@ func init() {
 type upsideDownMerge struct{}
 
 func (m *upsideDownMerge) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	if len(key) > 0 && key[0] == 'l' {
+		return m.fullMergeFieldStats(key, existingValue, operands)
+	}
+
 	// set up record based on key
 	dr, err := NewDictionaryRowK(key)
 	if err != nil {
@@ -64,6 +68,16 @@ func (m *upsideDownMerge) FullMerge(key, existingValue []byte, operands [][]byte
 }
 
 func (m *upsideDownMerge) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	if len(key) > 0 && key[0] == 'l' {
+		rv := make([]byte, fieldStatsDeltaSize)
+		for i := 0; i < fieldStatsDeltaSize; i += 8 {
+			left := int64(binary.LittleEndian.Uint64(leftOperand[i:]))
+			right := int64(binary.LittleEndian.Uint64(rightOperand[i:]))
+			binary.LittleEndian.PutUint64(rv[i:], uint64(left+right))
+		}
+		return rv, true
+	}
+
 	left := int64(binary.LittleEndian.Uint64(leftOperand))
 	right := int64(binary.LittleEndian.Uint64(rightOperand))
 	rv := make([]byte, 8)
@@ -74,3 +88,40 @@ func (m *upsideDownMerge) PartialMerge(key, leftOperand, rightOperand []byte) ([
 func (m *upsideDownMerge) Name() string {
 	return "upsideDownMerge"
 }
+
+// fieldStatsDeltaSize is the size of a field stats merge operand, a pair of
+// little endian int64 deltas for the doc count and the total field length
+const fieldStatsDeltaSize = 16
+
+func (m *upsideDownMerge) fullMergeFieldStats(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	fs, err := NewFieldStatsRowK(key)
+	if err != nil {
+		return nil, false
+	}
+	if len(existingValue) > 0 {
+		err = fs.parseFieldStatsV(existingValue)
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	for _, operand := range operands {
+		if len(operand) < fieldStatsDeltaSize {
+			return nil, false
+		}
+		fs.docCount = applyDelta(fs.docCount, int64(binary.LittleEndian.Uint64(operand)))
+		fs.totalLength = applyDelta(fs.totalLength, int64(binary.LittleEndian.Uint64(operand[8:])))
+	}
+
+	return fs.Value(), true
+}
+
+func applyDelta(val uint64, delta int64) uint64 {
+	if delta < 0 && uint64(-delta) > val {
+		// subtracting delta from existing would overflow
+		return 0
+	} else if delta < 0 {
+		return val - uint64(-delta)
+	}
+	return val + uint64(delta)
+}
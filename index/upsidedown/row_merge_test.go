@@ -50,6 +50,50 @@ func TestPartialMerge(t *testing.T) {
 
 }
 
+func TestFieldStatsMerge(t *testing.T) {
+	mo := &upsideDownMerge{}
+	key := NewFieldStatsRow(0, 0, 0).Key()
+
+	incr := make([]byte, fieldStatsDeltaSize)
+	binary.LittleEndian.PutUint64(incr, 1)
+	binary.LittleEndian.PutUint64(incr[8:], 5)
+	decr := make([]byte, fieldStatsDeltaSize)
+	negOne := int64(-1)
+	negThree := int64(-3)
+	binary.LittleEndian.PutUint64(decr, uint64(negOne))
+	binary.LittleEndian.PutUint64(decr[8:], uint64(negThree))
+
+	partial, ok := mo.PartialMerge(key, incr, incr)
+	if !ok {
+		t.Fatalf("expected partial merge ok")
+	}
+
+	val, ok := mo.FullMerge(key, nil, [][]byte{partial, decr})
+	if !ok {
+		t.Fatalf("expected full merge ok")
+	}
+	fs, err := NewFieldStatsRowKV(key, val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.docCount != 1 || fs.totalLength != 7 {
+		t.Errorf("expected doc count 1 and total length 7, got %d and %d", fs.docCount, fs.totalLength)
+	}
+
+	// further decrements must not underflow
+	val, ok = mo.FullMerge(key, val, [][]byte{decr, decr, decr})
+	if !ok {
+		t.Fatalf("expected full merge ok")
+	}
+	fs, err = NewFieldStatsRowKV(key, val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.docCount != 0 || fs.totalLength != 0 {
+		t.Errorf("expected doc count 0 and total length 0, got %d and %d", fs.docCount, fs.totalLength)
+	}
+}
+
 func decodeCount(in []byte) uint64 {
 	buf := bytes.NewBuffer(in)
 	count, _ := binary.ReadUvarint(buf)
@@ -53,6 +53,11 @@ func TestRows(t *testing.T) {
 			[]byte{'d', 0, 0, 'b', 'e', 'e', 'r'},
 			[]byte{27},
 		},
+		{
+			NewFieldStatsRow(1, 2, 300),
+			[]byte{'l', 1, 0},
+			[]byte{2, 172, 2},
+		},
 		{
 			NewTermFrequencyRow([]byte{'b', 'e', 'e', 'r'}, 0, []byte("catz"), 3, 3.14),
 			[]byte{'t', 0, 0, 'b', 'e', 'e', 'r', ByteSeparator, 'c', 'a', 't', 'z'},
@@ -85,6 +90,11 @@ func TestRows(t *testing.T) {
 			[]byte{'b', 'b', 'u', 'd', 'w', 'e', 'i', 's', 'e', 'r'},
 			[]byte{10, 8, 8, 0, 18, 4, 'b', 'e', 'e', 'r'},
 		},
+		{
+			NewBackIndexRow([]byte("budweiser"), []*BackIndexTermsEntry{{Field: proto.Uint32(0), Terms: []string{"beer"}, Length: proto.Uint64(3)}}, nil),
+			[]byte{'b', 'b', 'u', 'd', 'w', 'e', 'i', 's', 'e', 'r'},
+			[]byte{10, 10, 8, 0, 18, 4, 'b', 'e', 'e', 'r', 24, 3},
+		},
 		{
 			NewBackIndexRow([]byte("budweiser"), []*BackIndexTermsEntry{{Field: proto.Uint32(0), Terms: []string{"beer"}}, {Field: proto.Uint32(1), Terms: []string{"beat"}}}, nil),
 			[]byte{'b', 'b', 'u', 'd', 'w', 'e', 'i', 's', 'e', 'r'},
@@ -137,6 +147,26 @@ func TestRows(t *testing.T) {
 
 }
 
+func TestTermFrequencyRowKDoc(t *testing.T) {
+	term := []byte{'a', ByteSeparator, 'b'}
+	row := NewTermFrequencyRow(term, 2, []byte("doc"), 1, 1.0)
+	parsed, err := newTermFrequencyRowKDoc(row.Key(), []byte("doc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.term, term) || parsed.field != 2 || string(parsed.doc) != "doc" {
+		t.Errorf("expected term %q field 2 doc 'doc', got %q %d %q", term, parsed.term, parsed.field, parsed.doc)
+	}
+	if !reflect.DeepEqual(parsed.DictionaryRowKey(), row.DictionaryRowKey()) {
+		t.Errorf("expected dictionary key %q, got %q", row.DictionaryRowKey(), parsed.DictionaryRowKey())
+	}
+
+	_, err = newTermFrequencyRowKDoc(row.Key(), []byte("other"))
+	if err == nil {
+		t.Errorf("expected error parsing the key of another doc")
+	}
+}
+
 func TestInvalidRows(t *testing.T) {
 	tests := []struct {
 		key []byte
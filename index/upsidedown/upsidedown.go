@@ -90,7 +90,7 @@ func (udc *UpsideDownCouch) init(kvwriter store.KVWriter) (err error) {
 		{NewVersionRow(udc.version)},
 	}
 
-	err = udc.batchRows(kvwriter, nil, rowsAll, nil)
+	err = udc.batchRows(kvwriter, nil, rowsAll, nil, nil)
 	return
 }
 
@@ -147,7 +147,7 @@ func PutRowBuffer(buf []byte) {
 	rowBufferPool.Put(buf)
 }
 
-func (udc *UpsideDownCouch) batchRows(writer store.KVWriter, addRowsAll [][]UpsideDownCouchRow, updateRowsAll [][]UpsideDownCouchRow, deleteRowsAll [][]UpsideDownCouchRow) (err error) {
+func (udc *UpsideDownCouch) batchRows(writer store.KVWriter, addRowsAll [][]UpsideDownCouchRow, updateRowsAll [][]UpsideDownCouchRow, deleteRowsAll [][]UpsideDownCouchRow, fieldStats fieldStatsDeltas) (err error) {
 	dictionaryDeltas := make(map[string]int64)
 
 	// count up bytes needed for buffering.
@@ -212,13 +212,21 @@ func (udc *UpsideDownCouch) batchRows(writer store.KVWriter, addRowsAll [][]Upsi
 
 	PutRowBuffer(rowBuf)
 
-	mergeNum := len(dictionaryDeltas)
+	for field, delta := range fieldStats {
+		if delta.docCount == 0 && delta.totalLength == 0 {
+			delete(fieldStats, field)
+		}
+	}
+
+	mergeNum := len(dictionaryDeltas) + len(fieldStats)
 	mergeKeyBytes := 0
-	mergeValBytes := mergeNum * DictionaryRowMaxValueSize
+	mergeValBytes := len(dictionaryDeltas)*DictionaryRowMaxValueSize +
+		len(fieldStats)*fieldStatsDeltaSize
 
 	for dictRowKey := range dictionaryDeltas {
 		mergeKeyBytes += len(dictRowKey)
 	}
+	mergeKeyBytes += len(fieldStats) * 3
 
 	// prepare batch
 	totBytes := addKeyBytes + addValBytes +
@@ -288,10 +296,48 @@ func (udc *UpsideDownCouch) batchRows(writer store.KVWriter, addRowsAll [][]Upsi
 		buf = buf[dictRowKeyLen+DictionaryRowMaxValueSize:]
 	}
 
+	for field, delta := range fieldStats {
+		fieldStatsRowKeyLen := fieldStatsRowKeyTo(buf, field)
+		binary.LittleEndian.PutUint64(buf[fieldStatsRowKeyLen:], uint64(delta.docCount))
+		binary.LittleEndian.PutUint64(buf[fieldStatsRowKeyLen+8:], uint64(delta.totalLength))
+		wb.Merge(buf[:fieldStatsRowKeyLen], buf[fieldStatsRowKeyLen:fieldStatsRowKeyLen+fieldStatsDeltaSize])
+		buf = buf[fieldStatsRowKeyLen+fieldStatsDeltaSize:]
+	}
+
 	// write out the batch
 	return writer.ExecuteBatch(wb)
 }
 
+type fieldStatsDelta struct {
+	docCount    int64
+	totalLength int64
+}
+
+// fieldStatsDeltas accumulates the changes to the per field length
+// statistics caused by the back index rows added and removed in a batch
+type fieldStatsDeltas map[uint16]*fieldStatsDelta
+
+func (f fieldStatsDeltas) accumulate(backIndexRow *BackIndexRow, sign int64) {
+	if f == nil || backIndexRow == nil {
+		return
+	}
+	for _, termsEntry := range backIndexRow.termsEntries {
+		// entries written before field lengths were tracked are skipped,
+		// they were never counted
+		if termsEntry.Length == nil {
+			continue
+		}
+		field := uint16(termsEntry.GetField())
+		delta := f[field]
+		if delta == nil {
+			delta = &fieldStatsDelta{}
+			f[field] = delta
+		}
+		delta.docCount += sign
+		delta.totalLength += sign * int64(termsEntry.GetLength())
+	}
+}
+
 func (udc *UpsideDownCouch) Open() (err error) {
 	//acquire the write mutex for the duratin of Open()
 	udc.writeMutex.Lock()
@@ -470,7 +516,9 @@ func (udc *UpsideDownCouch) Update(doc *document.Document) (err error) {
 	var updateRowsAll [][]UpsideDownCouchRow
 	var deleteRowsAll [][]UpsideDownCouchRow
 
-	addRows, updateRows, deleteRows := udc.mergeOldAndNew(backIndexRow, result.Rows)
+	fieldStats := make(fieldStatsDeltas)
+
+	addRows, updateRows, deleteRows := udc.mergeOldAndNew(backIndexRow, result.Rows, fieldStats)
 	if len(addRows) > 0 {
 		addRowsAll = append(addRowsAll, addRows)
 	}
@@ -481,7 +529,7 @@ func (udc *UpsideDownCouch) Update(doc *document.Document) (err error) {
 		deleteRowsAll = append(deleteRowsAll, deleteRows)
 	}
 
-	err = udc.batchRows(kvwriter, addRowsAll, updateRowsAll, deleteRowsAll)
+	err = udc.batchRows(kvwriter, addRowsAll, updateRowsAll, deleteRowsAll, fieldStats)
 	if err == nil && backIndexRow == nil {
 		udc.m.Lock()
 		udc.docCount++
@@ -497,13 +545,19 @@ func (udc *UpsideDownCouch) Update(doc *document.Document) (err error) {
 	return
 }
 
-func (udc *UpsideDownCouch) mergeOldAndNew(backIndexRow *BackIndexRow, rows []index.IndexRow) (addRows []UpsideDownCouchRow, updateRows []UpsideDownCouchRow, deleteRows []UpsideDownCouchRow) {
+func (udc *UpsideDownCouch) mergeOldAndNew(backIndexRow *BackIndexRow, rows []index.IndexRow, fieldStats fieldStatsDeltas) (addRows []UpsideDownCouchRow, updateRows []UpsideDownCouchRow, deleteRows []UpsideDownCouchRow) {
 	addRows = make([]UpsideDownCouchRow, 0, len(rows))
 
+	// the old back index row is replaced by the new one
+	fieldStats.accumulate(backIndexRow, -1)
+
 	if backIndexRow == nil {
 		addRows = addRows[0:len(rows)]
 		for i, row := range rows {
 			addRows[i] = row
+			if row, ok := row.(*BackIndexRow); ok {
+				fieldStats.accumulate(row, 1)
+			}
 		}
 		return addRows, nil, nil
 	}
@@ -558,6 +612,9 @@ func (udc *UpsideDownCouch) mergeOldAndNew(backIndexRow *BackIndexRow, rows []in
 				}
 			}
 			addRows = append(addRows, row)
+		case *BackIndexRow:
+			fieldStats.accumulate(row, 1)
+			updateRows = append(updateRows, row)
 		default:
 			updateRows = append(updateRows, row)
 		}
@@ -566,7 +623,7 @@ func (udc *UpsideDownCouch) mergeOldAndNew(backIndexRow *BackIndexRow, rows []in
 
 	// any of the existing rows that weren't updated need to be deleted
 	for existingTermKey := range existingTermKeys {
-		termFreqRow, err := NewTermFrequencyRowK([]byte(existingTermKey))
+		termFreqRow, err := newTermFrequencyRowKDoc([]byte(existingTermKey), backIndexRow.doc)
 		if err == nil {
 			deleteRows = append(deleteRows, termFreqRow)
 		}
@@ -606,6 +663,8 @@ func encodeFieldType(f document.Field) byte {
 		fieldType = 'b'
 	case *document.GeoPointField:
 		fieldType = 'g'
+	case *document.CompletionField:
+		fieldType = 's'
 	case *document.CompositeField:
 		fieldType = 'c'
 	}
@@ -635,7 +694,7 @@ func (udc *UpsideDownCouch) indexField(docID []byte, includeTermVectors bool, fi
 
 		rows = append(rows, termFreqRow)
 	}
-	backIndexTermsEntry := BackIndexTermsEntry{Field: proto.Uint32(uint32(fieldIndex)), Terms: terms}
+	backIndexTermsEntry := BackIndexTermsEntry{Field: proto.Uint32(uint32(fieldIndex)), Terms: terms, Length: proto.Uint64(uint64(fieldLength))}
 	backIndexTermsEntries = append(backIndexTermsEntries, &backIndexTermsEntry)
 
 	return rows, backIndexTermsEntries
@@ -688,12 +747,14 @@ func (udc *UpsideDownCouch) Delete(id string) (err error) {
 
 	var deleteRowsAll [][]UpsideDownCouchRow
 
-	deleteRows := udc.deleteSingle(id, backIndexRow, nil)
+	fieldStats := make(fieldStatsDeltas)
+
+	deleteRows := udc.deleteSingle(id, backIndexRow, nil, fieldStats)
 	if len(deleteRows) > 0 {
 		deleteRowsAll = append(deleteRowsAll, deleteRows)
 	}
 
-	err = udc.batchRows(kvwriter, nil, nil, deleteRowsAll)
+	err = udc.batchRows(kvwriter, nil, nil, deleteRowsAll, fieldStats)
 	if err == nil {
 		udc.m.Lock()
 		udc.docCount--
@@ -708,8 +769,9 @@ func (udc *UpsideDownCouch) Delete(id string) (err error) {
 	return
 }
 
-func (udc *UpsideDownCouch) deleteSingle(id string, backIndexRow *BackIndexRow, deleteRows []UpsideDownCouchRow) []UpsideDownCouchRow {
+func (udc *UpsideDownCouch) deleteSingle(id string, backIndexRow *BackIndexRow, deleteRows []UpsideDownCouchRow, fieldStats fieldStatsDeltas) []UpsideDownCouchRow {
 	idBytes := []byte(id)
+	fieldStats.accumulate(backIndexRow, -1)
 
 	for _, backIndexEntry := range backIndexRow.termsEntries {
 		for i := range backIndexEntry.Terms {
@@ -739,6 +801,8 @@ func decodeFieldType(typ byte, name string, pos []uint64, value []byte) document
 		return document.NewBooleanFieldFromBytes(name, pos, value)
 	case 'g':
 		return document.NewGeoPointFieldFromBytes(name, pos, value)
+	case 's':
+		return document.NewCompletionFieldFromBytes(name, pos, value)
 	}
 	return nil
 }
@@ -899,17 +963,19 @@ func (udc *UpsideDownCouch) Batch(batch *index.Batch) (err error) {
 		deleteRowsAll = append(deleteRowsAll, deleteRows)
 	}
 
+	fieldStats := make(fieldStatsDeltas)
+
 	// process back index rows as they arrive
 	for dbir := range docBackIndexRowCh {
 		if dbir.doc == nil && dbir.backIndexRow != nil {
 			// delete
-			deleteRows := udc.deleteSingle(dbir.docID, dbir.backIndexRow, nil)
+			deleteRows := udc.deleteSingle(dbir.docID, dbir.backIndexRow, nil, fieldStats)
 			if len(deleteRows) > 0 {
 				deleteRowsAll = append(deleteRowsAll, deleteRows)
 			}
 			docsDeleted++
 		} else if dbir.doc != nil {
-			addRows, updateRows, deleteRows := udc.mergeOldAndNew(dbir.backIndexRow, newRowsMap[dbir.docID])
+			addRows, updateRows, deleteRows := udc.mergeOldAndNew(dbir.backIndexRow, newRowsMap[dbir.docID], fieldStats)
 			if len(addRows) > 0 {
 				addRowsAll = append(addRowsAll, addRows)
 			}
@@ -936,7 +1002,7 @@ func (udc *UpsideDownCouch) Batch(batch *index.Batch) (err error) {
 		return
 	}
 
-	err = udc.batchRows(kvwriter, addRowsAll, updateRowsAll, deleteRowsAll)
+	err = udc.batchRows(kvwriter, addRowsAll, updateRowsAll, deleteRowsAll, fieldStats)
 	if err != nil {
 		_ = kvwriter.Close()
 		atomic.AddUint64(&udc.stats.errors, 1)
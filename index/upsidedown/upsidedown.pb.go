@@ -29,6 +29,7 @@ var _ = math.Inf
 type BackIndexTermsEntry struct {
 	Field            *uint32  `protobuf:"varint,1,req,name=field" json:"field,omitempty"`
 	Terms            []string `protobuf:"bytes,2,rep,name=terms" json:"terms,omitempty"`
+	Length           *uint64  `protobuf:"varint,3,opt,name=length" json:"length,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -50,6 +51,13 @@ func (m *BackIndexTermsEntry) GetTerms() []string {
 	return nil
 }
 
+func (m *BackIndexTermsEntry) GetLength() uint64 {
+	if m != nil && m.Length != nil {
+		return *m.Length
+	}
+	return 0
+}
+
 type BackIndexStoreEntry struct {
 	Field            *uint32  `protobuf:"varint,1,req,name=field" json:"field,omitempty"`
 	ArrayPositions   []uint64 `protobuf:"varint,2,rep,name=arrayPositions" json:"arrayPositions,omitempty"`
@@ -158,6 +166,23 @@ func (m *BackIndexTermsEntry) Unmarshal(data []byte) error {
 			}
 			m.Terms = append(m.Terms, string(data[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Length", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Length = &v
 		default:
 			var sizeOfWire int
 			for {
@@ -479,6 +504,9 @@ func (m *BackIndexTermsEntry) Size() (n int) {
 			n += 1 + l + sovUpsidedown(uint64(l))
 		}
 	}
+	if m.Length != nil {
+		n += 1 + sovUpsidedown(uint64(*m.Length))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -573,6 +601,11 @@ func (m *BackIndexTermsEntry) MarshalTo(data []byte) (n int, err error) {
 			i += copy(data[i:], s)
 		}
 	}
+	if m.Length != nil {
+		data[i] = 0x18
+		i++
+		i = encodeVarintUpsidedown(data, i, uint64(*m.Length))
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
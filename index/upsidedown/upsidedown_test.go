@@ -169,8 +169,8 @@ func TestIndexInsert(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// should have 4 rows (1 for version, 1 for schema field, and 1 for single term, and 1 for the term count, and 1 for the back index entry)
-	expectedLength := uint64(1 + 1 + 1 + 1 + 1)
+	// should have 6 rows (1 for version, 1 for schema field, and 1 for single term, and 1 for the term count, and 1 for the back index entry, and 1 for the field stats)
+	expectedLength := uint64(1 + 1 + 1 + 1 + 1 + 1)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -297,8 +297,8 @@ func TestIndexInsertThenDelete(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// should have 2 rows (1 for version, 1 for schema field, 1 for dictionary row garbage)
-	expectedLength := uint64(1 + 1 + 1)
+	// should have 4 rows (1 for version, 1 for schema field, 1 for dictionary row garbage, 1 for the field stats)
+	expectedLength := uint64(1 + 1 + 1 + 1)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -347,8 +347,8 @@ func TestIndexInsertThenUpdate(t *testing.T) {
 		t.Errorf("Error deleting entry from index: %v", err)
 	}
 
-	// should have 2 rows (1 for version, 1 for schema field, and 2 for the two term, and 2 for the term counts, and 1 for the back index entry)
-	expectedLength := uint64(1 + 1 + 2 + 2 + 1)
+	// should have 8 rows (1 for version, 1 for schema field, and 2 for the two term, and 2 for the term counts, and 1 for the back index entry, and 1 for the field stats)
+	expectedLength := uint64(1 + 1 + 2 + 2 + 1 + 1)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -365,8 +365,8 @@ func TestIndexInsertThenUpdate(t *testing.T) {
 		t.Errorf("Error deleting entry from index: %v", err)
 	}
 
-	// should have 2 rows (1 for version, 1 for schema field, and 1 for the remaining term, and 2 for the term diciontary, and 1 for the back index entry)
-	expectedLength = uint64(1 + 1 + 1 + 2 + 1)
+	// should have 7 rows (1 for version, 1 for schema field, and 1 for the remaining term, and 2 for the term diciontary, and 1 for the back index entry, and 1 for the field stats)
+	expectedLength = uint64(1 + 1 + 1 + 2 + 1 + 1)
 	rowCount, err = idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -376,6 +376,100 @@ func TestIndexInsertThenUpdate(t *testing.T) {
 	}
 }
 
+func TestIndexFieldStats(t *testing.T) {
+	defer func() {
+		err := DestroyTest()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	analysisQueue := index.NewAnalysisQueue(1)
+	idx, err := NewUpsideDownCouch(boltdb.Name, boltTestConfig, analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Open()
+	if err != nil {
+		t.Errorf("error opening index: %v", err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	checkStats := func(expectedDocCount, expectedTotalLength uint64) {
+		reader, err := idx.Reader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := reader.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		fs, err := reader.(index.IndexReaderFieldStats).FieldStats("name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fs == nil {
+			t.Fatalf("expected field stats for name")
+		}
+		if fs.DocCount != expectedDocCount {
+			t.Errorf("expected doc count %d, got %d", expectedDocCount, fs.DocCount)
+		}
+		if fs.TotalLength != expectedTotalLength {
+			t.Errorf("expected total length %d, got %d", expectedTotalLength, fs.TotalLength)
+		}
+	}
+
+	doc := document.NewDocument("1")
+	doc.AddField(document.NewTextFieldWithAnalyzer("name", []uint64{}, []byte("test fail"), testAnalyzer))
+	err = idx.Update(doc)
+	if err != nil {
+		t.Errorf("Error updating index: %v", err)
+	}
+	checkStats(1, 2)
+
+	batch := index.NewBatch()
+	doc = document.NewDocument("2")
+	doc.AddField(document.NewTextFieldWithAnalyzer("name", []uint64{}, []byte("one two three four"), testAnalyzer))
+	batch.Update(doc)
+	doc = document.NewDocument("1")
+	doc.AddField(document.NewTextFieldWithAnalyzer("name", []uint64{}, []byte("test"), testAnalyzer))
+	batch.Update(doc)
+	err = idx.Batch(batch)
+	if err != nil {
+		t.Errorf("Error executing batch: %v", err)
+	}
+	checkStats(2, 5)
+
+	err = idx.Delete("2")
+	if err != nil {
+		t.Errorf("Error deleting entry from index: %v", err)
+	}
+	checkStats(1, 1)
+
+	reader, err := idx.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := reader.(index.IndexReaderFieldStats).FieldStats("unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs != nil {
+		t.Errorf("expected no field stats for unknown field, got %v", fs)
+	}
+	err = reader.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestIndexInsertMultiple(t *testing.T) {
 	defer func() {
 		err := DestroyTest()
@@ -412,8 +506,8 @@ func TestIndexInsertMultiple(t *testing.T) {
 	}
 	expectedCount++
 
-	// should have 4 rows (1 for version, 1 for schema field, and 2 for single term, and 1 for the term count, and 2 for the back index entries)
-	expectedLength := uint64(1 + 1 + 2 + 1 + 2)
+	// should have 8 rows (1 for version, 1 for schema field, and 2 for single term, and 1 for the term count, and 2 for the back index entries, and 1 for the field stats)
+	expectedLength := uint64(1 + 1 + 2 + 1 + 2 + 1)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -533,8 +627,8 @@ func TestIndexInsertWithStore(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// should have 6 rows (1 for version, 1 for schema field, and 1 for single term, and 1 for the stored field and 1 for the term count, and 1 for the back index entry)
-	expectedLength := uint64(1 + 1 + 1 + 1 + 1 + 1)
+	// should have 7 rows (1 for version, 1 for schema field, and 1 for single term, and 1 for the stored field and 1 for the term count, and 1 for the back index entry, and 1 for the field stats)
+	expectedLength := uint64(1 + 1 + 1 + 1 + 1 + 1 + 1)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -837,7 +931,7 @@ func TestIndexInsertUpdateDeleteWithMultipleTypesStored(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// should have 72 rows
+	// should have 77 rows
 	// 1 for version
 	// 3 for schema fields
 	// 1 for text term
@@ -848,7 +942,8 @@ func TestIndexInsertUpdateDeleteWithMultipleTypesStored(t *testing.T) {
 	// 16 for numeric term counts
 	// 16 for date term counts
 	// 1 for the back index entry
-	expectedLength := uint64(1 + 3 + 1 + (64 / document.DefaultPrecisionStep) + (64 / document.DefaultPrecisionStep) + 3 + 1 + (64 / document.DefaultPrecisionStep) + (64 / document.DefaultPrecisionStep) + 1)
+	// 3 for the field stats
+	expectedLength := uint64(1 + 3 + 1 + (64 / document.DefaultPrecisionStep) + (64 / document.DefaultPrecisionStep) + 3 + 1 + (64 / document.DefaultPrecisionStep) + (64 / document.DefaultPrecisionStep) + 1 + 3)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
@@ -1086,7 +1181,8 @@ func TestIndexUpdateComposites(t *testing.T) {
 	// 2 for the stored field
 	// 4 for the text term count
 	// 1 for the back index entry
-	expectedLength := uint64(1 + 3 + 4 + 2 + 4 + 1)
+	// 3 for the field stats
+	expectedLength := uint64(1 + 3 + 4 + 2 + 4 + 1 + 3)
 	rowCount, err := idx.(*UpsideDownCouch).rowCount()
 	if err != nil {
 		t.Error(err)
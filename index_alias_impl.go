@@ -15,6 +15,7 @@
 package bleve
 
 import (
+	"io"
 	"sort"
 	"sync"
 	"time"
@@ -26,6 +27,8 @@ import (
 	"github.com/edwindvinas/bleve/index/store"
 	"github.com/edwindvinas/bleve/mapping"
 	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/completion"
+	"github.com/edwindvinas/bleve/search/query"
 )
 
 type indexAliasImpl struct {
@@ -118,6 +121,70 @@ func (i *indexAliasImpl) Document(id string) (*document.Document, error) {
 	return i.indexes[0].Document(id)
 }
 
+func (i *indexAliasImpl) OpenSnapshot(keepAlive time.Duration) (string, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return "", ErrorIndexClosed
+	}
+
+	err := i.isAliasToSingleIndex()
+	if err != nil {
+		return "", err
+	}
+
+	return i.indexes[0].OpenSnapshot(keepAlive)
+}
+
+func (i *indexAliasImpl) RenewSnapshot(id string, keepAlive time.Duration) error {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return ErrorIndexClosed
+	}
+
+	err := i.isAliasToSingleIndex()
+	if err != nil {
+		return err
+	}
+
+	return i.indexes[0].RenewSnapshot(id, keepAlive)
+}
+
+func (i *indexAliasImpl) CloseSnapshot(id string) error {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return ErrorIndexClosed
+	}
+
+	err := i.isAliasToSingleIndex()
+	if err != nil {
+		return err
+	}
+
+	return i.indexes[0].CloseSnapshot(id)
+}
+
+func (i *indexAliasImpl) DocumentInSnapshot(snapshotID, id string) (*document.Document, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return nil, ErrorIndexClosed
+	}
+
+	err := i.isAliasToSingleIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return i.indexes[0].DocumentInSnapshot(snapshotID, id)
+}
+
 func (i *indexAliasImpl) DocCount() (uint64, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
@@ -163,6 +230,43 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 	return MultiSearch(ctx, req, i.indexes...)
 }
 
+// Complete returns the highest weighted completions across all of the
+// indexes in the alias.
+func (i *indexAliasImpl) Complete(req *CompletionRequest) (*CompletionResult, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	start := time.Now()
+
+	if !i.open {
+		return nil, ErrorIndexClosed
+	}
+
+	if len(i.indexes) < 1 {
+		return nil, ErrorAliasEmpty
+	}
+
+	// short circuit the simple case
+	if len(i.indexes) == 1 {
+		return i.indexes[0].Complete(req)
+	}
+
+	lists := make([]completion.Completions, 0, len(i.indexes))
+	for _, in := range i.indexes {
+		res, err := in.Complete(req)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, res.Completions)
+	}
+
+	return &CompletionResult{
+		Request:     req,
+		Completions: completion.Merge(req.size(), lists...),
+		Took:        time.Since(start),
+	}, nil
+}
+
 func (i *indexAliasImpl) Fields() ([]string, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
@@ -361,6 +465,22 @@ func (i *indexAliasImpl) DeleteInternal(key []byte) error {
 	return i.indexes[0].DeleteInternal(key)
 }
 
+func (i *indexAliasImpl) Backup(w io.Writer) error {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return ErrorIndexClosed
+	}
+
+	err := i.isAliasToSingleIndex()
+	if err != nil {
+		return err
+	}
+
+	return i.indexes[0].Backup(w)
+}
+
 func (i *indexAliasImpl) Advanced() (index.Index, store.KVStore, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
@@ -434,10 +554,39 @@ func createChildSearchRequest(req *SearchRequest) *SearchRequest {
 		Explain:          req.Explain,
 		Sort:             req.Sort,
 		IncludeLocations: req.IncludeLocations,
+		SearchAfter:      req.SearchAfter,
+		SearchBefore:     req.SearchBefore,
+		Snapshot:         req.Snapshot,
+		Suggest:          req.Suggest,
+		Collapse:         req.Collapse,
+		Rescore:          req.Rescore,
+		partial:          true,
 	}
 	return &rv
 }
 
+// resolveMoreLikeThisDocument looks up the document of a more like
+// this query once in the indexes, so that every index searches for the
+// text of its stored fields and not only the one holding it.
+func resolveMoreLikeThisDocument(req *SearchRequest, indexes []Index) *SearchRequest {
+	q, ok := req.Query.(*query.MoreLikeThisQuery)
+	if !ok || q.LikeDoc == "" {
+		return req
+	}
+	for _, in := range indexes {
+		// indexes failing to look it up, such as aliases
+		// to several indexes, resolve it themselves
+		doc, err := in.Document(q.LikeDoc)
+		if err != nil || doc == nil {
+			continue
+		}
+		rv := *req
+		rv.Query = q.ForDocument(doc, in.Mapping())
+		return &rv
+	}
+	return req
+}
+
 type asyncSearchResult struct {
 	Name   string
 	Result *SearchResult
@@ -449,6 +598,19 @@ type asyncSearchResult struct {
 func MultiSearch(ctx context.Context, req *SearchRequest, indexes ...Index) (*SearchResult, error) {
 
 	searchStart := time.Now()
+
+	err := req.validatePagination()
+	if err != nil {
+		return nil, err
+	}
+
+	// snapshot ids are only meaningful to the index which opened them
+	if req.Snapshot != "" && len(indexes) > 1 {
+		return nil, ErrorAliasMulti
+	}
+
+	req = resolveMoreLikeThisDocument(req, indexes)
+
 	asyncResults := make(chan *asyncSearchResult, len(indexes))
 
 	// run search on each index in separate go routine
@@ -507,6 +669,11 @@ func MultiSearch(ctx context.Context, req *SearchRequest, indexes ...Index) (*Se
 		sort.Sort(sorter)
 	}
 
+	// keep the best hit of groups found in several indexes
+	if req.Collapse != nil {
+		sr.Hits = req.Collapse.collapse(sr.Hits, req.Sort)
+	}
+
 	// now skip over the correct From
 	if req.From > 0 && len(sr.Hits) > req.From {
 		sr.Hits = sr.Hits[req.From:]
@@ -516,12 +683,20 @@ func MultiSearch(ctx context.Context, req *SearchRequest, indexes ...Index) (*Se
 
 	// now trim to the correct size
 	if req.Size > 0 && len(sr.Hits) > req.Size {
-		sr.Hits = sr.Hits[0:req.Size]
+		if req.SearchBefore != nil {
+			// keep the hits closest to the search before position
+			sr.Hits = sr.Hits[len(sr.Hits)-req.Size:]
+		} else {
+			sr.Hits = sr.Hits[0:req.Size]
+		}
 	}
 
 	// fix up facets
-	for name, fr := range req.Facets {
-		sr.Facets.Fixup(name, fr.Size)
+	req.Facets.fixup(sr.Facets)
+
+	// fix up suggestions
+	if req.Suggest != nil && sr.Suggest != nil {
+		req.Suggest.fixup(sr.Suggest)
 	}
 
 	// fix up original request
@@ -16,6 +16,7 @@ package bleve
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 	"testing"
 	"time"
@@ -488,6 +489,14 @@ func TestIndexAliasMulti(t *testing.T) {
 		t.Errorf("expected nil, got %v", indexStat)
 	}
 
+	// snapshot ids are per index, they can't be searched across indexes
+	snapshotReq := NewSearchRequest(NewTermQuery("test"))
+	snapshotReq.Snapshot = "1"
+	_, err = alias.Search(snapshotReq)
+	if err != ErrorAliasMulti {
+		t.Errorf("expected %v, got %v", ErrorAliasMulti, err)
+	}
+
 	// now a few things that should work
 	sr := NewSearchRequest(NewTermQuery("test"))
 	expected := &SearchResult{
@@ -1332,6 +1341,33 @@ func (i *stubIndex) DeleteInternal(key []byte) error {
 	return i.err
 }
 
+func (i *stubIndex) OpenSnapshot(keepAlive time.Duration) (string, error) {
+	return "", i.err
+}
+
+func (i *stubIndex) RenewSnapshot(id string, keepAlive time.Duration) error {
+	return i.err
+}
+
+func (i *stubIndex) CloseSnapshot(id string) error {
+	return i.err
+}
+
+func (i *stubIndex) DocumentInSnapshot(snapshotID, id string) (*document.Document, error) {
+	if i.documentResult != nil {
+		return i.documentResult, nil
+	}
+	return nil, i.err
+}
+
+func (i *stubIndex) Backup(w io.Writer) error {
+	return i.err
+}
+
+func (i *stubIndex) Complete(req *CompletionRequest) (*CompletionResult, error) {
+	return nil, i.err
+}
+
 func (i *stubIndex) Advanced() (index.Index, store.KVStore, error) {
 	return nil, nil, nil
 }
@@ -32,8 +32,10 @@ import (
 	"github.com/edwindvinas/bleve/registry"
 	"github.com/edwindvinas/bleve/search"
 	"github.com/edwindvinas/bleve/search/collector"
+	"github.com/edwindvinas/bleve/search/completion"
 	"github.com/edwindvinas/bleve/search/facet"
 	"github.com/edwindvinas/bleve/search/highlight"
+	"github.com/edwindvinas/bleve/search/hyperloglog"
 )
 
 type indexImpl struct {
@@ -45,6 +47,18 @@ type indexImpl struct {
 	mutex sync.RWMutex
 	open  bool
 	stats *IndexStat
+
+	snapshotSeq    uint64
+	snapshotsMutex sync.Mutex
+	snapshots      map[string]*indexSnapshot
+
+	completionMutex sync.RWMutex
+	completionTries map[string]*completion.Trie
+	// completionBatchMutex serializes the batches updating completions
+	completionBatchMutex sync.Mutex
+
+	// nestedMutex serializes the batches updating nested documents
+	nestedMutex sync.Mutex
 }
 
 const storePath = "store"
@@ -244,12 +258,27 @@ func (i *indexImpl) Index(id string, data interface{}) (err error) {
 		return ErrorIndexClosed
 	}
 
+	err = checkNestedID(i.m, id)
+	if err != nil {
+		return
+	}
+
 	doc := document.NewDocument(id)
 	err = i.m.MapDocument(doc, data)
 	if err != nil {
 		return
 	}
-	err = i.i.Update(doc)
+	if hasNested(i.m) {
+		b := index.NewBatch()
+		b.Update(doc)
+		err = i.batchNested(b)
+	} else if hasCompletion(i.m) {
+		b := index.NewBatch()
+		b.Update(doc)
+		err = i.batchCompletion(b)
+	} else {
+		err = i.i.Update(doc)
+	}
 	return
 }
 
@@ -267,7 +296,17 @@ func (i *indexImpl) Delete(id string) (err error) {
 		return ErrorIndexClosed
 	}
 
-	err = i.i.Delete(id)
+	if hasNested(i.m) {
+		b := index.NewBatch()
+		b.Delete(id)
+		err = i.batchNested(b)
+	} else if hasCompletion(i.m) {
+		b := index.NewBatch()
+		b.Delete(id)
+		err = i.batchCompletion(b)
+	} else {
+		err = i.i.Delete(id)
+	}
 	return
 }
 
@@ -283,9 +322,38 @@ func (i *indexImpl) Batch(b *Batch) error {
 		return ErrorIndexClosed
 	}
 
+	if hasNested(i.m) {
+		return i.batchNested(b.internal)
+	}
+	if hasCompletion(i.m) {
+		return i.batchCompletion(b.internal)
+	}
 	return i.i.Batch(b.internal)
 }
 
+// batchNested executes the batch, along with the updates and deletions
+// of the nested documents of its documents.
+func (i *indexImpl) batchNested(b *index.Batch) error {
+	i.nestedMutex.Lock()
+	defer i.nestedMutex.Unlock()
+
+	indexReader, err := i.i.Reader()
+	if err != nil {
+		return err
+	}
+	err = batchNested(indexReader, b)
+	if cerr := indexReader.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if hasCompletion(i.m) {
+		return i.batchCompletion(b)
+	}
+	return i.i.Batch(b)
+}
+
 // Document is used to find the values of all the
 // stored fields for a document in the index.  These
 // stored fields are put back into a Document object
@@ -335,6 +403,16 @@ func (i *indexImpl) DocCount() (count uint64, err error) {
 		}
 	}()
 
+	if hasNested(i.m) {
+		// nested documents are not counted
+		var parentReader index.IndexReader
+		parentReader, err = hideNestedCount(indexReader)
+		if err != nil {
+			return 0, err
+		}
+		return parentReader.DocCount()
+	}
+
 	count, err = indexReader.DocCount()
 	return
 }
@@ -357,66 +435,151 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 		return nil, ErrorIndexClosed
 	}
 
-	collector := collector.NewTopNCollector(req.Size, req.From, req.Sort)
+	err = req.validatePagination()
+	if err != nil {
+		return nil, err
+	}
 
-	// open a reader for this search
-	indexReader, err := i.i.Reader()
+	if req.Suggest != nil {
+		err = req.Suggest.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Collapse != nil {
+		err = req.Collapse.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = req.validateRescore()
 	if err != nil {
-		return nil, fmt.Errorf("error opening index reader %v", err)
+		return nil, err
 	}
-	defer func() {
-		if cerr := indexReader.Close(); err == nil && cerr != nil {
-			err = cerr
+
+	var coll *collector.TopNCollector
+	if len(req.Rescore) > 0 {
+		// collect the whole rescore window, then page
+		coll = collector.NewTopNCollector(req.rescoreWindow(), 0, req.Sort)
+	} else if req.SearchAfter != nil {
+		coll = collector.NewTopNCollectorAfter(req.Size, req.Sort, req.SearchAfter)
+	} else if req.SearchBefore != nil {
+		// collect in the reverse order, then reverse the hits
+		var sortOrder search.SortOrder
+		sortOrder, err = req.Sort.Reversed()
+		if err != nil {
+			return nil, err
 		}
-	}()
+		coll = collector.NewTopNCollectorAfter(req.Size, sortOrder, req.SearchBefore)
+	} else {
+		coll = collector.NewTopNCollector(req.Size, req.From, req.Sort)
+	}
 
-	searcher, err := req.Query.Searcher(indexReader, i.m, search.SearcherOptions{
+	var indexReader index.IndexReader
+	if req.Snapshot != "" {
+		// search the pinned snapshot
+		var snapshot *indexSnapshot
+		snapshot, err = i.acquireSnapshot(req.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if rerr := i.releaseSnapshot(snapshot); err == nil && rerr != nil {
+				err = rerr
+			}
+		}()
+		indexReader = snapshot.reader
+	} else {
+		// open a reader for this search
+		indexReader, err = i.i.Reader()
+		if err != nil {
+			return nil, fmt.Errorf("error opening index reader %v", err)
+		}
+		defer func() {
+			if cerr := indexReader.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	if hasNested(i.m) {
+		// score and count with the number of parent documents
+		var parentReader index.IndexReader
+		parentReader, err = hideNestedCount(indexReader)
+		if err != nil {
+			return nil, err
+		}
+		indexReader = parentReader
+	}
+
+	searcherOptions := search.SearcherOptions{
 		Explain:            req.Explain,
 		IncludeTermVectors: req.IncludeLocations || req.Highlight != nil,
-	})
+	}
+	if similarities, ok := i.m.(search.SimilarityLookup); ok {
+		searcherOptions.Similarities = similarities
+	}
+	searcher, err := req.Query.Searcher(indexReader, i.m, searcherOptions)
 	if err != nil {
 		return nil, err
 	}
+	if hasNested(i.m) {
+		searcher = hideNested(indexReader, searcher)
+	}
 	defer func() {
 		if serr := searcher.Close(); err == nil && serr != nil {
 			err = serr
 		}
 	}()
 
+	if req.Collapse != nil {
+		coll.SetCollapse(req.Collapse.Field, req.Collapse.InnerHits)
+	}
+
 	if req.Facets != nil {
-		facetsBuilder := search.NewFacetsBuilder(indexReader)
-		for facetName, facetRequest := range req.Facets {
-			if facetRequest.NumericRanges != nil {
-				// build numeric range facet
-				facetBuilder := facet.NewNumericFacetBuilder(facetRequest.Field, facetRequest.Size)
-				for _, nr := range facetRequest.NumericRanges {
-					facetBuilder.AddRange(nr.Name, nr.Min, nr.Max)
-				}
-				facetsBuilder.Add(facetName, facetBuilder)
-			} else if facetRequest.DateTimeRanges != nil {
-				// build date range facet
-				facetBuilder := facet.NewDateTimeFacetBuilder(facetRequest.Field, facetRequest.Size)
-				dateTimeParser := i.m.DateTimeParserNamed("")
-				for _, dr := range facetRequest.DateTimeRanges {
-					start, end := dr.ParseDates(dateTimeParser)
-					facetBuilder.AddRange(dr.Name, start, end)
-				}
-				facetsBuilder.Add(facetName, facetBuilder)
-			} else {
-				// build terms facet
-				facetBuilder := facet.NewTermsFacetBuilder(facetRequest.Field, facetRequest.Size)
-				facetsBuilder.Add(facetName, facetBuilder)
-			}
+		facetsBuilder, err := i.newFacetsBuilder(indexReader, req.Facets)
+		if err != nil {
+			return nil, err
 		}
-		collector.SetFacetsBuilder(facetsBuilder)
+		coll.SetFacetsBuilder(facetsBuilder)
 	}
 
-	err = collector.Collect(ctx, searcher, indexReader)
+	err = coll.Collect(ctx, searcher, indexReader)
 	if err != nil {
 		return nil, err
 	}
 
-	hits := collector.Results()
+	hits := coll.Results()
+	maxScore := coll.MaxScore()
+
+	if len(req.Rescore) > 0 {
+		err = i.rescore(indexReader, hits, req, searcherOptions)
+		if err != nil {
+			return nil, err
+		}
+		maxScore = 0
+		for _, hit := range hits {
+			if hit.Score > maxScore {
+				maxScore = hit.Score
+			}
+		}
+		if req.From < len(hits) {
+			hits = hits[req.From:]
+		} else {
+			hits = search.DocumentMatchCollection{}
+		}
+		if len(hits) > req.Size {
+			hits = hits[:req.Size]
+		}
+	}
+
+	if req.SearchBefore != nil {
+		// hits were collected in reverse order
+		for i, j := 0, len(hits)-1; i < j; i, j = i+1, j-1 {
+			hits[i], hits[j] = hits[j], hits[i]
+		}
+	}
 
 	var highlighter highlight.Highlighter
 
@@ -437,7 +600,7 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 		}
 	}
 
-	for _, hit := range hits {
+	loadHit := func(hit *search.DocumentMatch) error {
 		if len(req.Fields) > 0 || highlighter != nil {
 			doc, err := indexReader.Document(hit.ID)
 			if err == nil && doc != nil {
@@ -472,6 +635,11 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 											value = []float64{lon, lat}
 										}
 									}
+								case *document.CompletionField:
+									input, err := docF.Input()
+									if err == nil {
+										value = input
+									}
 								}
 								if value != nil {
 									hit.AddFieldValue(docF.Name(), value)
@@ -496,12 +664,37 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 			} else if doc == nil {
 				// unexpected case, a doc ID that was found as a search hit
 				// was unable to be found during document lookup
-				return nil, ErrorIndexReadInconsistency
+				return ErrorIndexReadInconsistency
 			}
 		}
 		if i.name != "" {
 			hit.Index = i.name
 		}
+		return nil
+	}
+
+	for _, hit := range hits {
+		err = loadHit(hit)
+		if err != nil {
+			return nil, err
+		}
+		for _, innerHit := range hit.InnerHits {
+			err = loadHit(innerHit)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var suggestResult *search.SuggestResult
+	if req.Suggest != nil {
+		suggestResult, err = i.suggest(indexReader, req.Suggest)
+		if err != nil {
+			return nil, err
+		}
+		if !req.partial {
+			req.Suggest.fixup(suggestResult)
+		}
 	}
 
 	atomic.AddUint64(&i.stats.searches, 1)
@@ -513,6 +706,11 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 		logger.Printf("slow search took %s - %v", searchDuration, req)
 	}
 
+	facetResults := coll.FacetResults()
+	if !req.partial {
+		req.Facets.fixup(facetResults)
+	}
+
 	return &SearchResult{
 		Status: &SearchStatus{
 			Total:      1,
@@ -522,13 +720,106 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 		},
 		Request:  req,
 		Hits:     hits,
-		Total:    collector.Total(),
-		MaxScore: collector.MaxScore(),
+		Total:    coll.Total(),
+		MaxScore: maxScore,
 		Took:     searchDuration,
-		Facets:   collector.FacetResults(),
+		Facets:   facetResults,
+		Suggest:  suggestResult,
 	}, nil
 }
 
+func (i *indexImpl) newFacetsBuilder(indexReader index.IndexReader, facetsRequest FacetsRequest) (*search.FacetsBuilder, error) {
+	facetsBuilder := search.NewFacetsBuilder(indexReader)
+	for facetName, facetRequest := range facetsRequest {
+		facetBuilder, err := i.newFacetBuilder(indexReader, facetRequest)
+		if err != nil {
+			return nil, err
+		}
+		facetsBuilder.Add(facetName, facetBuilder)
+	}
+	return facetsBuilder, nil
+}
+
+func (i *indexImpl) newFacetBuilder(indexReader index.IndexReader, facetRequest *FacetRequest) (search.FacetBuilder, error) {
+	switch facetRequest.Type {
+	case FacetTypeStats:
+		return facet.NewStatsFacetBuilder(facetRequest.Field), nil
+	case FacetTypePercentiles:
+		percents := facetRequest.Percents
+		if len(percents) == 0 {
+			percents = DefaultPercents
+		}
+		return facet.NewPercentilesFacetBuilder(facetRequest.Field, percents, facetRequest.Compression), nil
+	case FacetTypeCardinality:
+		precision := facetRequest.Precision
+		if precision == 0 {
+			precision = hyperloglog.DefaultPrecision
+		}
+		cardinalityFacetBuilder, err := facet.NewCardinalityFacetBuilder(facetRequest.Field, uint8(precision))
+		if err != nil {
+			return nil, err
+		}
+		if types, ok := i.m.(mapping.FieldTypeLookup); ok {
+			switch types.FieldTypeForPath(facetRequest.Field) {
+			case "number", "datetime":
+				cardinalityFacetBuilder.SetNumeric(true)
+			case "":
+			default:
+				cardinalityFacetBuilder.SetNumeric(false)
+			}
+		}
+		return cardinalityFacetBuilder, nil
+	}
+
+	var facetBuilder search.BucketFacetBuilder
+	if facetRequest.Type == FacetTypeHistogram || facetRequest.Type == FacetTypeDateHistogram {
+		// build histogram facet
+		histogramFacetBuilder, err := facetRequest.newHistogramFacetBuilder(i.m.DateTimeParserNamed(""))
+		if err != nil {
+			return nil, err
+		}
+		facetBuilder = histogramFacetBuilder
+	} else if facetRequest.NumericRanges != nil {
+		// build numeric range facet
+		numericFacetBuilder := facet.NewNumericFacetBuilder(facetRequest.Field, facetRequest.Size)
+		for _, nr := range facetRequest.NumericRanges {
+			numericFacetBuilder.AddRange(nr.Name, nr.Min, nr.Max)
+		}
+		facetBuilder = numericFacetBuilder
+	} else if facetRequest.DateTimeRanges != nil {
+		// build date range facet
+		dateTimeFacetBuilder := facet.NewDateTimeFacetBuilder(facetRequest.Field, facetRequest.Size)
+		dateTimeParser := i.m.DateTimeParserNamed("")
+		for _, dr := range facetRequest.DateTimeRanges {
+			start, end := dr.ParseDates(dateTimeParser)
+			dateTimeFacetBuilder.AddRange(dr.Name, start, end)
+		}
+		facetBuilder = dateTimeFacetBuilder
+	} else {
+		// build terms facet
+		facetBuilder = facet.NewTermsFacetBuilder(facetRequest.Field, facetRequest.Size)
+	}
+
+	if len(facetRequest.Facets) > 0 {
+		// build the sub-facets of each bucket, once up front
+		// so the builders which follow cannot fail
+		_, err := i.newFacetsBuilder(indexReader, facetRequest.Facets)
+		if err != nil {
+			return nil, err
+		}
+		// histograms return all of their buckets
+		size := facetRequest.Size
+		if facetRequest.Type == FacetTypeHistogram || facetRequest.Type == FacetTypeDateHistogram {
+			size = 0
+		}
+		return facet.NewNestedFacetBuilder(facetBuilder, size, indexReader, func() *search.FacetsBuilder {
+			children, _ := i.newFacetsBuilder(indexReader, facetRequest.Facets)
+			return children
+		}), nil
+	}
+	return facetBuilder, nil
+}
+
 // Fields returns the name of all the fields this
 // Index has operated on.
 func (i *indexImpl) Fields() (fields []string, err error) {
@@ -644,6 +935,11 @@ func (i *indexImpl) Close() error {
 	indexStats.UnRegister(i)
 
 	i.open = false
+	err := i.closeSnapshots()
+	if err != nil {
+		_ = i.i.Close()
+		return err
+	}
 	return i.i.Close()
 }
 
@@ -0,0 +1,204 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+)
+
+// DefaultSnapshotKeepAlive is the keep alive used for snapshots
+// opened without an explicit keep alive
+var DefaultSnapshotKeepAlive = 5 * time.Minute
+
+// indexSnapshot pins an index reader, and the consistent view of the
+// index it provides, until it is closed or its keep alive expires
+type indexSnapshot struct {
+	reader    index.IndexReader
+	keepAlive time.Duration
+	expires   time.Time
+	timer     *time.Timer
+	refs      int
+	removed   bool
+}
+
+// OpenSnapshot pins the current state of the index and returns an ID
+// which can be used in SearchRequest.Snapshot and DocumentInSnapshot to
+// read from that state. The snapshot is released once it has not been
+// used for the keep alive duration, or when CloseSnapshot is called.
+func (i *indexImpl) OpenSnapshot(keepAlive time.Duration) (string, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return "", ErrorIndexClosed
+	}
+
+	if keepAlive <= 0 {
+		keepAlive = DefaultSnapshotKeepAlive
+	}
+
+	indexReader, err := i.i.Reader()
+	if err != nil {
+		return "", err
+	}
+
+	id := strconv.FormatUint(atomic.AddUint64(&i.snapshotSeq, 1), 10)
+	snapshot := &indexSnapshot{
+		reader:    indexReader,
+		keepAlive: keepAlive,
+		expires:   time.Now().Add(keepAlive),
+	}
+
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+	if i.snapshots == nil {
+		i.snapshots = make(map[string]*indexSnapshot)
+	}
+	i.snapshots[id] = snapshot
+	snapshot.timer = time.AfterFunc(keepAlive, func() {
+		i.expireSnapshot(id)
+	})
+
+	return id, nil
+}
+
+// RenewSnapshot extends the life of the snapshot by the keep alive
+// duration, a keep alive of 0 reuses the snapshot's previous keep alive.
+func (i *indexImpl) RenewSnapshot(id string, keepAlive time.Duration) error {
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+
+	snapshot, ok := i.snapshots[id]
+	if !ok {
+		return ErrorSnapshotNotFound
+	}
+	if keepAlive > 0 {
+		snapshot.keepAlive = keepAlive
+	}
+	snapshot.renew()
+	return nil
+}
+
+// CloseSnapshot releases the snapshot, searches already using it are
+// allowed to complete.
+func (i *indexImpl) CloseSnapshot(id string) error {
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+
+	snapshot, ok := i.snapshots[id]
+	if !ok {
+		return ErrorSnapshotNotFound
+	}
+	return i.removeSnapshotLocked(id, snapshot)
+}
+
+// DocumentInSnapshot returns the specified document as it was when the
+// snapshot was opened, or nil if the document was not indexed or stored.
+func (i *indexImpl) DocumentInSnapshot(snapshotID, id string) (doc *document.Document, err error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return nil, ErrorIndexClosed
+	}
+
+	snapshot, err := i.acquireSnapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := i.releaseSnapshot(snapshot); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	return snapshot.reader.Document(id)
+}
+
+// acquireSnapshot looks up a snapshot for use, extending its life,
+// it must be released with releaseSnapshot
+func (i *indexImpl) acquireSnapshot(id string) (*indexSnapshot, error) {
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+
+	snapshot, ok := i.snapshots[id]
+	if !ok {
+		return nil, ErrorSnapshotNotFound
+	}
+	snapshot.refs++
+	snapshot.renew()
+	return snapshot, nil
+}
+
+func (i *indexImpl) releaseSnapshot(snapshot *indexSnapshot) error {
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+
+	snapshot.refs--
+	if snapshot.removed && snapshot.refs == 0 {
+		return snapshot.reader.Close()
+	}
+	return nil
+}
+
+func (i *indexImpl) expireSnapshot(id string) {
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+
+	snapshot, ok := i.snapshots[id]
+	if !ok || time.Now().Before(snapshot.expires) {
+		// already removed, or renewed since the timer fired
+		return
+	}
+	err := i.removeSnapshotLocked(id, snapshot)
+	if err != nil {
+		logger.Printf("error closing expired snapshot %s: %v", id, err)
+	}
+}
+
+// closeSnapshots releases all the snapshots, used when closing the index
+func (i *indexImpl) closeSnapshots() error {
+	i.snapshotsMutex.Lock()
+	defer i.snapshotsMutex.Unlock()
+
+	var rv error
+	for id, snapshot := range i.snapshots {
+		err := i.removeSnapshotLocked(id, snapshot)
+		if err != nil && rv == nil {
+			rv = err
+		}
+	}
+	return rv
+}
+
+func (i *indexImpl) removeSnapshotLocked(id string, snapshot *indexSnapshot) error {
+	delete(i.snapshots, id)
+	snapshot.timer.Stop()
+	snapshot.removed = true
+	if snapshot.refs == 0 {
+		return snapshot.reader.Close()
+	}
+	return nil
+}
+
+func (s *indexSnapshot) renew() {
+	s.expires = time.Now().Add(s.keepAlive)
+	s.timer.Reset(s.keepAlive)
+}
@@ -15,6 +15,7 @@
 package bleve
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -31,7 +32,10 @@ import (
 
 	"strconv"
 
+	"github.com/edwindvinas/bleve/analysis/analyzer/custom"
 	"github.com/edwindvinas/bleve/analysis/analyzer/keyword"
+	"github.com/edwindvinas/bleve/analysis/synonymmap"
+	"github.com/edwindvinas/bleve/analysis/token/synonym"
 	"github.com/edwindvinas/bleve/index"
 	"github.com/edwindvinas/bleve/index/store/null"
 	"github.com/edwindvinas/bleve/mapping"
@@ -1731,3 +1735,1911 @@ func TestBug408(t *testing.T) {
 		}
 	}
 }
+
+func TestBM25Similarity(t *testing.T) {
+	defer func() {
+		err := os.RemoveAll("testidx")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	m := NewIndexMapping()
+	m.DefaultSimilarity = "bm25"
+
+	index, err := New("testidx", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := map[string]interface{}{
+		"short": map[string]interface{}{
+			"desc": "pale ale",
+		},
+		"long": map[string]interface{}{
+			"desc": "a dark ale brewed with roasted barley and a little bit of chocolate",
+		},
+		"other": map[string]interface{}{
+			"desc": "a crisp lager",
+		},
+	}
+	for id, doc := range docs {
+		err = index.Index(id, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q := NewTermQuery("ale")
+	q.SetField("desc")
+	req := NewSearchRequest(q)
+	req.Explain = true
+	res, err := index.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 2 {
+		t.Fatalf("expected 2 hits, got %d", len(res.Hits))
+	}
+	if res.Hits[0].ID != "short" {
+		t.Errorf("expected shorter field to score higher, got %s first", res.Hits[0].ID)
+	}
+	if res.Hits[0].Expl == nil || !strings.Contains(res.Hits[0].Expl.Message, "BM25") {
+		t.Errorf("expected BM25 explanation, got %v", res.Hits[0].Expl)
+	}
+
+	err = index.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchAfterBefore(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	names := []string{"Noam", "Uri", "David", "Yosef", "Eitan", "Itay", "Ariel"}
+	for i := 0; i < 50; i++ {
+		doc := map[string]interface{}{
+			"Name": names[i%len(names)],
+			"Type": "person",
+		}
+		idx := idx1
+		if i%2 == 0 {
+			idx = idx2
+		}
+		err = idx.Index(fmt.Sprintf("%02d", i), doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	alias := NewIndexAlias(idx1, idx2)
+
+	for _, index := range []Index{idx1, alias} {
+		req := NewSearchRequestOptions(NewMatchQuery("person"), 100, 0, false)
+		req.SortBy([]string{"-Name", "_id"})
+		all, err := index.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected []string
+		for _, hit := range all.Hits {
+			expected = append(expected, hit.ID)
+		}
+
+		// page forwards
+		var actual []string
+		var after []string
+		for {
+			req = NewSearchRequestOptions(NewMatchQuery("person"), 7, 0, false)
+			req.SortBy([]string{"-Name", "_id"})
+			req.SetSearchAfter(after)
+			page, err := index.Search(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if page.Total != all.Total {
+				t.Errorf("expected total %d, got %d", all.Total, page.Total)
+			}
+			if len(page.Hits) == 0 {
+				break
+			}
+			for _, hit := range page.Hits {
+				actual = append(actual, hit.ID)
+			}
+			after = page.Hits[len(page.Hits)-1].Sort
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected search after pages %v, got %v", expected, actual)
+		}
+
+		// page backwards from the last hit
+		actual = nil
+		before := all.Hits[len(all.Hits)-1].Sort
+		for {
+			req = NewSearchRequestOptions(NewMatchQuery("person"), 7, 0, false)
+			req.SortBy([]string{"-Name", "_id"})
+			req.SetSearchBefore(before)
+			page, err := index.Search(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Hits) == 0 {
+				break
+			}
+			var pageIDs []string
+			for _, hit := range page.Hits {
+				pageIDs = append(pageIDs, hit.ID)
+			}
+			actual = append(pageIDs, actual...)
+			before = page.Hits[0].Sort
+		}
+		if !reflect.DeepEqual(expected[:len(expected)-1], actual) {
+			t.Errorf("expected search before pages %v, got %v", expected[:len(expected)-1], actual)
+		}
+	}
+
+	// invalid requests
+	req := NewSearchRequestOptions(NewMatchQuery("person"), 10, 10, false)
+	req.SetSearchAfter([]string{"_score"})
+	_, err = idx1.Search(req)
+	if err == nil {
+		t.Errorf("expected error using search after with from")
+	}
+	req.From = 0
+	_, err = idx1.Search(req)
+	if err == nil {
+		t.Errorf("expected error using non-numeric score position")
+	}
+	req.SearchAfter = []string{"1.5"}
+	req.SetSearchBefore([]string{"1.5"})
+	_, err = alias.Search(req)
+	if err == nil {
+		t.Errorf("expected error using search after and before together")
+	}
+
+	// sorts implemented elsewhere can search after, but not before
+	req = NewSearchRequestOptions(NewMatchQuery("person"), 3, 0, false)
+	req.Sort = search.SortOrder{&idSort{&search.SortDocID{}}}
+	req.SetSearchAfter([]string{"10"})
+	res, err := alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 3 || res.Hits[0].ID != "11" {
+		t.Errorf("expected 3 hits after 10, got %v", res.Hits)
+	}
+	req.SearchAfter = nil
+	req.SetSearchBefore([]string{"10"})
+	_, err = alias.Search(req)
+	if err == nil {
+		t.Errorf("expected error searching before with a sort which cannot be reversed")
+	}
+}
+
+// idSort is a search sort which does not implement
+// search.ReversibleSearchSort
+type idSort struct {
+	search.SearchSort
+}
+
+func TestIndexSnapshot(t *testing.T) {
+	index, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := index.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	err = index.Index("a", map[string]interface{}{"name": "marty", "type": "person"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotID, err := index.OpenSnapshot(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := index.NewBatch()
+	err = batch.Index("a", map[string]interface{}{"name": "steve", "type": "person"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = batch.Index("b", map[string]interface{}{"name": "mike", "type": "person"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = index.Batch(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := NewSearchRequest(NewMatchQuery("person"))
+	res, err := index.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 2 {
+		t.Errorf("expected 2 hits in current index, got %d", res.Total)
+	}
+
+	req.Snapshot = snapshotID
+	res, err = index.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 {
+		t.Errorf("expected 1 hit in snapshot, got %d", res.Total)
+	}
+
+	doc, err := index.DocumentInSnapshot(snapshotID, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range doc.Fields {
+		if field.Name() == "name" && string(field.Value()) != "marty" {
+			t.Errorf("expected snapshot document name marty, got %s", field.Value())
+		}
+	}
+	doc, err = index.DocumentInSnapshot(snapshotID, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc != nil {
+		t.Errorf("expected document b to be missing from snapshot")
+	}
+
+	err = index.RenewSnapshot(snapshotID, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = index.CloseSnapshot(snapshotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = index.Search(req)
+	if err != ErrorSnapshotNotFound {
+		t.Errorf("expected snapshot not found error, got %v", err)
+	}
+
+	// snapshots expire after the keep alive
+	snapshotID, err = index.OpenSnapshot(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	_, err = index.DocumentInSnapshot(snapshotID, "a")
+	if err != ErrorSnapshotNotFound {
+		t.Errorf("expected expired snapshot, got %v", err)
+	}
+
+	// snapshots still open are released when the index is closed
+	_, err = index.OpenSnapshot(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchSuggest(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	docs := []string{
+		"the quick brown fox",
+		"a quick brown dog",
+		"quick thinking",
+		"brown bread",
+		"a quack doctor",
+	}
+	for i, desc := range docs {
+		idx := idx1
+		if i%2 == 1 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), map[string]interface{}{"desc": desc})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	alias := NewIndexAlias(idx1, idx2)
+
+	req := NewSearchRequestOptions(NewMatchNoneQuery(), 0, 0, false)
+	req.Suggest = NewSuggestRequest("Quikc brwn fox", "desc")
+	req.Suggest.Phrase = true
+	res, err := alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Suggest == nil || len(res.Suggest.Terms) != 3 {
+		t.Fatalf("expected suggestions for 3 terms, got %#v", res.Suggest)
+	}
+	quikc := res.Suggest.Terms[0]
+	if quikc.Text != "quikc" || quikc.Start != 0 || quikc.End != 5 || quikc.Freq != 0 {
+		t.Errorf("unexpected term suggestion %#v", quikc)
+	}
+	if len(quikc.Options) == 0 || quikc.Options[0].Text != "quick" || quikc.Options[0].Freq != 3 {
+		t.Errorf("expected 'quick' in 3 docs as the first correction, got %v", quikc.Options)
+	}
+	brwn := res.Suggest.Terms[1]
+	if len(brwn.Options) != 1 || brwn.Options[0].Text != "brown" || brwn.Options[0].Freq != 3 {
+		t.Errorf("expected only 'brown' in 3 docs as correction, got %v", brwn.Options)
+	}
+	fox := res.Suggest.Terms[2]
+	if fox.Freq != 1 || len(fox.Options) != 0 {
+		t.Errorf("expected no corrections for indexed term 'fox', got %#v", fox)
+	}
+	if len(res.Suggest.Phrases) == 0 || res.Suggest.Phrases[0].Text != "quick brown fox" {
+		t.Errorf("expected 'quick brown fox' as the first phrase, got %v", res.Suggest.Phrases)
+	}
+
+	// popular mode suggests more frequent terms even for indexed terms
+	req.Suggest = NewSuggestRequest("quack", "desc")
+	req.Suggest.Mode = search.SuggestModePopular
+	res, err = idx1.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Suggest.Terms) != 1 || len(res.Suggest.Terms[0].Options) != 1 ||
+		res.Suggest.Terms[0].Options[0].Text != "quick" {
+		t.Errorf("expected 'quick' as popular correction for 'quack', got %#v", res.Suggest.Terms)
+	}
+
+	// the mode applies to the frequencies merged across the alias, color
+	// is only more popular than colour once both indexes are counted
+	err = idx1.Index("colour", map[string]interface{}{"desc": "colour"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx1.Index("color1", map[string]interface{}{"desc": "color"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx2.Index("color2", map[string]interface{}{"desc": "color"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Suggest = NewSuggestRequest("colour", "desc")
+	req.Suggest.Mode = search.SuggestModePopular
+	res, err = alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Suggest.Terms) != 1 || len(res.Suggest.Terms[0].Options) != 1 ||
+		res.Suggest.Terms[0].Options[0].Text != "color" || res.Suggest.Terms[0].Options[0].Freq != 2 {
+		t.Errorf("expected 'color' in 2 docs as popular correction for 'colour', got %#v", res.Suggest.Terms)
+	}
+
+	// corrections share the first rune of the term by default
+	req.Suggest = NewSuggestRequest("xuick", "desc")
+	res, err = alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Suggest.Terms) != 1 || len(res.Suggest.Terms[0].Options) != 0 {
+		t.Errorf("expected no corrections for 'xuick', got %#v", res.Suggest.Terms)
+	}
+
+	// unless the prefix length is explicitly 0
+	req.Suggest.SetPrefixLength(0)
+	res, err = alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Suggest.Terms) != 1 || len(res.Suggest.Terms[0].Options) == 0 ||
+		res.Suggest.Terms[0].Options[0].Text != "quick" {
+		t.Errorf("expected 'quick' as first correction for 'xuick', got %#v", res.Suggest.Terms)
+	}
+
+	// and no edits means no corrections
+	req.Suggest = NewSuggestRequest("quikc", "desc")
+	req.Suggest.SetMaxEdits(0)
+	res, err = alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Suggest.Terms) != 1 || len(res.Suggest.Terms[0].Options) != 0 {
+		t.Errorf("expected no corrections for 'quikc' without edits, got %#v", res.Suggest.Terms)
+	}
+
+	req.Suggest.Mode = "sometimes"
+	_, err = idx1.Search(req)
+	if err == nil {
+		t.Errorf("expected error for unknown suggest mode")
+	}
+}
+
+func TestIndexCompletion(t *testing.T) {
+	artistMapping := NewDocumentMapping()
+	artistMapping.AddFieldMappingsAt("suggest", NewCompletionFieldMapping())
+	indexMapping := NewIndexMapping()
+	indexMapping.DefaultMapping = artistMapping
+
+	idx1, err := NewMemOnly(indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	err = idx1.Index("nirvana", map[string]interface{}{
+		"name":    "Nirvana",
+		"suggest": map[string]interface{}{"input": "Nirvana", "weight": 34},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx1.Index("nin", map[string]interface{}{
+		"name": "Nine Inch Nails",
+		"suggest": map[string]interface{}{
+			"input":  []interface{}{"Nine Inch Nails", "NIN"},
+			"weight": 50,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx2.Index("nick", map[string]interface{}{
+		"name":    "Nick Cave",
+		"suggest": "Nick Cave",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx2.Index("nirvana", map[string]interface{}{
+		"name":    "Nirvana",
+		"suggest": map[string]interface{}{"input": "Nirvana", "weight": 60},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completionTexts := func(res *CompletionResult) []string {
+		var rv []string
+		for _, c := range res.Completions {
+			rv = append(rv, fmt.Sprintf("%s:%d", c.Text, c.Weight))
+		}
+		return rv
+	}
+
+	res, err := idx1.Complete(NewCompletionRequest("NI", "suggest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"NIN:50", "Nine Inch Nails:50", "Nirvana:34"}
+	if !reflect.DeepEqual(completionTexts(res), expected) {
+		t.Errorf("expected %v, got %v", expected, completionTexts(res))
+	}
+
+	// completions are updated when the index changes
+	err = idx1.Delete("nin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = idx1.Complete(NewCompletionRequest("ni", "suggest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"Nirvana:34"}
+	if !reflect.DeepEqual(completionTexts(res), expected) {
+		t.Errorf("expected %v after delete, got %v", expected, completionTexts(res))
+	}
+
+	// the trie is updated in place by later changes
+	trie := idx1.(*indexImpl).completionTries["suggest"]
+	if trie == nil {
+		t.Fatalf("expected the suggest completions to be kept")
+	}
+	batch := idx1.NewBatch()
+	err = batch.Index("nirvana", map[string]interface{}{
+		"name":    "Nirvana",
+		"suggest": map[string]interface{}{"input": "Nirvana", "weight": 12},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = batch.Index("nico", map[string]interface{}{
+		"name":    "Nico",
+		"suggest": map[string]interface{}{"input": "Nico", "weight": 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx1.Batch(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = idx1.Complete(NewCompletionRequest("ni", "suggest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"Nico:20", "Nirvana:12"}
+	if !reflect.DeepEqual(completionTexts(res), expected) {
+		t.Errorf("expected %v after batch, got %v", expected, completionTexts(res))
+	}
+	err = idx1.Index("nico", map[string]interface{}{"name": "Nico"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = idx1.Complete(NewCompletionRequest("ni", "suggest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"Nirvana:12"}
+	if !reflect.DeepEqual(completionTexts(res), expected) {
+		t.Errorf("expected %v after update, got %v", expected, completionTexts(res))
+	}
+	if idx1.(*indexImpl).completionTries["suggest"] != trie {
+		t.Errorf("expected the suggest completions to be updated, not rebuilt")
+	}
+
+	// fuzzy prefix, merged across an alias
+	alias := NewIndexAlias(idx1, idx2)
+	req := NewCompletionRequest("nirvn", "suggest")
+	req.Fuzziness = 1
+	res, err = alias.Complete(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"Nirvana:60"}
+	if !reflect.DeepEqual(completionTexts(res), expected) {
+		t.Errorf("expected %v for fuzzy alias completion, got %v", expected, completionTexts(res))
+	}
+	req.Prefix = "nri"
+	res, err = alias.Complete(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"Nirvana:60", "Nick Cave:1"}
+	if !reflect.DeepEqual(completionTexts(res), expected) {
+		t.Errorf("expected %v for fuzzy alias completion, got %v", expected, completionTexts(res))
+	}
+
+	// completions are kept out of regular search and of the dictionary
+	sres, err := idx1.Search(NewSearchRequest(NewMatchQuery("nirvana")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sres.Total != 1 {
+		t.Errorf("expected 1 match for nirvana in name, got %d", sres.Total)
+	}
+	fieldDict, err := idx1.FieldDict("suggest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := fieldDict.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry != nil {
+		t.Errorf("expected no suggest terms, got %q", entry.Term)
+	}
+	err = fieldDict.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = idx1.Complete(NewCompletionRequest("ni", ""))
+	if err == nil {
+		t.Errorf("expected error for missing completion field")
+	}
+}
+
+func TestIndexCompletionReopen(t *testing.T) {
+	defer func() {
+		err := os.RemoveAll("testidx")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	indexMapping := NewIndexMapping()
+	indexMapping.DefaultMapping.AddFieldMappingsAt("suggest", NewCompletionFieldMapping())
+	idx, err := New("testidx", indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, weight := range map[string]int{"Nirvana": 34, "Nico": 20, "Nick Cave": 12} {
+		err = idx.Index(id, map[string]interface{}{
+			"suggest": map[string]interface{}{"input": id, "weight": weight},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = idx.Delete("Nico")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the completions are loaded from the internal storage
+	idx, err = Open("testidx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	res, err := idx.Complete(NewCompletionRequest("ni", "suggest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range res.Completions {
+		got = append(got, fmt.Sprintf("%s:%d", c.Text, c.Weight))
+	}
+	expected := []string{"Nirvana:34", "Nick Cave:12"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// the completions of a document are removed from the loaded trie
+	err = idx.Delete("Nirvana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = idx.Complete(NewCompletionRequest("ni", "suggest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Completions) != 1 || res.Completions[0].Text != "Nick Cave" {
+		t.Errorf("expected Nick Cave only, got %v", res.Completions)
+	}
+}
+
+func TestSearchSubFacets(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	products := []map[string]interface{}{
+		{"category": "phone", "brand": "acme", "price": 50},
+		{"category": "phone", "brand": "acme", "price": 500},
+		{"category": "phone", "brand": "globex", "price": 20},
+		{"category": "laptop", "brand": "initech", "price": 900},
+		{"category": "phone", "brand": "globex", "price": 30},
+		{"category": "phone", "brand": "globex", "price": 40},
+	}
+	for i, product := range products {
+		idx := idx1
+		if i >= 3 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), product)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var req SearchRequest
+	err = json.Unmarshal([]byte(`{
+		"query": {"match_all": {}},
+		"facets": {
+			"categories": {
+				"field": "category",
+				"size": 5,
+				"facets": {
+					"brands": {"field": "brand", "size": 2},
+					"prices": {
+						"field": "price",
+						"size": 5,
+						"numeric_ranges": [{"name": "cheap", "max": 100}]
+					}
+				}
+			}
+		}
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, idx := range []Index{idx1, NewIndexAlias(idx1, idx2)} {
+		res, err := idx.Search(&req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		categories := res.Facets["categories"]
+		if len(categories.Terms) == 0 || categories.Terms[0].Term != "phone" {
+			t.Fatalf("expected phone as top category, got %#v", categories.Terms)
+		}
+		phone := categories.Terms[0]
+		brands := phone.Facets["brands"]
+		if brands == nil || len(brands.Terms) != 2 {
+			t.Fatalf("expected 2 brands for phones, got %#v", brands)
+		}
+		prices := phone.Facets["prices"]
+		if prices == nil || len(prices.NumericRanges) != 1 {
+			t.Fatalf("expected cheap price range for phones, got %#v", prices)
+		}
+
+		if idx == idx1 {
+			if phone.Count != 3 || brands.Terms[0].Term != "acme" || brands.Terms[0].Count != 2 ||
+				brands.Terms[1].Count != 1 || prices.NumericRanges[0].Count != 2 {
+				t.Errorf("unexpected phone facets %#v %#v %#v", phone, brands.Terms[0], prices.NumericRanges[0])
+			}
+		} else {
+			// merged across the alias, then sorted again
+			if phone.Count != 5 || brands.Terms[0].Term != "globex" || brands.Terms[0].Count != 3 ||
+				brands.Terms[1].Count != 2 || prices.NumericRanges[0].Count != 4 {
+				t.Errorf("unexpected merged phone facets %#v %#v %#v", phone, brands.Terms[0], prices.NumericRanges[0])
+			}
+		}
+	}
+}
+
+func TestSearchStatsFacets(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	products := []map[string]interface{}{
+		{"category": "phone", "price": 50},
+		{"category": "phone", "price": 500},
+		{"category": "laptop", "price": 900},
+		{"category": "phone", "price": 20},
+		{"category": "laptop", "price": 1100},
+		{"category": "phone"},
+	}
+	for i, product := range products {
+		idx := idx1
+		if i >= 3 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), product)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var req SearchRequest
+	err = json.Unmarshal([]byte(`{
+		"query": {"match_all": {}},
+		"facets": {
+			"price_stats": {"field": "price", "type": "stats"},
+			"price_percentiles": {"field": "price", "type": "percentiles", "percents": [0, 100]},
+			"categories": {
+				"field": "category",
+				"size": 5,
+				"facets": {
+					"price_stats": {"field": "price", "type": "stats"}
+				}
+			}
+		}
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := NewIndexAlias(idx1, idx2).Search(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := res.Facets["price_stats"]
+	expected := search.StatsFacet{Count: 5, Min: 20, Max: 1100, Sum: 2570, Avg: 514}
+	if stats.Stats == nil || *stats.Stats != expected {
+		t.Errorf("expected stats %#v, got %#v", expected, stats.Stats)
+	}
+	if stats.Missing != 1 {
+		t.Errorf("expected 1 missing price, got %d", stats.Missing)
+	}
+
+	percentiles := res.Facets["price_percentiles"].Percentiles
+	if len(percentiles) != 2 || percentiles[0].Value != 20 || percentiles[1].Value != 1100 {
+		t.Errorf("expected percentiles from 20 to 1100, got %v", percentiles)
+	}
+
+	categories := res.Facets["categories"]
+	if len(categories.Terms) != 2 || categories.Terms[0].Term != "phone" {
+		t.Fatalf("expected phone as top category, got %#v", categories.Terms)
+	}
+	phoneStats := categories.Terms[0].Facets["price_stats"].Stats
+	expected = search.StatsFacet{Count: 3, Min: 20, Max: 500, Sum: 570, Avg: 190}
+	if phoneStats == nil || *phoneStats != expected {
+		t.Errorf("expected phone stats %#v, got %#v", expected, phoneStats)
+	}
+
+	req.Facets["price_stats"].AddFacet("invalid", NewFacetRequest("category", 1))
+	err = req.Validate()
+	if err == nil {
+		t.Errorf("expected error for sub-facets of a stats facet")
+	}
+}
+
+func TestSearchHistogramFacets(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	orders := []map[string]interface{}{
+		{"placed": "2017-01-10T10:00:00Z", "amount": 12, "status": "paid"},
+		{"placed": "2017-01-20T10:00:00Z", "amount": 25, "status": "paid"},
+		{"placed": "2017-04-05T10:00:00Z", "amount": 31, "status": "open"},
+		{"placed": "2017-04-25T10:00:00Z", "amount": 38, "status": "paid"},
+	}
+	for i, order := range orders {
+		// the months between the orders are in neither index
+		idx := idx1
+		if i >= 2 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), order)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var req SearchRequest
+	err = json.Unmarshal([]byte(`{
+		"query": {"match_all": {}},
+		"facets": {
+			"months": {
+				"field": "placed",
+				"type": "date_histogram",
+				"date_interval": "month",
+				"time_zone": "UTC",
+				"facets": {
+					"statuses": {"field": "status", "size": 2}
+				}
+			},
+			"amounts": {
+				"field": "amount",
+				"type": "histogram",
+				"interval": 10,
+				"min_doc_count": 1,
+				"extended_bounds": {"min": 0, "max": 100}
+			}
+		}
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = req.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := NewIndexAlias(idx1, idx2).Search(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	months := res.Facets["months"].Histogram
+	expected := []string{
+		"2017-01-01T00:00:00Z",
+		"2017-02-01T00:00:00Z",
+		"2017-03-01T00:00:00Z",
+		"2017-04-01T00:00:00Z",
+	}
+	if len(months) != len(expected) {
+		t.Fatalf("expected %d months, got %d", len(expected), len(months))
+	}
+	for i, month := range months {
+		if month.Name != expected[i] {
+			t.Errorf("expected month %s, got %s", expected[i], month.Name)
+		}
+	}
+	if months[0].Count != 2 || months[1].Count != 0 || months[3].Count != 2 {
+		t.Errorf("unexpected month counts %d %d %d", months[0].Count, months[1].Count, months[3].Count)
+	}
+	statuses := months[3].Facets["statuses"]
+	if statuses == nil || len(statuses.Terms) != 2 {
+		t.Errorf("expected 2 statuses in april, got %#v", statuses)
+	}
+
+	amounts := res.Facets["amounts"].Histogram
+	if len(amounts) != 3 || amounts[0].Name != "10" || amounts[1].Name != "20" ||
+		amounts[2].Name != "30" || amounts[2].Count != 2 {
+		t.Errorf("unexpected amounts %v", res.Facets["amounts"])
+	}
+
+	req.Facets["months"].DateInterval = "fortnight"
+	err = req.Validate()
+	if err == nil {
+		t.Errorf("expected error for invalid date interval")
+	}
+	_, err = idx1.Search(&req)
+	if err == nil {
+		t.Errorf("expected search error for invalid date interval")
+	}
+}
+
+func TestSearchHistogramFacetsMinDocCountSplit(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	// the 10 bucket has 3 hits in each index, the 50 bucket 1 in total
+	amounts := []float64{12, 14, 15, 11, 16, 19, 55}
+	for i, amount := range amounts {
+		idx := idx1
+		if i >= 3 && i < 6 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), map[string]interface{}{"amount": amount})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	facet := NewHistogramFacetRequest("amount", 10)
+	facet.MinDocCount = 5
+	req.AddFacet("amounts", facet)
+
+	res, err := NewIndexAlias(idx1, idx2).Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buckets := res.Facets["amounts"].Histogram
+	if len(buckets) != 1 || buckets[0].Name != "10" || buckets[0].Count != 6 {
+		t.Errorf("expected only bucket 10 with 6 hits, got %v", res.Facets["amounts"])
+	}
+
+	// a single index applies the minimum to its own counts
+	res, err = idx1.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buckets := res.Facets["amounts"].Histogram; len(buckets) != 0 {
+		t.Errorf("expected no buckets, got %v", res.Facets["amounts"])
+	}
+}
+
+func TestSearchCardinalityFacet(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	batch1 := idx1.NewBatch()
+	batch2 := idx2.NewBatch()
+	for i := 0; i < 300; i++ {
+		// users 100 to 199 visit both indexes
+		err = batch1.Index("a"+strconv.Itoa(i), map[string]interface{}{
+			"user":  "user" + strconv.Itoa(i%200),
+			"visit": i,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = batch2.Index("b"+strconv.Itoa(i), map[string]interface{}{
+			"user":  "user" + strconv.Itoa(100+i%200),
+			"visit": i,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = idx1.Batch(batch1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx2.Batch(batch2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.AddFacet("users", NewCardinalityFacetRequest("user"))
+	req.AddFacet("visits", NewCardinalityFacetRequest("visit"))
+	err = req.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the cardinality is approximate, within about 1%
+	checkCardinality := func(res *SearchResult, name string, expected int) {
+		cardinality := res.Facets[name].Cardinality
+		if cardinality == nil {
+			t.Fatalf("expected cardinality for %s", name)
+		}
+		if *cardinality < expected*99/100 || *cardinality > expected*101/100 {
+			t.Errorf("expected about %d %s, got %d", expected, name, *cardinality)
+		}
+	}
+
+	res, err := idx1.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCardinality(res, "users", 200)
+	checkCardinality(res, "visits", 300)
+
+	res, err = NewIndexAlias(idx1, idx2).Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCardinality(res, "users", 300)
+
+	req.Facets["users"].Precision = 30
+	err = req.Validate()
+	if err == nil {
+		t.Errorf("expected error for precision 30")
+	}
+
+	// keyword ids looking like lower precision numeric terms
+	m := NewIndexMapping()
+	codeMapping := NewTextFieldMapping()
+	codeMapping.Analyzer = keyword.Name
+	m.DefaultMapping.AddFieldMappingsAt("code", codeMapping)
+	idx3, err := NewMemOnly(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx3.Close()
+	}()
+	for i := 0; i < 50; i++ {
+		err = idx3.Index(strconv.Itoa(i), map[string]interface{}{
+			"code": strconv.Itoa(10000001 + i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	req = NewSearchRequest(NewMatchAllQuery())
+	req.AddFacet("codes", NewCardinalityFacetRequest("code"))
+	res, err = idx3.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCardinality(res, "codes", 50)
+	if res.Facets["codes"].Total != 50 {
+		t.Errorf("expected total 50, got %d", res.Facets["codes"].Total)
+	}
+}
+
+func TestSearchCollapse(t *testing.T) {
+	m := NewIndexMapping()
+	m.DefaultMapping.AddFieldMappingsAt("product", NewTextFieldMapping())
+	m.DefaultMapping.Properties["product"].Fields[0].Analyzer = keyword.Name
+	m.DefaultMapping.Properties["product"].Fields[0].Store = true
+
+	idx1, err := NewMemOnly(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	variants := []map[string]interface{}{
+		{"product": "Shirt", "rank": 9},
+		{"product": "Shirt", "rank": 7},
+		{"product": "Shoe", "rank": 8},
+		{"product": "Shirt", "rank": 10},
+		{"product": "Shoe", "rank": 3},
+		{"rank": 5},
+	}
+	for i, variant := range variants {
+		idx := idx1
+		if i >= 3 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), variant)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var req SearchRequest
+	err = json.Unmarshal([]byte(`{
+		"query": {"match_all": {}},
+		"sort": ["-rank"],
+		"fields": ["product"],
+		"collapse": {"field": "product", "inner_hits": 2}
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, idx := range []Index{idx1, NewIndexAlias(idx1, idx2)} {
+		res, err := idx.Search(&req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected []string
+		var expectedShirts []string
+		if idx == idx1 {
+			expected = []string{"0", "2"}
+			expectedShirts = []string{"0", "1"}
+		} else {
+			expected = []string{"3", "2", "5"}
+			expectedShirts = []string{"3", "0"}
+		}
+		if len(res.Hits) != len(expected) {
+			t.Fatalf("expected %d hits, got %d", len(expected), len(res.Hits))
+		}
+		for i, hit := range res.Hits {
+			if hit.ID != expected[i] {
+				t.Errorf("expected hit %d to be %s, got %s", i, expected[i], hit.ID)
+			}
+		}
+
+		shirts := res.Hits[0].InnerHits
+		if res.Hits[0].Collapse != "Shirt" || len(shirts) != len(expectedShirts) {
+			t.Fatalf("expected %d shirts, got %s %v", len(expectedShirts), res.Hits[0].Collapse, shirts)
+		}
+		for i, shirt := range shirts {
+			if shirt.ID != expectedShirts[i] || shirt.Fields["product"] != "Shirt" {
+				t.Errorf("expected shirt %s with its fields, got %s %v", expectedShirts[i], shirt.ID, shirt.Fields)
+			}
+		}
+	}
+
+	req.SearchAfter = []string{"a"}
+	err = req.Validate()
+	if err == nil {
+		t.Errorf("expected error for collapse with search after")
+	}
+}
+
+func TestSearchRescore(t *testing.T) {
+	idx1, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx2, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = idx1.Close()
+		_ = idx2.Close()
+	}()
+
+	docs := []string{
+		"fox quick",
+		"the fox is quick",
+		"quick fox",
+		"fox",
+	}
+	for i, doc := range docs {
+		idx := idx1
+		if i%2 == 1 {
+			idx = idx2
+		}
+		err = idx.Index(strconv.Itoa(i), map[string]interface{}{"body": doc})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var req SearchRequest
+	err = json.Unmarshal([]byte(`{
+		"query": {"match": "fox quick", "field": "body"},
+		"size": 2,
+		"explain": true,
+		"rescore": [{
+			"query": {"match_phrase": "quick fox", "field": "body"},
+			"window_size": 3,
+			"query_weight": 0.5,
+			"rescore_query_weight": 10
+		}]
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = req.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := req
+	plain.Rescore = nil
+
+	for _, idx := range []Index{idx1, NewIndexAlias(idx1, idx2)} {
+		before, err := idx.Search(&plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := idx.Search(&req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Hits) != 2 {
+			t.Fatalf("expected 2 hits, got %d", len(res.Hits))
+		}
+		if res.Total != before.Total {
+			t.Errorf("expected total %d, got %d", before.Total, res.Total)
+		}
+
+		// only the exact phrase matches the rescore query
+		top := res.Hits[0]
+		if top.ID != "2" {
+			t.Errorf("expected the phrase match first, got %s", top.ID)
+		}
+		if top.Score != res.MaxScore {
+			t.Errorf("expected max score %f, got %f", top.Score, res.MaxScore)
+		}
+		if top.Sort[0] != strconv.FormatFloat(top.Score, 'g', -1, 64) {
+			t.Errorf("expected sort value of rescored score, got %v", top.Sort)
+		}
+		if top.Expl == nil || top.Expl.Value != top.Score || len(top.Expl.Children) != 2 {
+			t.Errorf("expected rescore explanation, got %v", top.Expl)
+		}
+		second := res.Hits[1]
+		if second.Expl == nil || len(second.Expl.Children) != 1 {
+			t.Errorf("expected original score explanation only, got %v", second.Expl)
+		}
+		for _, hit := range before.Hits {
+			if hit.ID == second.ID && second.Score != hit.Score*0.5 {
+				t.Errorf("expected weighted original score %f, got %f", hit.Score*0.5, second.Score)
+			}
+		}
+	}
+
+	req.Sort = search.SortOrder{&search.SortField{Field: "body"}}
+	err = req.Validate()
+	if err == nil {
+		t.Errorf("expected error for rescore with a field sort")
+	}
+}
+
+func TestSearchFunctionScore(t *testing.T) {
+	m := NewIndexMapping()
+	m.DefaultMapping.AddFieldMappingsAt("loc", NewGeoPointFieldMapping())
+	idx, err := NewMemOnly(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]map[string]interface{}{
+		"a": {"name": "beer", "rating": 1.0, "updated": "2017-01-01T00:00:00Z", "loc": map[string]interface{}{"lon": 0, "lat": 0}},
+		"b": {"name": "beer", "rating": 99.0, "updated": "2017-01-20T00:00:00Z", "loc": map[string]interface{}{"lon": 1, "lat": 1}},
+		"c": {"name": "beer", "rating": 9.0, "updated": "2017-01-10T00:00:00Z", "loc": map[string]interface{}{"lon": 2, "lat": 2}},
+		"d": {"name": "wine"},
+	}
+	for id, doc := range docs {
+		err = idx.Index(id, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{
+			query: `{"query": {"match": "beer", "field": "name"},
+				"functions": [{"field_value_factor": {"field": "rating", "modifier": "log1p"}}]}`,
+			want: []string{"b", "c", "a"},
+		},
+		{
+			query: `{"query": {"match": "beer", "field": "name"},
+				"functions": [{"exp": {"field": "updated", "origin": "2017-01-09T00:00:00Z", "scale": "2d"}}]}`,
+			want: []string{"c", "a", "b"},
+		},
+		{
+			query: `{"query": {"match": "beer", "field": "name"},
+				"functions": [{"gauss": {"field": "loc", "origin": {"lon": 2, "lat": 2}, "scale": "200km"}}],
+				"boost_mode": "replace"}`,
+			want: []string{"c", "b", "a"},
+		},
+	}
+
+	for _, test := range tests {
+		q, err := query.ParseQuery([]byte(test.query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := NewSearchRequest(q)
+		req.Explain = true
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, hit := range res.Hits {
+			got = append(got, hit.ID)
+			if hit.Expl == nil || hit.Expl.Value != hit.Score {
+				t.Errorf("expected explanation of %s to match its score", hit.ID)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expected %v, got %v for %s", test.want, got, test.query)
+		}
+	}
+
+	// random scores are stable for a seed
+	q := NewFunctionScoreQuery(NewMatchAllQuery())
+	q.AddFunction(query.NewRandomScoreFunction(7))
+	q.SetBoostMode("replace")
+	first, err := idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range first.Hits {
+		if first.Hits[i].ID != again.Hits[i].ID || first.Hits[i].Score != again.Hits[i].Score {
+			t.Errorf("expected the same random scores for the same seed")
+		}
+	}
+}
+
+func TestSearchSpanQueries(t *testing.T) {
+	idx, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"a": "the contract allows termination at any time",
+		"b": "termination of this contract requires notice",
+		"c": "contract terms are renewed yearly unless notice of termination is given",
+	}
+	for id, body := range docs {
+		err = idx.Index(id, map[string]interface{}{"body": body})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{
+			query: `{"span_near": [{"span_term": "contract", "field": "body"},
+				{"span_term": "termination", "field": "body"}], "slop": 5, "in_order": true}`,
+			want: []string{"a"},
+		},
+		{
+			query: `{"span_near": [{"span_term": "contract", "field": "body"},
+				{"span_term": "termination", "field": "body"}], "slop": 5}`,
+			want: []string{"a", "b"},
+		},
+		{
+			query: `{"span_first": {"span_term": "contract", "field": "body"}, "end": 1}`,
+			want:  []string{"c"},
+		},
+		{
+			query: `{"span_not": {"span_term": "termination", "field": "body"},
+				"exclude": {"span_term": "notice", "field": "body"}, "pre": 2}`,
+			want: []string{"a", "b"},
+		},
+	}
+
+	for _, test := range tests {
+		q, err := query.ParseQuery([]byte(test.query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := NewSearchRequest(q)
+		req.SortBy([]string{"_id"})
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, hit := range res.Hits {
+			got = append(got, hit.ID)
+			if len(hit.Locations["body"]) == 0 {
+				t.Errorf("expected locations of the span terms in %s", hit.ID)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expected %v, got %v for %s", test.want, got, test.query)
+		}
+	}
+}
+
+func TestSearchSloppyPhrase(t *testing.T) {
+	idx, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"a": "quick brown fox",
+		"b": "quick fox",
+		"c": "fox quick",
+		"d": "quick red brown fox",
+	}
+	for id, body := range docs {
+		err = idx.Index(id, map[string]interface{}{"body": body})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: `body:"quick fox"`, want: []string{"b"}},
+		{query: `body:"quick fox"~1`, want: []string{"b", "a"}},
+		{query: `body:"quick fox"~2`, want: []string{"b", "a", "c", "d"}},
+	}
+	for _, test := range tests {
+		req := NewSearchRequest(NewQueryStringQuery(test.query))
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, hit := range res.Hits {
+			got = append(got, hit.ID)
+		}
+		if len(got) != len(test.want) {
+			t.Fatalf("expected %v, got %v for %s", test.want, got, test.query)
+		}
+		// the closest matches score the highest, c and d are
+		// both two moves away
+		for i := 0; i < len(got) && i < 2; i++ {
+			if got[i] != test.want[i] {
+				t.Errorf("expected %v, got %v for %s", test.want, got, test.query)
+			}
+		}
+	}
+}
+
+func TestSearchMoreLikeThis(t *testing.T) {
+	idx, err := NewMemOnly(NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"a": "bleve indexing bleve search bleve queries",
+		"b": "bleve is a search library with queries",
+		"c": "cooking pasta with tomato sauce",
+		"d": "tomato sauce recipes for pasta",
+		"e": "search engines and search queries",
+	}
+	for id, body := range docs {
+		err = idx.Index(id, map[string]interface{}{"body": body})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// bleve is rarer than search in the index, so scores higher
+	q := NewMoreLikeThisDocumentQuery("a", []string{"body"})
+	q.SetMinTermFreq(1)
+	q.SetMinDocFreq(1)
+	res, err := idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, hit := range res.Hits {
+		got = append(got, hit.ID)
+	}
+	if !reflect.DeepEqual(got, []string{"b", "e"}) {
+		t.Errorf("expected [b e], got %v", got)
+	}
+
+	// only the terms occurring twice in the text are searched for
+	q = NewMoreLikeThisQuery("pasta with tomato, more tomato", []string{"body"})
+	q.SetMinDocFreq(1)
+	res, err = idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, hit := range res.Hits {
+		got = append(got, hit.ID)
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"c", "d"}) {
+		t.Errorf("expected [c d], got %v", got)
+	}
+
+	// terms in too few documents are ignored
+	q = NewMoreLikeThisQuery("pasta pasta", []string{"body"})
+	q.SetMinDocFreq(3)
+	res, err = idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 0 {
+		t.Errorf("expected no hits, got %d", res.Total)
+	}
+}
+
+func TestSearchMoreLikeThisAlias(t *testing.T) {
+	var indexes []Index
+	for i := 0; i < 2; i++ {
+		idx, err := NewMemOnly(NewIndexMapping())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := idx.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		indexes = append(indexes, idx)
+	}
+
+	// the document is only in the first index
+	err := indexes[0].Index("a", map[string]interface{}{"body": "bleve indexing bleve search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := map[string]string{
+		"b": "bleve is a search library",
+		"c": "cooking pasta with tomato sauce",
+	}
+	for id, body := range docs {
+		err = indexes[1].Index(id, map[string]interface{}{"body": body})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alias := NewIndexAlias(indexes...)
+	q := NewMoreLikeThisDocumentQuery("a", []string{"body"})
+	q.SetMinTermFreq(1)
+	q.SetMinDocFreq(1)
+	res, err := alias.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, hit := range res.Hits {
+		got = append(got, hit.ID)
+	}
+	if !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("expected [b], got %v", got)
+	}
+
+	q.SetInclude(true)
+	res, err = alias.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, hit := range res.Hits {
+		got = append(got, hit.ID)
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", got)
+	}
+}
+
+func TestSearchNested(t *testing.T) {
+	reviewsMapping := NewDocumentMapping()
+	reviewsMapping.Nested = true
+	indexMapping := NewIndexMapping()
+	indexMapping.DefaultMapping.AddSubDocumentMapping("reviews", reviewsMapping)
+	idx, err := NewMemOnly(indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	review := func(author string, rating float64) map[string]interface{} {
+		return map[string]interface{}{"author": author, "rating": rating}
+	}
+	err = idx.Index("a", map[string]interface{}{
+		"name":    "alpha",
+		"reviews": []interface{}{review("bob", 5), review("alice", 1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := idx.NewBatch()
+	err = b.Index("b", map[string]interface{}{
+		"name":    "beta",
+		"reviews": []interface{}{review("bob", 1), review("alice", 5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Batch(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(q query.Query) []string {
+		req := NewSearchRequest(q)
+		req.SortBy([]string{"_id"})
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, hit := range res.Hits {
+			got = append(got, hit.ID)
+		}
+		return got
+	}
+	bobRatedFive := func() query.Query {
+		author := NewMatchQuery("bob")
+		author.SetField("reviews.author")
+		five := 5.0
+		inclusive := true
+		rating := NewNumericRangeInclusiveQuery(&five, &five, &inclusive, &inclusive)
+		rating.SetField("reviews.rating")
+		return NewNestedQuery("reviews", NewConjunctionQuery(author, rating))
+	}
+
+	// nested documents are hidden
+	if got := search(NewMatchAllQuery()); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", got)
+	}
+	// conditions hold within a single element
+	if got := search(bobRatedFive()); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("expected [a], got %v", got)
+	}
+	q, err := query.ParseQuery([]byte(`{"nested":"reviews","query":{"query":"+reviews.author:alice +reviews.rating:>=5"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := search(q); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("expected [b], got %v", got)
+	}
+	// the elements are not indexed in the parent
+	author := NewMatchQuery("bob")
+	author.SetField("reviews.author")
+	if got := search(author); len(got) != 0 {
+		t.Errorf("expected no hits, got %v", got)
+	}
+
+	// updates replace the nested documents
+	err = idx.Index("a", map[string]interface{}{
+		"name":    "alpha",
+		"reviews": []interface{}{review("alice", 5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := search(bobRatedFive()); len(got) != 0 {
+		t.Errorf("expected no hits, got %v", got)
+	}
+	count, err := idx.DocCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nested documents are not counted
+	if count != 2 {
+		t.Errorf("expected 2 documents, got %d", count)
+	}
+	err = idx.Delete("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err = idx.DocCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 document, got %d", count)
+	}
+	if got := search(NewMatchAllQuery()); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("expected [a], got %v", got)
+	}
+
+	// several parents in one batch, in any order
+	b = idx.NewBatch()
+	err = b.Index("b", map[string]interface{}{
+		"name":    "beta",
+		"reviews": []interface{}{review("bob", 5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Index("a", map[string]interface{}{"name": "alpha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Batch(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := search(bobRatedFive()); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("expected [b], got %v", got)
+	}
+	count, err = idx.DocCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 documents, got %d", count)
+	}
+	nested, err := idx.GetInternal(nestedCountInternalKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nested) != 8 || nested[7] != 1 {
+		t.Errorf("expected 1 nested document, got %v", nested)
+	}
+
+	err = idx.Index("c\x00d", map[string]interface{}{"name": "gamma"})
+	if err != ErrorNestedID {
+		t.Errorf("expected nested id error, got %v", err)
+	}
+}
+
+func TestSynonymPhraseAcrossExpansion(t *testing.T) {
+	var indexMapping mapping.IndexMappingImpl
+	err := json.Unmarshal([]byte(`{
+		"analysis": {
+			"synonym_maps": {
+				"places": {
+					"type": "`+synonymmap.Name+`",
+					"synonyms": ["usa => united states of america"]
+				}
+			},
+			"token_filters": {
+				"place_synonyms": {
+					"type": "`+synonym.Name+`",
+					"synonym_map": "places"
+				}
+			},
+			"analyzers": {
+				"places": {
+					"type": "`+custom.Name+`",
+					"tokenizer": "unicode",
+					"token_filters": ["to_lower", "place_synonyms"]
+				}
+			}
+		},
+		"default_analyzer": "places"
+	}`), &indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := NewMemOnly(&indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	err = idx.Index("a", map[string]interface{}{"title": "usa today reports"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, phrase := range []string{"america today", "today reports", "usa today", "united states of america today reports"} {
+		q := NewMatchPhraseQuery(phrase)
+		q.SetField("title")
+		res, err := idx.Search(NewSearchRequest(q))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Total != 1 {
+			t.Errorf("expected phrase '%s' to match, got %d hits", phrase, res.Total)
+		}
+	}
+
+	q := NewMatchPhraseQuery("states today")
+	q.SetField("title")
+	res, err := idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 0 {
+		t.Errorf("expected phrase 'states today' not to match, got %d hits", res.Total)
+	}
+}
@@ -63,3 +63,9 @@ func NewBooleanFieldMapping() *mapping.FieldMapping {
 func NewGeoPointFieldMapping() *mapping.FieldMapping {
 	return mapping.NewGeoPointFieldMapping()
 }
+
+// NewCompletionFieldMapping returns a default field mapping for
+// completion (type-ahead) suggestions
+func NewCompletionFieldMapping() *mapping.FieldMapping {
+	return mapping.NewCompletionFieldMapping()
+}
@@ -18,9 +18,11 @@ type customAnalysis struct {
 	CharFilters     map[string]map[string]interface{} `json:"char_filters,omitempty"`
 	Tokenizers      map[string]map[string]interface{} `json:"tokenizers,omitempty"`
 	TokenMaps       map[string]map[string]interface{} `json:"token_maps,omitempty"`
+	SynonymMaps     map[string]map[string]interface{} `json:"synonym_maps,omitempty"`
 	TokenFilters    map[string]map[string]interface{} `json:"token_filters,omitempty"`
 	Analyzers       map[string]map[string]interface{} `json:"analyzers,omitempty"`
 	DateTimeParsers map[string]map[string]interface{} `json:"date_time_parsers,omitempty"`
+	Similarities    map[string]map[string]interface{} `json:"similarities,omitempty"`
 }
 
 func (c *customAnalysis) registerAll(i *IndexMappingImpl) error {
@@ -65,6 +67,12 @@ func (c *customAnalysis) registerAll(i *IndexMappingImpl) error {
 			return err
 		}
 	}
+	for name, config := range c.SynonymMaps {
+		_, err := i.cache.DefineSynonymMap(name, config)
+		if err != nil {
+			return err
+		}
+	}
 	for name, config := range c.TokenFilters {
 		_, err := i.cache.DefineTokenFilter(name, config)
 		if err != nil {
@@ -83,6 +91,12 @@ func (c *customAnalysis) registerAll(i *IndexMappingImpl) error {
 			return err
 		}
 	}
+	for name, config := range c.Similarities {
+		_, err := i.cache.DefineSimilarity(name, config)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -91,9 +105,11 @@ func newCustomAnalysis() *customAnalysis {
 		CharFilters:     make(map[string]map[string]interface{}),
 		Tokenizers:      make(map[string]map[string]interface{}),
 		TokenMaps:       make(map[string]map[string]interface{}),
+		SynonymMaps:     make(map[string]map[string]interface{}),
 		TokenFilters:    make(map[string]map[string]interface{}),
 		Analyzers:       make(map[string]map[string]interface{}),
 		DateTimeParsers: make(map[string]map[string]interface{}),
+		Similarities:    make(map[string]map[string]interface{}),
 	}
 	return &rv
 }
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/edwindvinas/bleve/document"
 	"github.com/edwindvinas/bleve/registry"
 )
 
@@ -36,9 +37,14 @@ import (
 // If not explicitly mapped, default mapping operations
 // are used.  To disable this automatic handling, set
 // Dynamic to false.
+// Sub-document values are flattened into the document, so that values
+// of different elements of an array of sub-documents cannot be told
+// apart. Setting Nested to true indexes each element as its own hidden
+// document instead, which query.NestedQuery joins back to its parent.
 type DocumentMapping struct {
 	Enabled         bool                        `json:"enabled"`
 	Dynamic         bool                        `json:"dynamic"`
+	Nested          bool                        `json:"nested,omitempty"`
 	Properties      map[string]*DocumentMapping `json:"properties,omitempty"`
 	Fields          []*FieldMapping             `json:"fields,omitempty"`
 	DefaultAnalyzer string                      `json:"default_analyzer"`
@@ -74,8 +80,14 @@ func (dm *DocumentMapping) Validate(cache *registry.Cache) error {
 				return err
 			}
 		}
+		if field.Similarity != "" {
+			_, err = cache.SimilarityNamed(field.Similarity)
+			if err != nil {
+				return err
+			}
+		}
 		switch field.Type {
-		case "text", "datetime", "number", "boolean", "geopoint":
+		case "text", "datetime", "number", "boolean", "geopoint", "completion":
 		default:
 			return fmt.Errorf("unknown field type: '%s'", field.Type)
 		}
@@ -273,6 +285,11 @@ func (dm *DocumentMapping) UnmarshalJSON(data []byte) error {
 			if err != nil {
 				return err
 			}
+		case "nested":
+			err := json.Unmarshal(v, &dm.Nested)
+			if err != nil {
+				return err
+			}
 		case "default_analyzer":
 			err := json.Unmarshal(v, &dm.DefaultAnalyzer)
 			if err != nil {
@@ -406,6 +423,11 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 		return
 	}
 
+	if subDocMapping != nil && subDocMapping.Nested && context.nestedPath != pathString {
+		dm.processNested(property, path, context)
+		return
+	}
+
 	propertyValue := reflect.ValueOf(property)
 	if !propertyValue.IsValid() {
 		// cannot do anything with the zero value
@@ -486,6 +508,8 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 				for _, fieldMapping := range subDocMapping.Fields {
 					if fieldMapping.Type == "geopoint" {
 						fieldMapping.processGeoPoint(property, pathString, path, indexes, context)
+					} else if fieldMapping.Type == "completion" {
+						fieldMapping.processCompletion(property, pathString, path, indexes, context)
 					}
 				}
 			}
@@ -496,6 +520,8 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 			for _, fieldMapping := range subDocMapping.Fields {
 				if fieldMapping.Type == "geopoint" {
 					fieldMapping.processGeoPoint(property, pathString, path, indexes, context)
+				} else if fieldMapping.Type == "completion" {
+					fieldMapping.processCompletion(property, pathString, path, indexes, context)
 				}
 			}
 		}
@@ -504,3 +530,66 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 		dm.walkDocument(property, path, indexes, context)
 	}
 }
+
+// processNested indexes each element of a nested sub-document as its own
+// document, held by the top level document.
+func (dm *DocumentMapping) processNested(property interface{}, path []string, context *walkContext) {
+	propertyValue := reflect.ValueOf(property)
+	if !propertyValue.IsValid() {
+		return
+	}
+	switch propertyValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < propertyValue.Len(); i++ {
+			if propertyValue.Index(i).CanInterface() {
+				dm.processNested(propertyValue.Index(i).Interface(), path, context)
+			}
+		}
+		return
+	case reflect.Ptr:
+		ptrElem := propertyValue.Elem()
+		if ptrElem.IsValid() && ptrElem.CanInterface() {
+			dm.processNested(ptrElem.Interface(), path, context)
+		}
+		return
+	}
+
+	pathString := encodePath(path)
+	root := context.root
+	doc := document.NewNestedDocument(root, pathString, len(root.Nested))
+	root.Nested = append(root.Nested, doc)
+	nestedContext := context.im.newWalkContext(doc, context.dm)
+	nestedContext.root = root
+	nestedContext.nestedPath = pathString
+	dm.processProperty(property, path, []uint64{}, nestedContext)
+}
+
+// hasNested returns true if the mapping or one of its sub-document
+// mappings is nested.
+func (dm *DocumentMapping) hasNested() bool {
+	if dm.Nested {
+		return true
+	}
+	for _, property := range dm.Properties {
+		if property.hasNested() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCompletion returns true if the mapping or one of its sub-document
+// mappings has completion fields.
+func (dm *DocumentMapping) hasCompletion() bool {
+	for _, field := range dm.Fields {
+		if field.Type == "completion" {
+			return true
+		}
+	}
+	for _, property := range dm.Properties {
+		if property.hasCompletion() {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,7 @@ package mapping
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/edwindvinas/bleve/analysis"
@@ -54,6 +55,11 @@ type FieldMapping struct {
 	IncludeTermVectors bool   `json:"include_term_vectors,omitempty"`
 	IncludeInAll       bool   `json:"include_in_all,omitempty"`
 	DateFormat         string `json:"date_format,omitempty"`
+
+	// Similarity specifies the name of the similarity used to score
+	// matches in this field. If empty, the IndexMapping.DefaultSimilarity
+	// is used.
+	Similarity string `json:"similarity,omitempty"`
 }
 
 // NewTextFieldMapping returns a default field mapping for text
@@ -135,6 +141,17 @@ func NewGeoPointFieldMapping() *FieldMapping {
 	}
 }
 
+// NewCompletionFieldMapping returns a default field mapping for
+// completion (type-ahead) suggestions. Values are either strings, or
+// objects with an "input" string or list of strings and a numeric
+// "weight" used to rank the completions.
+func NewCompletionFieldMapping() *FieldMapping {
+	return &FieldMapping{
+		Type:  "completion",
+		Index: true,
+	}
+}
+
 // Options returns the indexing options for this field.
 func (fm *FieldMapping) Options() document.IndexingOptions {
 	var rv document.IndexingOptions
@@ -173,6 +190,8 @@ func (fm *FieldMapping) processString(propertyValueString string, pathString str
 				fm.processTime(parsedDateTime, pathString, path, indexes, context)
 			}
 		}
+	} else if fm.Type == "completion" {
+		fm.processCompletion(propertyValueString, pathString, path, indexes, context)
 	}
 }
 
@@ -233,6 +252,85 @@ func (fm *FieldMapping) processGeoPoint(propertyMightBeGeoPoint interface{}, pat
 	}
 }
 
+func (fm *FieldMapping) processCompletion(propertyMightBeCompletion interface{}, pathString string, path []string, indexes []uint64, context *walkContext) {
+	inputs, weight, found := extractCompletion(propertyMightBeCompletion)
+	if found {
+		fieldName := getFieldName(pathString, path, fm)
+		options := fm.Options()
+		for _, input := range inputs {
+			field := document.NewCompletionFieldWithIndexingOptions(fieldName, indexes, input, weight, options)
+			context.doc.AddField(field)
+		}
+
+		// the completions are not useful in the _all field
+		context.excludedFromAll = append(context.excludedFromAll, fieldName)
+	}
+}
+
+// DefaultCompletionWeight is the weight of completion inputs which
+// do not specify one.
+const DefaultCompletionWeight = 1
+
+// extractCompletion interprets a completion value, either a string, or a
+// map or struct with an "input" (string or slice of strings) and an
+// optional numeric "weight".
+func extractCompletion(thing interface{}) (inputs []string, weight uint64, found bool) {
+	if input, ok := thing.(string); ok {
+		return []string{input}, DefaultCompletionWeight, true
+	}
+
+	weight = DefaultCompletionWeight
+	inputVal := lookupCompletionPart(thing, "input", "Input")
+	if inputStr, ok := inputVal.(string); ok {
+		inputs = append(inputs, inputStr)
+	} else if inputVal != nil {
+		val := reflect.ValueOf(inputVal)
+		if val.Kind() == reflect.Slice {
+			for i := 0; i < val.Len(); i++ {
+				elem := val.Index(i)
+				if elem.CanInterface() {
+					if inputStr, ok := elem.Interface().(string); ok {
+						inputs = append(inputs, inputStr)
+					}
+				}
+			}
+		}
+	}
+	weightVal := lookupCompletionPart(thing, "weight", "Weight")
+	if weightVal != nil {
+		val := reflect.ValueOf(weightVal)
+		switch val.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if val.Float() > 0 {
+				weight = uint64(val.Float())
+			} else {
+				weight = 0
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if val.Int() > 0 {
+				weight = uint64(val.Int())
+			} else {
+				weight = 0
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			weight = val.Uint()
+		}
+	}
+	return inputs, weight, len(inputs) > 0
+}
+
+// lookupCompletionPart finds the first of the map keys or struct fields
+// present in thing.
+func lookupCompletionPart(thing interface{}, parts ...string) interface{} {
+	for _, part := range parts {
+		rv := lookupPropertyPathPart(thing, part)
+		if rv != nil {
+			return rv
+		}
+	}
+	return nil
+}
+
 func (fm *FieldMapping) analyzerForField(path []string, context *walkContext) *analysis.Analyzer {
 	analyzerName := fm.Analyzer
 	if analyzerName == "" {
@@ -308,6 +406,11 @@ func (fm *FieldMapping) UnmarshalJSON(data []byte) error {
 			if err != nil {
 				return err
 			}
+		case "similarity":
+			err := json.Unmarshal(v, &fm.Similarity)
+			if err != nil {
+				return err
+			}
 		default:
 			invalidKeys = append(invalidKeys, k)
 		}
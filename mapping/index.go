@@ -23,6 +23,7 @@ import (
 	"github.com/edwindvinas/bleve/analysis/datetime/optional"
 	"github.com/edwindvinas/bleve/document"
 	"github.com/edwindvinas/bleve/registry"
+	"github.com/edwindvinas/bleve/search"
 )
 
 var MappingJSONStrict = false
@@ -48,6 +49,7 @@ type IndexMappingImpl struct {
 	DefaultAnalyzer       string                      `json:"default_analyzer"`
 	DefaultDateTimeParser string                      `json:"default_datetime_parser"`
 	DefaultField          string                      `json:"default_field"`
+	DefaultSimilarity     string                      `json:"default_similarity,omitempty"`
 	StoreDynamic          bool                        `json:"store_dynamic"`
 	IndexDynamic          bool                        `json:"index_dynamic"`
 	CustomAnalysis        *customAnalysis             `json:"analysis,omitempty"`
@@ -84,6 +86,16 @@ func (im *IndexMappingImpl) AddCustomTokenMap(name string, config map[string]int
 	return nil
 }
 
+// AddCustomSynonymMap defines a custom synonym map for use in this mapping
+func (im *IndexMappingImpl) AddCustomSynonymMap(name string, config map[string]interface{}) error {
+	_, err := im.cache.DefineSynonymMap(name, config)
+	if err != nil {
+		return err
+	}
+	im.CustomAnalysis.SynonymMaps[name] = config
+	return nil
+}
+
 // AddCustomTokenFilter defines a custom token filter for use in this mapping
 func (im *IndexMappingImpl) AddCustomTokenFilter(name string, config map[string]interface{}) error {
 	_, err := im.cache.DefineTokenFilter(name, config)
@@ -142,6 +154,16 @@ func (im *IndexMappingImpl) AddCustomDateTimeParser(name string, config map[stri
 	return nil
 }
 
+// AddCustomSimilarity defines a custom similarity for use in this mapping
+func (im *IndexMappingImpl) AddCustomSimilarity(name string, config map[string]interface{}) error {
+	_, err := im.cache.DefineSimilarity(name, config)
+	if err != nil {
+		return err
+	}
+	im.CustomAnalysis.Similarities[name] = config
+	return nil
+}
+
 // NewIndexMapping creates a new IndexMapping that will use all the default indexing rules
 func NewIndexMapping() *IndexMappingImpl {
 	return &IndexMappingImpl{
@@ -170,6 +192,12 @@ func (im *IndexMappingImpl) Validate() error {
 	if err != nil {
 		return err
 	}
+	if im.DefaultSimilarity != "" {
+		_, err = im.cache.SimilarityNamed(im.DefaultSimilarity)
+		if err != nil {
+			return err
+		}
+	}
 	err = im.DefaultMapping.Validate(im.cache)
 	if err != nil {
 		return err
@@ -196,6 +224,34 @@ func (im *IndexMappingImpl) mappingForType(docType string) *DocumentMapping {
 	return docMapping
 }
 
+// HasNested returns true if any of the document mappings has nested
+// sub-documents.
+func (im *IndexMappingImpl) HasNested() bool {
+	if im.DefaultMapping != nil && im.DefaultMapping.hasNested() {
+		return true
+	}
+	for _, docMapping := range im.TypeMapping {
+		if docMapping.hasNested() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCompletion returns true if any of the document mappings has
+// completion fields.
+func (im *IndexMappingImpl) HasCompletion() bool {
+	if im.DefaultMapping != nil && im.DefaultMapping.hasCompletion() {
+		return true
+	}
+	for _, docMapping := range im.TypeMapping {
+		if docMapping.hasCompletion() {
+			return true
+		}
+	}
+	return false
+}
+
 // UnmarshalJSON offers custom unmarshaling with optional strict validation
 func (im *IndexMappingImpl) UnmarshalJSON(data []byte) error {
 
@@ -251,6 +307,11 @@ func (im *IndexMappingImpl) UnmarshalJSON(data []byte) error {
 			if err != nil {
 				return err
 			}
+		case "default_similarity":
+			err := json.Unmarshal(v, &im.DefaultSimilarity)
+			if err != nil {
+				return err
+			}
 		case "default_mapping":
 			err := json.Unmarshal(v, &im.DefaultMapping)
 			if err != nil {
@@ -327,6 +388,11 @@ type walkContext struct {
 	im              *IndexMappingImpl
 	dm              *DocumentMapping
 	excludedFromAll []string
+
+	// root is the top level document, holding the nested documents
+	root *document.Document
+	// nestedPath is the path of the nested sub-document being walked
+	nestedPath string
 }
 
 func (im *IndexMappingImpl) newWalkContext(doc *document.Document, dm *DocumentMapping) *walkContext {
@@ -335,6 +401,7 @@ func (im *IndexMappingImpl) newWalkContext(doc *document.Document, dm *DocumentM
 		im:              im,
 		dm:              dm,
 		excludedFromAll: []string{},
+		root:            doc,
 	}
 }
 
@@ -423,6 +490,54 @@ func (im *IndexMappingImpl) FieldAnalyzer(field string) string {
 	return im.AnalyzerNameForPath(field)
 }
 
+// SimilarityForField returns the similarity used to score matches in a
+// field, or nil to use the default scoring.
+func (im *IndexMappingImpl) SimilarityForField(field string) search.Similarity {
+	name := im.similarityNameForPath(field)
+	if name == "" {
+		return nil
+	}
+	similarity, err := im.cache.SimilarityNamed(name)
+	if err != nil {
+		logger.Printf("error using similarity named: %s", name)
+		return nil
+	}
+	return similarity
+}
+
+// FieldTypeForPath returns the type of the field explicitly mapped at
+// path, or "" if it is not mapped.
+func (im *IndexMappingImpl) FieldTypeForPath(path string) string {
+	for _, docMapping := range im.TypeMapping {
+		field := docMapping.fieldDescribedByPath(path)
+		if field != nil {
+			return field.Type
+		}
+	}
+	field := im.DefaultMapping.fieldDescribedByPath(path)
+	if field != nil {
+		return field.Type
+	}
+	return ""
+}
+
+func (im *IndexMappingImpl) similarityNameForPath(path string) string {
+	// first we look for explicit mapping on the field
+	for _, docMapping := range im.TypeMapping {
+		field := docMapping.fieldDescribedByPath(path)
+		if field != nil && field.Similarity != "" {
+			return field.Similarity
+		}
+	}
+	// now try the default mapping
+	field := im.DefaultMapping.fieldDescribedByPath(path)
+	if field != nil && field.Similarity != "" {
+		return field.Similarity
+	}
+
+	return im.DefaultSimilarity
+}
+
 // wrapper to satisfy new interface
 
 func (im *IndexMappingImpl) DefaultSearchField() string {
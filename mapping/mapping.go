@@ -36,6 +36,12 @@ func SetLog(l *log.Logger) {
 	logger = l
 }
 
+// A FieldTypeLookup is an IndexMapping which knows the type
+// fields are explicitly mapped with.
+type FieldTypeLookup interface {
+	FieldTypeForPath(path string) string
+}
+
 type IndexMapping interface {
 	MapDocument(doc *document.Document, data interface{}) error
 	Validate() error
@@ -20,10 +20,14 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/edwindvinas/bleve/analysis/analyzer/custom"
+	"github.com/edwindvinas/bleve/analysis/synonymmap"
+	"github.com/edwindvinas/bleve/analysis/token/synonym"
 	"github.com/edwindvinas/bleve/analysis/tokenizer/exception"
 	"github.com/edwindvinas/bleve/analysis/tokenizer/regexp"
 	"github.com/edwindvinas/bleve/document"
 	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search/similarity/bm25"
 )
 
 var mappingSource = []byte(`{
@@ -900,3 +904,185 @@ func TestMappingForGeo(t *testing.T) {
 		t.Errorf("expected to find geo point, did not")
 	}
 }
+
+func TestMappingSimilarity(t *testing.T) {
+	mappingBytes := []byte(`{
+		"default_similarity": "bm25",
+		"analysis": {
+			"similarities": {
+				"short_text": {
+					"type": "bm25",
+					"k1": 2.0,
+					"b": 0.3
+				}
+			}
+		},
+		"default_mapping": {
+			"properties": {
+				"title": {
+					"fields": [
+						{
+							"type": "text",
+							"similarity": "short_text"
+						}
+					]
+				}
+			}
+		}
+	}`)
+
+	var im IndexMappingImpl
+	err := json.Unmarshal(mappingBytes, &im)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = im.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	title := im.SimilarityForField("title")
+	if !reflect.DeepEqual(title, bm25.New(2.0, 0.3)) {
+		t.Errorf("expected custom bm25 similarity for title, got %#v", title)
+	}
+	body := im.SimilarityForField("body")
+	if !reflect.DeepEqual(body, bm25.New(bm25.DefaultK1, bm25.DefaultB)) {
+		t.Errorf("expected default bm25 similarity for body, got %#v", body)
+	}
+
+	im.DefaultSimilarity = ""
+	if im.SimilarityForField("body") != nil {
+		t.Errorf("expected nil similarity without a default")
+	}
+
+	im.DefaultSimilarity = "unknown"
+	err = im.Validate()
+	if err == nil {
+		t.Errorf("expected error validating unknown similarity")
+	}
+
+	im = IndexMappingImpl{}
+	err = json.Unmarshal([]byte(`{"analysis":{"similarities":{"bad":{"type":"bm25","b":2}}}}`), &im)
+	if err == nil {
+		t.Errorf("expected error for invalid bm25 parameter b")
+	}
+}
+
+func TestMappingSynonyms(t *testing.T) {
+	mappingBytes := []byte(`{
+		"analysis": {
+			"synonym_maps": {
+				"products": {
+					"type": "` + synonymmap.Name + `",
+					"synonyms": [
+						"tv, television",
+						"usa => united states of america"
+					]
+				}
+			},
+			"token_filters": {
+				"product_synonyms": {
+					"type": "` + synonym.Name + `",
+					"synonym_map": "products"
+				}
+			},
+			"analyzers": {
+				"products": {
+					"type": "` + custom.Name + `",
+					"tokenizer": "unicode",
+					"token_filters": ["to_lower", "product_synonyms"]
+				}
+			}
+		}
+	}`)
+
+	var im IndexMappingImpl
+	err := json.Unmarshal(mappingBytes, &im)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := im.CustomAnalysis.SynonymMaps["products"]; !ok {
+		t.Errorf("expected synonym map to be kept in custom analysis")
+	}
+
+	tokens, err := im.AnalyzeText("products", []byte("USA TV"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var terms []string
+	var positions []int
+	for _, token := range tokens {
+		terms = append(terms, string(token.Term))
+		positions = append(positions, token.Position)
+	}
+	expectedTerms := []string{"united", "states", "of", "america", "tv", "television"}
+	expectedPositions := []int{1, 2, 3, 4, 5, 5}
+	if !reflect.DeepEqual(terms, expectedTerms) {
+		t.Errorf("expected terms %v, got %v", expectedTerms, terms)
+	}
+	if !reflect.DeepEqual(positions, expectedPositions) {
+		t.Errorf("expected positions %v, got %v", expectedPositions, positions)
+	}
+
+	err = im.AddCustomTokenFilter("missing_synonyms", map[string]interface{}{
+		"type":        synonym.Name,
+		"synonym_map": "missing",
+	})
+	if err == nil {
+		t.Errorf("expected error for unknown synonym map")
+	}
+}
+
+func TestMappingNested(t *testing.T) {
+	mappingBytes := []byte(`{
+		"default_mapping": {
+			"properties": {
+				"reviews": {
+					"nested": true
+				}
+			}
+		}
+	}`)
+	var im IndexMappingImpl
+	err := json.Unmarshal(mappingBytes, &im)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !im.HasNested() {
+		t.Fatalf("expected mapping to have nested sub-documents")
+	}
+
+	doc := document.NewDocument("x")
+	err = im.MapDocument(doc, map[string]interface{}{
+		"name": "product",
+		"reviews": []interface{}{
+			map[string]interface{}{"author": "bob"},
+			map[string]interface{}{"author": "alice"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range doc.Fields {
+		if field.Name() == "reviews.author" {
+			t.Errorf("expected nested field not to be indexed in the parent")
+		}
+	}
+	if len(doc.Nested) != 2 {
+		t.Fatalf("expected 2 nested documents, got %d", len(doc.Nested))
+	}
+	for i, author := range []string{"bob", "alice"} {
+		nested := doc.Nested[i]
+		if document.NestedParentID(nested.ID) != "x" {
+			t.Errorf("expected nested document of x, got %q", nested.ID)
+		}
+		var got []string
+		for _, field := range nested.Fields {
+			got = append(got, field.Name()+"="+string(field.Value()))
+		}
+		expected := []string{document.NestedPathField + "=reviews", "reviews.author=" + author}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected fields %v, got %v", expected, got)
+		}
+	}
+}
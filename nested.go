@@ -0,0 +1,188 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+// Nested documents are indexed alongside their parent, under identifiers
+// made of the parent identifier and a suffix starting with
+// document.NestedSeparator. This relies on internal identifiers being
+// ordered like the external ones, which the nested documents of a
+// parent then immediately follow.
+//
+// The number of nested documents is kept in the internal storage, so that
+// the document count of the index, and the statistics scoring relies on,
+// only include the parent documents.
+
+// nestedCountInternalKey holds the number of nested documents
+var nestedCountInternalKey = []byte("_nested_count")
+
+type nestedIndexMapping interface {
+	HasNested() bool
+}
+
+// hasNested returns true if the mapping can create nested documents.
+func hasNested(m mapping.IndexMapping) bool {
+	if nm, ok := m.(nestedIndexMapping); ok {
+		return nm.HasNested()
+	}
+	return false
+}
+
+// checkNestedID returns an error if id cannot identify a document of an
+// index with nested documents.
+func checkNestedID(m mapping.IndexMapping, id string) error {
+	if hasNested(m) && document.IsNestedID(id) {
+		return ErrorNestedID
+	}
+	return nil
+}
+
+// batchNested adds the nested documents of the batch documents to the
+// batch, and the deletion of those they previously had, along with the
+// updated number of nested documents. Batches must not be executed
+// concurrently, as the number is read before the batch is.
+func batchNested(indexReader index.IndexReader, b *index.Batch) error {
+	ids := make([]string, 0, len(b.IndexOps))
+	added := uint64(0)
+	for id, doc := range b.IndexOps {
+		if document.IsNestedID(id) {
+			continue
+		}
+		ids = append(ids, id)
+		if doc != nil {
+			for _, nested := range doc.Nested {
+				b.Update(nested)
+			}
+			added += uint64(len(doc.Nested))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	// the reader moves forward only
+	sort.Strings(ids)
+
+	docIDReader, err := indexReader.DocIDReaderAll()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = docIDReader.Close()
+	}()
+	removed := uint64(0)
+	for _, id := range ids {
+		prefix := []byte(id + document.NestedSeparator)
+		internalID, err := docIDReader.Advance(index.IndexInternalID(prefix))
+		for err == nil && internalID != nil && bytes.HasPrefix(internalID, prefix) {
+			nestedID := string(internalID)
+			if _, ok := b.IndexOps[nestedID]; !ok {
+				b.Delete(nestedID)
+			}
+			removed++
+			internalID, err = docIDReader.Next()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	count, err := nestedCount(indexReader)
+	if err != nil {
+		return err
+	}
+	count += added
+	if removed > count {
+		removed = count
+	}
+	count -= removed
+	val := make([]byte, 8)
+	binary.BigEndian.PutUint64(val, count)
+	b.SetInternal(nestedCountInternalKey, val)
+	return nil
+}
+
+// nestedCount returns the number of nested documents seen by the reader.
+func nestedCount(indexReader index.IndexReader) (uint64, error) {
+	val, err := indexReader.GetInternal(nestedCountInternalKey)
+	if err != nil || len(val) != 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(val), nil
+}
+
+// parentReader is an index reader whose document count excludes the
+// nested documents.
+type parentReader struct {
+	index.IndexReader
+	count uint64
+}
+
+func (r *parentReader) DocCount() (uint64, error) {
+	return r.count, nil
+}
+
+// parentFieldStatsReader is a parentReader keeping the field statistics
+// of the reader it wraps.
+type parentFieldStatsReader struct {
+	*parentReader
+	index.IndexReaderFieldStats
+}
+
+// hideNestedCount wraps the reader so that its document count only
+// includes the parent documents.
+func hideNestedCount(indexReader index.IndexReader) (index.IndexReader, error) {
+	count, err := indexReader.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	nested, err := nestedCount(indexReader)
+	if err != nil {
+		return nil, err
+	}
+	if nested > count {
+		nested = count
+	}
+	rv := &parentReader{
+		IndexReader: indexReader,
+		count:       count - nested,
+	}
+	if fsr, ok := indexReader.(index.IndexReaderFieldStats); ok {
+		return &parentFieldStatsReader{
+			parentReader:          rv,
+			IndexReaderFieldStats: fsr,
+		}, nil
+	}
+	return rv, nil
+}
+
+// hideNested wraps the searcher so that it does not return nested
+// documents.
+func hideNested(indexReader index.IndexReader, s search.Searcher) search.Searcher {
+	return searcher.NewFilteringSearcher(s, func(d *search.DocumentMatch) bool {
+		id, err := indexReader.ExternalID(d.IndexInternalID)
+		return err == nil && !document.IsNestedID(id)
+	})
+}
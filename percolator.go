@@ -0,0 +1,251 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/query"
+)
+
+// percolateDocID is the identifier of the document being percolated in
+// its temporary index.
+const percolateDocID = "_percolate"
+
+// A Percolator holds queries registered under identifiers, and finds
+// which of them match a document, the reverse of a search.
+// Each percolated document is analyzed with the IndexMapping into a
+// temporary in-memory index. Queries are first prefiltered by the terms
+// they require, see query.ExtractTerms, so that only queries which may
+// match the document are run against it.
+// A Percolator is safe for concurrent use.
+type Percolator struct {
+	m mapping.IndexMapping
+
+	mutex      sync.RWMutex
+	queries    map[string]query.Query
+	terms      map[string][]query.FieldTerm
+	byTerm     map[query.FieldTerm]map[string]struct{}
+	unfiltered map[string]struct{}
+}
+
+// NewPercolator creates a Percolator analyzing documents with the
+// provided mapping.
+func NewPercolator(m mapping.IndexMapping) (*Percolator, error) {
+	err := m.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &Percolator{
+		m:          m,
+		queries:    make(map[string]query.Query),
+		terms:      make(map[string][]query.FieldTerm),
+		byTerm:     make(map[query.FieldTerm]map[string]struct{}),
+		unfiltered: make(map[string]struct{}),
+	}, nil
+}
+
+// Register adds the query under the identifier, replacing any query
+// previously registered under it.
+func (p *Percolator) Register(id string, q query.Query) error {
+	if q == nil {
+		return fmt.Errorf("percolator query must not be nil")
+	}
+	if vq, ok := q.(query.ValidatableQuery); ok {
+		err := vq.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	terms, ok, err := query.ExtractTerms(p.m, q)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.unregisterLOCKED(id)
+	p.queries[id] = q
+	if !ok {
+		p.unfiltered[id] = struct{}{}
+		return nil
+	}
+	p.terms[id] = terms
+	for _, term := range terms {
+		ids := p.byTerm[term]
+		if ids == nil {
+			ids = make(map[string]struct{})
+			p.byTerm[term] = ids
+		}
+		ids[id] = struct{}{}
+	}
+	return nil
+}
+
+// RegisterJSON adds the query, in the JSON form accepted by
+// query.ParseQuery, under the identifier.
+func (p *Percolator) RegisterJSON(id string, data []byte) error {
+	q, err := query.ParseQuery(data)
+	if err != nil {
+		return err
+	}
+	return p.Register(id, q)
+}
+
+// Unregister removes the query registered under the identifier, if any.
+func (p *Percolator) Unregister(id string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.unregisterLOCKED(id)
+}
+
+func (p *Percolator) unregisterLOCKED(id string) {
+	for _, term := range p.terms[id] {
+		ids := p.byTerm[term]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(p.byTerm, term)
+		}
+	}
+	delete(p.terms, id)
+	delete(p.unfiltered, id)
+	delete(p.queries, id)
+}
+
+// Query returns the query registered under the identifier, or nil.
+func (p *Percolator) Query(id string) query.Query {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.queries[id]
+}
+
+// Count returns the number of registered queries.
+func (p *Percolator) Count() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return len(p.queries)
+}
+
+// Percolate returns the sorted identifiers of the registered queries
+// matching the document.
+func (p *Percolator) Percolate(data interface{}) (ids []string, err error) {
+	idx, err := NewMemOnly(p.m)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := idx.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+	err = idx.Index(percolateDocID, data)
+	if err != nil {
+		return nil, err
+	}
+	i, _, err := idx.Advanced()
+	if err != nil {
+		return nil, err
+	}
+	indexReader, err := i.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := indexReader.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	candidates, err := p.candidates(indexReader)
+	if err != nil {
+		return nil, err
+	}
+	ids = make([]string, 0, len(candidates))
+	for id := range candidates {
+		matched, err := p.matches(indexReader, p.queries[id])
+		if err != nil {
+			return nil, fmt.Errorf("percolator query '%s': %v", id, err)
+		}
+		if matched {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// candidates returns the queries which require none of the terms they
+// were registered with, and those requiring one of the document terms.
+func (p *Percolator) candidates(indexReader index.IndexReader) (map[string]struct{}, error) {
+	rv := make(map[string]struct{}, len(p.unfiltered))
+	for id := range p.unfiltered {
+		rv[id] = struct{}{}
+	}
+	fields, err := indexReader.Fields()
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		dict, err := indexReader.FieldDict(field)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := dict.Next()
+		for err == nil && entry != nil {
+			for id := range p.byTerm[query.FieldTerm{Field: field, Term: entry.Term}] {
+				rv[id] = struct{}{}
+			}
+			entry, err = dict.Next()
+		}
+		if cerr := dict.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+func (p *Percolator) matches(indexReader index.IndexReader, q query.Query) (matched bool, err error) {
+	searcher, err := q.Searcher(indexReader, p.m, search.SearcherOptions{})
+	if err != nil {
+		return false, err
+	}
+	if hasNested(p.m) {
+		searcher = hideNested(indexReader, searcher)
+	}
+	defer func() {
+		if serr := searcher.Close(); err == nil && serr != nil {
+			err = serr
+		}
+	}()
+	searchContext := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(searcher.DocumentMatchPoolSize(), 0),
+	}
+	match, err := searcher.Next(searchContext)
+	if err != nil {
+		return false, err
+	}
+	return match != nil, nil
+}
@@ -0,0 +1,123 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/mapping"
+)
+
+func TestPercolator(t *testing.T) {
+	p, err := NewPercolator(mapping.NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.Register("beer", NewMatchQuery("beer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Register("ipa", NewQueryStringQuery("+style:ipa -name:light"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.RegisterJSON("strong", []byte(`{"min":8,"field":"abv"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.RegisterJSON("phrase", []byte(`{"match_phrase":"cold beer"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Register("wine", NewTermQuery("wine"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Count() != 5 {
+		t.Errorf("expected 5 queries, got %d", p.Count())
+	}
+
+	tests := []struct {
+		doc    interface{}
+		expect []string
+	}{
+		{
+			doc: map[string]interface{}{
+				"name":  "Hop Bomb",
+				"style": "IPA",
+				"abv":   9.5,
+				"desc":  "a cold beer",
+			},
+			expect: []string{"beer", "ipa", "phrase", "strong"},
+		},
+		{
+			doc: map[string]interface{}{
+				"name":  "Light Session",
+				"style": "ipa",
+				"abv":   4.0,
+				"desc":  "beer that is cold",
+			},
+			expect: []string{"beer"},
+		},
+		{
+			doc: map[string]interface{}{
+				"name": "Red",
+				"desc": "a glass of water",
+			},
+			expect: []string{},
+		},
+	}
+	for i, test := range tests {
+		ids, err := p.Percolate(test.doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, test.expect) {
+			t.Errorf("test %d: expected %v, got %v", i, test.expect, ids)
+		}
+	}
+
+	// replacing and removing queries
+	err = p.Register("beer", NewMatchQuery("water"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Unregister("ipa")
+	p.Unregister("missing")
+	if p.Count() != 4 {
+		t.Errorf("expected 4 queries, got %d", p.Count())
+	}
+	ids, err := p.Percolate(tests[2].doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"beer"}) {
+		t.Errorf("expected [beer], got %v", ids)
+	}
+	ids, err = p.Percolate(tests[0].doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"phrase", "strong"}) {
+		t.Errorf("expected [phrase strong], got %v", ids)
+	}
+
+	err = p.RegisterJSON("bad", []byte(`{"min":8,"max":2,"inclusive_min":false,"field":"abv","bogus":`))
+	if err == nil {
+		t.Errorf("expected error registering invalid json")
+	}
+}
@@ -88,6 +88,15 @@ func NewFuzzyQuery(term string) *query.FuzzyQuery {
 	return query.NewFuzzyQuery(term)
 }
 
+// NewFunctionScoreQuery creates a new Query which modifies the score
+// of the documents matching the given query with functions, added with
+// AddFunction.  Functions include the value of a numeric field, decays
+// with the distance of a numeric, date or geo point field from an
+// origin, and random scores.
+func NewFunctionScoreQuery(q query.Query) *query.FunctionScoreQuery {
+	return query.NewFunctionScoreQuery(q)
+}
+
 // NewMatchAllQuery creates a Query which will
 // match all documents in the index.
 func NewMatchAllQuery() *query.MatchAllQuery {
@@ -122,6 +131,29 @@ func NewMatchQuery(match string) *query.MatchQuery {
 	return query.NewMatchQuery(match)
 }
 
+// NewMoreLikeThisQuery creates a new Query finding
+// documents similar to the text, in the given fields.
+// The most significant terms of the text, frequent in
+// it but rare in the index, are searched for.
+func NewMoreLikeThisQuery(like string, fields []string) *query.MoreLikeThisQuery {
+	return query.NewMoreLikeThisQuery(like, fields)
+}
+
+// NewMoreLikeThisDocumentQuery creates a new Query
+// finding documents similar to the fields of the
+// document with the given id.  Through an IndexAlias
+// only the stored fields of the document are used.
+func NewMoreLikeThisDocumentQuery(id string, fields []string) *query.MoreLikeThisQuery {
+	return query.NewMoreLikeThisDocumentQuery(id, fields)
+}
+
+// NewNestedQuery creates a Query matching the documents with
+// elements of the nested sub-document at path matched by the
+// given query, evaluated against each element on its own.
+func NewNestedQuery(path string, q query.Query) *query.NestedQuery {
+	return query.NewNestedQuery(path, q)
+}
+
 // NewNumericRangeQuery creates a new Query for ranges
 // of numeric values.
 // Either, but not both endpoints can be nil.
@@ -187,6 +219,44 @@ func NewQueryStringQuery(q string) *query.QueryStringQuery {
 	return query.NewQueryStringQuery(q)
 }
 
+// NewSpanTermQuery creates a new span Query matching
+// each occurrence of a term.  Span queries can be
+// combined by NewSpanNearQuery, NewSpanOrQuery,
+// NewSpanNotQuery and NewSpanFirstQuery.  Queried
+// fields must have been indexed with IncludeTermVectors
+// set to true.
+func NewSpanTermQuery(term string) *query.SpanTermQuery {
+	return query.NewSpanTermQuery(term)
+}
+
+// NewSpanNearQuery creates a new span Query matching
+// a span of each of the clauses, with at most slop
+// positions between them, in the order of the clauses
+// if inOrder is true.
+func NewSpanNearQuery(clauses []query.SpanQuery, slop int, inOrder bool) *query.SpanNearQuery {
+	return query.NewSpanNearQuery(clauses, slop, inOrder)
+}
+
+// NewSpanOrQuery creates a new span Query matching
+// the spans of any of the clauses.
+func NewSpanOrQuery(clauses ...query.SpanQuery) *query.SpanOrQuery {
+	return query.NewSpanOrQuery(clauses)
+}
+
+// NewSpanNotQuery creates a new span Query matching
+// the spans of include which do not overlap a span
+// of exclude.
+func NewSpanNotQuery(include, exclude query.SpanQuery) *query.SpanNotQuery {
+	return query.NewSpanNotQuery(include, exclude)
+}
+
+// NewSpanFirstQuery creates a new span Query matching
+// the spans of match which end within the first end
+// positions of the field.
+func NewSpanFirstQuery(match query.SpanQuery, end int) *query.SpanFirstQuery {
+	return query.NewSpanFirstQuery(match, end)
+}
+
 // NewTermQuery creates a new Query for finding an
 // exact term match in the index.
 func NewTermQuery(term string) *query.TermQuery {
@@ -216,3 +286,11 @@ func NewGeoBoundingBoxQuery(topLeftLon, topLeftLat, bottomRightLon, bottomRightL
 func NewGeoDistanceQuery(lon, lat float64, distance string) *query.GeoDistanceQuery {
 	return query.NewGeoDistanceQuery(lon, lat, distance)
 }
+
+// NewGeoPolygonQuery creates a new Query for performing geo polygon
+// searches. The arguments describe the [lon, lat] vertices of a polygon.
+// Documents which have an indexed geo point inside the polygon will be
+// returned. Areas to exclude can be added with AddHole.
+func NewGeoPolygonQuery(points [][]float64) *query.GeoPolygonQuery {
+	return query.NewGeoPolygonQuery(points)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/search"
 	"github.com/edwindvinas/bleve/search/highlight"
 )
 
@@ -33,20 +34,26 @@ var highlighters = make(HighlighterRegistry, 0)
 var charFilters = make(CharFilterRegistry, 0)
 var tokenizers = make(TokenizerRegistry, 0)
 var tokenMaps = make(TokenMapRegistry, 0)
+var synonymMaps = make(SynonymMapRegistry, 0)
 var tokenFilters = make(TokenFilterRegistry, 0)
 var analyzers = make(AnalyzerRegistry, 0)
 var dateTimeParsers = make(DateTimeParserRegistry, 0)
 
+// scoring
+var similarities = make(SimilarityRegistry, 0)
+
 type Cache struct {
 	CharFilters        *CharFilterCache
 	Tokenizers         *TokenizerCache
 	TokenMaps          *TokenMapCache
+	SynonymMaps        *SynonymMapCache
 	TokenFilters       *TokenFilterCache
 	Analyzers          *AnalyzerCache
 	DateTimeParsers    *DateTimeParserCache
 	FragmentFormatters *FragmentFormatterCache
 	Fragmenters        *FragmenterCache
 	Highlighters       *HighlighterCache
+	Similarities       *SimilarityCache
 }
 
 func NewCache() *Cache {
@@ -54,12 +61,14 @@ func NewCache() *Cache {
 		CharFilters:        NewCharFilterCache(),
 		Tokenizers:         NewTokenizerCache(),
 		TokenMaps:          NewTokenMapCache(),
+		SynonymMaps:        NewSynonymMapCache(),
 		TokenFilters:       NewTokenFilterCache(),
 		Analyzers:          NewAnalyzerCache(),
 		DateTimeParsers:    NewDateTimeParserCache(),
 		FragmentFormatters: NewFragmentFormatterCache(),
 		Fragmenters:        NewFragmenterCache(),
 		Highlighters:       NewHighlighterCache(),
+		Similarities:       NewSimilarityCache(),
 	}
 }
 
@@ -111,6 +120,18 @@ func (c *Cache) DefineTokenMap(name string, config map[string]interface{}) (anal
 	return c.TokenMaps.DefineTokenMap(name, typ, config, c)
 }
 
+func (c *Cache) SynonymMapNamed(name string) (analysis.SynonymMap, error) {
+	return c.SynonymMaps.SynonymMapNamed(name, c)
+}
+
+func (c *Cache) DefineSynonymMap(name string, config map[string]interface{}) (analysis.SynonymMap, error) {
+	typ, err := typeFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return c.SynonymMaps.DefineSynonymMap(name, typ, config, c)
+}
+
 func (c *Cache) TokenFilterNamed(name string) (analysis.TokenFilter, error) {
 	return c.TokenFilters.TokenFilterNamed(name, c)
 }
@@ -182,3 +203,15 @@ func (c *Cache) DefineHighlighter(name string, config map[string]interface{}) (h
 	}
 	return c.Highlighters.DefineHighlighter(name, typ, config, c)
 }
+
+func (c *Cache) SimilarityNamed(name string) (search.Similarity, error) {
+	return c.Similarities.SimilarityNamed(name, c)
+}
+
+func (c *Cache) DefineSimilarity(name string, config map[string]interface{}) (search.Similarity, error) {
+	typ, err := typeFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return c.Similarities.DefineSimilarity(name, typ, config, c)
+}
@@ -0,0 +1,89 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/search"
+)
+
+func RegisterSimilarity(name string, constructor SimilarityConstructor) {
+	_, exists := similarities[name]
+	if exists {
+		panic(fmt.Errorf("attempted to register duplicate similarity named '%s'", name))
+	}
+	similarities[name] = constructor
+}
+
+type SimilarityConstructor func(config map[string]interface{}, cache *Cache) (search.Similarity, error)
+type SimilarityRegistry map[string]SimilarityConstructor
+
+type SimilarityCache struct {
+	*ConcurrentCache
+}
+
+func NewSimilarityCache() *SimilarityCache {
+	return &SimilarityCache{
+		NewConcurrentCache(),
+	}
+}
+
+func SimilarityBuild(name string, config map[string]interface{}, cache *Cache) (interface{}, error) {
+	cons, registered := similarities[name]
+	if !registered {
+		return nil, fmt.Errorf("no similarity with name or type '%s' registered", name)
+	}
+	similarity, err := cons(config, cache)
+	if err != nil {
+		return nil, fmt.Errorf("error building similarity: %v", err)
+	}
+	return similarity, nil
+}
+
+func (c *SimilarityCache) SimilarityNamed(name string, cache *Cache) (search.Similarity, error) {
+	item, err := c.ItemNamed(name, cache, SimilarityBuild)
+	if err != nil {
+		return nil, err
+	}
+	return item.(search.Similarity), nil
+}
+
+func (c *SimilarityCache) DefineSimilarity(name string, typ string, config map[string]interface{}, cache *Cache) (search.Similarity, error) {
+	item, err := c.DefineItem(name, typ, config, cache, SimilarityBuild)
+	if err != nil {
+		if err == ErrAlreadyDefined {
+			return nil, fmt.Errorf("similarity named '%s' already defined", name)
+		}
+		return nil, err
+	}
+	return item.(search.Similarity), nil
+}
+
+func SimilarityTypesAndInstances() ([]string, []string) {
+	emptyConfig := map[string]interface{}{}
+	emptyCache := NewCache()
+	var types []string
+	var instances []string
+	for name, cons := range similarities {
+		_, err := cons(emptyConfig, emptyCache)
+		if err == nil {
+			instances = append(instances, name)
+		} else {
+			types = append(types, name)
+		}
+	}
+	return types, instances
+}
@@ -0,0 +1,89 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/analysis"
+)
+
+func RegisterSynonymMap(name string, constructor SynonymMapConstructor) {
+	_, exists := synonymMaps[name]
+	if exists {
+		panic(fmt.Errorf("attempted to register duplicate synonym map named '%s'", name))
+	}
+	synonymMaps[name] = constructor
+}
+
+type SynonymMapConstructor func(config map[string]interface{}, cache *Cache) (analysis.SynonymMap, error)
+type SynonymMapRegistry map[string]SynonymMapConstructor
+
+type SynonymMapCache struct {
+	*ConcurrentCache
+}
+
+func NewSynonymMapCache() *SynonymMapCache {
+	return &SynonymMapCache{
+		NewConcurrentCache(),
+	}
+}
+
+func SynonymMapBuild(name string, config map[string]interface{}, cache *Cache) (interface{}, error) {
+	cons, registered := synonymMaps[name]
+	if !registered {
+		return nil, fmt.Errorf("no synonym map with name or type '%s' registered", name)
+	}
+	synonymMap, err := cons(config, cache)
+	if err != nil {
+		return nil, fmt.Errorf("error building synonym map: %v", err)
+	}
+	return synonymMap, nil
+}
+
+func (c *SynonymMapCache) SynonymMapNamed(name string, cache *Cache) (analysis.SynonymMap, error) {
+	item, err := c.ItemNamed(name, cache, SynonymMapBuild)
+	if err != nil {
+		return nil, err
+	}
+	return item.(analysis.SynonymMap), nil
+}
+
+func (c *SynonymMapCache) DefineSynonymMap(name string, typ string, config map[string]interface{}, cache *Cache) (analysis.SynonymMap, error) {
+	item, err := c.DefineItem(name, typ, config, cache, SynonymMapBuild)
+	if err != nil {
+		if err == ErrAlreadyDefined {
+			return nil, fmt.Errorf("synonym map named '%s' already defined", name)
+		}
+		return nil, err
+	}
+	return item.(analysis.SynonymMap), nil
+}
+
+func SynonymMapTypesAndInstances() ([]string, []string) {
+	emptyConfig := map[string]interface{}{}
+	emptyCache := NewCache()
+	var types []string
+	var instances []string
+	for name, cons := range synonymMaps {
+		_, err := cons(emptyConfig, emptyCache)
+		if err == nil {
+			instances = append(instances, name)
+		} else {
+			types = append(types, name)
+		}
+	}
+	return types, instances
+}
@@ -0,0 +1,303 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/query"
+)
+
+// DefaultRescoreWindowSize is the number of top hits rescored when a
+// RescoreRequest does not specify a window size.
+const DefaultRescoreWindowSize = 10
+
+// The ways the original score and the rescore score of a hit can be
+// combined, after each has been multiplied by its weight.
+const (
+	RescoreScoreModeTotal    = "total"
+	RescoreScoreModeMultiply = "multiply"
+	RescoreScoreModeAvg      = "avg"
+	RescoreScoreModeMax      = "max"
+	RescoreScoreModeMin      = "min"
+)
+
+// A RescoreRequest re-ranks the top WindowSize hits of a search with
+// a secondary, typically more expensive, Query.
+// The score of each hit in the window is the combination, according
+// to ScoreMode, of its original score multiplied by QueryWeight and
+// its score for Query multiplied by RescoreQueryWeight. Hits in the
+// window which do not match Query keep their original score
+// multiplied by QueryWeight.
+// The hits in the window are then sorted again, the hits outside the
+// window keep their place after it.
+type RescoreRequest struct {
+	Query              query.Query `json:"query"`
+	WindowSize         int         `json:"window_size,omitempty"`
+	QueryWeight        float64     `json:"query_weight"`
+	RescoreQueryWeight float64     `json:"rescore_query_weight"`
+	ScoreMode          string      `json:"score_mode,omitempty"`
+}
+
+// NewRescoreRequest creates a RescoreRequest applying the query to
+// the top windowSize hits, adding the original and rescore scores.
+func NewRescoreRequest(q query.Query, windowSize int) *RescoreRequest {
+	return &RescoreRequest{
+		Query:              q,
+		WindowSize:         windowSize,
+		QueryWeight:        1,
+		RescoreQueryWeight: 1,
+	}
+}
+
+func (r *RescoreRequest) Validate() error {
+	if r.Query == nil {
+		return fmt.Errorf("rescore must specify a query")
+	}
+	if vq, ok := r.Query.(query.ValidatableQuery); ok {
+		err := vq.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if r.WindowSize < 0 {
+		return fmt.Errorf("rescore window size must not be negative")
+	}
+	switch r.ScoreMode {
+	case "", RescoreScoreModeTotal, RescoreScoreModeMultiply,
+		RescoreScoreModeAvg, RescoreScoreModeMax, RescoreScoreModeMin:
+	default:
+		return fmt.Errorf("unknown rescore score mode '%s'", r.ScoreMode)
+	}
+	return nil
+}
+
+func (r *RescoreRequest) UnmarshalJSON(input []byte) error {
+	var temp struct {
+		Q                  json.RawMessage `json:"query"`
+		WindowSize         int             `json:"window_size"`
+		QueryWeight        *float64        `json:"query_weight"`
+		RescoreQueryWeight *float64        `json:"rescore_query_weight"`
+		ScoreMode          string          `json:"score_mode"`
+	}
+
+	err := json.Unmarshal(input, &temp)
+	if err != nil {
+		return err
+	}
+
+	r.Query, err = query.ParseQuery(temp.Q)
+	if err != nil {
+		return err
+	}
+	r.WindowSize = temp.WindowSize
+	r.QueryWeight = 1
+	if temp.QueryWeight != nil {
+		r.QueryWeight = *temp.QueryWeight
+	}
+	r.RescoreQueryWeight = 1
+	if temp.RescoreQueryWeight != nil {
+		r.RescoreQueryWeight = *temp.RescoreQueryWeight
+	}
+	r.ScoreMode = temp.ScoreMode
+	return nil
+}
+
+func (r *RescoreRequest) windowSize() int {
+	if r.WindowSize == 0 {
+		return DefaultRescoreWindowSize
+	}
+	return r.WindowSize
+}
+
+func (r *RescoreRequest) scoreMode() string {
+	if r.ScoreMode == "" {
+		return RescoreScoreModeTotal
+	}
+	return r.ScoreMode
+}
+
+func (r *RescoreRequest) combine(original, rescore float64) float64 {
+	original *= r.QueryWeight
+	rescore *= r.RescoreQueryWeight
+	switch r.scoreMode() {
+	case RescoreScoreModeMultiply:
+		return original * rescore
+	case RescoreScoreModeAvg:
+		return (original + rescore) / 2
+	case RescoreScoreModeMax:
+		if rescore > original {
+			return rescore
+		}
+		return original
+	case RescoreScoreModeMin:
+		if rescore < original {
+			return rescore
+		}
+		return original
+	}
+	return original + rescore
+}
+
+// validateRescore checks the rescore requests, and that the hits are
+// ordered by score so that rescoring them makes sense.
+func (r *SearchRequest) validateRescore() error {
+	if len(r.Rescore) == 0 {
+		return nil
+	}
+	if r.SearchAfter != nil || r.SearchBefore != nil {
+		return fmt.Errorf("cannot use search after or search before with rescore")
+	}
+	if len(r.Sort) > 1 || (len(r.Sort) == 1 &&
+		(!r.Sort[0].RequiresScoring() || !r.Sort.CacheDescending()[0])) {
+		return fmt.Errorf("rescore requires the hits to be sorted by descending score")
+	}
+	for _, rescore := range r.Rescore {
+		err := rescore.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rescoreWindow returns the number of hits which must be collected
+// for all of the rescore requests.
+func (r *SearchRequest) rescoreWindow() int {
+	rv := r.Size + r.From
+	for _, rescore := range r.Rescore {
+		if rescore.windowSize() > rv {
+			rv = rescore.windowSize()
+		}
+	}
+	return rv
+}
+
+type hitsByInternalID search.DocumentMatchCollection
+
+func (h hitsByInternalID) Len() int      { return len(h) }
+func (h hitsByInternalID) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h hitsByInternalID) Less(i, j int) bool {
+	return h[i].IndexInternalID.Compare(h[j].IndexInternalID) < 0
+}
+
+// rescore applies each of the rescore requests in turn to the hits,
+// which are sorted by descending score, and sorts the rescored window
+// again.
+func (i *indexImpl) rescore(indexReader index.IndexReader, hits search.DocumentMatchCollection,
+	req *SearchRequest, searcherOptions search.SearcherOptions) error {
+	for _, rescore := range req.Rescore {
+		window := rescore.windowSize()
+		if window > len(hits) {
+			window = len(hits)
+		}
+		err := i.rescoreHits(indexReader, hits[:window], rescore, searcherOptions)
+		if err != nil {
+			return err
+		}
+		sort.Stable(hits[:window])
+	}
+	return nil
+}
+
+func (i *indexImpl) rescoreHits(indexReader index.IndexReader, hits search.DocumentMatchCollection,
+	rescore *RescoreRequest, searcherOptions search.SearcherOptions) (err error) {
+	searcher, err := rescore.Query.Searcher(indexReader, i.m, searcherOptions)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := searcher.Close(); err == nil && serr != nil {
+			err = serr
+		}
+	}()
+	searchContext := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(searcher.DocumentMatchPoolSize()+1, 0),
+	}
+
+	// the searcher only moves forward, so visit the hits in index order
+	byID := make(hitsByInternalID, len(hits))
+	copy(byID, hits)
+	sort.Sort(byID)
+
+	var match *search.DocumentMatch
+	exhausted := false
+	for _, hit := range byID {
+		if !exhausted && (match == nil || match.IndexInternalID.Compare(hit.IndexInternalID) < 0) {
+			if match != nil {
+				searchContext.DocumentMatchPool.Put(match)
+			}
+			match, err = searcher.Advance(searchContext, hit.IndexInternalID)
+			if err != nil {
+				return err
+			}
+			exhausted = match == nil
+		}
+
+		matched := !exhausted && match.IndexInternalID.Equals(hit.IndexInternalID)
+		score := hit.Score * rescore.QueryWeight
+		if matched {
+			score = rescore.combine(hit.Score, match.Score)
+		}
+
+		if searcherOptions.Explain {
+			children := []*search.Explanation{
+				{
+					Value:   hit.Score * rescore.QueryWeight,
+					Message: "original score, product of:",
+					Children: []*search.Explanation{
+						hit.Expl,
+						{
+							Value:   rescore.QueryWeight,
+							Message: "query weight",
+						},
+					},
+				},
+			}
+			message := "rescored, no match of the rescore query:"
+			if matched {
+				message = fmt.Sprintf("rescored, %s of:", rescore.scoreMode())
+				children = append(children, &search.Explanation{
+					Value:   match.Score * rescore.RescoreQueryWeight,
+					Message: "rescore score, product of:",
+					Children: []*search.Explanation{
+						match.Expl,
+						{
+							Value:   rescore.RescoreQueryWeight,
+							Message: "rescore query weight",
+						},
+					},
+				})
+			}
+			hit.Expl = &search.Explanation{
+				Value:    score,
+				Message:  message,
+				Children: children,
+			}
+		}
+
+		hit.Score = score
+		if len(hit.Sort) > 0 {
+			// the hits are sorted by score, keep their sort value current
+			hit.Sort = []string{strconv.FormatFloat(score, 'g', -1, 64)}
+		}
+	}
+	return nil
+}
@@ -17,12 +17,16 @@ package bleve
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/edwindvinas/bleve/analysis"
 	"github.com/edwindvinas/bleve/analysis/datetime/optional"
 	"github.com/edwindvinas/bleve/registry"
 	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/facet"
+	"github.com/edwindvinas/bleve/search/hyperloglog"
 	"github.com/edwindvinas/bleve/search/query"
 )
 
@@ -100,17 +104,122 @@ func (dr *dateTimeRange) MarshalJSON() ([]byte, error) {
 	return json.Marshal(rv)
 }
 
+// The types of facet besides the default, which counts
+// the terms or the ranges of a field.  Histograms group
+// documents into buckets of a fixed interval, stats and
+// percentiles summarize the numeric values of a field,
+// cardinality estimates the number of distinct terms.
+const (
+	FacetTypeHistogram     = "histogram"
+	FacetTypeDateHistogram = "date_histogram"
+	FacetTypeStats         = "stats"
+	FacetTypePercentiles   = "percentiles"
+	FacetTypeCardinality   = "cardinality"
+)
+
+// DefaultPercents are the percentiles computed when a
+// percentiles facet does not specify any.
+var DefaultPercents = []float64{1, 5, 25, 50, 75, 95, 99}
+
+// histogramBounds are the extended bounds of a histogram,
+// Min and Max for numbers, Start and End for dates.
+type histogramBounds struct {
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Start *string  `json:"start,omitempty"`
+	End   *string  `json:"end,omitempty"`
+}
+
 // A FacetRequest describes a facet or aggregation
 // of the result document set you would like to be
 // built.
+// Type selects a histogram, stats, percentiles or
+// cardinality facet, by default terms or ranges are
+// counted.
+// Interval and Offset configure a numeric histogram,
+// DateInterval and TimeZone a date histogram.
+// MinDocCount drops the histogram buckets with fewer
+// documents, with the default of 0 the empty buckets
+// between the first and last, or the ExtendedBounds,
+// are included.
+// Percents and Compression configure a percentiles
+// facet, Compression trades accuracy for memory.
+// Precision configures a cardinality facet, higher
+// precisions are more accurate and use more memory.
+// Facets describes sub-facets, computed separately
+// over the documents of each bucket of this facet.
+// Unless all buckets are returned, the sub-facets are
+// computed once the top Size buckets are known.
 type FacetRequest struct {
 	Size           int              `json:"size"`
 	Field          string           `json:"field"`
+	Type           string           `json:"type,omitempty"`
 	NumericRanges  []*numericRange  `json:"numeric_ranges,omitempty"`
 	DateTimeRanges []*dateTimeRange `json:"date_ranges,omitempty"`
+	Interval       float64          `json:"interval,omitempty"`
+	Offset         float64          `json:"offset,omitempty"`
+	DateInterval   string           `json:"date_interval,omitempty"`
+	TimeZone       string           `json:"time_zone,omitempty"`
+	MinDocCount    int              `json:"min_doc_count,omitempty"`
+	ExtendedBounds *histogramBounds `json:"extended_bounds,omitempty"`
+	Percents       []float64        `json:"percents,omitempty"`
+	Compression    float64          `json:"compression,omitempty"`
+	Precision      int              `json:"precision,omitempty"`
+	Facets         FacetsRequest    `json:"facets,omitempty"`
 }
 
 func (fr *FacetRequest) Validate() error {
+	switch fr.Type {
+	case "":
+	case FacetTypeHistogram, FacetTypeDateHistogram:
+		if len(fr.NumericRanges) > 0 || len(fr.DateTimeRanges) > 0 {
+			return fmt.Errorf("%s facet cannot contain ranges", fr.Type)
+		}
+		if fr.MinDocCount < 0 {
+			return fmt.Errorf("min doc count must not be negative")
+		}
+		dateTimeParser, err := cache.DateTimeParserNamed(defaultDateTimeParser)
+		if err != nil {
+			return err
+		}
+		_, err = fr.newHistogramFacetBuilder(dateTimeParser)
+		if err != nil {
+			return err
+		}
+		return fr.Facets.Validate()
+	case FacetTypeStats, FacetTypePercentiles:
+		if len(fr.NumericRanges) > 0 || len(fr.DateTimeRanges) > 0 {
+			return fmt.Errorf("%s facet cannot contain ranges", fr.Type)
+		}
+		if len(fr.Facets) > 0 {
+			return fmt.Errorf("%s facet cannot contain sub-facets", fr.Type)
+		}
+		for _, percent := range fr.Percents {
+			if percent < 0 || percent > 100 {
+				return fmt.Errorf("percent %f must be between 0 and 100", percent)
+			}
+		}
+		if fr.Compression < 0 {
+			return fmt.Errorf("compression must not be negative")
+		}
+		return nil
+	case FacetTypeCardinality:
+		if len(fr.NumericRanges) > 0 || len(fr.DateTimeRanges) > 0 {
+			return fmt.Errorf("%s facet cannot contain ranges", fr.Type)
+		}
+		if len(fr.Facets) > 0 {
+			return fmt.Errorf("%s facet cannot contain sub-facets", fr.Type)
+		}
+		if fr.Precision != 0 &&
+			(fr.Precision < hyperloglog.MinPrecision || fr.Precision > hyperloglog.MaxPrecision) {
+			return fmt.Errorf("precision must be between %d and %d",
+				hyperloglog.MinPrecision, hyperloglog.MaxPrecision)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown facet type '%s'", fr.Type)
+	}
+
 	nrCount := len(fr.NumericRanges)
 	drCount := len(fr.DateTimeRanges)
 	if nrCount > 0 && drCount > 0 {
@@ -146,7 +255,7 @@ func (fr *FacetRequest) Validate() error {
 			}
 		}
 	}
-	return nil
+	return fr.Facets.Validate()
 }
 
 // NewFacetRequest creates a facet on the specified
@@ -159,6 +268,127 @@ func NewFacetRequest(field string, size int) *FacetRequest {
 	}
 }
 
+// NewStatsFacetRequest creates a facet computing the
+// count, min, max, sum and average of the numeric
+// values of the specified field.
+func NewStatsFacetRequest(field string) *FacetRequest {
+	return &FacetRequest{
+		Field: field,
+		Type:  FacetTypeStats,
+	}
+}
+
+// NewPercentilesFacetRequest creates a facet estimating
+// the specified percentiles of the numeric values of
+// the specified field, DefaultPercents are used if none
+// are given.
+func NewPercentilesFacetRequest(field string, percents ...float64) *FacetRequest {
+	return &FacetRequest{
+		Field:    field,
+		Type:     FacetTypePercentiles,
+		Percents: percents,
+	}
+}
+
+// NewCardinalityFacetRequest creates a facet estimating
+// the number of distinct terms of the specified field.
+func NewCardinalityFacetRequest(field string) *FacetRequest {
+	return &FacetRequest{
+		Field: field,
+		Type:  FacetTypeCardinality,
+	}
+}
+
+// NewHistogramFacetRequest creates a facet counting
+// the numeric values of the specified field in buckets
+// spanning the specified interval.
+func NewHistogramFacetRequest(field string, interval float64) *FacetRequest {
+	return &FacetRequest{
+		Field:    field,
+		Type:     FacetTypeHistogram,
+		Interval: interval,
+	}
+}
+
+// NewDateHistogramFacetRequest creates a facet counting
+// the date values of the specified field in buckets of
+// the specified interval, either a calendar unit (minute,
+// hour, day, week, month, quarter or year) or a fixed
+// duration such as 90m.
+func NewDateHistogramFacetRequest(field string, interval string) *FacetRequest {
+	return &FacetRequest{
+		Field:        field,
+		Type:         FacetTypeDateHistogram,
+		DateInterval: interval,
+	}
+}
+
+// SetExtendedBounds sets the range a numeric histogram
+// covers with empty buckets, either may be nil.
+func (fr *FacetRequest) SetExtendedBounds(min, max *float64) {
+	fr.ExtendedBounds = &histogramBounds{Min: min, Max: max}
+}
+
+// SetExtendedDateBounds sets the range a date histogram
+// covers with empty buckets, either may be zero.
+func (fr *FacetRequest) SetExtendedDateBounds(start, end time.Time) {
+	fr.ExtendedBounds = &histogramBounds{}
+	if !start.IsZero() {
+		s := start.Format(time.RFC3339Nano)
+		fr.ExtendedBounds.Start = &s
+	}
+	if !end.IsZero() {
+		e := end.Format(time.RFC3339Nano)
+		fr.ExtendedBounds.End = &e
+	}
+}
+
+// newHistogramFacetBuilder builds the facet builder of a
+// histogram or date histogram facet.
+func (fr *FacetRequest) newHistogramFacetBuilder(dateTimeParser analysis.DateTimeParser) (*facet.HistogramFacetBuilder, error) {
+	var rv *facet.HistogramFacetBuilder
+	var err error
+	if fr.Type == FacetTypeDateHistogram {
+		location := time.UTC
+		if fr.TimeZone != "" {
+			location, err = time.LoadLocation(fr.TimeZone)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rv, err = facet.NewDateHistogramFacetBuilder(fr.Field, fr.DateInterval, location)
+		if err != nil {
+			return nil, err
+		}
+		if fr.ExtendedBounds != nil {
+			var start, end time.Time
+			if fr.ExtendedBounds.Start != nil {
+				start, err = dateTimeParser.ParseDateTime(*fr.ExtendedBounds.Start)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if fr.ExtendedBounds.End != nil {
+				end, err = dateTimeParser.ParseDateTime(*fr.ExtendedBounds.End)
+				if err != nil {
+					return nil, err
+				}
+			}
+			rv.SetExtendedDateBounds(start, end)
+		}
+	} else {
+		rv, err = facet.NewHistogramFacetBuilder(fr.Field, fr.Interval, fr.Offset)
+		if err != nil {
+			return nil, err
+		}
+		if fr.ExtendedBounds != nil {
+			rv.SetExtendedBounds(fr.ExtendedBounds.Min, fr.ExtendedBounds.Max)
+		}
+	}
+	rv.SetMinDocCount(fr.MinDocCount)
+	return rv, nil
+}
+
 // AddDateTimeRange adds a bucket to a field
 // containing date values.  Documents with a
 // date value falling into this range are tabulated
@@ -181,6 +411,15 @@ func (fr *FacetRequest) AddNumericRange(name string, min, max *float64) {
 	fr.NumericRanges = append(fr.NumericRanges, &numericRange{Name: name, Min: min, Max: max})
 }
 
+// AddFacet adds a sub-facet, which is computed
+// for the documents of each bucket of this facet.
+func (fr *FacetRequest) AddFacet(facetName string, f *FacetRequest) {
+	if fr.Facets == nil {
+		fr.Facets = make(FacetsRequest, 1)
+	}
+	fr.Facets[facetName] = f
+}
+
 // FacetsRequest groups together all the
 // FacetRequest objects for a single query.
 type FacetsRequest map[string]*FacetRequest
@@ -195,6 +434,33 @@ func (fr FacetsRequest) Validate() error {
 	return nil
 }
 
+// fixup sorts and trims the facet results merged from
+// several indexes, including the sub-facets of each bucket.
+func (fr FacetsRequest) fixup(results search.FacetResults) {
+	for name, facetRequest := range fr {
+		results.Fixup(name, facetRequest.Size)
+		if facetRequest.Type == FacetTypeHistogram || facetRequest.Type == FacetTypeDateHistogram {
+			// fill in the buckets missing from every index
+			if result, ok := results[name]; ok {
+				dateTimeParser, err := cache.DateTimeParserNamed(defaultDateTimeParser)
+				if err == nil {
+					histogramFacetBuilder, err := facetRequest.newHistogramFacetBuilder(dateTimeParser)
+					if err == nil {
+						histogramFacetBuilder.Fixup(result)
+					}
+				}
+			}
+		}
+		if len(facetRequest.Facets) > 0 {
+			if result, ok := results[name]; ok {
+				for _, bucketFacets := range result.BucketFacets() {
+					facetRequest.Facets.fixup(bucketFacets)
+				}
+			}
+		}
+	}
+}
+
 // HighlightRequest describes how field matches
 // should be highlighted.
 type HighlightRequest struct {
@@ -223,6 +489,67 @@ func (h *HighlightRequest) AddField(field string) {
 	h.Fields = append(h.Fields, field)
 }
 
+// CollapseRequest keeps only the best hit for each
+// distinct value of a field, which should hold a single
+// term, such as a keyword field.  Hits without a value
+// are all kept.  When InnerHits is greater than zero the
+// best InnerHits hits of each group are returned with
+// its best hit.
+type CollapseRequest struct {
+	Field     string `json:"field"`
+	InnerHits int    `json:"inner_hits,omitempty"`
+}
+
+// NewCollapseRequest creates a CollapseRequest on the
+// specified field, returning no inner hits.
+func NewCollapseRequest(field string) *CollapseRequest {
+	return &CollapseRequest{
+		Field: field,
+	}
+}
+
+func (c *CollapseRequest) Validate() error {
+	if c.Field == "" {
+		return fmt.Errorf("collapse must specify a field")
+	}
+	if c.InnerHits < 0 {
+		return fmt.Errorf("collapse inner hits must not be negative")
+	}
+	return nil
+}
+
+// collapse keeps the first hit of each group of the sorted
+// hits, which were merged from several indexes, along with
+// the best inner hits of the group from all the indexes.
+func (c *CollapseRequest) collapse(hits search.DocumentMatchCollection, sortOrder search.SortOrder) search.DocumentMatchCollection {
+	rv := hits[:0]
+	groups := make(map[string]*search.DocumentMatch)
+	for _, hit := range hits {
+		if hit.Collapse == "" {
+			rv = append(rv, hit)
+			continue
+		}
+		best, ok := groups[hit.Collapse]
+		if !ok {
+			groups[hit.Collapse] = hit
+			rv = append(rv, hit)
+			continue
+		}
+		if c.InnerHits > 0 {
+			best.InnerHits = append(best.InnerHits, hit.InnerHits...)
+		}
+	}
+	if c.InnerHits > 0 {
+		for _, best := range groups {
+			sort.Sort(newMultiSearchHitSorter(sortOrder, best.InnerHits))
+			if len(best.InnerHits) > c.InnerHits {
+				best.InnerHits = best.InnerHits[:c.InnerHits]
+			}
+		}
+	}
+	return rv
+}
+
 // A SearchRequest describes all the parameters
 // needed to search the index.
 // Query is required.
@@ -237,6 +564,22 @@ func (h *HighlightRequest) AddField(field string) {
 // Explain triggers inclusion of additional search
 // result score explanations.
 // Sort describes the desired order for the results to be returned.
+// SearchAfter/SearchBefore page through the results using the Sort
+// values of a hit from a previous page, only hits sorting after
+// (or before) that hit are returned. From must be 0 when either is used,
+// and a unique sort (such as one ending with _id) should be used to avoid
+// skipping hits with identical sort values. Search before requires the
+// sorts to implement search.ReversibleSearchSort. The Sort values of the
+// hits hold the score itself for score sorts, rather than "_score".
+// Snapshot runs the search against a snapshot opened with
+// Index.OpenSnapshot, instead of the current state of the index. Snapshot
+// ids belong to a single index, so it cannot be used with an alias to
+// several indexes.
+// Suggest requests spelling corrections for a piece of text.
+// Collapse keeps only the best hit for each value of a field,
+// it cannot be used with SearchAfter/SearchBefore.
+// Rescore re-ranks the top hits with secondary queries, applied
+// in turn, it requires the hits to be sorted by descending score.
 //
 // A special field named "*" can be used to return all fields.
 type SearchRequest struct {
@@ -249,6 +592,16 @@ type SearchRequest struct {
 	Explain          bool              `json:"explain"`
 	Sort             search.SortOrder  `json:"sort"`
 	IncludeLocations bool              `json:"includeLocations"`
+	SearchAfter      []string          `json:"search_after,omitempty"`
+	SearchBefore     []string          `json:"search_before,omitempty"`
+	Snapshot         string            `json:"snapshot,omitempty"`
+	Suggest          *SuggestRequest   `json:"suggest,omitempty"`
+	Collapse         *CollapseRequest  `json:"collapse,omitempty"`
+	Rescore          []*RescoreRequest `json:"rescore,omitempty"`
+
+	// partial is set on the requests MultiSearch sends to each
+	// index, their results are only fixed up once merged
+	partial bool
 }
 
 func (r *SearchRequest) Validate() error {
@@ -259,9 +612,82 @@ func (r *SearchRequest) Validate() error {
 		}
 	}
 
+	err := r.validatePagination()
+	if err != nil {
+		return err
+	}
+
+	if r.Suggest != nil {
+		err = r.Suggest.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Collapse != nil {
+		err = r.Collapse.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = r.validateRescore()
+	if err != nil {
+		return err
+	}
+
 	return r.Facets.Validate()
 }
 
+func (r *SearchRequest) validatePagination() error {
+	if r.SearchAfter == nil && r.SearchBefore == nil {
+		return nil
+	}
+	if r.Collapse != nil {
+		return fmt.Errorf("cannot use search after or search before with collapse")
+	}
+	if r.SearchAfter != nil && r.SearchBefore != nil {
+		return fmt.Errorf("cannot use search after and search before together")
+	}
+	if r.From != 0 {
+		return fmt.Errorf("cannot use search after or search before with from != 0")
+	}
+	if r.SearchBefore != nil {
+		err := r.Sort.Reversible()
+		if err != nil {
+			return fmt.Errorf("cannot use search before: %v", err)
+		}
+	}
+	position := r.SearchAfter
+	if position == nil {
+		position = r.SearchBefore
+	}
+	if len(position) != len(r.Sort) {
+		return fmt.Errorf("search after/before must have the same size as the sort order, expected %d values, got %d", len(r.Sort), len(position))
+	}
+	for i, ss := range r.Sort {
+		if ss.RequiresScoring() {
+			_, err := strconv.ParseFloat(position[i], 64)
+			if err != nil {
+				return fmt.Errorf("search after/before value for score must be a number, got '%s'", position[i])
+			}
+		}
+	}
+	return nil
+}
+
+// SetSearchAfter sets the request to return the hits
+// sorting after the provided sort values
+func (r *SearchRequest) SetSearchAfter(after []string) {
+	r.SearchAfter = after
+}
+
+// SetSearchBefore sets the request to return the hits
+// sorting before the provided sort values
+func (r *SearchRequest) SetSearchBefore(before []string) {
+	r.SearchBefore = before
+}
+
 // AddFacet adds a FacetRequest to this SearchRequest
 func (r *SearchRequest) AddFacet(facetName string, f *FacetRequest) {
 	if r.Facets == nil {
@@ -298,6 +724,12 @@ func (r *SearchRequest) UnmarshalJSON(input []byte) error {
 		Explain          bool              `json:"explain"`
 		Sort             []json.RawMessage `json:"sort"`
 		IncludeLocations bool              `json:"includeLocations"`
+		SearchAfter      []string          `json:"search_after"`
+		SearchBefore     []string          `json:"search_before"`
+		Snapshot         string            `json:"snapshot"`
+		Suggest          *SuggestRequest   `json:"suggest"`
+		Collapse         *CollapseRequest  `json:"collapse"`
+		Rescore          []*RescoreRequest `json:"rescore"`
 	}
 
 	err := json.Unmarshal(input, &temp)
@@ -324,6 +756,12 @@ func (r *SearchRequest) UnmarshalJSON(input []byte) error {
 	r.Fields = temp.Fields
 	r.Facets = temp.Facets
 	r.IncludeLocations = temp.IncludeLocations
+	r.SearchAfter = temp.SearchAfter
+	r.SearchBefore = temp.SearchBefore
+	r.Snapshot = temp.Snapshot
+	r.Suggest = temp.Suggest
+	r.Collapse = temp.Collapse
+	r.Rescore = temp.Rescore
 	r.Query, err = query.ParseQuery(temp.Q)
 	if err != nil {
 		return err
@@ -420,6 +858,7 @@ type SearchResult struct {
 	MaxScore float64                        `json:"max_score"`
 	Took     time.Duration                  `json:"took"`
 	Facets   search.FacetResults            `json:"facets"`
+	Suggest  *search.SuggestResult          `json:"suggest,omitempty"`
 }
 
 func (sr *SearchResult) String() string {
@@ -455,6 +894,18 @@ func (sr *SearchResult) String() string {
 			for _, t := range f.Terms {
 				rv += fmt.Sprintf("\t%s(%d)\n", t.Term, t.Count)
 			}
+			for _, h := range f.Histogram {
+				rv += fmt.Sprintf("\t%s(%d)\n", h.Name, h.Count)
+			}
+			if f.Stats != nil {
+				rv += fmt.Sprintf("\tcount(%d) min(%g) max(%g) sum(%g) avg(%g)\n", f.Stats.Count, f.Stats.Min, f.Stats.Max, f.Stats.Sum, f.Stats.Avg)
+			}
+			for _, p := range f.Percentiles {
+				rv += fmt.Sprintf("\t%g%%(%g)\n", p.Percent, p.Value)
+			}
+			if f.Cardinality != nil {
+				rv += fmt.Sprintf("\tcardinality(%d)\n", *f.Cardinality)
+			}
 			if f.Other != 0 {
 				rv += fmt.Sprintf("\tOther(%d)\n", f.Other)
 			}
@@ -472,4 +923,9 @@ func (sr *SearchResult) Merge(other *SearchResult) {
 		sr.MaxScore = other.MaxScore
 	}
 	sr.Facets.Merge(other.Facets)
+	if sr.Suggest == nil {
+		sr.Suggest = other.Suggest
+	} else if other.Suggest != nil {
+		sr.Suggest.Merge(other.Suggest)
+	}
 }
@@ -15,6 +15,8 @@
 package collector
 
 import (
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/edwindvinas/bleve/index"
@@ -49,6 +51,11 @@ type TopNCollector struct {
 	cachedDesc    []bool
 
 	lowestMatchOutsideResults *search.DocumentMatch
+	searchAfter               *search.DocumentMatch
+
+	collapseField string
+	innerHits     int
+	groups        map[string]search.DocumentMatchCollection
 }
 
 // CheckDoneEvery controls how frequently we check the context deadline
@@ -58,6 +65,37 @@ const CheckDoneEvery = uint64(1024)
 // skipping over the first 'skip' hits
 // ordering hits by the provided sort order
 func NewTopNCollector(size int, skip int, sort search.SortOrder) *TopNCollector {
+	return newTopNCollector(size, skip, sort)
+}
+
+// NewTopNCollectorAfter builds a collector to find the top 'size' hits
+// which sort after the provided sort values, these are typically the
+// DocumentMatch.Sort values of the last hit of a previous page.
+// Sort values for score positions are parsed as numbers, values which
+// cannot be parsed are treated as a score of 0.
+func NewTopNCollectorAfter(size int, sort search.SortOrder, after []string) *TopNCollector {
+	hc := newTopNCollector(size, 0, sort)
+	hc.searchAfter = &search.DocumentMatch{
+		Sort: after,
+		// hits with the same sort values as the cursor are at the
+		// cursor, make sure they compare before it and are skipped
+		HitNumber: math.MaxUint64,
+	}
+	for pos, ss := range sort {
+		if pos >= len(after) {
+			break
+		}
+		if ss.RequiresDocID() {
+			hc.searchAfter.ID = after[pos]
+		}
+		if ss.RequiresScoring() {
+			hc.searchAfter.Score, _ = strconv.ParseFloat(after[pos], 64)
+		}
+	}
+	return hc
+}
+
+func newTopNCollector(size int, skip int, sort search.SortOrder) *TopNCollector {
 	hc := &TopNCollector{size: size, skip: skip, sort: sort}
 
 	// pre-allocate space on the store to avoid reslicing
@@ -127,6 +165,10 @@ func (hc *TopNCollector) Collect(ctx context.Context, searcher search.Searcher,
 	if err != nil {
 		return err
 	}
+	// only the best hit of each group competes for the results
+	for _, group := range hc.groups {
+		hc.addToStore(searchContext, group[0])
+	}
 	// finalize actual results
 	err = hc.finalizeResults(reader)
 	if err != nil {
@@ -172,6 +214,51 @@ func (hc *TopNCollector) collectSingle(ctx *search.SearchContext, reader index.I
 		hc.sort.Value(d)
 	}
 
+	// skip hits at or before the search after position
+	if hc.searchAfter != nil {
+		cmp := hc.sort.Compare(hc.cachedScoring, hc.cachedDesc, d, hc.searchAfter)
+		if cmp <= 0 {
+			ctx.DocumentMatchPool.Put(d)
+			return nil
+		}
+	}
+
+	if d.Collapse != "" {
+		hc.collapseSingle(ctx, d)
+		return nil
+	}
+
+	hc.addToStore(ctx, d)
+	return nil
+}
+
+// collapseSingle adds the hit to the hits of its group, which are kept
+// in order, the best first, and trimmed to the inner hits wanted
+func (hc *TopNCollector) collapseSingle(ctx *search.SearchContext, d *search.DocumentMatch) {
+	group := hc.groups[d.Collapse]
+	pos := len(group)
+	for pos > 0 && hc.sort.Compare(hc.cachedScoring, hc.cachedDesc, d, group[pos-1]) < 0 {
+		pos--
+	}
+	keep := hc.innerHits
+	if keep < 1 {
+		keep = 1
+	}
+	if pos >= keep {
+		ctx.DocumentMatchPool.Put(d)
+		return
+	}
+	group = append(group, nil)
+	copy(group[pos+1:], group[pos:])
+	group[pos] = d
+	if len(group) > keep {
+		ctx.DocumentMatchPool.Put(group[keep])
+		group = group[:keep]
+	}
+	hc.groups[d.Collapse] = group
+}
+
+func (hc *TopNCollector) addToStore(ctx *search.SearchContext, d *search.DocumentMatch) {
 	// optimization, we track lowest sorting hit already removed from heap
 	// with this one comparison, we can avoid all heap operations if
 	// this hit would have been added and then immediately removed
@@ -180,7 +267,7 @@ func (hc *TopNCollector) collectSingle(ctx *search.SearchContext, reader index.I
 		if cmp >= 0 {
 			// this hit can't possibly be in the result set, so avoid heap ops
 			ctx.DocumentMatchPool.Put(d)
-			return nil
+			return
 		}
 	}
 
@@ -198,8 +285,6 @@ func (hc *TopNCollector) collectSingle(ctx *search.SearchContext, reader index.I
 			}
 		}
 	}
-
-	return nil
 }
 
 // visitFieldTerms is responsible for visiting the field terms of the
@@ -207,6 +292,7 @@ func (hc *TopNCollector) collectSingle(ctx *search.SearchContext, reader index.I
 func (hc *TopNCollector) visitFieldTerms(reader index.IndexReader, d *search.DocumentMatch) error {
 	if hc.facetsBuilder != nil {
 		hc.facetsBuilder.StartDoc()
+		hc.facetsBuilder.SetDocID(d.IndexInternalID)
 	}
 
 	err := reader.DocumentVisitFieldTerms(d.IndexInternalID, hc.neededFields, func(field string, term []byte) {
@@ -214,6 +300,9 @@ func (hc *TopNCollector) visitFieldTerms(reader index.IndexReader, d *search.Doc
 			hc.facetsBuilder.UpdateVisitor(field, term)
 		}
 		hc.sort.UpdateVisitor(field, term)
+		if field == hc.collapseField && d.Collapse == "" {
+			d.Collapse = string(term)
+		}
 	})
 
 	if hc.facetsBuilder != nil {
@@ -229,6 +318,18 @@ func (hc *TopNCollector) SetFacetsBuilder(facetsBuilder *search.FacetsBuilder) {
 	hc.neededFields = append(hc.neededFields, hc.facetsBuilder.RequiredFields()...)
 }
 
+// SetCollapse keeps only the best hit for each distinct term of the
+// field, which should hold a single term, such as a keyword field.
+// Hits without a term for the field are all kept.  When innerHits is
+// greater than zero the best innerHits hits of each group, including
+// the best, are returned as the InnerHits of the best hit.
+func (hc *TopNCollector) SetCollapse(field string, innerHits int) {
+	hc.collapseField = field
+	hc.innerHits = innerHits
+	hc.groups = make(map[string]search.DocumentMatchCollection)
+	hc.neededFields = append(hc.neededFields, field)
+}
+
 // finalizeResults starts with the heap containing the final top size+skip
 // it now throws away the results to be skipped
 // and does final doc id lookup (if necessary)
@@ -243,12 +344,57 @@ func (hc *TopNCollector) finalizeResults(r index.IndexReader) error {
 				return err
 			}
 		}
+		hc.fixupScoreSortValues(doc)
+		if doc.Collapse != "" && hc.innerHits > 0 {
+			return hc.finalizeInnerHits(r, doc)
+		}
 		return nil
 	})
 
 	return err
 }
 
+// finalizeInnerHits attaches copies of the hits of the group, so the
+// best hit does not contain itself
+func (hc *TopNCollector) finalizeInnerHits(r index.IndexReader, doc *search.DocumentMatch) error {
+	group := hc.groups[doc.Collapse]
+	doc.InnerHits = make(search.DocumentMatchCollection, 0, len(group))
+	for _, hit := range group {
+		if hit.ID == "" {
+			var err error
+			hit.ID, err = r.ExternalID(hit.IndexInternalID)
+			if err != nil {
+				return err
+			}
+		}
+		hc.fixupScoreSortValues(hit)
+		innerHit := *hit
+		innerHit.InnerHits = nil
+		doc.InnerHits = append(doc.InnerHits, &innerHit)
+	}
+	return nil
+}
+
+// fixupScoreSortValues replaces the placeholder sort values of score
+// sorts with the actual score, so that the sort values of a hit can be
+// used as a search after position
+func (hc *TopNCollector) fixupScoreSortValues(doc *search.DocumentMatch) {
+	var sortValues []string
+	for x, isScore := range hc.cachedScoring {
+		if isScore && x < len(doc.Sort) {
+			if sortValues == nil {
+				// the sort values may be shared, so copy them first
+				sortValues = make([]string, len(doc.Sort))
+				copy(sortValues, doc.Sort)
+			}
+			sortValues[x] = strconv.FormatFloat(doc.Score, 'g', -1, 64)
+		}
+	}
+	if sortValues != nil {
+		doc.Sort = sortValues
+	}
+}
+
 // Results returns the collected hits
 func (hc *TopNCollector) Results() search.DocumentMatchCollection {
 	return hc.results
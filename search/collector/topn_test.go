@@ -515,3 +515,162 @@ func BenchmarkTop10000of1000000Scores(b *testing.B) {
 		return NewTopNCollector(10000, 0, search.SortOrder{&search.SortScore{Desc: true}})
 	}, b)
 }
+
+func TestTopNCollectorSearchAfter(t *testing.T) {
+
+	newSearcher := func() *stubSearcher {
+		return &stubSearcher{
+			matches: []*search.DocumentMatch{
+				{
+					IndexInternalID: index.IndexInternalID("a"),
+					Score:           3,
+				},
+				{
+					IndexInternalID: index.IndexInternalID("b"),
+					Score:           5,
+				},
+				{
+					IndexInternalID: index.IndexInternalID("c"),
+					Score:           3,
+				},
+				{
+					IndexInternalID: index.IndexInternalID("d"),
+					Score:           1,
+				},
+				{
+					IndexInternalID: index.IndexInternalID("e"),
+					Score:           3,
+				},
+			},
+		}
+	}
+	sort := search.SortOrder{&search.SortScore{Desc: true}, &search.SortDocID{}}
+
+	// first page
+	collector := NewTopNCollector(2, 0, sort)
+	err := collector.Collect(context.Background(), newSearcher(), &stubReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := collector.Results()
+	if len(results) != 2 || results[0].ID != "b" || results[1].ID != "a" {
+		t.Fatalf("expected first page b, a, got %v", results)
+	}
+	if results[1].Sort[0] != "3" || results[1].Sort[1] != "a" {
+		t.Fatalf("expected sort values [3 a], got %v", results[1].Sort)
+	}
+
+	// second page, starting after the last hit of the first page
+	collector = NewTopNCollectorAfter(2, sort, results[1].Sort)
+	err = collector.Collect(context.Background(), newSearcher(), &stubReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = collector.Results()
+	if len(results) != 2 || results[0].ID != "c" || results[1].ID != "e" {
+		t.Fatalf("expected second page c, e, got %v", results)
+	}
+	if collector.Total() != 5 {
+		t.Errorf("expected total 5, got %d", collector.Total())
+	}
+
+	// last page
+	collector = NewTopNCollectorAfter(2, sort, results[1].Sort)
+	err = collector.Collect(context.Background(), newSearcher(), &stubReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = collector.Results()
+	if len(results) != 1 || results[0].ID != "d" {
+		t.Fatalf("expected last page d, got %v", results)
+	}
+}
+
+// stubTermsReader returns the terms of the collapse field of each doc
+type stubTermsReader struct {
+	stubReader
+	terms map[string]string
+}
+
+func (sr *stubTermsReader) DocumentVisitFieldTerms(id index.IndexInternalID, fields []string, visitor index.DocumentFieldTermVisitor) error {
+	if term, ok := sr.terms[string(id)]; ok {
+		visitor("product", []byte(term))
+	}
+	return nil
+}
+
+func TestTopNCollectorCollapse(t *testing.T) {
+	searcher := &stubSearcher{
+		matches: []*search.DocumentMatch{
+			{IndexInternalID: index.IndexInternalID("a"), Score: 5},
+			{IndexInternalID: index.IndexInternalID("b"), Score: 9},
+			{IndexInternalID: index.IndexInternalID("c"), Score: 7},
+			{IndexInternalID: index.IndexInternalID("d"), Score: 8},
+			{IndexInternalID: index.IndexInternalID("e"), Score: 6},
+			{IndexInternalID: index.IndexInternalID("f"), Score: 1},
+			{IndexInternalID: index.IndexInternalID("g"), Score: 4},
+		},
+	}
+	reader := &stubTermsReader{
+		terms: map[string]string{
+			"a": "shirt",
+			"b": "shirt",
+			"c": "shirt",
+			"d": "shoe",
+			"e": "shoe",
+			"g": "hat",
+		},
+	}
+
+	collector := NewTopNCollector(3, 0, search.SortOrder{&search.SortScore{Desc: true}})
+	collector.SetCollapse("product", 2)
+	err := collector.Collect(context.Background(), searcher, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if collector.Total() != 7 {
+		t.Errorf("expected 7 total hits, got %d", collector.Total())
+	}
+	results := collector.Results()
+	expected := []string{"b", "d", "g"}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, hit := range results {
+		if hit.ID != expected[i] {
+			t.Errorf("expected hit %d to be %s, got %s", i, expected[i], hit.ID)
+		}
+	}
+
+	shirts := results[0].InnerHits
+	if results[0].Collapse != "shirt" || len(shirts) != 2 ||
+		shirts[0].ID != "b" || shirts[1].ID != "c" {
+		t.Errorf("expected shirts b and c, got %s %v", results[0].Collapse, shirts)
+	}
+	if len(shirts) > 0 && shirts[0].InnerHits != nil {
+		t.Errorf("expected inner hits to have no inner hits")
+	}
+	if len(results[2].InnerHits) != 1 || results[2].InnerHits[0].ID != "g" {
+		t.Errorf("expected the hat alone, got %v", results[2].InnerHits)
+	}
+
+	// the hit without a product competes on its own
+	searcher.index = 0
+	collector = NewTopNCollector(10, 1, search.SortOrder{&search.SortScore{Desc: true}})
+	collector.SetCollapse("product", 0)
+	err = collector.Collect(context.Background(), searcher, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = collector.Results()
+	expected = []string{"d", "g", "f"}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, hit := range results {
+		if hit.ID != expected[i] || hit.InnerHits != nil {
+			t.Errorf("expected hit %d to be %s without inner hits, got %s %v", i, expected[i], hit.ID, hit.InnerHits)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package completion implements a weighted prefix tree, used to find the
+// highest weighted completions of a prefix without visiting every entry.
+package completion
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Completion is a suggested text along with its weight.
+type Completion struct {
+	Text   string `json:"text"`
+	Weight uint64 `json:"weight"`
+}
+
+// Completions orders completions by weight, then by text.
+type Completions []*Completion
+
+func (c Completions) Len() int      { return len(c) }
+func (c Completions) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c Completions) Less(i, j int) bool {
+	if c[i].Weight != c[j].Weight {
+		return c[i].Weight > c[j].Weight
+	}
+	return c[i].Text < c[j].Text
+}
+
+// entry is a completion along with the weights it was inserted with,
+// its weight is the highest of them.
+type entry struct {
+	Completion
+	weights []weightCount
+}
+
+// weightCount counts the insertions of a completion with a weight.
+type weightCount struct {
+	weight uint64
+	count  uint64
+}
+
+func (e *entry) add(weight uint64, count uint64) {
+	for i := range e.weights {
+		if e.weights[i].weight == weight {
+			e.weights[i].count += count
+			return
+		}
+	}
+	e.weights = append(e.weights, weightCount{weight: weight, count: count})
+	if weight > e.Weight {
+		e.Weight = weight
+	}
+}
+
+// remove removes an insertion with the weight, and returns whether
+// there was one.
+func (e *entry) remove(weight uint64) bool {
+	for i := range e.weights {
+		if e.weights[i].weight != weight {
+			continue
+		}
+		e.weights[i].count--
+		if e.weights[i].count == 0 {
+			e.weights = append(e.weights[:i], e.weights[i+1:]...)
+		}
+		e.Weight = 0
+		for _, wc := range e.weights {
+			if wc.weight > e.Weight {
+				e.Weight = wc.weight
+			}
+		}
+		return true
+	}
+	return false
+}
+
+type node struct {
+	label    byte
+	children []*node
+	entries  []*entry
+	// maxWeight is the highest weight of any entry in this subtree
+	maxWeight uint64
+}
+
+func (n *node) child(label byte) *node {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= label
+	})
+	if i < len(n.children) && n.children[i].label == label {
+		return n.children[i]
+	}
+	return nil
+}
+
+func (n *node) removeChild(label byte) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= label
+	})
+	if i < len(n.children) && n.children[i].label == label {
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	}
+}
+
+// updateMaxWeight recomputes the highest weight of the subtree from the
+// entries and the children of the node.
+func (n *node) updateMaxWeight() {
+	n.maxWeight = 0
+	for _, e := range n.entries {
+		if e.Weight > n.maxWeight {
+			n.maxWeight = e.Weight
+		}
+	}
+	for _, child := range n.children {
+		if child.maxWeight > n.maxWeight {
+			n.maxWeight = child.maxWeight
+		}
+	}
+}
+
+func (n *node) addChild(label byte) *node {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= label
+	})
+	if i < len(n.children) && n.children[i].label == label {
+		return n.children[i]
+	}
+	rv := &node{label: label}
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = rv
+	return rv
+}
+
+// Trie maps keys to the completions found by prefixes of that key. Each
+// node tracks the highest weight below it, so the top completions are
+// found best-first.  A completion inserted several times, by several
+// documents, is kept until each insertion is removed, with the highest
+// weight of those left.
+type Trie struct {
+	root *node
+	size int
+}
+
+func NewTrie() *Trie {
+	return &Trie{
+		root: &node{},
+	}
+}
+
+// Len returns the number of completions in the trie.
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// Insert adds the completion text with the weight, found by the prefixes
+// of key. If the text was already inserted with the same key the highest
+// weight is used.
+func (t *Trie) Insert(key, text string, weight uint64) {
+	n := t.root
+	path := []*node{n}
+	for i := 0; i < len(key); i++ {
+		n = n.addChild(key[i])
+		path = append(path, n)
+	}
+
+	found := false
+	for _, e := range n.entries {
+		if e.Text == text {
+			e.add(weight, 1)
+			found = true
+			break
+		}
+	}
+	if !found {
+		e := &entry{Completion: Completion{Text: text}}
+		e.add(weight, 1)
+		n.entries = append(n.entries, e)
+		t.size++
+	}
+
+	for _, p := range path {
+		if weight > p.maxWeight {
+			p.maxWeight = weight
+		}
+	}
+}
+
+// Remove removes an insertion of the completion text found by key with
+// the weight, if any.  The completion is removed once all its insertions
+// are, along with the nodes left without completions.
+func (t *Trie) Remove(key, text string, weight uint64) {
+	n := t.root
+	path := []*node{n}
+	for i := 0; i < len(key); i++ {
+		n = n.child(key[i])
+		if n == nil {
+			return
+		}
+		path = append(path, n)
+	}
+
+	found := false
+	for i, e := range n.entries {
+		if e.Text == text {
+			found = e.remove(weight)
+			if found && len(e.weights) == 0 {
+				n.entries = append(n.entries[:i], n.entries[i+1:]...)
+				t.size--
+			}
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		p := path[i]
+		if i > 0 && len(p.entries) == 0 && len(p.children) == 0 {
+			path[i-1].removeChild(p.label)
+			continue
+		}
+		p.updateMaxWeight()
+	}
+}
+
+// TopK returns the k highest weighted completions of the prefix.
+func (t *Trie) TopK(prefix string, k int) Completions {
+	n := t.root
+	for i := 0; i < len(prefix) && n != nil; i++ {
+		n = n.child(prefix[i])
+	}
+	if n == nil {
+		return Completions{}
+	}
+	return topK([]*node{n}, k)
+}
+
+// FuzzyTopK returns the k highest weighted completions of any prefix
+// within fuzziness edits of prefix.
+func (t *Trie) FuzzyTopK(prefix string, fuzziness, k int) Completions {
+	if fuzziness <= 0 {
+		return t.TopK(prefix, k)
+	}
+
+	// row holds the edit distances between the current trie path and
+	// each prefix of the prefix
+	row := make([]int, len(prefix)+1)
+	for i := range row {
+		row[i] = i
+	}
+	var starts []*node
+	if row[len(prefix)] <= fuzziness {
+		starts = append(starts, t.root)
+	} else {
+		for _, child := range t.root.children {
+			starts = fuzzyStarts(child, prefix, row, fuzziness, starts)
+		}
+	}
+	return topK(starts, k)
+}
+
+// fuzzyStarts finds the nodes whose path is within fuzziness edits of
+// prefix. The search stops descending at a match, as the whole subtree
+// are completions of it.
+func fuzzyStarts(n *node, prefix string, prevRow []int, fuzziness int,
+	starts []*node) []*node {
+	row := make([]int, len(prevRow))
+	row[0] = prevRow[0] + 1
+	rowMin := row[0]
+	for j := 1; j < len(row); j++ {
+		cost := 1
+		if prefix[j-1] == n.label {
+			cost = 0
+		}
+		row[j] = prevRow[j-1] + cost
+		if prevRow[j]+1 < row[j] {
+			row[j] = prevRow[j] + 1
+		}
+		if row[j-1]+1 < row[j] {
+			row[j] = row[j-1] + 1
+		}
+		if row[j] < rowMin {
+			rowMin = row[j]
+		}
+	}
+
+	if row[len(prefix)] <= fuzziness {
+		return append(starts, n)
+	}
+	if rowMin > fuzziness {
+		return starts
+	}
+	for _, child := range n.children {
+		starts = fuzzyStarts(child, prefix, row, fuzziness, starts)
+	}
+	return starts
+}
+
+// topK visits the subtrees best-first, in order of their highest weight,
+// until k completions are found.
+func topK(starts []*node, k int) Completions {
+	rv := make(Completions, 0, k)
+	if k <= 0 {
+		return rv
+	}
+
+	q := &queue{}
+	for _, n := range starts {
+		q.pushNode(n)
+	}
+	for q.Len() > 0 && len(rv) < k {
+		item := heap.Pop(q).(*queueItem)
+		if item.entry != nil {
+			rv = append(rv, &Completion{
+				Text:   item.entry.Text,
+				Weight: item.entry.Weight,
+			})
+			continue
+		}
+		for _, e := range item.node.entries {
+			q.pushEntry(e)
+		}
+		for _, child := range item.node.children {
+			q.pushNode(child)
+		}
+	}
+	return rv
+}
+
+type queueItem struct {
+	weight uint64
+	seq    int
+	node   *node
+	entry  *entry
+}
+
+type queue struct {
+	items []*queueItem
+	seq   int
+}
+
+func (q *queue) pushNode(n *node) {
+	q.seq++
+	heap.Push(q, &queueItem{weight: n.maxWeight, seq: q.seq, node: n})
+}
+
+func (q *queue) pushEntry(e *entry) {
+	q.seq++
+	heap.Push(q, &queueItem{weight: e.Weight, seq: q.seq, entry: e})
+}
+
+func (q *queue) Len() int      { return len(q.items) }
+func (q *queue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *queue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if a.weight != b.weight {
+		return a.weight > b.weight
+	}
+	// completions before subtrees of the same weight
+	if (a.entry != nil) != (b.entry != nil) {
+		return a.entry != nil
+	}
+	if a.entry != nil && a.entry.Text != b.entry.Text {
+		return a.entry.Text < b.entry.Text
+	}
+	return a.seq < b.seq
+}
+
+func (q *queue) Push(x interface{}) {
+	q.items = append(q.items, x.(*queueItem))
+}
+
+func (q *queue) Pop() interface{} {
+	n := len(q.items)
+	rv := q.items[n-1]
+	q.items = q.items[:n-1]
+	return rv
+}
+
+// Merge combines the completions found in several tries, keeping the
+// highest weight of each text, and returns the top size of them.
+func Merge(size int, lists ...Completions) Completions {
+	byText := make(map[string]*Completion)
+	rv := Completions{}
+	for _, list := range lists {
+		for _, c := range list {
+			existing, ok := byText[c.Text]
+			if !ok {
+				existing = &Completion{Text: c.Text, Weight: c.Weight}
+				byText[c.Text] = existing
+				rv = append(rv, existing)
+			} else if c.Weight > existing.Weight {
+				existing.Weight = c.Weight
+			}
+		}
+	}
+	sort.Sort(rv)
+	if len(rv) > size {
+		rv = rv[:size]
+	}
+	return rv
+}
+
+// MarshalBinary encodes the nodes of the trie depth-first, each with its
+// label, its completions and their weights, and its children.
+func (t *Trie) MarshalBinary() ([]byte, error) {
+	return t.root.appendBinary(nil), nil
+}
+
+func (n *node) appendBinary(buf []byte) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	putUvarint := func(v uint64) {
+		l := binary.PutUvarint(tmp[:], v)
+		buf = append(buf, tmp[:l]...)
+	}
+	buf = append(buf, n.label)
+	putUvarint(uint64(len(n.entries)))
+	for _, e := range n.entries {
+		putUvarint(uint64(len(e.Text)))
+		buf = append(buf, e.Text...)
+		putUvarint(uint64(len(e.weights)))
+		for _, wc := range e.weights {
+			putUvarint(wc.weight)
+			putUvarint(wc.count)
+		}
+	}
+	putUvarint(uint64(len(n.children)))
+	for _, child := range n.children {
+		buf = child.appendBinary(buf)
+	}
+	return buf
+}
+
+// UnmarshalBinary decodes a trie encoded by MarshalBinary.
+func (t *Trie) UnmarshalBinary(data []byte) error {
+	d := trieDecoder{data: data}
+	root := d.node()
+	if d.err == nil && len(d.data) > 0 {
+		d.err = fmt.Errorf("%d extra bytes after the trie", len(d.data))
+	}
+	if d.err != nil {
+		return d.err
+	}
+	t.root = root
+	t.size = d.size
+	return nil
+}
+
+type trieDecoder struct {
+	data []byte
+	size int
+	err  error
+}
+
+func (d *trieDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, l := binary.Uvarint(d.data)
+	if l <= 0 {
+		d.err = fmt.Errorf("invalid trie encoding")
+		return 0
+	}
+	d.data = d.data[l:]
+	return v
+}
+
+func (d *trieDecoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.data)) {
+		d.err = fmt.Errorf("invalid trie encoding")
+		return nil
+	}
+	rv := d.data[:n]
+	d.data = d.data[n:]
+	return rv
+}
+
+func (d *trieDecoder) node() *node {
+	label := d.bytes(1)
+	if d.err != nil {
+		return nil
+	}
+	n := &node{label: label[0]}
+	numEntries := d.uvarint()
+	for i := uint64(0); i < numEntries && d.err == nil; i++ {
+		e := &entry{Completion: Completion{Text: string(d.bytes(d.uvarint()))}}
+		numWeights := d.uvarint()
+		for j := uint64(0); j < numWeights && d.err == nil; j++ {
+			weight := d.uvarint()
+			e.add(weight, d.uvarint())
+		}
+		n.entries = append(n.entries, e)
+		d.size++
+	}
+	numChildren := d.uvarint()
+	for i := uint64(0); i < numChildren && d.err == nil; i++ {
+		n.children = append(n.children, d.node())
+	}
+	if d.err != nil {
+		return nil
+	}
+	n.updateMaxWeight()
+	return n
+}
@@ -0,0 +1,187 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func texts(completions Completions) []string {
+	rv := []string{}
+	for _, c := range completions {
+		rv = append(rv, c.Text)
+	}
+	return rv
+}
+
+func buildTestTrie() *Trie {
+	t := NewTrie()
+	t.Insert("nirvana", "Nirvana", 10)
+	t.Insert("nine inch nails", "Nine Inch Nails", 50)
+	t.Insert("nickelback", "Nickelback", 2)
+	t.Insert("nick cave", "Nick Cave", 30)
+	t.Insert("nick drake", "Nick Drake", 30)
+	t.Insert("no doubt", "No Doubt", 20)
+	t.Insert("metallica", "Metallica", 100)
+	// a duplicate keeps the highest weight
+	t.Insert("nirvana", "Nirvana", 5)
+	t.Insert("nirvana", "Nirvana", 40)
+	return t
+}
+
+func TestTrieTopK(t *testing.T) {
+	trie := buildTestTrie()
+	if trie.Len() != 7 {
+		t.Errorf("expected 7 completions, got %d", trie.Len())
+	}
+
+	tests := []struct {
+		prefix string
+		k      int
+		want   []string
+	}{
+		{"n", 3, []string{"Nine Inch Nails", "Nirvana", "Nick Cave"}},
+		{"ni", 10, []string{"Nine Inch Nails", "Nirvana", "Nick Cave", "Nick Drake", "Nickelback"}},
+		{"nick", 2, []string{"Nick Cave", "Nick Drake"}},
+		{"", 2, []string{"Metallica", "Nine Inch Nails"}},
+		{"nirvana", 5, []string{"Nirvana"}},
+		{"nirvanas", 5, []string{}},
+		{"x", 5, []string{}},
+		{"n", 0, []string{}},
+	}
+
+	for _, test := range tests {
+		got := texts(trie.TopK(test.prefix, test.k))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expected %v for prefix %q k %d, got %v", test.want, test.prefix, test.k, got)
+		}
+	}
+
+	got := trie.TopK("nirv", 1)
+	if len(got) != 1 || got[0].Weight != 40 {
+		t.Errorf("expected highest duplicate weight 40, got %v", got)
+	}
+}
+
+func TestTrieFuzzyTopK(t *testing.T) {
+	trie := buildTestTrie()
+
+	tests := []struct {
+		prefix    string
+		fuzziness int
+		want      []string
+	}{
+		{"nriv", 0, []string{}},
+		{"nirvna", 1, []string{"Nirvana"}},
+		{"metalic", 1, []string{"Metallica"}},
+		{"nicl", 1, []string{"Nick Cave", "Nick Drake", "Nickelback"}},
+		{"nriv", 2, []string{"Nine Inch Nails", "Nirvana", "Nick Cave", "Nick Drake", "Nickelback"}},
+	}
+
+	for _, test := range tests {
+		got := texts(trie.FuzzyTopK(test.prefix, test.fuzziness, 10))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expected %v for prefix %q fuzziness %d, got %v", test.want, test.prefix, test.fuzziness, got)
+		}
+	}
+}
+
+func TestTrieRemove(t *testing.T) {
+	trie := buildTestTrie()
+	trie.Remove("nine inch nails", "Nine Inch Nails", 50)
+	trie.Remove("nick cave", "Nick Cave", 30)
+	// missing keys, texts and weights are ignored
+	trie.Remove("nick cav", "Nick Cave", 30)
+	trie.Remove("nick drake", "Nick Cave", 30)
+	trie.Remove("nick drake", "Nick Drake", 31)
+	if trie.Len() != 5 {
+		t.Errorf("expected 5 completions, got %d", trie.Len())
+	}
+
+	got := texts(trie.TopK("ni", 10))
+	want := []string{"Nirvana", "Nick Drake", "Nickelback"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if trie.root.child('n').maxWeight != 40 {
+		t.Errorf("expected max weight 40 below n, got %d", trie.root.child('n').maxWeight)
+	}
+	if trie.root.child('n').child('i').child('n') != nil {
+		t.Errorf("expected the nodes of the removed completion to be removed")
+	}
+
+	// the highest weight left is used once the highest is removed
+	trie.Remove("nirvana", "Nirvana", 40)
+	got = texts(trie.TopK("ni", 10))
+	want = []string{"Nick Drake", "Nirvana", "Nickelback"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// a removed completion can be inserted again with a lower weight
+	trie.Remove("nirvana", "Nirvana", 10)
+	trie.Remove("nirvana", "Nirvana", 5)
+	if len(trie.TopK("nirvana", 1)) != 0 {
+		t.Errorf("expected Nirvana to be removed")
+	}
+	trie.Insert("nirvana", "Nirvana", 1)
+	got = texts(trie.TopK("ni", 10))
+	want = []string{"Nick Drake", "Nickelback", "Nirvana"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestTrieBinary(t *testing.T) {
+	trie := buildTestTrie()
+	data, err := trie.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := NewTrie()
+	err = decoded.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Len() != trie.Len() {
+		t.Errorf("expected %d completions, got %d", trie.Len(), decoded.Len())
+	}
+	if !reflect.DeepEqual(decoded.TopK("", 10), trie.TopK("", 10)) {
+		t.Errorf("expected %v, got %v", trie.TopK("", 10), decoded.TopK("", 10))
+	}
+	// the weights of each insertion are kept
+	decoded.Remove("nirvana", "Nirvana", 40)
+	got := decoded.TopK("nirv", 1)
+	if len(got) != 1 || got[0].Weight != 10 {
+		t.Errorf("expected weight 10 left, got %v", got)
+	}
+
+	err = decoded.UnmarshalBinary(data[:len(data)-1])
+	if err == nil {
+		t.Errorf("expected error for truncated trie")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	got := Merge(3,
+		Completions{{Text: "a", Weight: 5}, {Text: "b", Weight: 3}},
+		Completions{{Text: "b", Weight: 7}, {Text: "c", Weight: 1}, {Text: "d", Weight: 5}},
+	)
+	expected := Completions{{Text: "b", Weight: 7}, {Text: "a", Weight: 5}, {Text: "d", Weight: 5}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
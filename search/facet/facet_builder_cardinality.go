@@ -0,0 +1,116 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/hyperloglog"
+)
+
+// CardinalityFacetBuilder estimates the number of distinct
+// terms of a field using a HyperLogLog sketch.
+type CardinalityFacetBuilder struct {
+	field    string
+	sketch   *hyperloglog.HyperLogLog
+	total    int
+	missing  int
+	sawValue bool
+
+	// numeric is nil if the type of the field is not known
+	numeric       *bool
+	sawFullPrec   bool
+	lowerPrecTerm [][]byte
+}
+
+func NewCardinalityFacetBuilder(field string, precision uint8) (*CardinalityFacetBuilder, error) {
+	sketch, err := hyperloglog.New(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &CardinalityFacetBuilder{
+		field:  field,
+		sketch: sketch,
+	}, nil
+}
+
+// SetNumeric sets whether the field is mapped as a number or date,
+// whose values are also indexed at lower precisions which must not be
+// counted.  If it is not set, the lower precision terms of a document
+// are skipped only when it also has a full precision numeric term.
+func (fb *CardinalityFacetBuilder) SetNumeric(numeric bool) {
+	fb.numeric = &numeric
+}
+
+func (fb *CardinalityFacetBuilder) Field() string {
+	return fb.field
+}
+
+func (fb *CardinalityFacetBuilder) UpdateVisitor(field string, term []byte) {
+	if field == fb.field {
+		fb.sawValue = true
+		if fb.numeric != nil && !*fb.numeric {
+			fb.add(term)
+			return
+		}
+		// numeric and date values are also indexed at lower
+		// precisions, only count the full precision terms
+		valid, shift := numeric.ValidPrefixCodedTerm(string(term))
+		if valid && shift > 0 {
+			if fb.numeric == nil {
+				fb.lowerPrecTerm = append(fb.lowerPrecTerm, append([]byte(nil), term...))
+			}
+			return
+		}
+		if valid {
+			fb.sawFullPrec = true
+		}
+		fb.add(term)
+	}
+}
+
+func (fb *CardinalityFacetBuilder) add(term []byte) {
+	fb.sketch.Add(term)
+	fb.total++
+}
+
+func (fb *CardinalityFacetBuilder) StartDoc() {
+	fb.sawValue = false
+	fb.sawFullPrec = false
+	fb.lowerPrecTerm = fb.lowerPrecTerm[:0]
+}
+
+func (fb *CardinalityFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+	// terms only looking like lower precision numeric terms
+	if !fb.sawFullPrec {
+		for _, term := range fb.lowerPrecTerm {
+			fb.add(term)
+		}
+	}
+}
+
+func (fb *CardinalityFacetBuilder) Result() *search.FacetResult {
+	cardinality := int(fb.sketch.Count())
+	return &search.FacetResult{
+		Field:       fb.field,
+		Total:       fb.total,
+		Missing:     fb.missing,
+		Cardinality: &cardinality,
+		Sketch:      fb.sketch,
+	}
+}
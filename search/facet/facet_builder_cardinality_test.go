@@ -0,0 +1,121 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/edwindvinas/bleve/search"
+)
+
+func TestCardinalityFacetBuilder(t *testing.T) {
+	fb, err := NewCardinalityFacetBuilder("user", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		fb.StartDoc()
+		fb.UpdateVisitor("user", []byte("user"+strconv.Itoa(i%40)))
+		fb.UpdateVisitor("session", []byte("session"+strconv.Itoa(i)))
+		fb.EndDoc()
+	}
+	fb.StartDoc()
+	fb.EndDoc()
+
+	res := fb.Result()
+	if res.Cardinality == nil || *res.Cardinality != 40 {
+		t.Errorf("expected cardinality 40, got %v", res.Cardinality)
+	}
+	if res.Total != 100 || res.Missing != 1 {
+		t.Errorf("expected total 100 and missing 1, got %d and %d", res.Total, res.Missing)
+	}
+
+	// numeric values count once, not once per precision
+	fb, _ = NewCardinalityFacetBuilder("price", 14)
+	visitNumericDocs(fb, "price", [][]float64{{1}, {2}, {2}, {3.5}})
+	res = fb.Result()
+	if *res.Cardinality != 3 {
+		t.Errorf("expected cardinality 3, got %d", *res.Cardinality)
+	}
+
+	fb, _ = NewCardinalityFacetBuilder("price", 14)
+	fb.SetNumeric(true)
+	visitNumericDocs(fb, "price", [][]float64{{1}, {2}, {2}, {3.5}})
+	res = fb.Result()
+	if *res.Cardinality != 3 {
+		t.Errorf("expected cardinality 3 for a numeric field, got %d", *res.Cardinality)
+	}
+
+	_, err = NewCardinalityFacetBuilder("user", 2)
+	if err == nil {
+		t.Errorf("expected error for precision 2")
+	}
+}
+
+func TestCardinalityFacetBuilderPrefixCodedLookalikes(t *testing.T) {
+	// a leading '1' reads as a shift of 17 with a matching length
+	for _, numeric := range []*bool{nil, new(bool)} {
+		fb, _ := NewCardinalityFacetBuilder("id", 14)
+		if numeric != nil {
+			fb.SetNumeric(*numeric)
+		}
+		for i := 0; i < 50; i++ {
+			fb.StartDoc()
+			fb.UpdateVisitor("id", []byte(strconv.Itoa(10000001+i)))
+			fb.EndDoc()
+		}
+		res := fb.Result()
+		if *res.Cardinality != 50 || res.Total != 50 {
+			t.Errorf("expected cardinality and total 50, got %d and %d", *res.Cardinality, res.Total)
+		}
+	}
+}
+
+func TestCardinalityFacetResultsMerge(t *testing.T) {
+	results := make([]*search.FacetResult, 2)
+	for i := range results {
+		fb, _ := NewCardinalityFacetBuilder("user", 14)
+		// the halves share 500 users
+		for j := i * 500; j < i*500+1000; j++ {
+			fb.StartDoc()
+			fb.UpdateVisitor("user", []byte("user"+strconv.Itoa(j)))
+			fb.EndDoc()
+		}
+		results[i] = fb.Result()
+	}
+
+	// the results of remote indexes are decoded from json
+	for i, result := range results {
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results[i] = new(search.FacetResult)
+		err = json.Unmarshal(data, results[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results[0].Merge(results[1])
+	if *results[0].Cardinality < 1480 || *results[0].Cardinality > 1520 {
+		t.Errorf("expected cardinality about 1500, got %d", *results[0].Cardinality)
+	}
+	if results[0].Total != 2000 {
+		t.Errorf("expected total 2000, got %d", results[0].Total)
+	}
+}
@@ -35,6 +35,7 @@ type DateTimeFacetBuilder struct {
 	missing    int
 	ranges     map[string]*dateTimeRange
 	sawValue   bool
+	docBuckets []string
 }
 
 func NewDateTimeFacetBuilder(field string, size int) *DateTimeFacetBuilder {
@@ -74,6 +75,7 @@ func (fb *DateTimeFacetBuilder) UpdateVisitor(field string, term []byte) {
 					if (r.start.IsZero() || t.After(r.start) || t.Equal(r.start)) && (r.end.IsZero() || t.Before(r.end)) {
 						fb.termsCount[rangeName] = fb.termsCount[rangeName] + 1
 						fb.total++
+						fb.docBuckets = append(fb.docBuckets, rangeName)
 					}
 				}
 			}
@@ -83,6 +85,12 @@ func (fb *DateTimeFacetBuilder) UpdateVisitor(field string, term []byte) {
 
 func (fb *DateTimeFacetBuilder) StartDoc() {
 	fb.sawValue = false
+	fb.docBuckets = fb.docBuckets[:0]
+}
+
+// DocBuckets returns the buckets the current document was counted in.
+func (fb *DateTimeFacetBuilder) DocBuckets() []string {
+	return fb.docBuckets
 }
 
 func (fb *DateTimeFacetBuilder) EndDoc() {
@@ -0,0 +1,412 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// MaxHistogramBuckets limits the number of empty buckets
+// added to fill the gaps of a histogram.
+var MaxHistogramBuckets = 10000
+
+// histogramInterval places values into buckets, the key of
+// a bucket is the smallest value it can contain.
+type histogramInterval interface {
+	// floor returns the key of the bucket containing the value
+	floor(v float64) float64
+	// next returns the key of the bucket following the bucket
+	next(key float64) float64
+	name(key float64) string
+}
+
+type numericInterval struct {
+	interval float64
+	offset   float64
+}
+
+func (i *numericInterval) floor(v float64) float64 {
+	return math.Floor((v-i.offset)/i.interval)*i.interval + i.offset
+}
+
+func (i *numericInterval) next(key float64) float64 {
+	// count buckets rather than adding the interval,
+	// so the keys match those computed by floor
+	n := math.Floor((key-i.offset)/i.interval + 0.5)
+	return (n+1)*i.interval + i.offset
+}
+
+func (i *numericInterval) name(key float64) string {
+	return strconv.FormatFloat(key, 'f', -1, 64)
+}
+
+// date keys are milliseconds since the epoch
+func millisToTime(ms float64) time.Time {
+	return time.Unix(0, int64(ms)*int64(time.Millisecond))
+}
+
+func timeToMillis(t time.Time) float64 {
+	return float64(t.UnixNano() / int64(time.Millisecond))
+}
+
+// fixedDateInterval buckets dates by a fixed duration,
+// aligned on the midnight of the epoch in the time zone.
+type fixedDateInterval struct {
+	millis   int64
+	location *time.Location
+}
+
+// offset returns the offset of the zone at v, in milliseconds
+func (i *fixedDateInterval) offset(v float64) int64 {
+	_, offset := millisToTime(v).In(i.location).Zone()
+	return int64(offset) * 1000
+}
+
+// instant returns the date of the local time, preferring the
+// offset given when the local time is valid with it, so that
+// the hour repeated when the zone goes back is not merged
+func (i *fixedDateInterval) instant(local, offset int64) float64 {
+	v := float64(local - offset)
+	other := i.offset(v)
+	if other == offset {
+		return v
+	}
+	w := float64(local - other)
+	if i.offset(w) == other {
+		return w
+	}
+	// the local time was skipped when the zone went forward,
+	// the bucket starts at the change
+	lo, hi := math.Min(v, w), math.Max(v, w)
+	for hi-lo > 1 {
+		mid := math.Floor((lo + hi) / 2)
+		if i.offset(mid) == i.offset(lo) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+func (i *fixedDateInterval) floor(v float64) float64 {
+	offset := i.offset(v)
+	local := int64(v) + offset
+	n := local / i.millis
+	if local%i.millis < 0 {
+		n--
+	}
+	return i.instant(n*i.millis, offset)
+}
+
+func (i *fixedDateInterval) next(key float64) float64 {
+	// the offset may change between the keys, so the key
+	// following is the floor of the key plus the interval
+	// rather than the sum, unless the zone went back by the
+	// interval or more and that is the key itself
+	if n := i.floor(key + float64(i.millis)); n > key {
+		return n
+	}
+	offset := i.offset(key)
+	return i.instant(int64(key)+offset+i.millis, offset)
+}
+
+func (i *fixedDateInterval) name(key float64) string {
+	return millisToTime(key).In(i.location).Format(time.RFC3339Nano)
+}
+
+// calendarDateInterval buckets dates by calendar units,
+// which vary in length, in the time zone.
+type calendarDateInterval struct {
+	unit     string
+	location *time.Location
+}
+
+func (i *calendarDateInterval) floor(v float64) float64 {
+	t := millisToTime(v).In(i.location)
+	y, m, d := t.Date()
+	switch i.unit {
+	case "minute":
+		t = time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, i.location)
+	case "hour":
+		t = time.Date(y, m, d, t.Hour(), 0, 0, 0, i.location)
+	case "day":
+		t = time.Date(y, m, d, 0, 0, 0, 0, i.location)
+	case "week":
+		// weeks start on monday
+		t = time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, i.location)
+	case "month":
+		t = time.Date(y, m, 1, 0, 0, 0, 0, i.location)
+	case "quarter":
+		t = time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, i.location)
+	case "year":
+		t = time.Date(y, 1, 1, 0, 0, 0, 0, i.location)
+	}
+	return timeToMillis(t)
+}
+
+func (i *calendarDateInterval) next(key float64) float64 {
+	t := millisToTime(key).In(i.location)
+	switch i.unit {
+	case "minute":
+		t = t.Add(time.Minute)
+	case "hour":
+		t = t.Add(time.Hour)
+	case "day":
+		t = t.AddDate(0, 0, 1)
+	case "week":
+		t = t.AddDate(0, 0, 7)
+	case "month":
+		t = t.AddDate(0, 1, 0)
+	case "quarter":
+		t = t.AddDate(0, 3, 0)
+	case "year":
+		t = t.AddDate(1, 0, 0)
+	}
+	return timeToMillis(t)
+}
+
+func (i *calendarDateInterval) name(key float64) string {
+	return millisToTime(key).In(i.location).Format(time.RFC3339Nano)
+}
+
+var calendarUnits = map[string]bool{
+	"minute":  true,
+	"hour":    true,
+	"day":     true,
+	"week":    true,
+	"month":   true,
+	"quarter": true,
+	"year":    true,
+}
+
+// HistogramFacetBuilder counts the values of a field in
+// buckets of a fixed interval, the buckets are created as
+// values are seen.
+type HistogramFacetBuilder struct {
+	field       string
+	interval    histogramInterval
+	date        bool
+	minDocCount int
+	boundsMin   *float64
+	boundsMax   *float64
+	counts      map[float64]int
+	total       int
+	missing     int
+	sawValue    bool
+	docBuckets  []string
+}
+
+func newHistogramFacetBuilder(field string, interval histogramInterval, date bool) *HistogramFacetBuilder {
+	return &HistogramFacetBuilder{
+		field:    field,
+		interval: interval,
+		date:     date,
+		counts:   make(map[float64]int),
+	}
+}
+
+// NewHistogramFacetBuilder buckets the numeric values of
+// the field, each bucket spans interval and the buckets
+// are shifted by offset.
+func NewHistogramFacetBuilder(field string, interval, offset float64) (*HistogramFacetBuilder, error) {
+	if interval <= 0 || math.IsInf(interval, 0) || math.IsNaN(interval) {
+		return nil, fmt.Errorf("histogram interval must be positive")
+	}
+	return newHistogramFacetBuilder(field, &numericInterval{
+		interval: interval,
+		offset:   offset,
+	}, false), nil
+}
+
+// NewDateHistogramFacetBuilder buckets the date values of
+// the field.  The interval is either a calendar unit, one
+// of minute, hour, day, week, month, quarter or year, or a
+// fixed duration such as 90m.  Buckets are aligned in the
+// time zone of the location, nil means UTC.
+func NewDateHistogramFacetBuilder(field string, interval string, location *time.Location) (*HistogramFacetBuilder, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	if calendarUnits[interval] {
+		return newHistogramFacetBuilder(field, &calendarDateInterval{
+			unit:     interval,
+			location: location,
+		}, true), nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date histogram interval '%s'", interval)
+	}
+	if d < time.Millisecond {
+		return nil, fmt.Errorf("date histogram interval '%s' must be at least 1ms", interval)
+	}
+	return newHistogramFacetBuilder(field, &fixedDateInterval{
+		millis:   int64(d / time.Millisecond),
+		location: location,
+	}, true), nil
+}
+
+// SetMinDocCount sets the count below which buckets are
+// dropped.  With the default of 0 the empty buckets between
+// the first and the last are included.
+func (fb *HistogramFacetBuilder) SetMinDocCount(minDocCount int) {
+	fb.minDocCount = minDocCount
+}
+
+// SetExtendedBounds extends the numeric histogram with
+// empty buckets up to min and max, either may be nil.
+// It has no effect unless empty buckets are included.
+func (fb *HistogramFacetBuilder) SetExtendedBounds(min, max *float64) {
+	fb.boundsMin = min
+	fb.boundsMax = max
+}
+
+// SetExtendedDateBounds extends the date histogram with
+// empty buckets up to start and end, either may be zero.
+// It has no effect unless empty buckets are included.
+func (fb *HistogramFacetBuilder) SetExtendedDateBounds(start, end time.Time) {
+	fb.boundsMin = nil
+	fb.boundsMax = nil
+	if !start.IsZero() {
+		min := timeToMillis(start)
+		fb.boundsMin = &min
+	}
+	if !end.IsZero() {
+		max := timeToMillis(end)
+		fb.boundsMax = &max
+	}
+}
+
+func (fb *HistogramFacetBuilder) Field() string {
+	return fb.field
+}
+
+func (fb *HistogramFacetBuilder) UpdateVisitor(field string, term []byte) {
+	if field == fb.field {
+		fb.sawValue = true
+		// only consider the values which are shifted 0
+		prefixCoded := numeric.PrefixCoded(term)
+		shift, err := prefixCoded.Shift()
+		if err == nil && shift == 0 {
+			i64, err := prefixCoded.Int64()
+			if err == nil {
+				var v float64
+				if fb.date {
+					v = timeToMillis(time.Unix(0, i64))
+				} else {
+					v = numeric.Int64ToFloat64(i64)
+				}
+				key := fb.interval.floor(v)
+				fb.counts[key] = fb.counts[key] + 1
+				fb.total++
+				fb.docBuckets = append(fb.docBuckets, fb.interval.name(key))
+			}
+		}
+	}
+}
+
+func (fb *HistogramFacetBuilder) StartDoc() {
+	fb.sawValue = false
+	fb.docBuckets = fb.docBuckets[:0]
+}
+
+// DocBuckets returns the buckets the current document was counted in.
+func (fb *HistogramFacetBuilder) DocBuckets() []string {
+	return fb.docBuckets
+}
+
+func (fb *HistogramFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+}
+
+func (fb *HistogramFacetBuilder) Result() *search.FacetResult {
+	rv := search.FacetResult{
+		Field:   fb.field,
+		Total:   fb.total,
+		Missing: fb.missing,
+	}
+
+	rv.Histogram = make(search.HistogramFacets, 0, len(fb.counts))
+	for key, count := range fb.counts {
+		rv.Histogram = append(rv.Histogram, &search.HistogramFacet{
+			Key:   key,
+			Name:  fb.interval.name(key),
+			Count: count,
+		})
+	}
+	sort.Sort(rv.Histogram)
+
+	return &rv
+}
+
+// Fixup drops the buckets below the minimum count, or when
+// empty buckets are included fills the gaps between the
+// buckets and up to the extended bounds.  Result leaves this
+// to the caller, so that the counts of several indexes can
+// be merged first.
+func (fb *HistogramFacetBuilder) Fixup(fr *search.FacetResult) {
+	rv := make(search.HistogramFacets, 0, len(fr.Histogram))
+	for _, hf := range fr.Histogram {
+		if hf.Count >= fb.minDocCount {
+			rv = append(rv, hf)
+		}
+	}
+
+	if fb.minDocCount <= 0 {
+		seen := make(map[float64]struct{}, len(rv))
+		var first, last *float64
+		extend := func(key float64) {
+			if first == nil || key < *first {
+				first = &key
+			}
+			if last == nil || key > *last {
+				last = &key
+			}
+		}
+		for _, hf := range rv {
+			seen[hf.Key] = struct{}{}
+			extend(hf.Key)
+		}
+		if fb.boundsMin != nil {
+			extend(fb.interval.floor(*fb.boundsMin))
+		}
+		if fb.boundsMax != nil {
+			extend(fb.interval.floor(*fb.boundsMax))
+		}
+		if first != nil {
+			for key := *first; key <= *last && len(rv) < MaxHistogramBuckets; key = fb.interval.next(key) {
+				if _, ok := seen[key]; !ok {
+					rv = append(rv, &search.HistogramFacet{
+						Key:  key,
+						Name: fb.interval.name(key),
+					})
+				}
+			}
+		}
+	}
+
+	sort.Sort(rv)
+	fr.Histogram = rv
+}
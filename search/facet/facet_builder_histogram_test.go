@@ -0,0 +1,224 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+func histogramCounts(fr *search.FacetResult) map[string]int {
+	rv := make(map[string]int)
+	for _, hf := range fr.Histogram {
+		rv[hf.Name] = hf.Count
+	}
+	return rv
+}
+
+func histogramNames(fr *search.FacetResult) []string {
+	var rv []string
+	for _, hf := range fr.Histogram {
+		rv = append(rv, hf.Name)
+	}
+	return rv
+}
+
+// fixedResult returns the result of fb as it is returned for a single index
+func fixedResult(fb *HistogramFacetBuilder) *search.FacetResult {
+	rv := fb.Result()
+	fb.Fixup(rv)
+	return rv
+}
+
+func TestHistogramFacetBuilder(t *testing.T) {
+	newBuilder := func() *HistogramFacetBuilder {
+		fb, err := NewHistogramFacetBuilder("price", 10, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fb
+	}
+	docs := [][]float64{{7}, {14.9}, {15}, {-3}, {}, {40, 44}}
+
+	fb := newBuilder()
+	visitNumericDocs(fb, "price", docs)
+	res := fixedResult(fb)
+	expectedNames := []string{"-5", "5", "15", "25", "35"}
+	if !reflect.DeepEqual(histogramNames(res), expectedNames) {
+		t.Errorf("expected buckets %v, got %v", expectedNames, histogramNames(res))
+	}
+	expectedCounts := map[string]int{"-5": 1, "5": 2, "15": 1, "25": 0, "35": 2}
+	if !reflect.DeepEqual(histogramCounts(res), expectedCounts) {
+		t.Errorf("expected counts %v, got %v", expectedCounts, histogramCounts(res))
+	}
+	if res.Total != 6 || res.Missing != 1 {
+		t.Errorf("expected total 6 and missing 1, got %d and %d", res.Total, res.Missing)
+	}
+
+	fb = newBuilder()
+	fb.SetMinDocCount(2)
+	visitNumericDocs(fb, "price", docs)
+	// the counts of each index are only filtered once merged
+	expectedNames = []string{"-5", "5", "15", "35"}
+	res = fb.Result()
+	if names := histogramNames(res); !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected unfiltered buckets %v, got %v", expectedNames, names)
+	}
+	fb.Fixup(res)
+	expectedNames = []string{"5", "35"}
+	if names := histogramNames(res); !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected buckets %v, got %v", expectedNames, names)
+	}
+
+	fb = newBuilder()
+	min, max := -20.0, 50.0
+	fb.SetExtendedBounds(&min, &max)
+	visitNumericDocs(fb, "price", docs)
+	expectedNames = []string{"-25", "-15", "-5", "5", "15", "25", "35", "45"}
+	if names := histogramNames(fixedResult(fb)); !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected buckets %v, got %v", expectedNames, names)
+	}
+
+	_, err := NewHistogramFacetBuilder("price", 0, 0)
+	if err == nil {
+		t.Errorf("expected error for zero interval")
+	}
+}
+
+func visitDateDocs(fb search.FacetBuilder, field string, dates []string) {
+	for _, date := range dates {
+		d, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			panic(err)
+		}
+		fb.StartDoc()
+		fb.UpdateVisitor(field, numeric.MustNewPrefixCodedInt64(d.UnixNano(), 0))
+		fb.EndDoc()
+	}
+}
+
+func TestDateHistogramFacetBuilder(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+	dates := []string{
+		"2017-01-31T23:30:00Z", // february in paris
+		"2017-01-15T10:00:00Z",
+		"2017-04-02T12:00:00Z",
+		"2017-03-26T00:30:00Z", // just before the change to summer time
+	}
+
+	tests := []struct {
+		interval string
+		location *time.Location
+		expected map[string]int
+	}{
+		{
+			interval: "month",
+			location: paris,
+			expected: map[string]int{
+				"2017-01-01T00:00:00+01:00": 1,
+				"2017-02-01T00:00:00+01:00": 1,
+				"2017-03-01T00:00:00+01:00": 1,
+				"2017-04-01T00:00:00+02:00": 1,
+			},
+		},
+		{
+			interval: "quarter",
+			location: nil,
+			expected: map[string]int{
+				"2017-01-01T00:00:00Z": 3,
+				"2017-04-01T00:00:00Z": 1,
+			},
+		},
+		{
+			interval: "24h",
+			location: paris,
+			expected: map[string]int{
+				"2017-01-15T00:00:00+01:00": 1,
+				"2017-02-01T00:00:00+01:00": 1,
+				"2017-03-26T00:00:00+01:00": 1,
+				"2017-04-02T00:00:00+02:00": 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		fb, err := NewDateHistogramFacetBuilder("date", test.interval, test.location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fb.SetMinDocCount(1)
+		visitDateDocs(fb, "date", dates)
+		counts := histogramCounts(fixedResult(fb))
+		if !reflect.DeepEqual(counts, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.interval, test.expected, counts)
+		}
+	}
+
+	// weeks start on monday, and empty weeks are filled in
+	fb, err := NewDateHistogramFacetBuilder("date", "week", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb.SetExtendedDateBounds(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	visitDateDocs(fb, "date", []string{"2017-01-11T10:00:00Z", "2017-01-15T23:00:00Z"})
+	expected := []string{"2016-12-26T00:00:00Z", "2017-01-02T00:00:00Z", "2017-01-09T00:00:00Z"}
+	res := fixedResult(fb)
+	if names := histogramNames(res); !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected weeks %v, got %v", expected, names)
+	}
+	if res.Histogram[2].Count != 2 {
+		t.Errorf("expected 2 dates in the last week, got %d", res.Histogram[2].Count)
+	}
+
+	// fixed intervals stay aligned on the local midnight when
+	// the empty days across the changes of time are filled in
+	dstTests := []struct {
+		dates    []string
+		expected []string
+	}{
+		{
+			dates: []string{"2017-03-25T12:00:00Z", "2017-03-28T12:00:00Z"},
+			expected: []string{"2017-03-25T00:00:00+01:00", "2017-03-26T00:00:00+01:00",
+				"2017-03-27T00:00:00+02:00", "2017-03-28T00:00:00+02:00"},
+		},
+		{
+			dates: []string{"2017-10-28T12:00:00Z", "2017-10-30T12:00:00Z"},
+			expected: []string{"2017-10-28T00:00:00+02:00", "2017-10-29T00:00:00+02:00",
+				"2017-10-30T00:00:00+01:00"},
+		},
+	}
+	for _, test := range dstTests {
+		fb, err = NewDateHistogramFacetBuilder("date", "24h", paris)
+		if err != nil {
+			t.Fatal(err)
+		}
+		visitDateDocs(fb, "date", test.dates)
+		if names := histogramNames(fixedResult(fb)); !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("expected days %v, got %v", test.expected, names)
+		}
+	}
+
+	_, err = NewDateHistogramFacetBuilder("date", "fortnight", nil)
+	if err == nil {
+		t.Errorf("expected error for invalid interval")
+	}
+}
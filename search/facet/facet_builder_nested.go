@@ -0,0 +1,244 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// NestedFacetBuilder computes sub-facets for each bucket of a parent
+// facet.
+//
+// When the parent returns all of its buckets, the field terms of each
+// document are buffered, and once the parent has placed the document
+// in its buckets they are replayed into the sub-facets of each of those
+// buckets.
+//
+// When the parent only returns its top size buckets, only the internal
+// ids of the documents and their buckets are recorded while counting.
+// Once the parent knows its top buckets, the terms of the documents of
+// those buckets are visited again to compute their sub-facets, so the
+// sub-facets are exact whatever the order of the documents.
+type NestedFacetBuilder struct {
+	parent      search.BucketFacetBuilder
+	newChildren func() *search.FacetsBuilder
+	reader      index.IndexReader
+	size        int
+	fields      []string
+	childFields map[string]struct{}
+	children    map[string]*search.FacetsBuilder
+	docFields   []string
+	docTerms    [][]byte
+
+	// the documents counted when the top buckets are not known yet
+	docID     index.IndexInternalID
+	docs      []nestedDoc
+	bucketIDs map[string]int
+	buckets   []string
+}
+
+type nestedDoc struct {
+	id      index.IndexInternalID
+	buckets []int
+}
+
+// NewNestedFacetBuilder wraps the parent facet builder, newChildren is
+// called to build the sub-facets of each bucket.  The parent returns its
+// top size buckets, or all of them when size is 0.  When it does not
+// return them all, reader is used to visit the terms of the documents in
+// the top buckets again.
+func NewNestedFacetBuilder(parent search.BucketFacetBuilder, size int,
+	reader index.IndexReader, newChildren func() *search.FacetsBuilder) *NestedFacetBuilder {
+	rv := &NestedFacetBuilder{
+		parent:      parent,
+		newChildren: newChildren,
+		reader:      reader,
+		size:        size,
+		fields:      []string{parent.Field()},
+		childFields: make(map[string]struct{}),
+		children:    make(map[string]*search.FacetsBuilder),
+		bucketIDs:   make(map[string]int),
+	}
+	for _, field := range newChildren().RequiredFields() {
+		if _, ok := rv.childFields[field]; !ok {
+			rv.childFields[field] = struct{}{}
+			rv.fields = append(rv.fields, field)
+		}
+	}
+	return rv
+}
+
+func (fb *NestedFacetBuilder) Field() string {
+	return fb.parent.Field()
+}
+
+// RequiredFields returns the fields of the parent and of all sub-facets.
+func (fb *NestedFacetBuilder) RequiredFields() []string {
+	return fb.fields
+}
+
+func (fb *NestedFacetBuilder) StartDoc() {
+	fb.parent.StartDoc()
+	fb.docID = nil
+	fb.docFields = fb.docFields[:0]
+	fb.docTerms = fb.docTerms[:0]
+}
+
+// SetDocID records the internal id of the current document.
+func (fb *NestedFacetBuilder) SetDocID(id index.IndexInternalID) {
+	fb.docID = id
+}
+
+func (fb *NestedFacetBuilder) UpdateVisitor(field string, term []byte) {
+	fb.parent.UpdateVisitor(field, term)
+	if _, ok := fb.childFields[field]; ok && fb.size == 0 {
+		// the term may be reused by the caller, keep a copy
+		termCopy := make([]byte, len(term))
+		copy(termCopy, term)
+		fb.docFields = append(fb.docFields, field)
+		fb.docTerms = append(fb.docTerms, termCopy)
+	}
+}
+
+func (fb *NestedFacetBuilder) EndDoc() {
+	docBuckets := fb.parent.DocBuckets()
+	if fb.size > 0 {
+		if len(docBuckets) > 0 && fb.docID != nil {
+			doc := nestedDoc{
+				id:      append(index.IndexInternalID(nil), fb.docID...),
+				buckets: make([]int, len(docBuckets)),
+			}
+			for i, bucket := range docBuckets {
+				doc.buckets[i] = fb.bucketID(bucket)
+			}
+			fb.docs = append(fb.docs, doc)
+		}
+	} else {
+		for _, bucket := range docBuckets {
+			children := fb.childrenOf(bucket)
+			children.StartDoc()
+			for i, field := range fb.docFields {
+				children.UpdateVisitor(field, fb.docTerms[i])
+			}
+			children.EndDoc()
+		}
+	}
+	fb.parent.EndDoc()
+}
+
+func (fb *NestedFacetBuilder) bucketID(bucket string) int {
+	id, ok := fb.bucketIDs[bucket]
+	if !ok {
+		id = len(fb.buckets)
+		fb.bucketIDs[bucket] = id
+		fb.buckets = append(fb.buckets, bucket)
+	}
+	return id
+}
+
+func (fb *NestedFacetBuilder) childrenOf(bucket string) *search.FacetsBuilder {
+	children, ok := fb.children[bucket]
+	if !ok {
+		children = fb.newChildren()
+		fb.children[bucket] = children
+	}
+	return children
+}
+
+// countTopBuckets computes the sub-facets of the buckets returned,
+// visiting the terms of their documents again.
+func (fb *NestedFacetBuilder) countTopBuckets(buckets []string) error {
+	top := make(map[int]*search.FacetsBuilder, len(buckets))
+	for _, bucket := range buckets {
+		if id, ok := fb.bucketIDs[bucket]; ok {
+			top[id] = fb.childrenOf(bucket)
+		}
+	}
+	fields := fb.fields[1:]
+	var docChildren []*search.FacetsBuilder
+	for _, doc := range fb.docs {
+		docChildren = docChildren[:0]
+		for _, id := range doc.buckets {
+			if children, ok := top[id]; ok {
+				docChildren = append(docChildren, children)
+			}
+		}
+		if len(docChildren) == 0 {
+			continue
+		}
+		for _, children := range docChildren {
+			children.StartDoc()
+			children.SetDocID(doc.id)
+		}
+		err := fb.reader.DocumentVisitFieldTerms(doc.id, fields, func(field string, term []byte) {
+			for _, children := range docChildren {
+				children.UpdateVisitor(field, term)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		for _, children := range docChildren {
+			children.EndDoc()
+		}
+	}
+	return nil
+}
+
+func (fb *NestedFacetBuilder) Result() *search.FacetResult {
+	rv := fb.parent.Result()
+	if fb.size > 0 {
+		var buckets []string
+		for _, tf := range rv.Terms {
+			buckets = append(buckets, tf.Term)
+		}
+		for _, nrf := range rv.NumericRanges {
+			buckets = append(buckets, nrf.Name)
+		}
+		for _, drf := range rv.DateRanges {
+			buckets = append(buckets, drf.Name)
+		}
+		for _, hf := range rv.Histogram {
+			buckets = append(buckets, hf.Name)
+		}
+		err := fb.countTopBuckets(buckets)
+		fb.docs = nil
+		if err != nil {
+			// the sub-facets would be inexact, leave them out
+			return rv
+		}
+	}
+	for _, tf := range rv.Terms {
+		tf.Facets = fb.childResults(tf.Term)
+	}
+	for _, nrf := range rv.NumericRanges {
+		nrf.Facets = fb.childResults(nrf.Name)
+	}
+	for _, drf := range rv.DateRanges {
+		drf.Facets = fb.childResults(drf.Name)
+	}
+	for _, hf := range rv.Histogram {
+		hf.Facets = fb.childResults(hf.Name)
+	}
+	return rv
+}
+
+func (fb *NestedFacetBuilder) childResults(bucket string) search.FacetResults {
+	if children, ok := fb.children[bucket]; ok {
+		return children.Results()
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// testDocReader visits the terms of the documents visited by visitDoc.
+type testDocReader struct {
+	index.IndexReader
+	fields map[string][]string
+	terms  map[string][][]byte
+}
+
+func newTestDocReader() *testDocReader {
+	return &testDocReader{
+		fields: make(map[string][]string),
+		terms:  make(map[string][][]byte),
+	}
+}
+
+func (r *testDocReader) DocumentVisitFieldTerms(id index.IndexInternalID, fields []string, visitor index.DocumentFieldTermVisitor) error {
+	for i, field := range r.fields[string(id)] {
+		for _, f := range fields {
+			if f == field {
+				visitor(field, r.terms[string(id)][i])
+			}
+		}
+	}
+	return nil
+}
+
+// visitDoc counts a document, given as pairs of fields and terms.
+func (r *testDocReader) visitDoc(fb search.FacetBuilder, id string, fieldTerms ...string) {
+	fb.StartDoc()
+	fb.(*NestedFacetBuilder).SetDocID(index.IndexInternalID(id))
+	for i := 0; i < len(fieldTerms); i += 2 {
+		term := []byte(fieldTerms[i+1])
+		if fieldTerms[i] == "price" {
+			price, _ := strconv.ParseFloat(fieldTerms[i+1], 64)
+			term = numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(price), 0)
+		}
+		r.fields[id] = append(r.fields[id], fieldTerms[i])
+		r.terms[id] = append(r.terms[id], term)
+		fb.UpdateVisitor(fieldTerms[i], term)
+	}
+	fb.EndDoc()
+}
+
+func TestNestedFacetBuilder(t *testing.T) {
+	// all of the buckets returned, or only the top ones
+	for _, size := range []int{0, 10} {
+		reader := newTestDocReader()
+		nfb := NewNestedFacetBuilder(NewTermsFacetBuilder("category", 10), size, reader,
+			func() *search.FacetsBuilder {
+				fb := search.NewFacetsBuilder(nil)
+				fb.Add("brands", NewTermsFacetBuilder("brand", 1))
+				price := NewNumericFacetBuilder("price", 10)
+				cheap := 0.0
+				expensive := 100.0
+				price.AddRange("cheap", &cheap, &expensive)
+				fb.Add("price", price)
+				return fb
+			})
+
+		expectedFields := []string{"category", "brand", "price"}
+		if !reflect.DeepEqual(nfb.RequiredFields(), expectedFields) {
+			t.Errorf("expected required fields %v, got %v", expectedFields, nfb.RequiredFields())
+		}
+
+		reader.visitDoc(nfb, "1", "category", "phone", "brand", "acme", "price", "50")
+		reader.visitDoc(nfb, "2", "category", "phone", "brand", "acme", "price", "500")
+		reader.visitDoc(nfb, "3", "category", "phone", "brand", "globex", "price", "20")
+		reader.visitDoc(nfb, "4", "category", "laptop", "brand", "initech", "price", "900")
+
+		result := nfb.Result()
+		if len(result.Terms) != 2 {
+			t.Fatalf("expected 2 categories, got %d", len(result.Terms))
+		}
+		phone := result.Terms[0]
+		if phone.Term != "phone" || phone.Count != 3 {
+			t.Errorf("expected phone with 3 docs, got %s %d", phone.Term, phone.Count)
+		}
+		brands := phone.Facets["brands"]
+		if brands.Total != 3 || brands.Other != 1 || len(brands.Terms) != 1 ||
+			brands.Terms[0].Term != "acme" || brands.Terms[0].Count != 2 {
+			t.Errorf("unexpected phone brands %#v", brands)
+		}
+		price := phone.Facets["price"]
+		if len(price.NumericRanges) != 1 || price.NumericRanges[0].Count != 2 {
+			t.Errorf("expected 2 cheap phones, got %#v", price.NumericRanges)
+		}
+		laptop := result.Terms[1]
+		if laptop.Facets["brands"].Terms[0].Term != "initech" || laptop.Facets["price"].Total != 0 {
+			t.Errorf("unexpected laptop sub facets %#v", laptop.Facets)
+		}
+	}
+}
+
+func TestNestedFacetBuilderDocumentOrder(t *testing.T) {
+	for _, popularFirst := range []bool{true, false} {
+		reader := newTestDocReader()
+		nfb := NewNestedFacetBuilder(NewTermsFacetBuilder("category", 2), 2, reader,
+			func() *search.FacetsBuilder {
+				fb := search.NewFacetsBuilder(nil)
+				fb.Add("brands", NewTermsFacetBuilder("brand", 1))
+				return fb
+			})
+
+		// the popular categories come before or after many rare ones
+		popular := func() {
+			for i := 0; i < 5; i++ {
+				reader.visitDoc(nfb, fmt.Sprintf("phone%d", i), "category", "phone", "brand", "acme")
+				reader.visitDoc(nfb, fmt.Sprintf("laptop%d", i), "category", "laptop", "brand", "initech")
+			}
+		}
+		if popularFirst {
+			popular()
+		}
+		for i := 0; i < 1000; i++ {
+			reader.visitDoc(nfb, fmt.Sprintf("rare%d", i), "category", fmt.Sprintf("rare%d", i), "brand", "globex")
+		}
+		if !popularFirst {
+			popular()
+		}
+		if len(nfb.children) != 0 {
+			t.Errorf("expected no sub facets while counting, got %d", len(nfb.children))
+		}
+
+		result := nfb.Result()
+		if len(result.Terms) != 2 {
+			t.Fatalf("expected 2 categories, got %d", len(result.Terms))
+		}
+		for _, tf := range result.Terms {
+			brands := tf.Facets["brands"]
+			if brands == nil || brands.Total != 5 {
+				t.Errorf("expected 5 brands for %s, got %#v", tf.Term, brands)
+			}
+		}
+		if len(nfb.children) != 2 {
+			t.Errorf("expected sub facets for 2 buckets, got %d", len(nfb.children))
+		}
+	}
+}
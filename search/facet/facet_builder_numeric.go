@@ -34,6 +34,7 @@ type NumericFacetBuilder struct {
 	missing    int
 	ranges     map[string]*numericRange
 	sawValue   bool
+	docBuckets []string
 }
 
 func NewNumericFacetBuilder(field string, size int) *NumericFacetBuilder {
@@ -73,6 +74,7 @@ func (fb *NumericFacetBuilder) UpdateVisitor(field string, term []byte) {
 					if (r.min == nil || f64 >= *r.min) && (r.max == nil || f64 < *r.max) {
 						fb.termsCount[rangeName] = fb.termsCount[rangeName] + 1
 						fb.total++
+						fb.docBuckets = append(fb.docBuckets, rangeName)
 					}
 				}
 			}
@@ -82,6 +84,12 @@ func (fb *NumericFacetBuilder) UpdateVisitor(field string, term []byte) {
 
 func (fb *NumericFacetBuilder) StartDoc() {
 	fb.sawValue = false
+	fb.docBuckets = fb.docBuckets[:0]
+}
+
+// DocBuckets returns the buckets the current document was counted in.
+func (fb *NumericFacetBuilder) DocBuckets() []string {
+	return fb.docBuckets
 }
 
 func (fb *NumericFacetBuilder) EndDoc() {
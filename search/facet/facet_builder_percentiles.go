@@ -0,0 +1,73 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/tdigest"
+)
+
+// PercentilesFacetBuilder estimates percentiles of the
+// numeric values of a field using a t-digest.
+type PercentilesFacetBuilder struct {
+	field    string
+	percents []float64
+	digest   *tdigest.TDigest
+	missing  int
+	sawValue bool
+}
+
+func NewPercentilesFacetBuilder(field string, percents []float64, compression float64) *PercentilesFacetBuilder {
+	return &PercentilesFacetBuilder{
+		field:    field,
+		percents: percents,
+		digest:   tdigest.New(compression),
+	}
+}
+
+func (fb *PercentilesFacetBuilder) Field() string {
+	return fb.field
+}
+
+func (fb *PercentilesFacetBuilder) UpdateVisitor(field string, term []byte) {
+	if field == fb.field {
+		fb.sawValue = true
+		f64, ok := decodeNumericTerm(term)
+		if ok {
+			fb.digest.Add(f64)
+		}
+	}
+}
+
+func (fb *PercentilesFacetBuilder) StartDoc() {
+	fb.sawValue = false
+}
+
+func (fb *PercentilesFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+}
+
+func (fb *PercentilesFacetBuilder) Result() *search.FacetResult {
+	rv := &search.FacetResult{
+		Field:       fb.field,
+		Total:       int(fb.digest.Count()),
+		Missing:     fb.missing,
+		Percentiles: search.NewPercentileFacets(fb.digest, fb.percents),
+	}
+	rv.SetDigest(fb.digest, fb.percents)
+	return rv
+}
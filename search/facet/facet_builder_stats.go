@@ -0,0 +1,90 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// StatsFacetBuilder computes the count, min, max, sum
+// and average of the numeric values of a field.
+type StatsFacetBuilder struct {
+	field    string
+	stats    search.StatsFacet
+	missing  int
+	sawValue bool
+}
+
+func NewStatsFacetBuilder(field string) *StatsFacetBuilder {
+	return &StatsFacetBuilder{
+		field: field,
+	}
+}
+
+func (fb *StatsFacetBuilder) Field() string {
+	return fb.field
+}
+
+func (fb *StatsFacetBuilder) UpdateVisitor(field string, term []byte) {
+	if field == fb.field {
+		fb.sawValue = true
+		f64, ok := decodeNumericTerm(term)
+		if ok {
+			fb.stats.Merge(&search.StatsFacet{
+				Count: 1,
+				Min:   f64,
+				Max:   f64,
+				Sum:   f64,
+			})
+		}
+	}
+}
+
+func (fb *StatsFacetBuilder) StartDoc() {
+	fb.sawValue = false
+}
+
+func (fb *StatsFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+}
+
+func (fb *StatsFacetBuilder) Result() *search.FacetResult {
+	stats := fb.stats
+	return &search.FacetResult{
+		Field:   fb.field,
+		Total:   stats.Count,
+		Missing: fb.missing,
+		Stats:   &stats,
+	}
+}
+
+// decodeNumericTerm returns the value of a numeric term,
+// only the full precision terms, which are shifted 0, are
+// considered.
+func decodeNumericTerm(term []byte) (float64, bool) {
+	prefixCoded := numeric.PrefixCoded(term)
+	shift, err := prefixCoded.Shift()
+	if err != nil || shift != 0 {
+		return 0, false
+	}
+	i64, err := prefixCoded.Int64()
+	if err != nil {
+		return 0, false
+	}
+	return numeric.Int64ToFloat64(i64), true
+}
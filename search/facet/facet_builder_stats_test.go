@@ -0,0 +1,86 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"math"
+	"testing"
+
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+func visitNumericDocs(fb search.FacetBuilder, field string, docs [][]float64) {
+	for _, values := range docs {
+		fb.StartDoc()
+		for _, v := range values {
+			i64 := numeric.Float64ToInt64(v)
+			// lower precision terms must be ignored
+			fb.UpdateVisitor(field, numeric.MustNewPrefixCodedInt64(i64, 4))
+			fb.UpdateVisitor(field, numeric.MustNewPrefixCodedInt64(i64, 0))
+		}
+		fb.UpdateVisitor("other", numeric.MustNewPrefixCodedInt64(0, 0))
+		fb.EndDoc()
+	}
+}
+
+func TestStatsFacetBuilder(t *testing.T) {
+	fb := NewStatsFacetBuilder("price")
+	visitNumericDocs(fb, "price", [][]float64{
+		{3.5},
+		{-2, 10},
+		{},
+		{0.5},
+	})
+
+	res := fb.Result()
+	if res.Total != 4 {
+		t.Errorf("expected total 4, got %d", res.Total)
+	}
+	if res.Missing != 1 {
+		t.Errorf("expected missing 1, got %d", res.Missing)
+	}
+	expected := search.StatsFacet{Count: 4, Min: -2, Max: 10, Sum: 12, Avg: 3}
+	if *res.Stats != expected {
+		t.Errorf("expected %#v, got %#v", expected, *res.Stats)
+	}
+}
+
+func TestPercentilesFacetBuilder(t *testing.T) {
+	fb := NewPercentilesFacetBuilder("price", []float64{0, 50, 90, 100}, 0)
+	var docs [][]float64
+	for i := 1; i <= 1000; i++ {
+		docs = append(docs, []float64{float64(i)})
+	}
+	docs = append(docs, nil)
+	visitNumericDocs(fb, "price", docs)
+
+	res := fb.Result()
+	if res.Total != 1000 {
+		t.Errorf("expected total 1000, got %d", res.Total)
+	}
+	if res.Missing != 1 {
+		t.Errorf("expected missing 1, got %d", res.Missing)
+	}
+	expected := []float64{1, 500, 900, 1000}
+	if len(res.Percentiles) != len(expected) {
+		t.Fatalf("expected %d percentiles, got %d", len(expected), len(res.Percentiles))
+	}
+	for i, p := range res.Percentiles {
+		if math.Abs(p.Value-expected[i]) > 5 {
+			t.Errorf("expected percentile %f near %f, got %f", p.Percent, expected[i], p.Value)
+		}
+	}
+}
@@ -27,6 +27,7 @@ type TermsFacetBuilder struct {
 	total      int
 	missing    int
 	sawValue   bool
+	docBuckets []string
 }
 
 func NewTermsFacetBuilder(field string, size int) *TermsFacetBuilder {
@@ -46,11 +47,18 @@ func (fb *TermsFacetBuilder) UpdateVisitor(field string, term []byte) {
 		fb.sawValue = true
 		fb.termsCount[string(term)] = fb.termsCount[string(term)] + 1
 		fb.total++
+		fb.docBuckets = append(fb.docBuckets, string(term))
 	}
 }
 
 func (fb *TermsFacetBuilder) StartDoc() {
 	fb.sawValue = false
+	fb.docBuckets = fb.docBuckets[:0]
+}
+
+// DocBuckets returns the buckets the current document was counted in.
+func (fb *TermsFacetBuilder) DocBuckets() []string {
+	return fb.docBuckets
 }
 
 func (fb *TermsFacetBuilder) EndDoc() {
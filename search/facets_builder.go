@@ -18,6 +18,8 @@ import (
 	"sort"
 
 	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search/hyperloglog"
+	"github.com/edwindvinas/bleve/search/tdigest"
 )
 
 type FacetBuilder interface {
@@ -29,10 +31,31 @@ type FacetBuilder interface {
 	Field() string
 }
 
+// BucketFacetBuilder is implemented by facet builders which group the
+// documents into buckets. DocBuckets returns the buckets the current
+// document was counted in, so sub-facets can be computed for each bucket.
+type BucketFacetBuilder interface {
+	FacetBuilder
+	DocBuckets() []string
+}
+
+// docIDSetter is implemented by facet builders which need the internal
+// id of the documents they count.
+type docIDSetter interface {
+	SetDocID(id index.IndexInternalID)
+}
+
+// requiredFieldser is implemented by facet builders which need the terms
+// of more than one field.
+type requiredFieldser interface {
+	RequiredFields() []string
+}
+
 type FacetsBuilder struct {
-	indexReader index.IndexReader
-	facets      map[string]FacetBuilder
-	fields      []string
+	indexReader  index.IndexReader
+	facets       map[string]FacetBuilder
+	fields       []string
+	docIDSetters []docIDSetter
 }
 
 func NewFacetsBuilder(indexReader index.IndexReader) *FacetsBuilder {
@@ -44,7 +67,14 @@ func NewFacetsBuilder(indexReader index.IndexReader) *FacetsBuilder {
 
 func (fb *FacetsBuilder) Add(name string, facetBuilder FacetBuilder) {
 	fb.facets[name] = facetBuilder
-	fb.fields = append(fb.fields, facetBuilder.Field())
+	if rf, ok := facetBuilder.(requiredFieldser); ok {
+		fb.fields = append(fb.fields, rf.RequiredFields()...)
+	} else {
+		fb.fields = append(fb.fields, facetBuilder.Field())
+	}
+	if ds, ok := facetBuilder.(docIDSetter); ok {
+		fb.docIDSetters = append(fb.docIDSetters, ds)
+	}
 }
 
 func (fb *FacetsBuilder) RequiredFields() []string {
@@ -57,6 +87,14 @@ func (fb *FacetsBuilder) StartDoc() {
 	}
 }
 
+// SetDocID passes the internal id of the current document to the
+// facet builders which need it.
+func (fb *FacetsBuilder) SetDocID(id index.IndexInternalID) {
+	for _, ds := range fb.docIDSetters {
+		ds.SetDocID(id)
+	}
+}
+
 func (fb *FacetsBuilder) EndDoc() {
 	for _, facetBuilder := range fb.facets {
 		facetBuilder.EndDoc()
@@ -70,8 +108,9 @@ func (fb *FacetsBuilder) UpdateVisitor(field string, term []byte) {
 }
 
 type TermFacet struct {
-	Term  string `json:"term"`
-	Count int    `json:"count"`
+	Term   string       `json:"term"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
 }
 
 type TermFacets []*TermFacet
@@ -80,6 +119,7 @@ func (tf TermFacets) Add(termFacet *TermFacet) TermFacets {
 	for _, existingTerm := range tf {
 		if termFacet.Term == existingTerm.Term {
 			existingTerm.Count += termFacet.Count
+			existingTerm.Facets = mergeFacetResults(existingTerm.Facets, termFacet.Facets)
 			return tf
 		}
 	}
@@ -98,10 +138,11 @@ func (tf TermFacets) Less(i, j int) bool {
 }
 
 type NumericRangeFacet struct {
-	Name  string   `json:"name"`
-	Min   *float64 `json:"min,omitempty"`
-	Max   *float64 `json:"max,omitempty"`
-	Count int      `json:"count"`
+	Name   string       `json:"name"`
+	Min    *float64     `json:"min,omitempty"`
+	Max    *float64     `json:"max,omitempty"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
 }
 
 func (nrf *NumericRangeFacet) Same(other *NumericRangeFacet) bool {
@@ -133,6 +174,7 @@ func (nrf NumericRangeFacets) Add(numericRangeFacet *NumericRangeFacet) NumericR
 	for _, existingNr := range nrf {
 		if numericRangeFacet.Same(existingNr) {
 			existingNr.Count += numericRangeFacet.Count
+			existingNr.Facets = mergeFacetResults(existingNr.Facets, numericRangeFacet.Facets)
 			return nrf
 		}
 	}
@@ -151,10 +193,11 @@ func (nrf NumericRangeFacets) Less(i, j int) bool {
 }
 
 type DateRangeFacet struct {
-	Name  string  `json:"name"`
-	Start *string `json:"start,omitempty"`
-	End   *string `json:"end,omitempty"`
-	Count int     `json:"count"`
+	Name   string       `json:"name"`
+	Start  *string      `json:"start,omitempty"`
+	End    *string      `json:"end,omitempty"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
 }
 
 func (drf *DateRangeFacet) Same(other *DateRangeFacet) bool {
@@ -186,6 +229,7 @@ func (drf DateRangeFacets) Add(dateRangeFacet *DateRangeFacet) DateRangeFacets {
 	for _, existingDr := range drf {
 		if dateRangeFacet.Same(existingDr) {
 			existingDr.Count += dateRangeFacet.Count
+			existingDr.Facets = mergeFacetResults(existingDr.Facets, dateRangeFacet.Facets)
 			return drf
 		}
 	}
@@ -203,6 +247,89 @@ func (drf DateRangeFacets) Less(i, j int) bool {
 	return drf[i].Count > drf[j].Count
 }
 
+// HistogramFacet is a bucket of a histogram.  Key is the
+// start of the bucket, for dates it is in milliseconds
+// since the epoch, Name is the formatted Key.
+type HistogramFacet struct {
+	Key    float64      `json:"key"`
+	Name   string       `json:"name"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
+}
+
+type HistogramFacets []*HistogramFacet
+
+func (hf HistogramFacets) Add(histogramFacet *HistogramFacet) HistogramFacets {
+	for _, existingH := range hf {
+		if histogramFacet.Key == existingH.Key {
+			existingH.Count += histogramFacet.Count
+			existingH.Facets = mergeFacetResults(existingH.Facets, histogramFacet.Facets)
+			return hf
+		}
+	}
+	// if we got here it wasn't already in the existing buckets
+	hf = append(hf, histogramFacet)
+	return hf
+}
+
+// histogram buckets are ordered by their keys
+func (hf HistogramFacets) Len() int           { return len(hf) }
+func (hf HistogramFacets) Swap(i, j int)      { hf[i], hf[j] = hf[j], hf[i] }
+func (hf HistogramFacets) Less(i, j int) bool { return hf[i].Key < hf[j].Key }
+
+// StatsFacet summarizes the numeric values of a field.
+// Min, Max and Avg are only meaningful when Count is
+// greater than zero.
+type StatsFacet struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Sum   float64 `json:"sum"`
+	Avg   float64 `json:"avg"`
+}
+
+// Merge combines the statistics of other into these.
+func (sf *StatsFacet) Merge(other *StatsFacet) {
+	if other.Count == 0 {
+		return
+	}
+	if sf.Count == 0 || other.Min < sf.Min {
+		sf.Min = other.Min
+	}
+	if sf.Count == 0 || other.Max > sf.Max {
+		sf.Max = other.Max
+	}
+	sf.Count += other.Count
+	sf.Sum += other.Sum
+	sf.Avg = sf.Sum / float64(sf.Count)
+}
+
+// PercentileFacet is the approximate value below which
+// Percent percent of the values of a field fall.
+type PercentileFacet struct {
+	Percent float64 `json:"percent"`
+	Value   float64 `json:"value"`
+}
+
+type PercentileFacets []*PercentileFacet
+
+// NewPercentileFacets estimates each of the percents
+// from the digest.  Percentiles of an empty digest are
+// omitted.
+func NewPercentileFacets(digest *tdigest.TDigest, percents []float64) PercentileFacets {
+	rv := make(PercentileFacets, 0, len(percents))
+	if digest.Count() == 0 {
+		return rv
+	}
+	for _, percent := range percents {
+		rv = append(rv, &PercentileFacet{
+			Percent: percent,
+			Value:   digest.Quantile(percent / 100),
+		})
+	}
+	return rv
+}
+
 type FacetResult struct {
 	Field         string             `json:"field"`
 	Total         int                `json:"total"`
@@ -211,6 +338,26 @@ type FacetResult struct {
 	Terms         TermFacets         `json:"terms,omitempty"`
 	NumericRanges NumericRangeFacets `json:"numeric_ranges,omitempty"`
 	DateRanges    DateRangeFacets    `json:"date_ranges,omitempty"`
+	Histogram     HistogramFacets    `json:"histogram,omitempty"`
+	Stats         *StatsFacet        `json:"stats,omitempty"`
+	Percentiles   PercentileFacets   `json:"percentiles,omitempty"`
+	Cardinality   *int               `json:"cardinality,omitempty"`
+
+	// Sketch holds the distinct values the cardinality was
+	// estimated from, so it can be recomputed on merge
+	Sketch *hyperloglog.HyperLogLog `json:"sketch,omitempty"`
+
+	// digest holds the distribution the percentiles were
+	// estimated from, so they can be recomputed on merge
+	digest   *tdigest.TDigest
+	percents []float64
+}
+
+// SetDigest records the distribution the percentiles were
+// estimated from, along with the percents requested.
+func (fr *FacetResult) SetDigest(digest *tdigest.TDigest, percents []float64) {
+	fr.digest = digest
+	fr.percents = percents
 }
 
 func (fr *FacetResult) Merge(other *FacetResult) {
@@ -232,6 +379,39 @@ func (fr *FacetResult) Merge(other *FacetResult) {
 			fr.DateRanges = fr.DateRanges.Add(dr)
 		}
 	}
+	if fr.Histogram != nil && other.Histogram != nil {
+		for _, h := range other.Histogram {
+			fr.Histogram = fr.Histogram.Add(h)
+		}
+	}
+	if fr.Stats != nil && other.Stats != nil {
+		fr.Stats.Merge(other.Stats)
+	}
+	if fr.Percentiles != nil && other.Percentiles != nil {
+		if fr.digest != nil && other.digest != nil {
+			fr.digest.Merge(other.digest)
+			percents := fr.percents
+			if len(percents) == 0 {
+				percents = other.percents
+			}
+			fr.Percentiles = NewPercentileFacets(fr.digest, percents)
+		} else if len(fr.Percentiles) == 0 {
+			// without the distributions only an empty
+			// result can be replaced
+			fr.Percentiles = other.Percentiles
+		}
+	}
+	if fr.Cardinality != nil && other.Cardinality != nil {
+		if fr.Sketch != nil && other.Sketch != nil &&
+			fr.Sketch.Merge(other.Sketch) == nil {
+			cardinality := int(fr.Sketch.Count())
+			fr.Cardinality = &cardinality
+		} else if *other.Cardinality > *fr.Cardinality {
+			// without the sketches the larger count is
+			// the best estimate
+			fr.Cardinality = other.Cardinality
+		}
+	}
 }
 
 func (fr *FacetResult) Fixup(size int) {
@@ -262,6 +442,9 @@ func (fr *FacetResult) Fixup(size int) {
 			}
 			fr.DateRanges = fr.DateRanges[0:size]
 		}
+	} else if fr.Histogram != nil {
+		// every bucket of a histogram is kept
+		sort.Sort(fr.Histogram)
 	}
 }
 
@@ -278,6 +461,42 @@ func (fr FacetResults) Merge(other FacetResults) {
 	}
 }
 
+// mergeFacetResults merges the sub-facets of two buckets, either of
+// which may be nil.
+func mergeFacetResults(fr, other FacetResults) FacetResults {
+	if fr == nil {
+		return other
+	}
+	fr.Merge(other)
+	return fr
+}
+
+// BucketFacets returns the sub-facet results of each bucket.
+func (fr *FacetResult) BucketFacets() []FacetResults {
+	var rv []FacetResults
+	for _, tf := range fr.Terms {
+		if tf.Facets != nil {
+			rv = append(rv, tf.Facets)
+		}
+	}
+	for _, nrf := range fr.NumericRanges {
+		if nrf.Facets != nil {
+			rv = append(rv, nrf.Facets)
+		}
+	}
+	for _, drf := range fr.DateRanges {
+		if drf.Facets != nil {
+			rv = append(rv, drf.Facets)
+		}
+	}
+	for _, hf := range fr.Histogram {
+		if hf.Facets != nil {
+			rv = append(rv, hf.Facets)
+		}
+	}
+	return rv
+}
+
 func (fr FacetResults) Fixup(name string, size int) {
 	facetResult, ok := fr[name]
 	if ok {
@@ -17,6 +17,8 @@ package search
 import (
 	"reflect"
 	"testing"
+
+	"github.com/edwindvinas/bleve/search/tdigest"
 )
 
 func TestTermFacetResultsMerge(t *testing.T) {
@@ -324,3 +326,77 @@ func TestDateFacetResultsMerge(t *testing.T) {
 		t.Errorf("expected %#v, got %#v", expectedFrs, frs1)
 	}
 }
+
+func TestStatsFacetResultsMerge(t *testing.T) {
+	frs1 := FacetResults{
+		"prices": &FacetResult{
+			Field: "price",
+			Total: 2,
+			Stats: &StatsFacet{Count: 2, Min: 3, Max: 5, Sum: 8, Avg: 4},
+		},
+	}
+	frs2 := FacetResults{
+		"prices": &FacetResult{
+			Field:   "price",
+			Total:   3,
+			Missing: 1,
+			Stats:   &StatsFacet{Count: 3, Min: 1, Max: 4, Sum: 7, Avg: 7.0 / 3},
+		},
+	}
+	frs3 := FacetResults{
+		"prices": &FacetResult{
+			Field:   "price",
+			Missing: 4,
+			Stats:   &StatsFacet{},
+		},
+	}
+
+	expectedFrs := FacetResults{
+		"prices": &FacetResult{
+			Field:   "price",
+			Total:   5,
+			Missing: 5,
+			Stats:   &StatsFacet{Count: 5, Min: 1, Max: 5, Sum: 15, Avg: 3},
+		},
+	}
+
+	frs1.Merge(frs2)
+	frs1.Merge(frs3)
+	frs1.Fixup("prices", 10)
+	if !reflect.DeepEqual(frs1, expectedFrs) {
+		t.Errorf("expected %#v, got %#v", expectedFrs["prices"].Stats, frs1["prices"].Stats)
+	}
+}
+
+func TestPercentileFacetResultsMerge(t *testing.T) {
+	percents := []float64{0, 50, 100}
+	newResult := func(values ...float64) *FacetResult {
+		digest := tdigest.New(tdigest.DefaultCompression)
+		for _, v := range values {
+			digest.Add(v)
+		}
+		rv := &FacetResult{
+			Field:       "price",
+			Total:       len(values),
+			Percentiles: NewPercentileFacets(digest, percents),
+		}
+		rv.SetDigest(digest, percents)
+		return rv
+	}
+
+	fr := newResult(1, 2, 3)
+	fr.Merge(newResult(4, 5))
+	fr.Merge(newResult())
+
+	expected := PercentileFacets{
+		{Percent: 0, Value: 1},
+		{Percent: 50, Value: 3},
+		{Percent: 100, Value: 5},
+	}
+	if !reflect.DeepEqual(fr.Percentiles, expected) {
+		t.Errorf("expected %v, got %v", expected, fr.Percentiles)
+	}
+	if fr.Total != 5 {
+		t.Errorf("expected total 5, got %d", fr.Total)
+	}
+}
@@ -0,0 +1,165 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hyperloglog implements the HyperLogLog sketch, which
+// estimates the number of distinct values added to it using a fixed
+// amount of memory.  Sketches of the same precision can be merged,
+// the result estimates the distinct values added to either.
+package hyperloglog
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// The supported precisions, a sketch of precision p uses 2^p
+// bytes and has a standard error of about 1.04/sqrt(2^p).
+const (
+	MinPrecision     = 4
+	MaxPrecision     = 18
+	DefaultPrecision = 14
+)
+
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+func New(precision uint8) (*HyperLogLog, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, fmt.Errorf("precision %d must be between %d and %d",
+			precision, MinPrecision, MaxPrecision)
+	}
+	return &HyperLogLog{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+func (h *HyperLogLog) Precision() uint8 {
+	return h.precision
+}
+
+// Add adds a value to the sketch.
+func (h *HyperLogLog) Add(value []byte) {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(value)
+	x := mix(hasher.Sum64())
+
+	// the first bits select the register, it records the
+	// longest run of leading zeros seen in the rest
+	index := x >> (64 - h.precision)
+	rest := x<<h.precision | 1<<(h.precision-1)
+	rank := uint8(1)
+	for rest&(1<<63) == 0 {
+		rank++
+		rest <<= 1
+	}
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// mix spreads the bits of the fnv hash, whose high bits
+// depend poorly on the last bytes of short values.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// Merge adds all of the values of other to the sketch.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if other.precision != h.precision {
+		return fmt.Errorf("cannot merge sketches of precision %d and %d",
+			h.precision, other.precision)
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+type hyperLogLogJSON struct {
+	Precision uint8  `json:"precision"`
+	Registers []byte `json:"registers"`
+}
+
+// MarshalJSON encodes the precision and registers of the sketch,
+// so sketches of remote indexes can be merged.
+func (h *HyperLogLog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hyperLogLogJSON{
+		Precision: h.precision,
+		Registers: h.registers,
+	})
+}
+
+func (h *HyperLogLog) UnmarshalJSON(data []byte) error {
+	var tmp hyperLogLogJSON
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if tmp.Precision < MinPrecision || tmp.Precision > MaxPrecision {
+		return fmt.Errorf("precision %d must be between %d and %d",
+			tmp.Precision, MinPrecision, MaxPrecision)
+	}
+	if len(tmp.Registers) != 1<<tmp.Precision {
+		return fmt.Errorf("sketch of precision %d must have %d registers, not %d",
+			tmp.Precision, 1<<tmp.Precision, len(tmp.Registers))
+	}
+	h.precision = tmp.Precision
+	h.registers = tmp.Registers
+	return nil
+}
+
+// Count estimates the number of distinct values added.
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+	estimate := alpha * m * m / sum
+
+	// small cardinalities are better estimated by
+	// counting the registers still empty
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
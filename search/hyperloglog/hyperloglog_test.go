@@ -0,0 +1,93 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hyperloglog
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	tests := []int{0, 1, 10, 1000, 100000}
+	for _, n := range tests {
+		h, err := New(DefaultPrecision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < n; i++ {
+			// every value is added twice
+			h.Add([]byte("user" + strconv.Itoa(i)))
+			h.Add([]byte("user" + strconv.Itoa(i)))
+		}
+		got := float64(h.Count())
+		if math.Abs(got-float64(n)) > 0.02*float64(n) {
+			t.Errorf("expected about %d distinct values, got %f", n, got)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	h1, _ := New(12)
+	h2, _ := New(12)
+	for i := 0; i < 20000; i++ {
+		// the sketches share half of their values
+		h1.Add([]byte(strconv.Itoa(i)))
+		h2.Add([]byte(strconv.Itoa(i + 10000)))
+	}
+	err := h1.Merge(h2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := float64(h1.Count())
+	if math.Abs(got-30000) > 0.05*30000 {
+		t.Errorf("expected about 30000 distinct values, got %f", got)
+	}
+
+	h3, _ := New(10)
+	if h1.Merge(h3) == nil {
+		t.Errorf("expected error merging different precisions")
+	}
+	_, err = New(20)
+	if err == nil {
+		t.Errorf("expected error for precision 20")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	h, _ := New(10)
+	for i := 0; i < 500; i++ {
+		h.Add([]byte(strconv.Itoa(i)))
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded HyperLogLog
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Precision() != 10 || decoded.Count() != h.Count() {
+		t.Errorf("expected precision 10 and count %d, got %d and %d",
+			h.Count(), decoded.Precision(), decoded.Count())
+	}
+
+	err = json.Unmarshal([]byte(`{"precision":10,"registers":"AAAA"}`), &decoded)
+	if err == nil {
+		t.Errorf("expected error for missing registers")
+	}
+}
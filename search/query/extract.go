@@ -0,0 +1,182 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/mapping"
+)
+
+// FieldTerm is a term of a field, as found in the index.
+type FieldTerm struct {
+	Field string
+	Term  string
+}
+
+// ExtractTerms returns a set of terms such that every document matched
+// by the query contains at least one of them. It is used to quickly
+// discard queries which cannot match a document, without running them.
+// ok is false when no such set can be determined, for instance for
+// range, prefix or fuzzy queries, in which case the query must always be
+// run. An empty set with ok true means the query matches no document.
+func ExtractTerms(m mapping.IndexMapping, query Query) (terms []FieldTerm, ok bool, err error) {
+	query, err = expandQuery(m, query)
+	if err != nil {
+		return nil, false, err
+	}
+	return extractTerms(m, query)
+}
+
+func extractTerms(m mapping.IndexMapping, query Query) ([]FieldTerm, bool, error) {
+	switch q := query.(type) {
+	case *TermQuery:
+		return []FieldTerm{{Field: fieldOrDefault(m, q.FieldVal), Term: q.Term}}, true, nil
+	case *PhraseQuery:
+		if len(q.Terms) == 0 {
+			return nil, true, nil
+		}
+		// every term of the phrase is required, keep the one most
+		// likely to be selective
+		return []FieldTerm{{Field: fieldOrDefault(m, q.Field), Term: longestTerm(q.Terms)}}, true, nil
+	case *MultiPhraseQuery:
+		return extractPhraseTerms(fieldOrDefault(m, q.Field), q.Terms)
+	case *MatchQuery:
+		if q.Fuzziness != 0 {
+			return nil, false, nil
+		}
+		field := fieldOrDefault(m, q.FieldVal)
+		tokens, err := analyzeMatchText(m, field, q.Analyzer, q.Match)
+		if err != nil {
+			return nil, false, err
+		}
+		if q.Operator == MatchQueryOperatorAnd && len(tokens) > 0 {
+			tokens = []string{longestTerm(tokens)}
+		}
+		return fieldTerms(field, tokens), true, nil
+	case *MatchPhraseQuery:
+		field := fieldOrDefault(m, q.FieldVal)
+		analyzer, err := analyzerForField(m, field, q.Analyzer)
+		if err != nil {
+			return nil, false, err
+		}
+		return extractPhraseTerms(field, tokenStreamToPhrase(analyzer.Analyze([]byte(q.MatchPhrase))))
+	case *MatchNoneQuery:
+		return nil, true, nil
+	case *ConjunctionQuery:
+		// any required clause will do, keep the one with the fewest terms
+		var best []FieldTerm
+		found := false
+		for _, child := range q.Conjuncts {
+			terms, ok, err := extractTerms(m, child)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok && (!found || len(terms) < len(best)) {
+				best = terms
+				found = true
+			}
+		}
+		return best, found, nil
+	case *DisjunctionQuery:
+		var rv []FieldTerm
+		for _, child := range q.Disjuncts {
+			terms, ok, err := extractTerms(m, child)
+			if err != nil || !ok {
+				return nil, false, err
+			}
+			rv = append(rv, terms...)
+		}
+		return rv, true, nil
+	case *BooleanQuery:
+		// must clauses are required; should clauses are only required
+		// when there are no must clauses
+		if q.Must != nil {
+			return extractTerms(m, q.Must)
+		}
+		if q.Should != nil {
+			return extractTerms(m, q.Should)
+		}
+		return nil, false, nil
+	case *FunctionScoreQuery:
+		return extractTerms(m, q.Query)
+	case *NestedQuery:
+		return extractTerms(m, q.Query)
+	default:
+		return nil, false, nil
+	}
+}
+
+// extractPhraseTerms returns the alternatives of the phrase position
+// with the fewest of them, one of which must be present.
+func extractPhraseTerms(field string, phrase [][]string) ([]FieldTerm, bool, error) {
+	var best []string
+	for _, terms := range phrase {
+		if len(terms) > 0 && (best == nil || len(terms) < len(best)) {
+			best = terms
+		}
+	}
+	return fieldTerms(field, best), true, nil
+}
+
+func fieldOrDefault(m mapping.IndexMapping, field string) string {
+	if field == "" {
+		return m.DefaultSearchField()
+	}
+	return field
+}
+
+func analyzerForField(m mapping.IndexMapping, field, analyzerName string) (*analysis.Analyzer, error) {
+	if analyzerName == "" {
+		analyzerName = m.AnalyzerNameForPath(field)
+	}
+	analyzer := m.AnalyzerNamed(analyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+	}
+	return analyzer, nil
+}
+
+func analyzeMatchText(m mapping.IndexMapping, field, analyzerName, text string) ([]string, error) {
+	analyzer, err := analyzerForField(m, field, analyzerName)
+	if err != nil {
+		return nil, err
+	}
+	tokens := analyzer.Analyze([]byte(text))
+	rv := make([]string, len(tokens))
+	for i, token := range tokens {
+		rv[i] = string(token.Term)
+	}
+	return rv, nil
+}
+
+func fieldTerms(field string, terms []string) []FieldTerm {
+	rv := make([]FieldTerm, len(terms))
+	for i, term := range terms {
+		rv[i] = FieldTerm{Field: field, Term: term}
+	}
+	return rv
+}
+
+func longestTerm(terms []string) string {
+	rv := terms[0]
+	for _, term := range terms[1:] {
+		if len(term) > len(rv) {
+			rv = term
+		}
+	}
+	return rv
+}
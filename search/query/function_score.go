@@ -0,0 +1,429 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edwindvinas/bleve/geo"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+// DefaultDecay is the value of a decay function at offset+scale from
+// its origin when no decay is given.
+var DefaultDecay = 0.5
+
+type FunctionScoreQuery struct {
+	Query     Query            `json:"query"`
+	Functions []*ScoreFunction `json:"functions"`
+	ScoreMode string           `json:"score_mode,omitempty"`
+	BoostMode string           `json:"boost_mode,omitempty"`
+	BoostVal  *Boost           `json:"boost,omitempty"`
+}
+
+// ScoreFunction is one of the functions of a FunctionScoreQuery.  At
+// most one of FieldValueFactor, Gauss, Exp, Linear or RandomScore can
+// be set, when none is the function is only its weight.
+type ScoreFunction struct {
+	FieldValueFactor *FieldValueFactor `json:"field_value_factor,omitempty"`
+	Gauss            *DecayFunction    `json:"gauss,omitempty"`
+	Exp              *DecayFunction    `json:"exp,omitempty"`
+	Linear           *DecayFunction    `json:"linear,omitempty"`
+	RandomScore      *RandomScore      `json:"random_score,omitempty"`
+	Weight           *float64          `json:"weight,omitempty"`
+}
+
+// FieldValueFactor scores with the value of a numeric field multiplied
+// by Factor, then modified by one of log, log1p, log2p, ln, ln1p,
+// ln2p, square, sqrt or reciprocal.  Missing is used for documents
+// without a value.
+type FieldValueFactor struct {
+	Field    string   `json:"field"`
+	Factor   *float64 `json:"factor,omitempty"`
+	Modifier string   `json:"modifier,omitempty"`
+	Missing  *float64 `json:"missing,omitempty"`
+}
+
+// DecayFunction scores with the distance of the value of a field from
+// Origin.  The type of the origin selects the kind of field:
+// a number for numeric fields, with numeric Scale and Offset,
+// a date string, or "now", for date fields, with durations like
+// "12h" or "7d" as Scale and Offset,
+// a geo point for geo point fields, with distances like "10km" as
+// Scale and Offset.
+type DecayFunction struct {
+	Field  string      `json:"field"`
+	Origin interface{} `json:"origin"`
+	Scale  interface{} `json:"scale"`
+	Offset interface{} `json:"offset,omitempty"`
+	Decay  *float64    `json:"decay,omitempty"`
+}
+
+// RandomScore scores randomly between 0 and 1, the same way for
+// the same Seed.
+type RandomScore struct {
+	Seed int64 `json:"seed"`
+}
+
+// NewFunctionScoreQuery creates a new Query which modifies the score
+// of the documents matching the query with functions.
+func NewFunctionScoreQuery(query Query) *FunctionScoreQuery {
+	return &FunctionScoreQuery{
+		Query: query,
+	}
+}
+
+// NewFieldValueFactorFunction creates a function scoring with the
+// modified value of a numeric field multiplied by factor.
+func NewFieldValueFactorFunction(field string, factor float64, modifier string) *ScoreFunction {
+	return &ScoreFunction{
+		FieldValueFactor: &FieldValueFactor{
+			Field:    field,
+			Factor:   &factor,
+			Modifier: modifier,
+		},
+	}
+}
+
+// NewNumericDecayFunction creates a function of the given decay kind,
+// gauss, exp or linear, on the distance of a numeric field from origin.
+func NewNumericDecayFunction(kind, field string, origin, scale float64) *ScoreFunction {
+	return newDecayFunction(kind, field, origin, scale)
+}
+
+// NewDateDecayFunction creates a function of the given decay kind,
+// gauss, exp or linear, on the time between a date field and origin.
+func NewDateDecayFunction(kind, field string, origin time.Time, scale time.Duration) *ScoreFunction {
+	return newDecayFunction(kind, field, origin.Format(QueryDateTimeFormat), scale.String())
+}
+
+// NewGeoDecayFunction creates a function of the given decay kind,
+// gauss, exp or linear, on the distance of a geo point field from
+// the origin, scale is a distance like "10km".
+func NewGeoDecayFunction(kind, field string, lon, lat float64, scale string) *ScoreFunction {
+	return newDecayFunction(kind, field, []float64{lon, lat}, scale)
+}
+
+func newDecayFunction(kind, field string, origin, scale interface{}) *ScoreFunction {
+	decay := &DecayFunction{
+		Field:  field,
+		Origin: origin,
+		Scale:  scale,
+	}
+	rv := &ScoreFunction{}
+	switch kind {
+	case searcher.DecayExp:
+		rv.Exp = decay
+	case searcher.DecayLinear:
+		rv.Linear = decay
+	default:
+		rv.Gauss = decay
+	}
+	return rv
+}
+
+// NewRandomScoreFunction creates a function scoring randomly between
+// 0 and 1, the same way for the same seed.
+func NewRandomScoreFunction(seed int64) *ScoreFunction {
+	return &ScoreFunction{
+		RandomScore: &RandomScore{
+			Seed: seed,
+		},
+	}
+}
+
+// SetWeight sets the weight the value of the function is
+// multiplied by.
+func (f *ScoreFunction) SetWeight(weight float64) *ScoreFunction {
+	f.Weight = &weight
+	return f
+}
+
+func (q *FunctionScoreQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *FunctionScoreQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *FunctionScoreQuery) AddFunction(f ...*ScoreFunction) {
+	q.Functions = append(q.Functions, f...)
+}
+
+// SetScoreMode sets how the values of the functions are combined,
+// one of multiply, sum, avg, max, min or first.
+func (q *FunctionScoreQuery) SetScoreMode(mode string) {
+	q.ScoreMode = mode
+}
+
+// SetBoostMode sets how the combined value of the functions is
+// combined with the score of the query, one of multiply, sum
+// or replace.
+func (q *FunctionScoreQuery) SetBoostMode(mode string) {
+	q.BoostMode = mode
+}
+
+func (q *FunctionScoreQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	functions := make([]searcher.ScoreFunction, len(q.Functions))
+	weights := make([]float64, len(q.Functions))
+	for j, f := range q.Functions {
+		var err error
+		functions[j], err = f.scoreFunction(m)
+		if err != nil {
+			return nil, err
+		}
+		weights[j] = 1
+		if f.Weight != nil {
+			weights[j] = *f.Weight
+		}
+	}
+
+	child, err := q.Query.Searcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := searcher.NewFunctionScoreSearcher(i, child, functions, weights,
+		q.ScoreMode, q.BoostMode, q.BoostVal.Value(), options)
+	if err != nil {
+		_ = child.Close()
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (q *FunctionScoreQuery) Validate() error {
+	if q.Query == nil {
+		return fmt.Errorf("function score query must have a query")
+	}
+	if vq, ok := q.Query.(ValidatableQuery); ok {
+		err := vq.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	switch q.ScoreMode {
+	case "", searcher.FunctionScoreModeMultiply, searcher.FunctionScoreModeSum,
+		searcher.FunctionScoreModeAvg, searcher.FunctionScoreModeMax,
+		searcher.FunctionScoreModeMin, searcher.FunctionScoreModeFirst:
+	default:
+		return fmt.Errorf("unknown function score mode '%s'", q.ScoreMode)
+	}
+	switch q.BoostMode {
+	case "", searcher.FunctionBoostModeMultiply, searcher.FunctionBoostModeSum,
+		searcher.FunctionBoostModeReplace:
+	default:
+		return fmt.Errorf("unknown function boost mode '%s'", q.BoostMode)
+	}
+	for _, f := range q.Functions {
+		if f == nil {
+			return fmt.Errorf("function score query has an empty function")
+		}
+		// building the function validates its parameters, the field
+		// names are not resolved without a mapping
+		_, err := f.scoreFunction(nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *FunctionScoreQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Query     json.RawMessage  `json:"query"`
+		Functions []*ScoreFunction `json:"functions"`
+		ScoreMode string           `json:"score_mode,omitempty"`
+		BoostMode string           `json:"boost_mode,omitempty"`
+		Boost     *Boost           `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if tmp.Query != nil {
+		q.Query, err = ParseQuery(tmp.Query)
+		if err != nil {
+			return err
+		}
+	}
+	q.Functions = tmp.Functions
+	q.ScoreMode = tmp.ScoreMode
+	q.BoostMode = tmp.BoostMode
+	q.BoostVal = tmp.Boost
+	return nil
+}
+
+func (f *ScoreFunction) scoreFunction(m mapping.IndexMapping) (searcher.ScoreFunction, error) {
+	var rv searcher.ScoreFunction
+	set := func(sf searcher.ScoreFunction, err error) error {
+		if err != nil {
+			return err
+		}
+		if rv != nil {
+			return fmt.Errorf("score function must have only one of field_value_factor, gauss, exp, linear or random_score")
+		}
+		rv = sf
+		return nil
+	}
+
+	if f.FieldValueFactor != nil {
+		err := set(f.FieldValueFactor.scoreFunction(m))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, decay := range []struct {
+		kind     string
+		function *DecayFunction
+	}{
+		{searcher.DecayGauss, f.Gauss},
+		{searcher.DecayExp, f.Exp},
+		{searcher.DecayLinear, f.Linear},
+	} {
+		if decay.function != nil {
+			err := set(decay.function.scoreFunction(decay.kind, m))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if f.RandomScore != nil {
+		err := set(searcher.NewRandomScoreFunction(f.RandomScore.Seed), nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if rv == nil {
+		if f.Weight == nil {
+			return nil, fmt.Errorf("score function must have a function or a weight")
+		}
+		rv = &searcher.WeightFunction{}
+	}
+	return rv, nil
+}
+
+func scoreFunctionField(field string, m mapping.IndexMapping) string {
+	if field == "" && m != nil {
+		return m.DefaultSearchField()
+	}
+	return field
+}
+
+func (f *FieldValueFactor) scoreFunction(m mapping.IndexMapping) (searcher.ScoreFunction, error) {
+	factor := 1.0
+	if f.Factor != nil {
+		factor = *f.Factor
+	}
+	return searcher.NewFieldValueFactorFunction(scoreFunctionField(f.Field, m),
+		factor, f.Modifier, f.Missing)
+}
+
+func (f *DecayFunction) scoreFunction(kind string, m mapping.IndexMapping) (searcher.ScoreFunction, error) {
+	field := scoreFunctionField(f.Field, m)
+	decay := DefaultDecay
+	if f.Decay != nil {
+		decay = *f.Decay
+	}
+
+	switch origin := f.Origin.(type) {
+	case nil:
+		return nil, fmt.Errorf("decay function must have an origin")
+	case float64:
+		scale, ok := f.Scale.(float64)
+		if !ok {
+			return nil, fmt.Errorf("numeric decay scale must be a number")
+		}
+		var offset float64
+		if f.Offset != nil {
+			offset, ok = f.Offset.(float64)
+			if !ok {
+				return nil, fmt.Errorf("numeric decay offset must be a number")
+			}
+		}
+		return searcher.NewNumericDecayFunction(kind, field, origin, scale, offset, decay)
+	case string:
+		t := time.Now()
+		if origin != "now" {
+			var err error
+			t, err = queryTimeFromString(origin)
+			if err != nil {
+				return nil, err
+			}
+		}
+		scale, err := parseDecayDuration(f.Scale)
+		if err != nil {
+			return nil, err
+		}
+		var offset time.Duration
+		if f.Offset != nil {
+			offset, err = parseDecayDuration(f.Offset)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return searcher.NewDateDecayFunction(kind, field, t, scale, offset, decay)
+	}
+
+	lon, lat, found := geo.ExtractGeoPoint(f.Origin)
+	if !found {
+		return nil, fmt.Errorf("decay origin must be a number, a date or a geo point")
+	}
+	scale, err := parseDecayDistance(f.Scale)
+	if err != nil {
+		return nil, err
+	}
+	var offset float64
+	if f.Offset != nil {
+		offset, err = parseDecayDistance(f.Offset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return searcher.NewGeoDecayFunction(kind, field, lon, lat, scale, offset, decay)
+}
+
+// parseDecayDuration parses a duration like "1h30m", or a number of
+// days like "7d".
+func parseDecayDuration(v interface{}) (time.Duration, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("date decay scale and offset must be durations")
+	}
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", s)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+func parseDecayDistance(v interface{}) (float64, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("geo decay scale and offset must be distances")
+	}
+	return geo.ParseDistance(s)
+}
@@ -0,0 +1,125 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edwindvinas/bleve/geo"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type GeoPolygonQuery struct {
+	Points   [][]float64   `json:"polygon_points"`
+	Holes    [][][]float64 `json:"holes,omitempty"`
+	FieldVal string        `json:"field,omitempty"`
+	BoostVal *Boost        `json:"boost,omitempty"`
+}
+
+func NewGeoPolygonQuery(points [][]float64) *GeoPolygonQuery {
+	return &GeoPolygonQuery{
+		Points: points,
+	}
+}
+
+// AddHole excludes the area described by the points from the polygon.
+func (q *GeoPolygonQuery) AddHole(points [][]float64) {
+	q.Holes = append(q.Holes, points)
+}
+
+func (q *GeoPolygonQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *GeoPolygonQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *GeoPolygonQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *GeoPolygonQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *GeoPolygonQuery) Searcher(i index.IndexReader, m mapping.IndexMapping,
+	options search.SearcherOptions) (search.Searcher, error) {
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+
+	return searcher.NewGeoPolygonSearcher(i, q.Points, q.Holes, field,
+		q.BoostVal.Value(), options)
+}
+
+func (q *GeoPolygonQuery) Validate() error {
+	if len(q.Points) < 3 {
+		return fmt.Errorf("geo polygon must have at least 3 points")
+	}
+	for _, hole := range q.Holes {
+		if len(hole) < 3 {
+			return fmt.Errorf("geo polygon hole must have at least 3 points")
+		}
+	}
+	return nil
+}
+
+func (q *GeoPolygonQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Points   []interface{}   `json:"polygon_points"`
+		Holes    [][]interface{} `json:"holes,omitempty"`
+		FieldVal string          `json:"field,omitempty"`
+		BoostVal *Boost          `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	// now use our generic point parsing code from the geo package
+	q.Points, err = extractGeoPoints(tmp.Points)
+	if err != nil {
+		return err
+	}
+	q.Holes = nil
+	for _, hole := range tmp.Holes {
+		points, err := extractGeoPoints(hole)
+		if err != nil {
+			return err
+		}
+		q.Holes = append(q.Holes, points)
+	}
+	q.FieldVal = tmp.FieldVal
+	q.BoostVal = tmp.BoostVal
+	return nil
+}
+
+func extractGeoPoints(things []interface{}) ([][]float64, error) {
+	rv := make([][]float64, 0, len(things))
+	for _, thing := range things {
+		lon, lat, found := geo.ExtractGeoPoint(thing)
+		if !found {
+			return nil, fmt.Errorf("geo polygon point not in a valid format")
+		}
+		rv = append(rv, []float64{lon, lat})
+	}
+	return rv, nil
+}
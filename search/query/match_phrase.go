@@ -27,6 +27,7 @@ type MatchPhraseQuery struct {
 	MatchPhrase string `json:"match_phrase"`
 	FieldVal    string `json:"field,omitempty"`
 	Analyzer    string `json:"analyzer,omitempty"`
+	Slop        int    `json:"slop,omitempty"`
 	BoostVal    *Boost `json:"boost,omitempty"`
 }
 
@@ -61,6 +62,13 @@ func (q *MatchPhraseQuery) Field() string {
 	return q.FieldVal
 }
 
+// SetSlop allows the terms of the phrase to be up to slop
+// position moves away from the exact phrase, closer matches
+// scoring higher.
+func (q *MatchPhraseQuery) SetSlop(slop int) {
+	q.Slop = slop
+}
+
 func (q *MatchPhraseQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
 	field := q.FieldVal
 	if q.FieldVal == "" {
@@ -82,6 +90,7 @@ func (q *MatchPhraseQuery) Searcher(i index.IndexReader, m mapping.IndexMapping,
 	if len(tokens) > 0 {
 		phrase := tokenStreamToPhrase(tokens)
 		phraseQuery := NewMultiPhraseQuery(phrase, field)
+		phraseQuery.SetSlop(q.Slop)
 		phraseQuery.SetBoost(q.BoostVal.Value())
 		return phraseQuery.Searcher(i, m, options)
 	}
@@ -89,6 +98,13 @@ func (q *MatchPhraseQuery) Searcher(i index.IndexReader, m mapping.IndexMapping,
 	return noneQuery.Searcher(i, m, options)
 }
 
+func (q *MatchPhraseQuery) Validate() error {
+	if q.Slop < 0 {
+		return fmt.Errorf("phrase query slop must not be negative")
+	}
+	return nil
+}
+
 func tokenStreamToPhrase(tokens analysis.TokenStream) [][]string {
 	firstPosition := int(^uint(0) >> 1)
 	lastPosition := 0
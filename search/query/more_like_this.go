@@ -0,0 +1,377 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/edwindvinas/bleve/analysis"
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+// The defaults used by a MoreLikeThisQuery when its controls are 0.
+var (
+	DefaultMoreLikeThisMinTermFreq   = 2
+	DefaultMoreLikeThisMaxQueryTerms = 25
+	DefaultMoreLikeThisMinDocFreq    = 5
+)
+
+type MoreLikeThisQuery struct {
+	Like          string              `json:"like,omitempty"`
+	LikeDoc       string              `json:"like_doc,omitempty"`
+	LikeFields    map[string][]string `json:"like_fields,omitempty"`
+	Fields        []string            `json:"fields,omitempty"`
+	Analyzer      string              `json:"analyzer,omitempty"`
+	MinTermFreq   int                 `json:"min_term_freq,omitempty"`
+	MaxQueryTerms int                 `json:"max_query_terms,omitempty"`
+	MinDocFreq    int                 `json:"min_doc_freq,omitempty"`
+	MaxDocFreq    int                 `json:"max_doc_freq,omitempty"`
+	Include       bool                `json:"include,omitempty"`
+	BoostVal      *Boost              `json:"boost,omitempty"`
+}
+
+// NewMoreLikeThisQuery creates a new Query finding documents
+// similar to the text, in the given fields or the default
+// field if there are none.
+// The text is analyzed with the analyzer of each field, and
+// the most significant terms, those frequent in the text but
+// rare in the index, are searched for.
+func NewMoreLikeThisQuery(like string, fields []string) *MoreLikeThisQuery {
+	return &MoreLikeThisQuery{
+		Like:   like,
+		Fields: fields,
+	}
+}
+
+// NewMoreLikeThisDocumentQuery creates a new Query finding
+// documents similar to the document with the given id, like
+// NewMoreLikeThisQuery does for the text of its fields.  The
+// document is not a result unless SetInclude is used.
+// Searched through an IndexAlias, the document is looked up
+// once in the indexes of the alias and only the text of its
+// stored fields is searched for in all of them.
+func NewMoreLikeThisDocumentQuery(id string, fields []string) *MoreLikeThisQuery {
+	return &MoreLikeThisQuery{
+		LikeDoc: id,
+		Fields:  fields,
+	}
+}
+
+func (q *MoreLikeThisQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *MoreLikeThisQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+// SetMinTermFreq sets how many times a term must occur in the
+// text or document to be searched for.
+func (q *MoreLikeThisQuery) SetMinTermFreq(n int) {
+	q.MinTermFreq = n
+}
+
+// SetMaxQueryTerms sets the maximum number of terms searched for.
+func (q *MoreLikeThisQuery) SetMaxQueryTerms(n int) {
+	q.MaxQueryTerms = n
+}
+
+// SetMinDocFreq sets in how many documents of the index a term
+// must occur to be searched for.
+func (q *MoreLikeThisQuery) SetMinDocFreq(n int) {
+	q.MinDocFreq = n
+}
+
+// SetMaxDocFreq sets in how many documents of the index a term
+// may occur at most to be searched for, 0 means no limit.
+func (q *MoreLikeThisQuery) SetMaxDocFreq(n int) {
+	q.MaxDocFreq = n
+}
+
+// SetInclude sets whether the document the query is built from
+// can be a result.
+func (q *MoreLikeThisQuery) SetInclude(include bool) {
+	q.Include = include
+}
+
+func (q *MoreLikeThisQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	fields := q.Fields
+	if len(fields) == 0 {
+		fields = []string{m.DefaultSearchField()}
+	}
+
+	freqs := make(moreLikeThisFreqs)
+	for _, field := range fields {
+		if q.Like != "" {
+			analyzer, err := q.analyzer(m, field)
+			if err != nil {
+				return nil, err
+			}
+			freqs.addTokens(field, analyzer.Analyze([]byte(q.Like)))
+		}
+		if len(q.LikeFields[field]) > 0 {
+			analyzer, err := q.analyzer(m, field)
+			if err != nil {
+				return nil, err
+			}
+			for _, text := range q.LikeFields[field] {
+				freqs.addTokens(field, analyzer.Analyze([]byte(text)))
+			}
+		}
+	}
+	var likeID index.IndexInternalID
+	if q.LikeDoc != "" {
+		var err error
+		likeID, err = q.addDocumentFreqs(i, m, fields, freqs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	terms, err := q.significantTerms(i, freqs)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return searcher.NewMatchNoneSearcher(i)
+	}
+
+	searchers := make([]search.Searcher, 0, len(terms))
+	for _, term := range terms {
+		ts, err := searcher.NewTermSearcher(i, term.term, term.field, q.BoostVal.Value(), options)
+		if err != nil {
+			for _, s := range searchers {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		searchers = append(searchers, ts)
+	}
+	rv, err := searcher.NewDisjunctionSearcher(i, searchers, 1, options)
+	if err != nil {
+		for _, s := range searchers {
+			_ = s.Close()
+		}
+		return nil, err
+	}
+	if likeID != nil && !q.Include {
+		return searcher.NewFilteringSearcher(rv, func(d *search.DocumentMatch) bool {
+			return !d.IndexInternalID.Equals(likeID)
+		}), nil
+	}
+	return rv, nil
+}
+
+// ForDocument returns a Query finding the documents similar to doc
+// without looking it up in the index searched, searching for the text
+// of its stored fields, and excluding it unless Include is set.  The
+// query is returned unchanged if doc has no stored text in the fields.
+func (q *MoreLikeThisQuery) ForDocument(doc *document.Document, m mapping.IndexMapping) Query {
+	fields := q.Fields
+	if len(fields) == 0 {
+		fields = []string{m.DefaultSearchField()}
+	}
+
+	likeFields := make(map[string][]string, len(q.LikeFields)+len(fields))
+	for field, texts := range q.LikeFields {
+		likeFields[field] = append([]string(nil), texts...)
+	}
+	found := false
+	for _, field := range fields {
+		for _, f := range doc.Fields {
+			if _, ok := f.(*document.TextField); ok && f.Name() == field {
+				likeFields[field] = append(likeFields[field], string(f.Value()))
+				found = true
+			}
+		}
+	}
+	if !found {
+		return q
+	}
+
+	rv := *q
+	rv.LikeDoc = ""
+	rv.LikeFields = likeFields
+	if q.Include {
+		return &rv
+	}
+	return NewBooleanQuery([]Query{&rv}, nil, []Query{NewDocIDQuery([]string{doc.ID})})
+}
+
+func (q *MoreLikeThisQuery) analyzer(m mapping.IndexMapping, field string) (*analysis.Analyzer, error) {
+	analyzerName := q.Analyzer
+	if analyzerName == "" {
+		analyzerName = m.AnalyzerNameForPath(field)
+	}
+	analyzer := m.AnalyzerNamed(analyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+	}
+	return analyzer, nil
+}
+
+// addDocumentFreqs adds the terms of the fields of the document, and
+// returns its internal id, or nil if it is not in the index.  Stored
+// fields are analyzed again, the terms of the fields which are not
+// stored are counted once.
+func (q *MoreLikeThisQuery) addDocumentFreqs(i index.IndexReader, m mapping.IndexMapping, fields []string, freqs moreLikeThisFreqs) (index.IndexInternalID, error) {
+	doc, err := i.Document(q.LikeDoc)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	id, err := i.InternalID(q.LikeDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]bool)
+	for _, field := range fields {
+		for _, f := range doc.Fields {
+			if _, ok := f.(*document.TextField); !ok || f.Name() != field {
+				continue
+			}
+			analyzer, err := q.analyzer(m, field)
+			if err != nil {
+				return nil, err
+			}
+			freqs.addTokens(field, analyzer.Analyze(f.Value()))
+			stored[field] = true
+		}
+	}
+	var notStored []string
+	for _, field := range fields {
+		if !stored[field] {
+			notStored = append(notStored, field)
+		}
+	}
+	if len(notStored) > 0 {
+		err = i.DocumentVisitFieldTerms(id, notStored, func(field string, term []byte) {
+			freqs.add(field, string(term), 1)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return id, nil
+}
+
+// significantTerms selects the terms to search for, those scoring the
+// most by term frequency times inverse document frequency
+func (q *MoreLikeThisQuery) significantTerms(i index.IndexReader, freqs moreLikeThisFreqs) ([]*moreLikeThisTerm, error) {
+	minTermFreq := q.MinTermFreq
+	if minTermFreq <= 0 {
+		minTermFreq = DefaultMoreLikeThisMinTermFreq
+	}
+	maxQueryTerms := q.MaxQueryTerms
+	if maxQueryTerms <= 0 {
+		maxQueryTerms = DefaultMoreLikeThisMaxQueryTerms
+	}
+	minDocFreq := q.MinDocFreq
+	if minDocFreq <= 0 {
+		minDocFreq = DefaultMoreLikeThisMinDocFreq
+	}
+
+	docCount, err := i.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	var rv moreLikeThisTerms
+	for field, termFreqs := range freqs {
+		for term, freq := range termFreqs {
+			if freq < minTermFreq {
+				continue
+			}
+			reader, err := i.TermFieldReader([]byte(term), field, false, false, false)
+			if err != nil {
+				return nil, err
+			}
+			docFreq := reader.Count()
+			err = reader.Close()
+			if err != nil {
+				return nil, err
+			}
+			if docFreq == 0 || docFreq < uint64(minDocFreq) ||
+				(q.MaxDocFreq > 0 && docFreq > uint64(q.MaxDocFreq)) {
+				continue
+			}
+			idf := 1.0 + math.Log(float64(docCount)/float64(docFreq+1))
+			rv = append(rv, &moreLikeThisTerm{
+				field: field,
+				term:  term,
+				score: float64(freq) * idf,
+			})
+		}
+	}
+	sort.Sort(rv)
+	if len(rv) > maxQueryTerms {
+		rv = rv[:maxQueryTerms]
+	}
+	return rv, nil
+}
+
+func (q *MoreLikeThisQuery) Validate() error {
+	if q.Like == "" && q.LikeDoc == "" && len(q.LikeFields) == 0 {
+		return fmt.Errorf("more like this query must have a like text or document")
+	}
+	if q.MinTermFreq < 0 || q.MaxQueryTerms < 0 || q.MinDocFreq < 0 || q.MaxDocFreq < 0 {
+		return fmt.Errorf("more like this query controls must not be negative")
+	}
+	return nil
+}
+
+// moreLikeThisFreqs counts the terms of each field
+type moreLikeThisFreqs map[string]map[string]int
+
+func (f moreLikeThisFreqs) add(field, term string, freq int) {
+	termFreqs := f[field]
+	if termFreqs == nil {
+		termFreqs = make(map[string]int)
+		f[field] = termFreqs
+	}
+	termFreqs[term] += freq
+}
+
+func (f moreLikeThisFreqs) addTokens(field string, tokens analysis.TokenStream) {
+	for _, token := range tokens {
+		f.add(field, string(token.Term), 1)
+	}
+}
+
+type moreLikeThisTerm struct {
+	field string
+	term  string
+	score float64
+}
+
+type moreLikeThisTerms []*moreLikeThisTerm
+
+func (t moreLikeThisTerms) Len() int      { return len(t) }
+func (t moreLikeThisTerms) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+func (t moreLikeThisTerms) Less(i, j int) bool {
+	if t[i].score != t[j].score {
+		return t[i].score > t[j].score
+	}
+	if t[i].field != t[j].field {
+		return t[i].field < t[j].field
+	}
+	return t[i].term < t[j].term
+}
@@ -0,0 +1,118 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type NestedQuery struct {
+	Path      string `json:"nested"`
+	Query     Query  `json:"query"`
+	ScoreMode string `json:"score_mode,omitempty"`
+	BoostVal  *Boost `json:"boost,omitempty"`
+}
+
+// NewNestedQuery creates a new Query matching the documents with a
+// nested sub-document at path, see mapping.DocumentMapping, which is
+// matched by the query. The query is evaluated against each element of
+// the sub-document on its own, its fields being named after the full
+// path.
+func NewNestedQuery(path string, query Query) *NestedQuery {
+	return &NestedQuery{
+		Path:  path,
+		Query: query,
+	}
+}
+
+func (q *NestedQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *NestedQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+// SetScoreMode sets how the scores of the matching elements are combined,
+// one of searcher.NestedScoreModeAvg (the default),
+// searcher.NestedScoreModeMax, searcher.NestedScoreModeMin or
+// searcher.NestedScoreModeSum.
+func (q *NestedQuery) SetScoreMode(mode string) {
+	q.ScoreMode = mode
+}
+
+func (q *NestedQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	pathQuery := NewTermQuery(q.Path)
+	pathQuery.SetField(document.NestedPathField)
+	pathQuery.SetBoost(0)
+	child, err := NewConjunctionQuery([]Query{q.Query, pathQuery}).Searcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	return searcher.NewNestedSearcher(i, child, q.ScoreMode, q.BoostVal.Value(), options)
+}
+
+func (q *NestedQuery) Validate() error {
+	if q.Path == "" {
+		return fmt.Errorf("nested query must have a path")
+	}
+	if q.Query == nil {
+		return fmt.Errorf("nested query must have a query")
+	}
+	if vq, ok := q.Query.(ValidatableQuery); ok {
+		err := vq.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	switch q.ScoreMode {
+	case "", searcher.NestedScoreModeAvg, searcher.NestedScoreModeMax,
+		searcher.NestedScoreModeMin, searcher.NestedScoreModeSum:
+	default:
+		return fmt.Errorf("unknown nested score mode '%s'", q.ScoreMode)
+	}
+	return nil
+}
+
+func (q *NestedQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Path      string          `json:"nested"`
+		Query     json.RawMessage `json:"query"`
+		ScoreMode string          `json:"score_mode,omitempty"`
+		Boost     *Boost          `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if tmp.Query != nil {
+		q.Query, err = ParseQuery(tmp.Query)
+		if err != nil {
+			return err
+		}
+	}
+	q.Path = tmp.Path
+	q.ScoreMode = tmp.ScoreMode
+	q.BoostVal = tmp.Boost
+	return nil
+}
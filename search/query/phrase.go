@@ -27,6 +27,7 @@ import (
 type PhraseQuery struct {
 	Terms    []string `json:"terms"`
 	Field    string   `json:"field,omitempty"`
+	Slop     int      `json:"slop,omitempty"`
 	BoostVal *Boost   `json:"boost,omitempty"`
 }
 
@@ -52,14 +53,23 @@ func (q *PhraseQuery) Boost() float64 {
 	return q.BoostVal.Value()
 }
 
+// SetSlop allows the terms to be up to slop position moves
+// away from the exact phrase, closer matches scoring higher.
+func (q *PhraseQuery) SetSlop(slop int) {
+	q.Slop = slop
+}
+
 func (q *PhraseQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
-	return searcher.NewPhraseSearcher(i, q.Terms, q.Field, options)
+	return searcher.NewSloppyPhraseSearcher(i, q.Terms, q.Field, q.Slop, options)
 }
 
 func (q *PhraseQuery) Validate() error {
 	if len(q.Terms) < 1 {
 		return fmt.Errorf("phrase query must contain at least one term")
 	}
+	if q.Slop < 0 {
+		return fmt.Errorf("phrase query slop must not be negative")
+	}
 	return nil
 }
 
@@ -72,6 +82,7 @@ func (q *PhraseQuery) UnmarshalJSON(data []byte) error {
 	}
 	q.Terms = tmp.Terms
 	q.Field = tmp.Field
+	q.Slop = tmp.Slop
 	q.BoostVal = tmp.BoostVal
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/edwindvinas/bleve/index"
 	"github.com/edwindvinas/bleve/mapping"
 	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
 )
 
 var logger = log.New(ioutil.Discard, "bleve mapping ", log.LstdFlags)
@@ -63,6 +64,14 @@ type ValidatableQuery interface {
 	Validate() error
 }
 
+// A SpanQuery represents a Query matching spans of positions,
+// which can be combined by other span queries.
+type SpanQuery interface {
+	Query
+	SpanSearcher(i index.IndexReader, m mapping.IndexMapping,
+		options search.SearcherOptions) (searcher.SpanSearcher, error)
+}
+
 // ParseQuery deserializes a JSON representation of
 // a Query object.
 func ParseQuery(input []byte) (Query, error) {
@@ -152,6 +161,81 @@ func ParseQuery(input []byte) (Query, error) {
 		return &rv, nil
 	}
 
+	_, hasSpanTerm := tmp["span_term"]
+	if hasSpanTerm {
+		var rv SpanTermQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasSpanNear := tmp["span_near"]
+	if hasSpanNear {
+		var rv SpanNearQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasSpanOr := tmp["span_or"]
+	if hasSpanOr {
+		var rv SpanOrQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasSpanNot := tmp["span_not"]
+	if hasSpanNot {
+		var rv SpanNotQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasSpanFirst := tmp["span_first"]
+	if hasSpanFirst {
+		var rv SpanFirstQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasLike := tmp["like"]
+	_, hasLikeDoc := tmp["like_doc"]
+	_, hasLikeFields := tmp["like_fields"]
+	if hasLike || hasLikeDoc || hasLikeFields {
+		var rv MoreLikeThisQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasNested := tmp["nested"]
+	if hasNested {
+		var rv NestedQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, hasFunctions := tmp["functions"]
+	if hasFunctions {
+		var rv FunctionScoreQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+
 	_, hasSyntaxQuery := tmp["query"]
 	if hasSyntaxQuery {
 		var rv QueryStringQuery
@@ -264,6 +348,15 @@ func ParseQuery(input []byte) (Query, error) {
 		}
 		return &rv, nil
 	}
+	_, hasPolygonPoints := tmp["polygon_points"]
+	if hasPolygonPoints {
+		var rv GeoPolygonQuery
+		err := json.Unmarshal(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
 	_, hasDistance := tmp["distance"]
 	if hasDistance {
 		var rv GeoDistanceQuery
@@ -276,6 +369,20 @@ func ParseQuery(input []byte) (Query, error) {
 	return nil, fmt.Errorf("unknown query type")
 }
 
+// parseSpanQuery deserializes a JSON representation of a Query
+// which must be a SpanQuery.
+func parseSpanQuery(input []byte) (SpanQuery, error) {
+	q, err := ParseQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	sq, ok := q.(SpanQuery)
+	if !ok {
+		return nil, fmt.Errorf("span query clauses must be span queries")
+	}
+	return sq, nil
+}
+
 // expandQuery traverses the input query tree and returns a new tree where
 // query string queries have been expanded into base queries. Returned tree may
 // reference queries from the input tree or new queries.
@@ -336,6 +443,22 @@ func expandQuery(m mapping.IndexMapping, query Query) (Query, error) {
 				return nil, err
 			}
 			return &q, nil
+		case *FunctionScoreQuery:
+			q := *query.(*FunctionScoreQuery)
+			var err error
+			q.Query, err = expand(q.Query)
+			if err != nil {
+				return nil, err
+			}
+			return &q, nil
+		case *NestedQuery:
+			q := *query.(*NestedQuery)
+			var err error
+			q.Query, err = expand(q.Query)
+			if err != nil {
+				return nil, err
+			}
+			return &q, nil
 		default:
 			return query, nil
 		}
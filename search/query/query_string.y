@@ -144,6 +144,7 @@ tPHRASE {
 	phrase := $1
 	logDebugGrammar("PHRASE - %s", phrase)
 	q := NewMatchPhraseQuery(phrase)
+	q.SetSlop($<n>1)
 	$$ = q
 }
 |
@@ -186,6 +187,7 @@ tSTRING tCOLON tPHRASE {
 	phrase := $3
 	logDebugGrammar("FIELD - %s PHRASE - %s", field, phrase)
 	q := NewMatchPhraseQuery(phrase)
+	q.SetSlop($<n>3)
 	q.SetField(field)
 	$$ = q
 }
@@ -600,11 +600,12 @@ yydefault:
 			phrase := yyDollar[1].s
 			logDebugGrammar("PHRASE - %s", phrase)
 			q := NewMatchPhraseQuery(phrase)
+			q.SetSlop(yyDollar[1].n)
 			yyVAL.q = q
 		}
 	case 13:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line query_string.y:150
+		//line query_string.y:151
 		{
 			field := yyDollar[1].s
 			str := yyDollar[3].s
@@ -622,7 +623,7 @@ yydefault:
 		}
 	case 14:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line query_string.y:166
+		//line query_string.y:167
 		{
 			field := yyDollar[1].s
 			str := yyDollar[3].s
@@ -642,18 +643,19 @@ yydefault:
 		}
 	case 15:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line query_string.y:184
+		//line query_string.y:185
 		{
 			field := yyDollar[1].s
 			phrase := yyDollar[3].s
 			logDebugGrammar("FIELD - %s PHRASE - %s", field, phrase)
 			q := NewMatchPhraseQuery(phrase)
+			q.SetSlop(yyDollar[3].n)
 			q.SetField(field)
 			yyVAL.q = q
 		}
 	case 16:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line query_string.y:193
+		//line query_string.y:195
 		{
 			field := yyDollar[1].s
 			min, err := strconv.ParseFloat(yyDollar[4].s, 64)
@@ -668,7 +670,7 @@ yydefault:
 		}
 	case 17:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line query_string.y:206
+		//line query_string.y:208
 		{
 			field := yyDollar[1].s
 			min, err := strconv.ParseFloat(yyDollar[5].s, 64)
@@ -683,7 +685,7 @@ yydefault:
 		}
 	case 18:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line query_string.y:219
+		//line query_string.y:221
 		{
 			field := yyDollar[1].s
 			max, err := strconv.ParseFloat(yyDollar[4].s, 64)
@@ -698,7 +700,7 @@ yydefault:
 		}
 	case 19:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line query_string.y:232
+		//line query_string.y:234
 		{
 			field := yyDollar[1].s
 			max, err := strconv.ParseFloat(yyDollar[5].s, 64)
@@ -713,7 +715,7 @@ yydefault:
 		}
 	case 20:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line query_string.y:245
+		//line query_string.y:247
 		{
 			field := yyDollar[1].s
 			minInclusive := false
@@ -730,7 +732,7 @@ yydefault:
 		}
 	case 21:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line query_string.y:260
+		//line query_string.y:262
 		{
 			field := yyDollar[1].s
 			minInclusive := true
@@ -747,7 +749,7 @@ yydefault:
 		}
 	case 22:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line query_string.y:275
+		//line query_string.y:277
 		{
 			field := yyDollar[1].s
 			maxInclusive := false
@@ -764,7 +766,7 @@ yydefault:
 		}
 	case 23:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line query_string.y:290
+		//line query_string.y:292
 		{
 			field := yyDollar[1].s
 			maxInclusive := true
@@ -781,13 +783,13 @@ yydefault:
 		}
 	case 24:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line query_string.y:306
+		//line query_string.y:308
 		{
 			yyVAL.pf = nil
 		}
 	case 25:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line query_string.y:310
+		//line query_string.y:312
 		{
 			yyVAL.pf = nil
 			boost, err := strconv.ParseFloat(yyDollar[1].s, 64)
@@ -800,13 +802,13 @@ yydefault:
 		}
 	case 26:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line query_string.y:322
+		//line query_string.y:324
 		{
 			yyVAL.s = yyDollar[1].s
 		}
 	case 27:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line query_string.y:326
+		//line query_string.y:328
 		{
 			yyVAL.s = "-" + yyDollar[2].s
 		}
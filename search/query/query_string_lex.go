@@ -17,6 +17,7 @@ package query
 import (
 	"bufio"
 	"io"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -41,6 +42,7 @@ type queryStringLex struct {
 	nextToken     *yySymType
 	nextTokenType int
 	seenDot       bool
+	slop          string
 	nextRune      rune
 	nextRuneSize  int
 	atEOF         bool
@@ -50,6 +52,7 @@ func (l *queryStringLex) reset() {
 	l.buf = ""
 	l.inEscape = false
 	l.seenDot = false
+	l.slop = ""
 }
 
 func (l *queryStringLex) Error(msg string) {
@@ -142,14 +145,8 @@ func inPhraseState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 
 	// only a non-escaped " ends the phrase
 	if !l.inEscape && next == '"' {
-		// end phrase
-		l.nextTokenType = tPHRASE
-		l.nextToken = &yySymType{
-			s: l.buf,
-		}
-		logDebugTokens("PHRASE - '%s'", l.nextToken.s)
-		l.reset()
-		return startState, true
+		// end phrase, a slop may follow
+		return afterPhraseState, true
 	} else if !l.inEscape && next == '\\' {
 		l.inEscape = true
 	} else if l.inEscape {
@@ -163,6 +160,43 @@ func inPhraseState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 	return inPhraseState, true
 }
 
+func afterPhraseState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
+	if !eof && next == '~' {
+		return inPhraseSlopState, true
+	}
+	l.endPhrase(0)
+	return startState, false
+}
+
+func inPhraseSlopState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
+	// only a non-escaped space or boost ends the slop (or eof)
+	if eof || next == ' ' || next == '^' {
+		if l.slop == "" {
+			l.slop = "1"
+		}
+		slop, err := strconv.Atoi(l.slop)
+		if err != nil {
+			l.Error("invalid phrase slop: " + l.slop)
+			return nil, false
+		}
+		l.endPhrase(slop)
+		return startState, next != '^'
+	}
+	l.slop += string(next)
+	return inPhraseSlopState, true
+}
+
+// endPhrase emits the phrase in the buffer
+func (l *queryStringLex) endPhrase(slop int) {
+	l.nextTokenType = tPHRASE
+	l.nextToken = &yySymType{
+		s: l.buf,
+		n: slop,
+	}
+	logDebugTokens("PHRASE - '%s' SLOP - %d", l.nextToken.s, slop)
+	l.reset()
+}
+
 func singleCharOpState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 	l.nextToken = &yySymType{}
 
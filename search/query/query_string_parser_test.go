@@ -218,6 +218,43 @@ func TestQuerySyntaxParserValid(t *testing.T) {
 					}(),
 				}),
 		},
+		{
+			input:   `"quick fox"~3`,
+			mapping: mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						q := NewMatchPhraseQuery("quick fox")
+						q.SetSlop(3)
+						return q
+					}(),
+				},
+				nil),
+		},
+		{
+			input:   `+field3:"quick fox"~2^5 "lazy dog"~ cat`,
+			mapping: mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				[]Query{
+					func() Query {
+						q := NewMatchPhraseQuery("quick fox")
+						q.SetSlop(2)
+						q.SetField("field3")
+						q.SetBoost(5)
+						return q
+					}(),
+				},
+				[]Query{
+					func() Query {
+						q := NewMatchPhraseQuery("lazy dog")
+						q.SetSlop(1)
+						return q
+					}(),
+					NewMatchQuery("cat"),
+				},
+				nil),
+		},
 		{
 			input:   `+field6:test3 -field7:test4 field8:test5`,
 			mapping: mapping.NewIndexMapping(),
@@ -833,6 +870,7 @@ func TestQuerySyntaxParserInvalid(t *testing.T) {
 		{"field:<text"},
 		{"field:<=text"},
 		{"field:~text"},
+		{`"quick fox"~x`},
 		{"field:^text"},
 		{"field::text"},
 		{`"this is the time`},
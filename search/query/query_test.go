@@ -172,6 +172,15 @@ func TestParseQuery(t *testing.T) {
 			input:  []byte(`{"match_all":{}}`),
 			output: NewMatchAllQuery(),
 		},
+		{
+			input: []byte(`{"polygon_points":[[-122.5,37.7],{"lon":-122.3,"lat":37.7},{"lon":-122.4,"lat":37.8}],"holes":[[[-122.45,37.72],[-122.35,37.72],[-122.4,37.75]]],"field":"loc"}`),
+			output: func() Query {
+				q := NewGeoPolygonQuery([][]float64{{-122.5, 37.7}, {-122.3, 37.7}, {-122.4, 37.8}})
+				q.AddHole([][]float64{{-122.45, 37.72}, {-122.35, 37.72}, {-122.4, 37.75}})
+				q.SetField("loc")
+				return q
+			}(),
+		},
 		{
 			input:  []byte(`{"match_none":{}}`),
 			output: NewMatchNoneQuery(),
@@ -184,6 +193,70 @@ func TestParseQuery(t *testing.T) {
 			input:  []byte(`{"bool": true}`),
 			output: NewBoolFieldQuery(true),
 		},
+		{
+			input: []byte(`{"query":{"match":"beer","field":"desc"},"functions":[{"field_value_factor":{"field":"rating","factor":1.2,"modifier":"log1p"},"weight":2},{"gauss":{"field":"abv","origin":5,"scale":2}},{"random_score":{"seed":42}}],"score_mode":"sum","boost_mode":"replace"}`),
+			output: func() Query {
+				mq := NewMatchQuery("beer")
+				mq.SetField("desc")
+				q := NewFunctionScoreQuery(mq)
+				q.AddFunction(
+					NewFieldValueFactorFunction("rating", 1.2, "log1p").SetWeight(2),
+					NewNumericDecayFunction("gauss", "abv", 5, 2),
+					NewRandomScoreFunction(42))
+				q.SetScoreMode("sum")
+				q.SetBoostMode("replace")
+				return q
+			}(),
+		},
+		{
+			input: []byte(`{"span_near":[{"span_term":"contract","field":"body"},{"span_or":[{"span_term":"termination","field":"body"},{"span_term":"renewal","field":"body"}]}],"slop":5,"in_order":true}`),
+			output: func() Query {
+				contract := NewSpanTermQuery("contract")
+				contract.SetField("body")
+				termination := NewSpanTermQuery("termination")
+				termination.SetField("body")
+				renewal := NewSpanTermQuery("renewal")
+				renewal.SetField("body")
+				or := NewSpanOrQuery([]SpanQuery{termination, renewal})
+				return NewSpanNearQuery([]SpanQuery{contract, or}, 5, true)
+			}(),
+		},
+		{
+			input: []byte(`{"span_first":{"span_not":{"span_term":"contract"},"exclude":{"span_term":"void"},"pre":1},"end":10}`),
+			output: func() Query {
+				q := NewSpanNotQuery(NewSpanTermQuery("contract"), NewSpanTermQuery("void"))
+				q.SetDistance(1, 0)
+				return NewSpanFirstQuery(q, 10)
+			}(),
+		},
+		{
+			input: []byte(`{"span_near":[{"term":"contract"}],"slop":5}`),
+			err:   true,
+		},
+		{
+			input: []byte(`{"like_doc":"a","fields":["title","body"],"min_term_freq":1,"max_query_terms":10,"min_doc_freq":2}`),
+			output: func() Query {
+				q := NewMoreLikeThisDocumentQuery("a", []string{"title", "body"})
+				q.SetMinTermFreq(1)
+				q.SetMaxQueryTerms(10)
+				q.SetMinDocFreq(2)
+				return q
+			}(),
+		},
+		{
+			input:  []byte(`{"like":"some text"}`),
+			output: NewMoreLikeThisQuery("some text", nil),
+		},
+		{
+			input: []byte(`{"nested":"reviews","query":{"term":"bob","field":"reviews.author"},"score_mode":"max"}`),
+			output: func() Query {
+				tq := NewTermQuery("bob")
+				tq.SetField("reviews.author")
+				q := NewNestedQuery("reviews", tq)
+				q.SetScoreMode("max")
+				return q
+			}(),
+		},
 		{
 			input:  []byte(`{"madeitup":"queryhere"}`),
 			output: nil,
@@ -215,6 +288,10 @@ func TestQueryValidate(t *testing.T) {
 				return q
 			}(),
 		},
+		{
+			query: NewGeoPolygonQuery([][]float64{{0, 0}, {1, 0}}),
+			err:   true,
+		},
 		{
 			query: func() Query {
 				q := NewMatchQuery("beer")
@@ -364,6 +441,73 @@ func TestQueryValidate(t *testing.T) {
 				return q
 			}(),
 		},
+		{
+			query: func() Query {
+				q := NewFunctionScoreQuery(NewMatchAllQuery())
+				q.AddFunction(NewGeoDecayFunction("exp", "loc", 1, 2, "10km"),
+					NewDateDecayFunction("linear", "updated", time.Now(), 24*time.Hour))
+				return q
+			}(),
+		},
+		{
+			query: func() Query {
+				q := NewFunctionScoreQuery(NewMatchAllQuery())
+				q.AddFunction(NewNumericDecayFunction("gauss", "abv", 5, -1))
+				return q
+			}(),
+			err: true,
+		},
+		{
+			query: func() Query {
+				q := NewFunctionScoreQuery(NewMatchAllQuery())
+				q.AddFunction(NewFieldValueFactorFunction("rating", 1, "cube"))
+				return q
+			}(),
+			err: true,
+		},
+		{
+			query: func() Query {
+				q := NewFunctionScoreQuery(NewMatchAllQuery())
+				q.AddFunction(&ScoreFunction{})
+				return q
+			}(),
+			err: true,
+		},
+		{
+			query: NewSpanNearQuery([]SpanQuery{NewSpanTermQuery("contract")}, -1, false),
+			err:   true,
+		},
+		{
+			query: NewSpanNearQuery(nil, 1, false),
+			err:   true,
+		},
+		{
+			query: NewSpanFirstQuery(NewSpanOrQuery([]SpanQuery{NewSpanNearQuery(nil, 1, false)}), 3),
+			err:   true,
+		},
+		{
+			query: NewSpanNotQuery(NewSpanTermQuery("contract"), NewSpanTermQuery("void")),
+		},
+		{
+			query: NewMoreLikeThisQuery("", nil),
+			err:   true,
+		},
+		{
+			query: func() Query {
+				q := NewMoreLikeThisQuery("some text", nil)
+				q.SetMaxQueryTerms(-1)
+				return q
+			}(),
+			err: true,
+		},
+		{
+			query: func() Query {
+				q := NewFunctionScoreQuery(NewMatchAllQuery())
+				q.SetBoostMode("max")
+				return q
+			}(),
+			err: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -421,3 +565,84 @@ func TestDumpQuery(t *testing.T) {
 		t.Fatalf("query:\n%s\ndiffers from expected:\n%s", s, wanted)
 	}
 }
+
+func TestExtractTerms(t *testing.T) {
+	mapping := mapping.NewIndexMapping()
+	tests := []struct {
+		query  Query
+		ok     bool
+		expect []FieldTerm
+	}{
+		{
+			query:  NewTermQuery("beer"),
+			ok:     true,
+			expect: []FieldTerm{{Field: "_all", Term: "beer"}},
+		},
+		{
+			query: NewMatchQuery("cold beer"),
+			ok:    true,
+			expect: []FieldTerm{
+				{Field: "_all", Term: "cold"},
+				{Field: "_all", Term: "beer"},
+			},
+		},
+		{
+			query: func() Query {
+				q := NewMatchQuery("cold beer")
+				q.SetOperator(MatchQueryOperatorAnd)
+				q.SetField("desc")
+				return q
+			}(),
+			ok:     true,
+			expect: []FieldTerm{{Field: "desc", Term: "cold"}},
+		},
+		{
+			query:  NewMatchPhraseQuery("light beer"),
+			ok:     true,
+			expect: []FieldTerm{{Field: "_all", Term: "light"}},
+		},
+		{
+			query:  NewQueryStringQuery("+name:ipa description:hoppy"),
+			ok:     true,
+			expect: []FieldTerm{{Field: "name", Term: "ipa"}},
+		},
+		{
+			query: NewDisjunctionQuery([]Query{
+				NewTermQuery("a"),
+				NewPrefixQuery("b"),
+			}),
+			ok: false,
+		},
+		{
+			query: NewConjunctionQuery([]Query{
+				NewPrefixQuery("b"),
+				NewTermQuery("a"),
+			}),
+			ok:     true,
+			expect: []FieldTerm{{Field: "_all", Term: "a"}},
+		},
+		{
+			query: NewMatchAllQuery(),
+			ok:    false,
+		},
+		{
+			query: NewMatchNoneQuery(),
+			ok:    true,
+		},
+	}
+
+	for i, test := range tests {
+		terms, ok, err := ExtractTerms(mapping, test.query)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if ok != test.ok {
+			t.Errorf("test %d: expected ok %t, got %t", i, test.ok, ok)
+		}
+		if len(terms) != 0 || len(test.expect) != 0 {
+			if !reflect.DeepEqual(terms, test.expect) {
+				t.Errorf("test %d: expected %v, got %v", i, test.expect, terms)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type SpanFirstQuery struct {
+	Match    SpanQuery `json:"span_first"`
+	End      int       `json:"end"`
+	BoostVal *Boost    `json:"boost,omitempty"`
+}
+
+// NewSpanFirstQuery creates a new SpanQuery matching the spans
+// of match which end within the first end positions of the
+// field.
+func NewSpanFirstQuery(match SpanQuery, end int) *SpanFirstQuery {
+	return &SpanFirstQuery{
+		Match: match,
+		End:   end,
+	}
+}
+
+func (q *SpanFirstQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanFirstQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanFirstQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return q.SpanSearcher(i, m, options)
+}
+
+func (q *SpanFirstQuery) SpanSearcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (searcher.SpanSearcher, error) {
+	s, err := q.Match.SpanSearcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	return searcher.NewSpanFirstSearcher(i, s, q.End, q.BoostVal.Value(), options)
+}
+
+func (q *SpanFirstQuery) Validate() error {
+	if q.Match == nil {
+		return fmt.Errorf("span first query must have a span_first query")
+	}
+	if q.End < 0 {
+		return fmt.Errorf("span first query end must not be negative")
+	}
+	return validateSpanQueries(q.Match)
+}
+
+func (q *SpanFirstQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Match json.RawMessage `json:"span_first"`
+		End   int             `json:"end"`
+		Boost *Boost          `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Match, err = parseSpanQuery(tmp.Match)
+	if err != nil {
+		return err
+	}
+	q.End = tmp.End
+	q.BoostVal = tmp.Boost
+	return nil
+}
@@ -0,0 +1,143 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type SpanNearQuery struct {
+	Clauses  []SpanQuery `json:"span_near"`
+	Slop     int         `json:"slop"`
+	InOrder  bool        `json:"in_order,omitempty"`
+	BoostVal *Boost      `json:"boost,omitempty"`
+}
+
+// NewSpanNearQuery creates a new SpanQuery matching a span of
+// each of the clauses, with at most slop positions between
+// them.  If inOrder is true the spans must also appear in the
+// order of the clauses.
+func NewSpanNearQuery(clauses []SpanQuery, slop int, inOrder bool) *SpanNearQuery {
+	return &SpanNearQuery{
+		Clauses: clauses,
+		Slop:    slop,
+		InOrder: inOrder,
+	}
+}
+
+func (q *SpanNearQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanNearQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanNearQuery) AddClause(clauses ...SpanQuery) {
+	q.Clauses = append(q.Clauses, clauses...)
+}
+
+func (q *SpanNearQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return q.SpanSearcher(i, m, options)
+}
+
+func (q *SpanNearQuery) SpanSearcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (searcher.SpanSearcher, error) {
+	ss, err := spanSearchers(q.Clauses, i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := searcher.NewSpanNearSearcher(i, ss, q.Slop, q.InOrder, q.BoostVal.Value(), options)
+	if err != nil {
+		for _, s := range ss {
+			_ = s.Close()
+		}
+		return nil, err
+	}
+	return rv, nil
+}
+
+// spanSearchers builds the searchers of span query clauses, closing
+// those already built if one fails
+func spanSearchers(clauses []SpanQuery, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) ([]searcher.SpanSearcher, error) {
+	rv := make([]searcher.SpanSearcher, 0, len(clauses))
+	for _, clause := range clauses {
+		s, err := clause.SpanSearcher(i, m, options)
+		if err != nil {
+			for _, s := range rv {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		rv = append(rv, s)
+	}
+	return rv, nil
+}
+
+// validateSpanQueries validates the span query clauses
+func validateSpanQueries(clauses ...SpanQuery) error {
+	for _, clause := range clauses {
+		if clause == nil {
+			return fmt.Errorf("span query clauses must not be empty")
+		}
+		if vq, ok := clause.(ValidatableQuery); ok {
+			err := vq.Validate()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (q *SpanNearQuery) Validate() error {
+	if len(q.Clauses) < 1 {
+		return fmt.Errorf("span near query must have at least one clause")
+	}
+	if q.Slop < 0 {
+		return fmt.Errorf("span near query slop must not be negative")
+	}
+	return validateSpanQueries(q.Clauses...)
+}
+
+func (q *SpanNearQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Clauses []json.RawMessage `json:"span_near"`
+		Slop    int               `json:"slop"`
+		InOrder bool              `json:"in_order,omitempty"`
+		Boost   *Boost            `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Clauses = make([]SpanQuery, len(tmp.Clauses))
+	for i, clause := range tmp.Clauses {
+		q.Clauses[i], err = parseSpanQuery(clause)
+		if err != nil {
+			return err
+		}
+	}
+	q.Slop = tmp.Slop
+	q.InOrder = tmp.InOrder
+	q.BoostVal = tmp.Boost
+	return nil
+}
@@ -0,0 +1,110 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type SpanNotQuery struct {
+	Include  SpanQuery `json:"span_not"`
+	Exclude  SpanQuery `json:"exclude"`
+	Pre      int       `json:"pre,omitempty"`
+	Post     int       `json:"post,omitempty"`
+	BoostVal *Boost    `json:"boost,omitempty"`
+}
+
+// NewSpanNotQuery creates a new SpanQuery matching the spans
+// of include which do not overlap any span of exclude.
+func NewSpanNotQuery(include, exclude SpanQuery) *SpanNotQuery {
+	return &SpanNotQuery{
+		Include: include,
+		Exclude: exclude,
+	}
+}
+
+func (q *SpanNotQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanNotQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+// SetDistance also excludes the spans of include which end
+// within pre positions before, or start within post
+// positions after, a span of exclude.
+func (q *SpanNotQuery) SetDistance(pre, post int) {
+	q.Pre = pre
+	q.Post = post
+}
+
+func (q *SpanNotQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return q.SpanSearcher(i, m, options)
+}
+
+func (q *SpanNotQuery) SpanSearcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (searcher.SpanSearcher, error) {
+	ss, err := spanSearchers([]SpanQuery{q.Include, q.Exclude}, i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	return searcher.NewSpanNotSearcher(i, ss[0], ss[1], q.Pre, q.Post, q.BoostVal.Value(), options)
+}
+
+func (q *SpanNotQuery) Validate() error {
+	if q.Include == nil || q.Exclude == nil {
+		return fmt.Errorf("span not query must have a span_not and an exclude query")
+	}
+	if q.Pre < 0 || q.Post < 0 {
+		return fmt.Errorf("span not query pre and post must not be negative")
+	}
+	return validateSpanQueries(q.Include, q.Exclude)
+}
+
+func (q *SpanNotQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Include json.RawMessage `json:"span_not"`
+		Exclude json.RawMessage `json:"exclude"`
+		Pre     int             `json:"pre,omitempty"`
+		Post    int             `json:"post,omitempty"`
+		Boost   *Boost          `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Include, err = parseSpanQuery(tmp.Include)
+	if err != nil {
+		return err
+	}
+	if tmp.Exclude == nil {
+		return fmt.Errorf("span not query must have an exclude query")
+	}
+	q.Exclude, err = parseSpanQuery(tmp.Exclude)
+	if err != nil {
+		return err
+	}
+	q.Pre = tmp.Pre
+	q.Post = tmp.Post
+	q.BoostVal = tmp.Boost
+	return nil
+}
@@ -0,0 +1,86 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type SpanOrQuery struct {
+	Clauses  []SpanQuery `json:"span_or"`
+	BoostVal *Boost      `json:"boost,omitempty"`
+}
+
+// NewSpanOrQuery creates a new SpanQuery matching the spans
+// of any of the clauses.
+func NewSpanOrQuery(clauses []SpanQuery) *SpanOrQuery {
+	return &SpanOrQuery{
+		Clauses: clauses,
+	}
+}
+
+func (q *SpanOrQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanOrQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanOrQuery) AddClause(clauses ...SpanQuery) {
+	q.Clauses = append(q.Clauses, clauses...)
+}
+
+func (q *SpanOrQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return q.SpanSearcher(i, m, options)
+}
+
+func (q *SpanOrQuery) SpanSearcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (searcher.SpanSearcher, error) {
+	ss, err := spanSearchers(q.Clauses, i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	return searcher.NewSpanOrSearcher(i, ss, q.BoostVal.Value(), options)
+}
+
+func (q *SpanOrQuery) Validate() error {
+	return validateSpanQueries(q.Clauses...)
+}
+
+func (q *SpanOrQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Clauses []json.RawMessage `json:"span_or"`
+		Boost   *Boost            `json:"boost,omitempty"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Clauses = make([]SpanQuery, len(tmp.Clauses))
+	for i, clause := range tmp.Clauses {
+		q.Clauses[i], err = parseSpanQuery(clause)
+		if err != nil {
+			return err
+		}
+	}
+	q.BoostVal = tmp.Boost
+	return nil
+}
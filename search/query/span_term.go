@@ -0,0 +1,66 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/mapping"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/searcher"
+)
+
+type SpanTermQuery struct {
+	Term     string `json:"span_term"`
+	FieldVal string `json:"field,omitempty"`
+	BoostVal *Boost `json:"boost,omitempty"`
+}
+
+// NewSpanTermQuery creates a new SpanQuery matching a span
+// for each occurrence of a term.  The field must be indexed
+// with term vectors.
+func NewSpanTermQuery(term string) *SpanTermQuery {
+	return &SpanTermQuery{
+		Term: term,
+	}
+}
+
+func (q *SpanTermQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanTermQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanTermQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *SpanTermQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *SpanTermQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return q.SpanSearcher(i, m, options)
+}
+
+func (q *SpanTermQuery) SpanSearcher(i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (searcher.SpanSearcher, error) {
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	return searcher.NewSpanTermSearcher(i, q.Term, field, q.BoostVal.Value(), options)
+}
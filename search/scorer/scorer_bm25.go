@@ -0,0 +1,151 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// BM25TermQueryScorer scores term matches using the Okapi BM25 formula.
+// The length of the field in each document is recovered from the field
+// norm, and compared against the average field length in the index.
+type BM25TermQueryScorer struct {
+	queryTerm      []byte
+	queryField     string
+	queryBoost     float64
+	docTerm        uint64
+	docTotal       uint64
+	avgFieldLength float64
+	k1             float64
+	b              float64
+	idf            float64
+	options        search.SearcherOptions
+	idfExplanation *search.Explanation
+	queryNorm      float64
+}
+
+func NewBM25TermQueryScorer(queryTerm []byte, queryField string, queryBoost float64, docTotal, docTerm uint64, avgFieldLength, k1, b float64, options search.SearcherOptions) *BM25TermQueryScorer {
+	// guard against term counts exceeding the doc count
+	numerator := 0.0
+	if docTotal > docTerm {
+		numerator = float64(docTotal - docTerm)
+	}
+	rv := BM25TermQueryScorer{
+		queryTerm:      queryTerm,
+		queryField:     queryField,
+		queryBoost:     queryBoost,
+		docTerm:        docTerm,
+		docTotal:       docTotal,
+		avgFieldLength: avgFieldLength,
+		k1:             k1,
+		b:              b,
+		idf:            math.Log(1.0 + (numerator+0.5)/(float64(docTerm)+0.5)),
+		options:        options,
+		queryNorm:      1.0,
+	}
+
+	if options.Explain {
+		rv.idfExplanation = &search.Explanation{
+			Value:   rv.idf,
+			Message: fmt.Sprintf("idf(docFreq=%d, maxDocs=%d)", docTerm, docTotal),
+		}
+	}
+
+	return &rv
+}
+
+func (s *BM25TermQueryScorer) Weight() float64 {
+	sum := s.queryBoost * s.idf
+	return sum * sum
+}
+
+// SetQueryNorm records the query norm, BM25 scores are not normalized
+// by it.
+func (s *BM25TermQueryScorer) SetQueryNorm(qnorm float64) {
+	s.queryNorm = qnorm
+}
+
+func (s *BM25TermQueryScorer) Score(ctx *search.SearchContext, termMatch *index.TermFieldDoc) *search.DocumentMatch {
+	tf := float64(termMatch.Freq)
+
+	// the field norm is 1/sqrt(fieldLength)
+	fieldLength := s.avgFieldLength
+	if termMatch.Norm > 0 {
+		fieldLength = math.Floor(1.0/(termMatch.Norm*termMatch.Norm) + 0.5)
+	}
+	avgFieldLength := s.avgFieldLength
+	if avgFieldLength <= 0 {
+		// no statistics available, disable length normalization
+		avgFieldLength = fieldLength
+	}
+	lengthNorm := 1.0
+	if avgFieldLength > 0 {
+		lengthNorm = 1.0 - s.b + s.b*fieldLength/avgFieldLength
+	}
+
+	tfNorm := tf * (s.k1 + 1.0) / (tf + s.k1*lengthNorm)
+	score := s.queryBoost * s.idf * tfNorm
+
+	rv := ctx.DocumentMatchPool.Get()
+	rv.IndexInternalID = append(rv.IndexInternalID, termMatch.ID...)
+	rv.Score = score
+	if s.options.Explain {
+		childrenExplanations := make([]*search.Explanation, 3)
+		childrenExplanations[0] = &search.Explanation{
+			Value:   s.queryBoost,
+			Message: "boost",
+		}
+		childrenExplanations[1] = s.idfExplanation
+		childrenExplanations[2] = &search.Explanation{
+			Value:   tfNorm,
+			Message: "tfNorm, computed from:",
+			Children: []*search.Explanation{
+				{
+					Value:   tf,
+					Message: fmt.Sprintf("termFreq(%s:%s)=%d", s.queryField, string(s.queryTerm), termMatch.Freq),
+				},
+				{
+					Value:   s.k1,
+					Message: "parameter k1",
+				},
+				{
+					Value:   s.b,
+					Message: "parameter b",
+				},
+				{
+					Value:   avgFieldLength,
+					Message: fmt.Sprintf("avgFieldLength(field=%s)", s.queryField),
+				},
+				{
+					Value:   fieldLength,
+					Message: fmt.Sprintf("fieldLength(field=%s, doc=%s)", s.queryField, termMatch.ID),
+				},
+			},
+		}
+		rv.Expl = &search.Explanation{
+			Value:    score,
+			Message:  fmt.Sprintf("weight(%s:%s^%f in %s), BM25 product of:", s.queryField, string(s.queryTerm), s.queryBoost, termMatch.ID),
+			Children: childrenExplanations,
+		}
+	}
+
+	addTermMatchLocations(rv, s.queryTerm, termMatch)
+
+	return rv
+}
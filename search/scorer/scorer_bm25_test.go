@@ -0,0 +1,125 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+func TestBM25TermScorer(t *testing.T) {
+
+	var docTotal uint64 = 100
+	var docTerm uint64 = 9
+	var queryTerm = []byte("beer")
+	var queryField = "desc"
+	var queryBoost = 1.0
+	k1 := 1.2
+	b := 0.75
+	avgFieldLength := 4.0
+	scorer := NewBM25TermQueryScorer(queryTerm, queryField, queryBoost, docTotal, docTerm, avgFieldLength, k1, b, search.SearcherOptions{Explain: true})
+	idf := math.Log(1.0 + (float64(docTotal-docTerm)+0.5)/(float64(docTerm)+0.5))
+
+	tests := []struct {
+		freq        uint64
+		fieldLength float64
+	}{
+		// field length equal to the average
+		{
+			freq:        1,
+			fieldLength: 4,
+		},
+		// shorter than average
+		{
+			freq:        1,
+			fieldLength: 1,
+		},
+		// longer than average, repeated term
+		{
+			freq:        3,
+			fieldLength: 16,
+		},
+	}
+
+	for _, test := range tests {
+		ctx := &search.SearchContext{
+			DocumentMatchPool: search.NewDocumentMatchPool(1, 0),
+		}
+		termMatch := &index.TermFieldDoc{
+			ID:   index.IndexInternalID("one"),
+			Freq: test.freq,
+			Norm: float64(float32(1.0 / math.Sqrt(test.fieldLength))),
+			Vectors: []*index.TermFieldVector{
+				{
+					Field: "desc",
+					Pos:   1,
+					Start: 0,
+					End:   4,
+				},
+			},
+		}
+		actual := scorer.Score(ctx, termMatch)
+
+		tf := float64(test.freq)
+		expected := idf * tf * (k1 + 1) / (tf + k1*(1-b+b*test.fieldLength/avgFieldLength))
+		if math.Abs(actual.Score-expected) > 1e-9 {
+			t.Errorf("expected score %f, got %f for %#v", expected, actual.Score, test)
+		}
+		if actual.Expl == nil || actual.Expl.Value != actual.Score {
+			t.Errorf("expected explanation matching score, got %#v", actual.Expl)
+		}
+		if len(actual.Expl.Children) != 3 {
+			t.Fatalf("expected 3 explanation children, got %d", len(actual.Expl.Children))
+		}
+		fieldLengthExpl := actual.Expl.Children[2].Children[4]
+		if fieldLengthExpl.Value != test.fieldLength {
+			t.Errorf("expected field length %f, got %f", test.fieldLength, fieldLengthExpl.Value)
+		}
+		if len(actual.Locations["desc"]["beer"]) != 1 {
+			t.Errorf("expected 1 location, got %v", actual.Locations)
+		}
+	}
+
+	// shorter fields must score higher than longer ones
+	ctx := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(2, 0),
+	}
+	short := scorer.Score(ctx, &index.TermFieldDoc{ID: index.IndexInternalID("a"), Freq: 1, Norm: 1.0})
+	long := scorer.Score(ctx, &index.TermFieldDoc{ID: index.IndexInternalID("b"), Freq: 1, Norm: 0.25})
+	if short.Score <= long.Score {
+		t.Errorf("expected short field score %f to exceed long field score %f", short.Score, long.Score)
+	}
+}
+
+func TestBM25TermScorerWeight(t *testing.T) {
+	scorer := NewBM25TermQueryScorer([]byte("beer"), "desc", 3.0, 100, 9, 4.0, 1.2, 0.75, search.SearcherOptions{})
+	idf := math.Log(1.0 + (91.0+0.5)/(9.0+0.5))
+	expected := 3 * idf * 3 * idf
+	if math.Abs(scorer.Weight()-expected) > 1e-9 {
+		t.Errorf("expected weight %f, got %f", expected, scorer.Weight())
+	}
+	scorer.SetQueryNorm(2.0)
+	ctx := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(1, 0),
+	}
+	actual := scorer.Score(ctx, &index.TermFieldDoc{ID: index.IndexInternalID("one"), Freq: 1, Norm: 0.5})
+	expectedScore := 3.0 * idf * 2.2 / (1 + 1.2)
+	if math.Abs(actual.Score-expectedScore) > 1e-9 {
+		t.Errorf("expected score %f, got %f", expectedScore, actual.Score)
+	}
+}
@@ -140,6 +140,14 @@ func (s *TermQueryScorer) Score(ctx *search.SearchContext, termMatch *index.Term
 		rv.Expl = scoreExplanation
 	}
 
+	addTermMatchLocations(rv, s.queryTerm, termMatch)
+
+	return rv
+}
+
+// addTermMatchLocations records the term vectors of the termMatch as
+// locations of the queryTerm in the DocumentMatch
+func addTermMatchLocations(rv *search.DocumentMatch, queryTerm []byte, termMatch *index.TermFieldDoc) {
 	if termMatch.Vectors != nil && len(termMatch.Vectors) > 0 {
 		locs := make([]search.Location, len(termMatch.Vectors))
 		locsUsed := 0
@@ -174,9 +182,7 @@ func (s *TermQueryScorer) Score(ctx *search.SearchContext, termMatch *index.Term
 				positionsUsed += len(v.ArrayPositions)
 			}
 
-			tlm[string(s.queryTerm)] = append(tlm[string(s.queryTerm)], loc)
+			tlm[string(queryTerm)] = append(tlm[string(queryTerm)], loc)
 		}
 	}
-
-	return rv
 }
@@ -62,7 +62,16 @@ type DocumentMatch struct {
 	Expl            *Explanation          `json:"explanation,omitempty"`
 	Locations       FieldTermLocationMap  `json:"locations,omitempty"`
 	Fragments       FieldFragmentMap      `json:"fragments,omitempty"`
-	Sort            []string              `json:"sort,omitempty"`
+
+	// Sort holds the sort values of the hit, which can be used to
+	// search after or before it. The values of score sorts are the
+	// score formatted as a number.
+	Sort []string `json:"sort,omitempty"`
+
+	// Collapse is the value of the collapse field shared by the
+	// hits of a group, InnerHits are the best hits of the group.
+	Collapse  string                  `json:"collapse,omitempty"`
+	InnerHits DocumentMatchCollection `json:"inner_hits,omitempty"`
 
 	// Fields contains the values for document fields listed in
 	// SearchRequest.Fields. Text fields are returned as strings, numeric
@@ -137,6 +146,10 @@ type Searcher interface {
 type SearcherOptions struct {
 	Explain            bool
 	IncludeTermVectors bool
+
+	// Similarities, when set, selects the Similarity used to score
+	// term matches in each field
+	Similarities SimilarityLookup
 }
 
 // SearchContext represents the context around a single search
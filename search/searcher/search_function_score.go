@@ -0,0 +1,535 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"github.com/edwindvinas/bleve/geo"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// The ways the values of the functions of a FunctionScoreSearcher are
+// combined together.
+const (
+	FunctionScoreModeMultiply = "multiply"
+	FunctionScoreModeSum      = "sum"
+	FunctionScoreModeAvg      = "avg"
+	FunctionScoreModeMax      = "max"
+	FunctionScoreModeMin      = "min"
+	FunctionScoreModeFirst    = "first"
+)
+
+// The ways the combined value of the functions of a FunctionScoreSearcher
+// is combined with the score of the query.
+const (
+	FunctionBoostModeMultiply = "multiply"
+	FunctionBoostModeSum      = "sum"
+	FunctionBoostModeReplace  = "replace"
+)
+
+// A ScoreFunction computes a value for a document, typically from the
+// values of one of its fields.
+type ScoreFunction interface {
+	// Field returns the field the function needs the values of, or
+	// the empty string if it needs none
+	Field() string
+	// Score computes the function for the document, values are the
+	// full precision numeric values of the field, ok is false when
+	// the function does not apply to the document
+	Score(indexReader index.IndexReader, d *search.DocumentMatch,
+		values []int64) (score float64, ok bool, err error)
+	String() string
+}
+
+// FunctionScoreSearcher wraps any other searcher, and modifies the
+// score of its hits with the values of functions.
+type FunctionScoreSearcher struct {
+	indexReader index.IndexReader
+	child       search.Searcher
+	functions   []ScoreFunction
+	weights     []float64
+	scoreMode   string
+	boostMode   string
+	boost       float64
+	options     search.SearcherOptions
+	fields      []string
+	values      map[string][]int64
+}
+
+// NewFunctionScoreSearcher creates a searcher which computes each of
+// the functions for the hits of the child searcher, multiplied by its
+// weight.  The values are combined according to the score mode, then
+// with the score of the hit according to the boost mode, and the
+// result is multiplied by the boost.  Hits to which none of the
+// functions apply only have their score multiplied by the boost.
+func NewFunctionScoreSearcher(indexReader index.IndexReader, child search.Searcher,
+	functions []ScoreFunction, weights []float64, scoreMode, boostMode string,
+	boost float64, options search.SearcherOptions) (*FunctionScoreSearcher, error) {
+	if len(weights) != len(functions) {
+		return nil, fmt.Errorf("function score expects a weight for each function")
+	}
+	switch scoreMode {
+	case "":
+		scoreMode = FunctionScoreModeMultiply
+	case FunctionScoreModeMultiply, FunctionScoreModeSum, FunctionScoreModeAvg,
+		FunctionScoreModeMax, FunctionScoreModeMin, FunctionScoreModeFirst:
+	default:
+		return nil, fmt.Errorf("unknown function score mode '%s'", scoreMode)
+	}
+	switch boostMode {
+	case "":
+		boostMode = FunctionBoostModeMultiply
+	case FunctionBoostModeMultiply, FunctionBoostModeSum, FunctionBoostModeReplace:
+	default:
+		return nil, fmt.Errorf("unknown function boost mode '%s'", boostMode)
+	}
+
+	rv := &FunctionScoreSearcher{
+		indexReader: indexReader,
+		child:       child,
+		functions:   functions,
+		weights:     weights,
+		scoreMode:   scoreMode,
+		boostMode:   boostMode,
+		boost:       boost,
+		options:     options,
+		values:      make(map[string][]int64),
+	}
+	for _, function := range functions {
+		field := function.Field()
+		if _, ok := rv.values[field]; field != "" && !ok {
+			rv.values[field] = nil
+			rv.fields = append(rv.fields, field)
+		}
+	}
+	return rv, nil
+}
+
+func (s *FunctionScoreSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	next, err := s.child.Next(ctx)
+	if err != nil || next == nil {
+		return nil, err
+	}
+	return next, s.score(next)
+}
+
+func (s *FunctionScoreSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	adv, err := s.child.Advance(ctx, ID)
+	if err != nil || adv == nil {
+		return nil, err
+	}
+	return adv, s.score(adv)
+}
+
+func (s *FunctionScoreSearcher) score(d *search.DocumentMatch) error {
+	if len(s.fields) > 0 {
+		for field, values := range s.values {
+			s.values[field] = values[:0]
+		}
+		err := s.indexReader.DocumentVisitFieldTerms(d.IndexInternalID, s.fields,
+			func(field string, term []byte) {
+				// only consider the values which are shifted 0
+				prefixCoded := numeric.PrefixCoded(term)
+				shift, err := prefixCoded.Shift()
+				if err == nil && shift == 0 {
+					i64, err := prefixCoded.Int64()
+					if err == nil {
+						s.values[field] = append(s.values[field], i64)
+					}
+				}
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	var combined float64
+	applied := 0
+	var functionExpls []*search.Explanation
+	for i, function := range s.functions {
+		value, ok, err := function.Score(s.indexReader, d, s.values[function.Field()])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		value *= s.weights[i]
+		if applied == 0 {
+			combined = value
+		} else {
+			switch s.scoreMode {
+			case FunctionScoreModeMultiply:
+				combined *= value
+			case FunctionScoreModeSum, FunctionScoreModeAvg:
+				combined += value
+			case FunctionScoreModeMax:
+				combined = math.Max(combined, value)
+			case FunctionScoreModeMin:
+				combined = math.Min(combined, value)
+			}
+		}
+		applied++
+		if s.options.Explain {
+			functionExpls = append(functionExpls, &search.Explanation{
+				Value:   value,
+				Message: fmt.Sprintf("%s, weight %g", function, s.weights[i]),
+			})
+		}
+		if s.scoreMode == FunctionScoreModeFirst {
+			break
+		}
+	}
+	if s.scoreMode == FunctionScoreModeAvg && applied > 0 {
+		combined /= float64(applied)
+	}
+
+	score := d.Score
+	if applied > 0 {
+		switch s.boostMode {
+		case FunctionBoostModeMultiply:
+			score *= combined
+		case FunctionBoostModeSum:
+			score += combined
+		case FunctionBoostModeReplace:
+			score = combined
+		}
+	}
+	score *= s.boost
+
+	if s.options.Explain {
+		children := []*search.Explanation{d.Expl}
+		message := "function score, no function applied, product of:"
+		if applied > 0 {
+			message = fmt.Sprintf("function score, boost mode %s, then product of:", s.boostMode)
+			children = append(children, &search.Explanation{
+				Value:    combined,
+				Message:  fmt.Sprintf("functions, score mode %s of:", s.scoreMode),
+				Children: functionExpls,
+			})
+		}
+		children = append(children, &search.Explanation{
+			Value:   s.boost,
+			Message: "boost",
+		})
+		d.Expl = &search.Explanation{
+			Value:    score,
+			Message:  message,
+			Children: children,
+		}
+	}
+	d.Score = score
+	return nil
+}
+
+func (s *FunctionScoreSearcher) Close() error {
+	return s.child.Close()
+}
+
+func (s *FunctionScoreSearcher) Weight() float64 {
+	return s.child.Weight()
+}
+
+func (s *FunctionScoreSearcher) SetQueryNorm(n float64) {
+	s.child.SetQueryNorm(n)
+}
+
+func (s *FunctionScoreSearcher) Count() uint64 {
+	return s.child.Count()
+}
+
+func (s *FunctionScoreSearcher) Min() int {
+	return s.child.Min()
+}
+
+func (s *FunctionScoreSearcher) DocumentMatchPoolSize() int {
+	return s.child.DocumentMatchPoolSize()
+}
+
+// WeightFunction has the value 1 for every document, so that its
+// weight alone is applied.
+type WeightFunction struct{}
+
+func (f *WeightFunction) Field() string {
+	return ""
+}
+
+func (f *WeightFunction) Score(indexReader index.IndexReader, d *search.DocumentMatch,
+	values []int64) (float64, bool, error) {
+	return 1, true, nil
+}
+
+func (f *WeightFunction) String() string {
+	return "weight"
+}
+
+// The modifiers applied to the value of a FieldValueFactorFunction.
+const (
+	FieldValueModifierNone       = "none"
+	FieldValueModifierLog        = "log"
+	FieldValueModifierLog1p      = "log1p"
+	FieldValueModifierLog2p      = "log2p"
+	FieldValueModifierLn         = "ln"
+	FieldValueModifierLn1p       = "ln1p"
+	FieldValueModifierLn2p       = "ln2p"
+	FieldValueModifierSquare     = "square"
+	FieldValueModifierSqrt       = "sqrt"
+	FieldValueModifierReciprocal = "reciprocal"
+)
+
+var fieldValueModifiers = map[string]func(float64) float64{
+	FieldValueModifierNone:       func(v float64) float64 { return v },
+	FieldValueModifierLog:        math.Log10,
+	FieldValueModifierLog1p:      func(v float64) float64 { return math.Log10(v + 1) },
+	FieldValueModifierLog2p:      func(v float64) float64 { return math.Log10(v + 2) },
+	FieldValueModifierLn:         math.Log,
+	FieldValueModifierLn1p:       math.Log1p,
+	FieldValueModifierLn2p:       func(v float64) float64 { return math.Log(v + 2) },
+	FieldValueModifierSquare:     func(v float64) float64 { return v * v },
+	FieldValueModifierSqrt:       math.Sqrt,
+	FieldValueModifierReciprocal: func(v float64) float64 { return 1 / v },
+}
+
+// FieldValueFactorFunction is the value of a numeric field multiplied
+// by a factor, then modified.  Documents without a value use the
+// missing value, if any.  When the field has several values the first
+// is used.
+type FieldValueFactorFunction struct {
+	field    string
+	factor   float64
+	modifier string
+	modify   func(float64) float64
+	missing  *float64
+}
+
+func NewFieldValueFactorFunction(field string, factor float64, modifier string,
+	missing *float64) (*FieldValueFactorFunction, error) {
+	if modifier == "" {
+		modifier = FieldValueModifierNone
+	}
+	modify, ok := fieldValueModifiers[modifier]
+	if !ok {
+		return nil, fmt.Errorf("unknown field value modifier '%s'", modifier)
+	}
+	return &FieldValueFactorFunction{
+		field:    field,
+		factor:   factor,
+		modifier: modifier,
+		modify:   modify,
+		missing:  missing,
+	}, nil
+}
+
+func (f *FieldValueFactorFunction) Field() string {
+	return f.field
+}
+
+func (f *FieldValueFactorFunction) Score(indexReader index.IndexReader, d *search.DocumentMatch,
+	values []int64) (float64, bool, error) {
+	var value float64
+	if len(values) > 0 {
+		value = numeric.Int64ToFloat64(values[0])
+	} else if f.missing != nil {
+		value = *f.missing
+	} else {
+		return 0, false, nil
+	}
+	rv := f.modify(f.factor * value)
+	if math.IsNaN(rv) || math.IsInf(rv, 0) {
+		// the modifier is undefined for the value
+		rv = 0
+	}
+	return rv, true, nil
+}
+
+func (f *FieldValueFactorFunction) String() string {
+	return fmt.Sprintf("field value factor %s(%g * %s)", f.modifier, f.factor, f.field)
+}
+
+// The shapes of the curve of a DecayFunction.
+const (
+	DecayGauss  = "gauss"
+	DecayExp    = "exp"
+	DecayLinear = "linear"
+)
+
+// DecayFunction decreases from 1 as the value of a field moves away
+// from an origin.  Values within offset of the origin score 1, values
+// at offset+scale from the origin score decay.  When the field has
+// several values the closest to the origin is used, documents without
+// a value are not scored.
+type DecayFunction struct {
+	kind     string
+	field    string
+	scale    float64
+	offset   float64
+	decay    float64
+	distance func(value int64) float64
+	origin   string
+}
+
+func newDecayFunction(kind, field string, scale, offset, decay float64) (*DecayFunction, error) {
+	switch kind {
+	case DecayGauss, DecayExp, DecayLinear:
+	default:
+		return nil, fmt.Errorf("unknown decay function '%s'", kind)
+	}
+	if scale <= 0 {
+		return nil, fmt.Errorf("decay scale must be positive")
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("decay offset must not be negative")
+	}
+	if decay <= 0 || decay >= 1 {
+		return nil, fmt.Errorf("decay must be between 0 and 1")
+	}
+	return &DecayFunction{
+		kind:   kind,
+		field:  field,
+		scale:  scale,
+		offset: offset,
+		decay:  decay,
+	}, nil
+}
+
+// NewNumericDecayFunction decays with the distance of the values of a
+// numeric field from the origin.
+func NewNumericDecayFunction(kind, field string, origin, scale, offset,
+	decay float64) (*DecayFunction, error) {
+	rv, err := newDecayFunction(kind, field, scale, offset, decay)
+	if err != nil {
+		return nil, err
+	}
+	rv.origin = fmt.Sprintf("%g", origin)
+	rv.distance = func(value int64) float64 {
+		return math.Abs(numeric.Int64ToFloat64(value) - origin)
+	}
+	return rv, nil
+}
+
+// NewDateDecayFunction decays with the time between the values of a
+// date field and the origin.
+func NewDateDecayFunction(kind, field string, origin time.Time, scale, offset time.Duration,
+	decay float64) (*DecayFunction, error) {
+	rv, err := newDecayFunction(kind, field, float64(scale), float64(offset), decay)
+	if err != nil {
+		return nil, err
+	}
+	rv.origin = origin.Format(time.RFC3339)
+	originNanos := origin.UnixNano()
+	rv.distance = func(value int64) float64 {
+		return math.Abs(float64(value - originNanos))
+	}
+	return rv, nil
+}
+
+// NewGeoDecayFunction decays with the distance in meters between the
+// values of a geo point field and the origin.
+func NewGeoDecayFunction(kind, field string, originLon, originLat, scale, offset,
+	decay float64) (*DecayFunction, error) {
+	rv, err := newDecayFunction(kind, field, scale, offset, decay)
+	if err != nil {
+		return nil, err
+	}
+	rv.origin = fmt.Sprintf("[%g, %g]", originLon, originLat)
+	rv.distance = func(value int64) float64 {
+		lon := geo.MortonUnhashLon(uint64(value))
+		lat := geo.MortonUnhashLat(uint64(value))
+		return geo.Haversin(originLon, originLat, lon, lat) * 1000
+	}
+	return rv, nil
+}
+
+func (f *DecayFunction) Field() string {
+	return f.field
+}
+
+func (f *DecayFunction) Score(indexReader index.IndexReader, d *search.DocumentMatch,
+	values []int64) (float64, bool, error) {
+	if len(values) == 0 {
+		return 0, false, nil
+	}
+	distance := math.Inf(1)
+	for _, value := range values {
+		distance = math.Min(distance, f.distance(value))
+	}
+	distance = math.Max(0, distance-f.offset)
+
+	switch f.kind {
+	case DecayGauss:
+		sigmaSquared := -f.scale * f.scale / (2 * math.Log(f.decay))
+		return math.Exp(-distance * distance / (2 * sigmaSquared)), true, nil
+	case DecayExp:
+		lambda := math.Log(f.decay) / f.scale
+		return math.Exp(lambda * distance), true, nil
+	}
+	s := f.scale / (1 - f.decay)
+	return math.Max(0, (s-distance)/s), true, nil
+}
+
+func (f *DecayFunction) String() string {
+	return fmt.Sprintf("%s decay of %s from %s", f.kind, f.field, f.origin)
+}
+
+// RandomScoreFunction scores documents randomly between 0 and 1, the
+// score of a document is the same for the same seed.
+type RandomScoreFunction struct {
+	seed int64
+}
+
+func NewRandomScoreFunction(seed int64) *RandomScoreFunction {
+	return &RandomScoreFunction{
+		seed: seed,
+	}
+}
+
+func (f *RandomScoreFunction) Field() string {
+	return ""
+}
+
+func (f *RandomScoreFunction) Score(indexReader index.IndexReader, d *search.DocumentMatch,
+	values []int64) (float64, bool, error) {
+	// hash the external id, which unlike the internal id is stable
+	id := d.ID
+	if id == "" {
+		var err error
+		id, err = indexReader.ExternalID(d.IndexInternalID)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], uint64(f.seed))
+	hash := fnv.New64a()
+	_, _ = hash.Write(seed[:])
+	_, _ = hash.Write([]byte(id))
+	x := hash.Sum64()
+	// spread the bits, the fnv hash of similar ids are close
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	return float64(x>>11) / (1 << 53), true, nil
+}
+
+func (f *RandomScoreFunction) String() string {
+	return fmt.Sprintf("random score with seed %d", f.seed)
+}
@@ -0,0 +1,265 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/index/store/gtreap"
+	"github.com/edwindvinas/bleve/index/upsidedown"
+	"github.com/edwindvinas/bleve/search"
+)
+
+func TestFunctionScoreSearcher(t *testing.T) {
+
+	analysisQueue := index.NewAnalysisQueue(1)
+	i, err := upsidedown.NewUpsideDownCouch(
+		gtreap.Name,
+		map[string]interface{}{
+			"path": "",
+		},
+		analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = i.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, value := range map[string]float64{"a": 1, "b": 9, "c": 99} {
+		err = i.Update(&document.Document{
+			ID: id,
+			Fields: []document.Field{
+				document.NewNumericField("rating", []uint64{}, value),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = i.Update(&document.Document{
+		ID: "d",
+		Fields: []document.Field{
+			document.NewTextField("desc", []uint64{}, []byte("beer")),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexReader, err := i.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err = indexReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	log1p, err := NewFieldValueFactorFunction("rating", 1, FieldValueModifierLog1p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gauss, err := NewNumericDecayFunction(DecayGauss, "rating", 0, 9, 1, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linear, err := NewNumericDecayFunction(DecayLinear, "rating", 0, 10, 0, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		functions []ScoreFunction
+		weights   []float64
+		scoreMode string
+		boostMode string
+		boost     float64
+		want      map[string]float64
+	}{
+		{
+			functions: []ScoreFunction{log1p},
+			weights:   []float64{1},
+			want:      map[string]float64{"a": math.Log10(2), "b": 1, "c": 2, "d": 1},
+		},
+		{
+			functions: []ScoreFunction{log1p, &WeightFunction{}},
+			weights:   []float64{2, 3},
+			scoreMode: FunctionScoreModeSum,
+			boostMode: FunctionBoostModeReplace,
+			boost:     2,
+			want:      map[string]float64{"a": 2 * (2*math.Log10(2) + 3), "b": 10, "c": 14, "d": 6},
+		},
+		{
+			// 1 is within the offset, 10 is offset+scale away
+			functions: []ScoreFunction{gauss},
+			weights:   []float64{1},
+			want:      map[string]float64{"a": 1, "b": math.Pow(0.5, 64.0/81), "c": math.Pow(0.5, 98.0*98/81), "d": 1},
+		},
+		{
+			functions: []ScoreFunction{linear, log1p},
+			weights:   []float64{1, 1},
+			scoreMode: FunctionScoreModeMax,
+			boostMode: FunctionBoostModeSum,
+			want:      map[string]float64{"a": 1 + 0.95, "b": 2, "c": 3, "d": 1},
+		},
+		{
+			functions: []ScoreFunction{linear, log1p},
+			weights:   []float64{1, 1},
+			scoreMode: FunctionScoreModeFirst,
+			want:      map[string]float64{"a": 0.95, "b": 0.55, "c": 0, "d": 1},
+		},
+	}
+
+	for testIndex, test := range tests {
+		child, err := NewMatchAllSearcher(indexReader, 1.0, search.SearcherOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		boost := test.boost
+		if boost == 0 {
+			boost = 1
+		}
+		searcher, err := NewFunctionScoreSearcher(indexReader, child, test.functions,
+			test.weights, test.scoreMode, test.boostMode, boost,
+			search.SearcherOptions{Explain: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := &search.SearchContext{
+			DocumentMatchPool: search.NewDocumentMatchPool(searcher.DocumentMatchPoolSize(), 0),
+		}
+		got := make(map[string]float64)
+		next, err := searcher.Next(ctx)
+		for err == nil && next != nil {
+			id, err := indexReader.ExternalID(next.IndexInternalID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[id] = next.Score
+			if next.Expl == nil || !scoresCloseEnough(next.Expl.Value, next.Score) {
+				t.Errorf("test %d: expected explanation of %s to match its score", testIndex, id)
+			}
+			ctx.DocumentMatchPool.Put(next)
+			next, err = searcher.Next(ctx)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(test.want) {
+			t.Fatalf("test %d: expected %d hits, got %d", testIndex, len(test.want), len(got))
+		}
+		for id, score := range test.want {
+			if !scoresCloseEnough(got[id], score) {
+				t.Errorf("test %d: expected %s to score %f, got %f", testIndex, id, score, got[id])
+			}
+		}
+		err = searcher.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGeoDecayFunction(t *testing.T) {
+	i := setupGeo(t)
+	indexReader, err := i.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err = indexReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	exp, err := NewGeoDecayFunction(DecayExp, "loc", 0.0015, 0.0015, 100000, 0, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := NewMatchAllSearcher(indexReader, 1.0, search.SearcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	searcher, err := NewFunctionScoreSearcher(indexReader, child, []ScoreFunction{exp},
+		[]float64{1}, "", "", 1, search.SearcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err = searcher.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	ctx := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(searcher.DocumentMatchPoolSize(), 0),
+	}
+	scores := make(map[string]float64)
+	next, err := searcher.Next(ctx)
+	for err == nil && next != nil {
+		id, err := indexReader.ExternalID(next.IndexInternalID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scores[id] = next.Score
+		ctx.DocumentMatchPool.Put(next)
+		next, err = searcher.Next(ctx)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scores["a"] < 0.99 {
+		t.Errorf("expected the origin to score about 1, got %f", scores["a"])
+	}
+	// b is about 157km away from the origin
+	if scores["b"] < 0.3 || scores["b"] > 0.4 {
+		t.Errorf("expected b to score about 0.34, got %f", scores["b"])
+	}
+	if scores["j"] >= scores["b"] {
+		t.Errorf("expected j to score less than b, got %f and %f", scores["j"], scores["b"])
+	}
+}
+
+func TestRandomScoreFunction(t *testing.T) {
+	d := &search.DocumentMatch{ID: "a"}
+	first, _, err := NewRandomScoreFunction(1).Score(nil, d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := NewRandomScoreFunction(1).Score(nil, d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != again {
+		t.Errorf("expected the same seed to score the same, got %f and %f", first, again)
+	}
+	other, _, err := NewRandomScoreFunction(2).Score(nil, d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == other {
+		t.Errorf("expected another seed to score differently")
+	}
+	if first < 0 || first >= 1 || other < 0 || other >= 1 {
+		t.Errorf("expected scores in [0,1), got %f and %f", first, other)
+	}
+}
@@ -0,0 +1,77 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/geo"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/numeric"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// NewGeoPolygonSearcher finds documents with a geo point inside the
+// polygon described by the ring of [lon, lat] points, and outside all of
+// the holes. Candidates are found using the terms of the polygon's
+// bounding box, then each candidate point is checked against the polygon.
+func NewGeoPolygonSearcher(indexReader index.IndexReader,
+	polygon [][]float64, holes [][][]float64, field string, boost float64,
+	options search.SearcherOptions) (search.Searcher, error) {
+
+	if len(polygon) < 3 {
+		return nil, fmt.Errorf("geo polygon must have at least 3 points")
+	}
+
+	minLon, minLat, maxLon, maxLat := geo.PolygonBoundingBox(polygon)
+
+	// build a searcher for the box containing the polygon
+	boxSearcher, err := boxSearcher(indexReader,
+		minLon, maxLat, maxLon, minLat,
+		field, boost, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// wrap it in a filtering searcher which checks the polygon
+	return NewFilteringSearcher(boxSearcher,
+		buildPolygonFilter(indexReader, field, polygon, holes)), nil
+}
+
+func buildPolygonFilter(indexReader index.IndexReader, field string,
+	polygon [][]float64, holes [][][]float64) FilterFunc {
+	return func(d *search.DocumentMatch) bool {
+		var lon, lat float64
+		var found bool
+		err := indexReader.DocumentVisitFieldTerms(d.IndexInternalID,
+			[]string{field}, func(field string, term []byte) {
+				// only consider the values which are shifted 0
+				prefixCoded := numeric.PrefixCoded(term)
+				shift, err := prefixCoded.Shift()
+				if err == nil && shift == 0 {
+					i64, err := prefixCoded.Int64()
+					if err == nil {
+						lon = geo.MortonUnhashLon(uint64(i64))
+						lat = geo.MortonUnhashLat(uint64(i64))
+						found = true
+					}
+				}
+			})
+		if err == nil && found {
+			return geo.PolygonWithHolesContains(lon, lat, polygon, holes)
+		}
+		return false
+	}
+}
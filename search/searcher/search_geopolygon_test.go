@@ -0,0 +1,85 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+func TestGeoPolygonSearcher(t *testing.T) {
+
+	tests := []struct {
+		polygon [][]float64
+		holes   [][][]float64
+		field   string
+		want    []string
+	}{
+		// square around the first three points
+		{[][]float64{{-0.5, -0.5}, {2.5, -0.5}, {2.5, 2.5}, {-0.5, 2.5}}, nil, "loc", []string{"a", "b", "c"}},
+		// same square, with a hole around the middle point
+		{[][]float64{{-0.5, -0.5}, {2.5, -0.5}, {2.5, 2.5}, {-0.5, 2.5}},
+			[][][]float64{{{0.5, 0.5}, {1.5, 0.5}, {1.5, 1.5}, {0.5, 1.5}}}, "loc", []string{"a", "c"}},
+		// triangle whose bounding box contains points it does not
+		{[][]float64{{-0.5, -0.5}, {5.5, -0.5}, {-0.5, 5.5}}, nil, "loc", []string{"a", "b", "c"}},
+		// wrong field
+		{[][]float64{{-0.5, -0.5}, {2.5, -0.5}, {2.5, 2.5}, {-0.5, 2.5}}, nil, "nope", nil},
+	}
+
+	i := setupGeo(t)
+	indexReader, err := i.Reader()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func() {
+		err = indexReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	for _, test := range tests {
+		got, err := testGeoPolygonSearch(indexReader, test.polygon, test.holes, test.field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expected %v, got %v for %v %v %s", test.want, got, test.polygon, test.holes, test.field)
+		}
+	}
+}
+
+func testGeoPolygonSearch(i index.IndexReader, polygon [][]float64, holes [][][]float64, field string) ([]string, error) {
+	var rv []string
+	gps, err := NewGeoPolygonSearcher(i, polygon, holes, field, 1.0, search.SearcherOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ctx := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(gps.DocumentMatchPoolSize(), 0),
+	}
+	docMatch, err := gps.Next(ctx)
+	for docMatch != nil && err == nil {
+		rv = append(rv, string(docMatch.IndexInternalID))
+		docMatch, err = gps.Next(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
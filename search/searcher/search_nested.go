@@ -0,0 +1,198 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// Ways of combining the scores of the nested documents matching for a
+// same parent.
+const (
+	NestedScoreModeAvg = "avg"
+	NestedScoreModeMax = "max"
+	NestedScoreModeMin = "min"
+	NestedScoreModeSum = "sum"
+)
+
+// NestedSearcher joins the nested documents matched by its child
+// searcher to their top level parent document, see
+// document.NewNestedDocument. Nested documents must immediately follow
+// their parent in the index order.
+type NestedSearcher struct {
+	indexReader index.IndexReader
+	child       search.Searcher
+	scoreMode   string
+	boost       float64
+	options     search.SearcherOptions
+	started     bool
+	pending     *search.DocumentMatch
+}
+
+// NewNestedSearcher creates a searcher returning the parents of the
+// nested documents matched by the child searcher, scored by combining
+// their scores according to scoreMode, then multiplied by boost.
+func NewNestedSearcher(indexReader index.IndexReader, child search.Searcher, scoreMode string,
+	boost float64, options search.SearcherOptions) (*NestedSearcher, error) {
+	switch scoreMode {
+	case "":
+		scoreMode = NestedScoreModeAvg
+	case NestedScoreModeAvg, NestedScoreModeMax, NestedScoreModeMin, NestedScoreModeSum:
+	default:
+		return nil, fmt.Errorf("unknown nested score mode '%s'", scoreMode)
+	}
+	return &NestedSearcher{
+		indexReader: indexReader,
+		child:       child,
+		scoreMode:   scoreMode,
+		boost:       boost,
+		options:     options,
+	}, nil
+}
+
+func (s *NestedSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	if !s.started {
+		s.started = true
+		var err error
+		s.pending, err = s.child.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.join(ctx)
+}
+
+func (s *NestedSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	// the nested documents of ID follow it, those of the previous
+	// documents precede it
+	var err error
+	if !s.started {
+		s.started = true
+		s.pending, err = s.child.Advance(ctx, ID)
+	} else if s.pending != nil && s.pending.IndexInternalID.Compare(ID) < 0 {
+		ctx.DocumentMatchPool.Put(s.pending)
+		s.pending, err = s.child.Advance(ctx, ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.join(ctx)
+}
+
+// join consumes the pending nested document and those following it with
+// the same parent, and returns a match for the parent.
+func (s *NestedSearcher) join(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	if s.pending == nil {
+		return nil, nil
+	}
+	parentID, err := s.parentID(s.pending.IndexInternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var score float64
+	var count int
+	var expls []*search.Explanation
+	for s.pending != nil {
+		id, err := s.parentID(s.pending.IndexInternalID)
+		if err != nil {
+			return nil, err
+		}
+		if !id.Equals(parentID) {
+			break
+		}
+		switch {
+		case count == 0:
+			score = s.pending.Score
+		case s.scoreMode == NestedScoreModeMax:
+			score = math.Max(score, s.pending.Score)
+		case s.scoreMode == NestedScoreModeMin:
+			score = math.Min(score, s.pending.Score)
+		default:
+			score += s.pending.Score
+		}
+		count++
+		if s.options.Explain {
+			expls = append(expls, s.pending.Expl)
+		}
+		ctx.DocumentMatchPool.Put(s.pending)
+		s.pending, err = s.child.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.scoreMode == NestedScoreModeAvg {
+		score /= float64(count)
+	}
+
+	rv := ctx.DocumentMatchPool.Get()
+	rv.IndexInternalID = parentID
+	rv.Score = score * s.boost
+	if s.options.Explain {
+		rv.Expl = &search.Explanation{
+			Value:   rv.Score,
+			Message: "nested, product of:",
+			Children: []*search.Explanation{
+				{
+					Value:    score,
+					Message:  fmt.Sprintf("%s of %d nested documents:", s.scoreMode, count),
+					Children: expls,
+				},
+				{
+					Value:   s.boost,
+					Message: "boost",
+				},
+			},
+		}
+	}
+	return rv, nil
+}
+
+func (s *NestedSearcher) parentID(id index.IndexInternalID) (index.IndexInternalID, error) {
+	externalID, err := s.indexReader.ExternalID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.indexReader.InternalID(document.NestedParentID(externalID))
+}
+
+func (s *NestedSearcher) Close() error {
+	return s.child.Close()
+}
+
+func (s *NestedSearcher) Weight() float64 {
+	return s.child.Weight()
+}
+
+func (s *NestedSearcher) SetQueryNorm(n float64) {
+	s.child.SetQueryNorm(n)
+}
+
+func (s *NestedSearcher) Count() uint64 {
+	return s.child.Count()
+}
+
+func (s *NestedSearcher) Min() int {
+	return 0
+}
+
+func (s *NestedSearcher) DocumentMatchPoolSize() int {
+	return s.child.DocumentMatchPoolSize() + 1
+}
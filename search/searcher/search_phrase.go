@@ -30,6 +30,7 @@ type PhraseSearcher struct {
 	slop         int
 	terms        [][]string
 	initialized  bool
+	options      search.SearcherOptions
 }
 
 func NewPhraseSearcher(indexReader index.IndexReader, terms []string, field string, options search.SearcherOptions) (*PhraseSearcher, error) {
@@ -41,7 +42,27 @@ func NewPhraseSearcher(indexReader index.IndexReader, terms []string, field stri
 	return NewMultiPhraseSearcher(indexReader, mterms, field, options)
 }
 
+// NewSloppyPhraseSearcher creates a phrase searcher allowing the terms
+// to be up to slop position moves away from the exact phrase.
+func NewSloppyPhraseSearcher(indexReader index.IndexReader, terms []string, field string, slop int, options search.SearcherOptions) (*PhraseSearcher, error) {
+	mterms := make([][]string, len(terms))
+	for i, term := range terms {
+		mterms[i] = []string{term}
+	}
+	return NewSloppyMultiPhraseSearcher(indexReader, mterms, field, slop, options)
+}
+
 func NewMultiPhraseSearcher(indexReader index.IndexReader, terms [][]string, field string, options search.SearcherOptions) (*PhraseSearcher, error) {
+	return NewSloppyMultiPhraseSearcher(indexReader, terms, field, 0, options)
+}
+
+// NewSloppyMultiPhraseSearcher creates a multi phrase searcher allowing
+// the terms to be up to slop position moves away from the exact phrase.
+// The closer the terms are to the exact phrase, the higher the score.
+func NewSloppyMultiPhraseSearcher(indexReader index.IndexReader, terms [][]string, field string, slop int, options search.SearcherOptions) (*PhraseSearcher, error) {
+	if slop < 0 {
+		return nil, fmt.Errorf("phrase searcher slop must not be negative")
+	}
 	options.IncludeTermVectors = true
 	var termPositionSearchers []search.Searcher
 	for _, termPos := range terms {
@@ -99,6 +120,8 @@ func NewMultiPhraseSearcher(indexReader index.IndexReader, terms [][]string, fie
 		indexReader:  indexReader,
 		mustSearcher: mustSearcher,
 		terms:        terms,
+		slop:         slop,
+		options:      options,
 	}
 	rv.computeQueryNorm()
 	return &rv, nil
@@ -184,16 +207,20 @@ func (s *PhraseSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch,
 func (s *PhraseSearcher) checkCurrMustMatch(ctx *search.SearchContext) *search.DocumentMatch {
 	rvftlm := make(search.FieldTermLocationMap, 0)
 	freq := 0
+	minSlop := s.slop
 	// typically we would expect there to only actually be results in
 	// one field, but we allow for this to not be the case
 	// but, we note that phrase constraints can only be satisfied within
 	// a single field, so we can check them each independently
 	for field, tlm := range s.currMust.Locations {
 
-		f, rvtlm := s.checkCurrMustMatchField(ctx, tlm)
+		f, rvtlm, fieldSlop := s.checkCurrMustMatchField(ctx, tlm)
 		if f > 0 {
 			freq += f
 			rvftlm[field] = rvtlm
+			if fieldSlop < minSlop {
+				minSlop = fieldSlop
+			}
 		}
 	}
 
@@ -201,24 +228,49 @@ func (s *PhraseSearcher) checkCurrMustMatch(ctx *search.SearchContext) *search.D
 		// return match
 		rv := s.currMust
 		rv.Locations = rvftlm
+		if minSlop > 0 {
+			// the closest match scores the most
+			s.scoreSloppy(rv, minSlop)
+		}
 		return rv
 	}
 
 	return nil
 }
 
+// scoreSloppy scales the score of a sloppy match down by the number
+// of position moves of its closest match
+func (s *PhraseSearcher) scoreSloppy(d *search.DocumentMatch, slop int) {
+	factor := 1.0 / float64(1+slop)
+	d.Score *= factor
+	if s.options.Explain {
+		d.Expl = &search.Explanation{
+			Value:   d.Score,
+			Message: "product of:",
+			Children: []*search.Explanation{
+				d.Expl,
+				{Value: factor, Message: fmt.Sprintf("sloppyFreq(slop=%d)", slop)},
+			},
+		}
+	}
+}
+
 // checkCurrMustMatchField is soley concerned with determining if one particular
 // field within the currMust DocumentMatch Locations satisfies the phase
 // constraints (possibly more than once).  if so, the number of times it was
 // satisfied, and these locations are returned.  otherwise 0 and either
 // a nil or empty TermLocationMap
-func (s *PhraseSearcher) checkCurrMustMatchField(ctx *search.SearchContext, tlm search.TermLocationMap) (int, search.TermLocationMap) {
-	paths := findPhrasePaths(0, nil, s.terms, tlm, nil, 0)
+func (s *PhraseSearcher) checkCurrMustMatchField(ctx *search.SearchContext, tlm search.TermLocationMap) (int, search.TermLocationMap, int) {
+	paths := findPhrasePaths(0, nil, s.terms, tlm, nil, s.slop)
 	rv := make(search.TermLocationMap, len(s.terms))
+	minSlop := s.slop
 	for _, p := range paths {
 		p.MergeInto(rv)
+		if slop := p.slop(s.terms); slop < minSlop {
+			minSlop = slop
+		}
 	}
-	return len(paths), rv
+	return len(paths), rv, minSlop
 }
 
 type phrasePart struct {
@@ -234,6 +286,40 @@ func (p phrasePath) MergeInto(in search.TermLocationMap) {
 	}
 }
 
+func (p phrasePath) contains(loc *search.Location) bool {
+	for _, pp := range p {
+		if pp.loc == loc {
+			return true
+		}
+	}
+	return false
+}
+
+// slop returns the number of position moves separating the path from
+// the exact phrase, measured the same way as findPhrasePaths
+func (p phrasePath) slop(phraseTerms [][]string) int {
+	var rv int
+	var prevPos uint64
+	i := 0
+	for _, car := range phraseTerms {
+		if len(car) == 0 || (len(car) == 1 && car[0] == "") {
+			if prevPos != 0 {
+				prevPos++
+			}
+			continue
+		}
+		if i >= len(p) {
+			break
+		}
+		if prevPos != 0 {
+			rv += editDistance(prevPos+1, p[i].loc.Pos)
+		}
+		prevPos = p[i].loc.Pos
+		i++
+	}
+	return rv
+}
+
 // findPhrasePaths is a function to identify phase matches from a set of known
 // term locations.  the implementation is recursive, so care must be taken
 // with arguments and return values.
@@ -276,6 +362,10 @@ func findPhrasePaths(prevPos uint64, ap search.ArrayPositions, phraseTerms [][]s
 				// if the array positions are wrong, can't match, try next location
 				continue
 			}
+			if p.contains(loc) {
+				// a sloppy path can't use the same location twice
+				continue
+			}
 
 			// compute distance from previous phrase term
 			dist := 0
@@ -640,3 +640,68 @@ func TestFindMultiPhrasePaths(t *testing.T) {
 		}
 	}
 }
+
+func TestFindPhrasePathsSloppyRepeatedTerm(t *testing.T) {
+	tlm := search.TermLocationMap{
+		"beer": search.Locations{
+			&search.Location{
+				Pos: 3,
+			},
+		},
+	}
+
+	// the single occurrence can't match both phrase terms
+	actualPaths := findPhrasePaths(0, nil, [][]string{[]string{"beer"}, []string{"beer"}}, tlm, nil, 2)
+	if len(actualPaths) != 0 {
+		t.Fatalf("expected no paths, got %v", actualPaths)
+	}
+}
+
+func TestPhrasePathSlop(t *testing.T) {
+	tests := []struct {
+		phrase [][]string
+		path   phrasePath
+		slop   int
+	}{
+		{
+			phrase: [][]string{[]string{"one"}, []string{"two"}},
+			path: phrasePath{
+				&phrasePart{"one", &search.Location{Pos: 1}},
+				&phrasePart{"two", &search.Location{Pos: 2}},
+			},
+			slop: 0,
+		},
+		{
+			phrase: [][]string{[]string{"one"}, []string{"three"}, []string{"five"}},
+			path: phrasePath{
+				&phrasePart{"one", &search.Location{Pos: 1}},
+				&phrasePart{"three", &search.Location{Pos: 3}},
+				&phrasePart{"five", &search.Location{Pos: 5}},
+			},
+			slop: 2,
+		},
+		{
+			phrase: [][]string{[]string{"two"}, []string{"one"}},
+			path: phrasePath{
+				&phrasePart{"two", &search.Location{Pos: 2}},
+				&phrasePart{"one", &search.Location{Pos: 1}},
+			},
+			slop: 2,
+		},
+		{
+			phrase: [][]string{[]string{"one"}, nil, []string{"four"}},
+			path: phrasePath{
+				&phrasePart{"one", &search.Location{Pos: 1}},
+				&phrasePart{"four", &search.Location{Pos: 4}},
+			},
+			slop: 1,
+		},
+	}
+
+	for i, test := range tests {
+		slop := test.path.slop(test.phrase)
+		if slop != test.slop {
+			t.Errorf("expected slop %d, got %d for test %d", test.slop, slop, i)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"sort"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// Span is a range of positions of a field of a document matched by a
+// SpanSearcher.  Start is the position of the first term of the span,
+// End is one past the position of its last term.
+type Span struct {
+	Field          string
+	ArrayPositions search.ArrayPositions
+	Start          uint64
+	End            uint64
+	Terms          []SpanTerm
+}
+
+// SpanTerm is a term occurrence within a Span.
+type SpanTerm struct {
+	Term     string
+	Location *search.Location
+}
+
+func (s *Span) sameField(other *Span) bool {
+	return s.Field == other.Field && s.ArrayPositions.Equals(other.ArrayPositions)
+}
+
+// SpanSearcher is a Searcher which also reports the spans it matched
+// in the documents it returns.  Documents are only returned when at
+// least one span matched.
+type SpanSearcher interface {
+	search.Searcher
+	// Spans returns the spans matched in the document last returned
+	// by Next or Advance, ordered by start and end position
+	Spans() []*Span
+}
+
+type spansByPosition []*Span
+
+func (s spansByPosition) Len() int      { return len(s) }
+func (s spansByPosition) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s spansByPosition) Less(i, j int) bool {
+	if s[i].Start != s[j].Start {
+		return s[i].Start < s[j].Start
+	}
+	return s[i].End < s[j].End
+}
+
+// spanLocations builds the locations of the terms of the spans
+func spanLocations(spans []*Span) search.FieldTermLocationMap {
+	rv := make(search.FieldTermLocationMap)
+	seen := make(map[*search.Location]struct{})
+	for _, span := range spans {
+		for _, term := range span.Terms {
+			if _, ok := seen[term.Location]; ok {
+				continue
+			}
+			seen[term.Location] = struct{}{}
+			tlm := rv[span.Field]
+			if tlm == nil {
+				tlm = make(search.TermLocationMap)
+				rv[span.Field] = tlm
+			}
+			tlm.AddLocation(term.Term, term.Location)
+		}
+	}
+	return rv
+}
+
+// spanMatch sets the locations of the document to those of the terms
+// of the spans, and applies the boost to its score.
+func spanMatch(d *search.DocumentMatch, spans []*Span, boost float64, options search.SearcherOptions) *search.DocumentMatch {
+	d.Locations = spanLocations(spans)
+	if boost != 1 {
+		d.Score *= boost
+		if options.Explain {
+			d.Expl = &search.Explanation{
+				Value:   d.Score,
+				Message: "product of:",
+				Children: []*search.Explanation{
+					d.Expl,
+					{Value: boost, Message: "boost"},
+				},
+			}
+		}
+	}
+	return d
+}
+
+// spanCursor keeps the current document of a SpanSearcher, and its
+// spans, for the span searchers combining several others.
+type spanCursor struct {
+	searcher SpanSearcher
+	curr     *search.DocumentMatch
+	spans    []*Span
+	started  bool
+}
+
+func newSpanCursors(searchers []SpanSearcher) []*spanCursor {
+	rv := make([]*spanCursor, len(searchers))
+	for i, searcher := range searchers {
+		rv[i] = &spanCursor{searcher: searcher}
+	}
+	return rv
+}
+
+func (c *spanCursor) done() bool {
+	return c.started && c.curr == nil
+}
+
+func (c *spanCursor) next(ctx *search.SearchContext) error {
+	var err error
+	c.curr, err = c.searcher.Next(ctx)
+	c.started = true
+	c.spans = nil
+	if err != nil {
+		return err
+	}
+	if c.curr != nil {
+		c.spans = c.searcher.Spans()
+	}
+	return nil
+}
+
+// advance moves the cursor to the first document at or after ID,
+// unless it is already there
+func (c *spanCursor) advance(ctx *search.SearchContext, ID index.IndexInternalID) error {
+	if c.done() || (c.curr != nil && c.curr.IndexInternalID.Compare(ID) >= 0) {
+		return nil
+	}
+	if c.curr != nil {
+		ctx.DocumentMatchPool.Put(c.curr)
+	}
+	var err error
+	c.curr, err = c.searcher.Advance(ctx, ID)
+	c.started = true
+	c.spans = nil
+	if err != nil {
+		return err
+	}
+	if c.curr != nil {
+		c.spans = c.searcher.Spans()
+	}
+	return nil
+}
+
+// spanSearchersWeight and the other helpers below implement the parts
+// of the search.Searcher interface shared by the span searchers
+// combining several others
+func spanSearchersWeight(searchers []SpanSearcher) float64 {
+	var rv float64
+	for _, searcher := range searchers {
+		rv += searcher.Weight()
+	}
+	return rv
+}
+
+func spanSearchersSetQueryNorm(searchers []SpanSearcher, qnorm float64) {
+	for _, searcher := range searchers {
+		searcher.SetQueryNorm(qnorm)
+	}
+}
+
+func spanSearchersPoolSize(searchers []SpanSearcher) int {
+	rv := 1
+	for _, searcher := range searchers {
+		rv += searcher.DocumentMatchPoolSize()
+	}
+	return rv
+}
+
+func closeSpanSearchers(searchers []SpanSearcher) error {
+	var rv error
+	for _, searcher := range searchers {
+		err := searcher.Close()
+		if err != nil && rv == nil {
+			rv = err
+		}
+	}
+	return rv
+}
+
+func sortSpans(spans []*Span) {
+	sort.Stable(spansByPosition(spans))
+}
@@ -0,0 +1,112 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// SpanFirstSearcher matches the spans of its searcher which end
+// within the first end positions of the field.
+type SpanFirstSearcher struct {
+	indexReader index.IndexReader
+	searcher    SpanSearcher
+	end         uint64
+	boost       float64
+	options     search.SearcherOptions
+	spans       []*Span
+}
+
+func NewSpanFirstSearcher(indexReader index.IndexReader, searcher SpanSearcher, end int, boost float64, options search.SearcherOptions) (*SpanFirstSearcher, error) {
+	if end < 0 {
+		end = 0
+	}
+	return &SpanFirstSearcher{
+		indexReader: indexReader,
+		searcher:    searcher,
+		end:         uint64(end),
+		boost:       boost,
+		options:     options,
+	}, nil
+}
+
+func (s *SpanFirstSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	d, err := s.searcher.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.nextMatch(ctx, d)
+}
+
+func (s *SpanFirstSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	d, err := s.searcher.Advance(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.nextMatch(ctx, d)
+}
+
+func (s *SpanFirstSearcher) nextMatch(ctx *search.SearchContext, d *search.DocumentMatch) (*search.DocumentMatch, error) {
+	for d != nil {
+		var spans []*Span
+		for _, span := range s.searcher.Spans() {
+			// positions start at 1
+			if span.End <= s.end+1 {
+				spans = append(spans, span)
+			}
+		}
+		if len(spans) > 0 {
+			s.spans = spans
+			return spanMatch(d, spans, s.boost, s.options), nil
+		}
+		ctx.DocumentMatchPool.Put(d)
+		var err error
+		d, err = s.searcher.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.spans = nil
+	return nil, nil
+}
+
+func (s *SpanFirstSearcher) Spans() []*Span {
+	return s.spans
+}
+
+func (s *SpanFirstSearcher) Weight() float64 {
+	return s.searcher.Weight()
+}
+
+func (s *SpanFirstSearcher) SetQueryNorm(qnorm float64) {
+	s.searcher.SetQueryNorm(qnorm)
+}
+
+func (s *SpanFirstSearcher) Count() uint64 {
+	return s.searcher.Count()
+}
+
+func (s *SpanFirstSearcher) Close() error {
+	return s.searcher.Close()
+}
+
+func (s *SpanFirstSearcher) Min() int {
+	return 0
+}
+
+func (s *SpanFirstSearcher) DocumentMatchPoolSize() int {
+	return s.searcher.DocumentMatchPoolSize()
+}
@@ -0,0 +1,332 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/scorer"
+)
+
+// SpanNearSearcher matches a span made of one span of each of its
+// clauses, in the same field, not overlapping each other, and with at
+// most slop positions between them.  When inOrder is true the spans
+// must also appear in the order of the clauses.
+type SpanNearSearcher struct {
+	indexReader index.IndexReader
+	searchers   []SpanSearcher
+	cursors     []*spanCursor
+	slop        uint64
+	inOrder     bool
+	boost       float64
+	scorer      *scorer.ConjunctionQueryScorer
+	options     search.SearcherOptions
+	spans       []*Span
+	initialized bool
+}
+
+func NewSpanNearSearcher(indexReader index.IndexReader, searchers []SpanSearcher, slop int, inOrder bool, boost float64, options search.SearcherOptions) (*SpanNearSearcher, error) {
+	if len(searchers) < 1 {
+		return nil, fmt.Errorf("span near searcher needs at least one clause")
+	}
+	if slop < 0 {
+		return nil, fmt.Errorf("span near searcher slop must not be negative")
+	}
+	return &SpanNearSearcher{
+		indexReader: indexReader,
+		searchers:   searchers,
+		cursors:     newSpanCursors(searchers),
+		slop:        uint64(slop),
+		inOrder:     inOrder,
+		boost:       boost,
+		scorer:      scorer.NewConjunctionQueryScorer(options),
+		options:     options,
+	}, nil
+}
+
+func (s *SpanNearSearcher) initSearchers(ctx *search.SearchContext) error {
+	for _, cursor := range s.cursors {
+		err := cursor.next(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	s.initialized = true
+	return nil
+}
+
+func (s *SpanNearSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	if !s.initialized {
+		err := s.initSearchers(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.nextMatch(ctx)
+}
+
+func (s *SpanNearSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	s.initialized = true
+	for _, cursor := range s.cursors {
+		err := cursor.advance(ctx, ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.nextMatch(ctx)
+}
+
+// nextMatch returns the first document at or after the current
+// documents of the clauses which has near spans
+func (s *SpanNearSearcher) nextMatch(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	for {
+		// align all the clauses on the same document
+		var max index.IndexInternalID
+		for _, cursor := range s.cursors {
+			if cursor.curr == nil {
+				s.spans = nil
+				return nil, nil
+			}
+			if max == nil || cursor.curr.IndexInternalID.Compare(max) > 0 {
+				max = cursor.curr.IndexInternalID
+			}
+		}
+		aligned := true
+		for _, cursor := range s.cursors {
+			if cursor.curr.IndexInternalID.Compare(max) < 0 {
+				err := cursor.advance(ctx, max)
+				if err != nil {
+					return nil, err
+				}
+				aligned = false
+				break
+			}
+		}
+		if !aligned {
+			continue
+		}
+
+		spans := s.nearSpans()
+		constituents := make([]*search.DocumentMatch, len(s.cursors))
+		for i, cursor := range s.cursors {
+			constituents[i] = cursor.curr
+		}
+		var rv *search.DocumentMatch
+		if len(spans) > 0 {
+			rv = s.scorer.Score(ctx, constituents)
+			rv = spanMatch(rv, spans, s.boost, s.options)
+			s.spans = spans
+		}
+		for i, cursor := range s.cursors {
+			if constituents[i] != rv {
+				ctx.DocumentMatchPool.Put(constituents[i])
+			}
+			cursor.curr = nil
+			err := cursor.next(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if rv != nil {
+			return rv, nil
+		}
+	}
+}
+
+// nearSpans finds the spans made of a span of each clause satisfying
+// the constraints, in the current document.  Like the near spans of
+// Lucene, it walks the sorted spans of the clauses rather than trying
+// every combination of them: in order, each span of the first clause
+// is followed by the first span of each clause starting after the
+// previous one, and otherwise the clause whose current span starts
+// first is advanced after each candidate.
+func (s *SpanNearSearcher) nearSpans() []*Span {
+	var rv []*Span
+	for _, group := range s.spansByField() {
+		if s.inOrder {
+			rv = s.orderedSpans(group, rv)
+		} else {
+			rv = s.unorderedSpans(group, rv)
+		}
+	}
+	sortSpans(rv)
+	return rv
+}
+
+// spansByField splits the spans of each clause by field and array
+// positions, keeping only the fields in which every clause has spans
+func (s *SpanNearSearcher) spansByField() [][][]*Span {
+	var rv [][][]*Span
+	for i, cursor := range s.cursors {
+	SPANS:
+		for _, span := range cursor.spans {
+			for _, group := range rv {
+				if group[0][0].sameField(span) {
+					group[i] = append(group[i], span)
+					continue SPANS
+				}
+			}
+			if i == 0 {
+				group := make([][]*Span, len(s.cursors))
+				group[0] = []*Span{span}
+				rv = append(rv, group)
+			}
+		}
+	}
+	complete := rv[:0]
+	for _, group := range rv {
+		if len(group[len(group)-1]) > 0 {
+			complete = append(complete, group)
+		}
+	}
+	return complete
+}
+
+// orderedSpans appends the spans starting with each span of the first
+// clause, followed by the first span of each following clause starting
+// after the end of the previous one
+func (s *SpanNearSearcher) orderedSpans(group [][]*Span, rv []*Span) []*Span {
+	chosen := make([]*Span, len(group))
+	seen := make(map[[2]uint64]struct{})
+SPANS:
+	for _, first := range group[0] {
+		chosen[0] = first
+		for i := 1; i < len(group); i++ {
+			spans := group[i]
+			end := chosen[i-1].End
+			j := sort.Search(len(spans), func(j int) bool {
+				return spans[j].Start >= end
+			})
+			if j == len(spans) {
+				continue SPANS
+			}
+			chosen[i] = spans[j]
+		}
+		rv = s.appendSpan(rv, seen, s.combine(chosen))
+	}
+	return rv
+}
+
+// unorderedSpans appends the spans made of the current span of each
+// clause, starting with the first span of each clause and advancing the
+// clause of the span which starts first
+func (s *SpanNearSearcher) unorderedSpans(group [][]*Span, rv []*Span) []*Span {
+	positions := make([]int, len(group))
+	chosen := make([]*Span, len(group))
+	seen := make(map[[2]uint64]struct{})
+	for {
+		min := 0
+		for i, spans := range group {
+			if positions[i] == len(spans) {
+				return rv
+			}
+			chosen[i] = spans[positions[i]]
+			if spansByPosition(chosen).Less(i, min) {
+				min = i
+			}
+		}
+		rv = s.appendSpan(rv, seen, s.combine(chosen))
+		positions[min]++
+	}
+}
+
+// appendSpan appends span unless it is nil or a span with the same
+// positions was already found
+func (s *SpanNearSearcher) appendSpan(rv []*Span, seen map[[2]uint64]struct{}, span *Span) []*Span {
+	if span == nil {
+		return rv
+	}
+	key := [2]uint64{span.Start, span.End}
+	if _, ok := seen[key]; ok {
+		return rv
+	}
+	seen[key] = struct{}{}
+	return append(rv, span)
+}
+
+// combine returns the span covering the spans, or nil if they do not
+// satisfy the constraints
+func (s *SpanNearSearcher) combine(spans []*Span) *Span {
+	ordered := spans
+	if !s.inOrder {
+		ordered = make([]*Span, len(spans))
+		copy(ordered, spans)
+		sortSpans(ordered)
+	}
+	for i := 1; i < len(ordered); i++ {
+		if !ordered[i].sameField(ordered[0]) || ordered[i].Start < ordered[i-1].End {
+			return nil
+		}
+	}
+	if gaps(ordered) > s.slop {
+		return nil
+	}
+	rv := &Span{
+		Field:          ordered[0].Field,
+		ArrayPositions: ordered[0].ArrayPositions,
+		Start:          ordered[0].Start,
+		End:            ordered[len(ordered)-1].End,
+	}
+	for _, span := range spans {
+		rv.Terms = append(rv.Terms, span.Terms...)
+	}
+	return rv
+}
+
+// gaps is the number of positions between consecutive spans, which
+// are ordered and do not overlap
+func gaps(spans []*Span) uint64 {
+	var rv uint64
+	for i := 1; i < len(spans); i++ {
+		rv += spans[i].Start - spans[i-1].End
+	}
+	return rv
+}
+
+func (s *SpanNearSearcher) Spans() []*Span {
+	return s.spans
+}
+
+func (s *SpanNearSearcher) Weight() float64 {
+	return spanSearchersWeight(s.searchers)
+}
+
+func (s *SpanNearSearcher) SetQueryNorm(qnorm float64) {
+	spanSearchersSetQueryNorm(s.searchers, qnorm)
+}
+
+func (s *SpanNearSearcher) Count() uint64 {
+	// for now return a worst case
+	var rv uint64
+	for _, searcher := range s.searchers {
+		rv += searcher.Count()
+	}
+	return rv
+}
+
+func (s *SpanNearSearcher) Close() error {
+	return closeSpanSearchers(s.searchers)
+}
+
+func (s *SpanNearSearcher) Min() int {
+	return 0
+}
+
+func (s *SpanNearSearcher) DocumentMatchPoolSize() int {
+	return spanSearchersPoolSize(s.searchers)
+}
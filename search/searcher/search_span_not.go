@@ -0,0 +1,137 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// SpanNotSearcher matches the spans of its include searcher which do
+// not overlap any span of its exclude searcher, nor come within pre
+// positions before or post positions after one.
+type SpanNotSearcher struct {
+	indexReader index.IndexReader
+	include     SpanSearcher
+	exclude     *spanCursor
+	pre         uint64
+	post        uint64
+	boost       float64
+	options     search.SearcherOptions
+	spans       []*Span
+}
+
+func NewSpanNotSearcher(indexReader index.IndexReader, include, exclude SpanSearcher, pre, post int, boost float64, options search.SearcherOptions) (*SpanNotSearcher, error) {
+	if pre < 0 {
+		pre = 0
+	}
+	if post < 0 {
+		post = 0
+	}
+	return &SpanNotSearcher{
+		indexReader: indexReader,
+		include:     include,
+		exclude:     &spanCursor{searcher: exclude},
+		pre:         uint64(pre),
+		post:        uint64(post),
+		boost:       boost,
+		options:     options,
+	}, nil
+}
+
+func (s *SpanNotSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	d, err := s.include.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.nextMatch(ctx, d)
+}
+
+func (s *SpanNotSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	d, err := s.include.Advance(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.nextMatch(ctx, d)
+}
+
+// nextMatch returns the first document from d on which some included
+// spans are not excluded
+func (s *SpanNotSearcher) nextMatch(ctx *search.SearchContext, d *search.DocumentMatch) (*search.DocumentMatch, error) {
+	for d != nil {
+		err := s.exclude.advance(ctx, d.IndexInternalID)
+		if err != nil {
+			return nil, err
+		}
+		spans := s.include.Spans()
+		if s.exclude.curr != nil && s.exclude.curr.IndexInternalID.Equals(d.IndexInternalID) {
+			spans = s.filter(spans, s.exclude.spans)
+		}
+		if len(spans) > 0 {
+			s.spans = spans
+			return spanMatch(d, spans, s.boost, s.options), nil
+		}
+		ctx.DocumentMatchPool.Put(d)
+		d, err = s.include.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.spans = nil
+	return nil, nil
+}
+
+func (s *SpanNotSearcher) filter(spans, excluded []*Span) []*Span {
+	var rv []*Span
+OUTER:
+	for _, span := range spans {
+		for _, exclude := range excluded {
+			if span.sameField(exclude) &&
+				exclude.Start < span.End+s.post && exclude.End+s.pre > span.Start {
+				continue OUTER
+			}
+		}
+		rv = append(rv, span)
+	}
+	return rv
+}
+
+func (s *SpanNotSearcher) Spans() []*Span {
+	return s.spans
+}
+
+func (s *SpanNotSearcher) Weight() float64 {
+	return s.include.Weight()
+}
+
+func (s *SpanNotSearcher) SetQueryNorm(qnorm float64) {
+	s.include.SetQueryNorm(qnorm)
+}
+
+func (s *SpanNotSearcher) Count() uint64 {
+	return s.include.Count()
+}
+
+func (s *SpanNotSearcher) Close() error {
+	return closeSpanSearchers([]SpanSearcher{s.include, s.exclude.searcher})
+}
+
+func (s *SpanNotSearcher) Min() int {
+	return 0
+}
+
+func (s *SpanNotSearcher) DocumentMatchPoolSize() int {
+	return spanSearchersPoolSize([]SpanSearcher{s.include, s.exclude.searcher})
+}
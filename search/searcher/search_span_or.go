@@ -0,0 +1,151 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/scorer"
+)
+
+// SpanOrSearcher matches the spans of any of its clauses.
+type SpanOrSearcher struct {
+	indexReader index.IndexReader
+	searchers   []SpanSearcher
+	cursors     []*spanCursor
+	boost       float64
+	scorer      *scorer.DisjunctionQueryScorer
+	options     search.SearcherOptions
+	spans       []*Span
+	initialized bool
+}
+
+func NewSpanOrSearcher(indexReader index.IndexReader, searchers []SpanSearcher, boost float64, options search.SearcherOptions) (*SpanOrSearcher, error) {
+	return &SpanOrSearcher{
+		indexReader: indexReader,
+		searchers:   searchers,
+		cursors:     newSpanCursors(searchers),
+		boost:       boost,
+		scorer:      scorer.NewDisjunctionQueryScorer(options),
+		options:     options,
+	}, nil
+}
+
+func (s *SpanOrSearcher) initSearchers(ctx *search.SearchContext) error {
+	for _, cursor := range s.cursors {
+		err := cursor.next(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	s.initialized = true
+	return nil
+}
+
+func (s *SpanOrSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	if !s.initialized {
+		err := s.initSearchers(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.nextMatch(ctx)
+}
+
+func (s *SpanOrSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	s.initialized = true
+	for _, cursor := range s.cursors {
+		err := cursor.advance(ctx, ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.nextMatch(ctx)
+}
+
+// nextMatch returns the smallest of the current documents of the
+// clauses, with the spans of all the clauses on that document
+func (s *SpanOrSearcher) nextMatch(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	var min index.IndexInternalID
+	for _, cursor := range s.cursors {
+		if cursor.curr != nil && (min == nil || cursor.curr.IndexInternalID.Compare(min) < 0) {
+			min = cursor.curr.IndexInternalID
+		}
+	}
+	if min == nil {
+		s.spans = nil
+		return nil, nil
+	}
+
+	var constituents []*search.DocumentMatch
+	var matching []*spanCursor
+	var spans []*Span
+	for _, cursor := range s.cursors {
+		if cursor.curr != nil && cursor.curr.IndexInternalID.Equals(min) {
+			constituents = append(constituents, cursor.curr)
+			matching = append(matching, cursor)
+			spans = append(spans, cursor.spans...)
+		}
+	}
+	sortSpans(spans)
+	s.spans = spans
+
+	rv := s.scorer.Score(ctx, constituents, len(constituents), len(s.cursors))
+	rv = spanMatch(rv, spans, s.boost, s.options)
+	for i, cursor := range matching {
+		if constituents[i] != rv {
+			ctx.DocumentMatchPool.Put(constituents[i])
+		}
+		cursor.curr = nil
+		err := cursor.next(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+func (s *SpanOrSearcher) Spans() []*Span {
+	return s.spans
+}
+
+func (s *SpanOrSearcher) Weight() float64 {
+	return spanSearchersWeight(s.searchers)
+}
+
+func (s *SpanOrSearcher) SetQueryNorm(qnorm float64) {
+	spanSearchersSetQueryNorm(s.searchers, qnorm)
+}
+
+func (s *SpanOrSearcher) Count() uint64 {
+	// for now return a worst case
+	var rv uint64
+	for _, searcher := range s.searchers {
+		rv += searcher.Count()
+	}
+	return rv
+}
+
+func (s *SpanOrSearcher) Close() error {
+	return closeSpanSearchers(s.searchers)
+}
+
+func (s *SpanOrSearcher) Min() int {
+	return 0
+}
+
+func (s *SpanOrSearcher) DocumentMatchPoolSize() int {
+	return spanSearchersPoolSize(s.searchers)
+}
@@ -0,0 +1,114 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+// SpanTermSearcher matches a span for each occurrence of a term, it
+// relies on the term vectors of the field.
+type SpanTermSearcher struct {
+	searcher *TermSearcher
+	term     string
+	field    string
+	spans    []*Span
+}
+
+func NewSpanTermSearcher(indexReader index.IndexReader, term string, field string, boost float64, options search.SearcherOptions) (*SpanTermSearcher, error) {
+	options.IncludeTermVectors = true
+	searcher, err := NewTermSearcher(indexReader, term, field, boost, options)
+	if err != nil {
+		return nil, err
+	}
+	return &SpanTermSearcher{
+		searcher: searcher,
+		term:     term,
+		field:    field,
+	}, nil
+}
+
+func (s *SpanTermSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	for {
+		d, err := s.searcher.Next(ctx)
+		if err != nil || d == nil {
+			s.spans = nil
+			return nil, err
+		}
+		if s.buildSpans(d) {
+			return d, nil
+		}
+		ctx.DocumentMatchPool.Put(d)
+	}
+}
+
+func (s *SpanTermSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	d, err := s.searcher.Advance(ctx, ID)
+	if err != nil || d == nil {
+		s.spans = nil
+		return nil, err
+	}
+	if s.buildSpans(d) {
+		return d, nil
+	}
+	ctx.DocumentMatchPool.Put(d)
+	return s.Next(ctx)
+}
+
+// buildSpans records a span for each location of the term in the
+// document, and returns whether there was any
+func (s *SpanTermSearcher) buildSpans(d *search.DocumentMatch) bool {
+	s.spans = nil
+	for _, loc := range d.Locations[s.field][s.term] {
+		s.spans = append(s.spans, &Span{
+			Field:          s.field,
+			ArrayPositions: loc.ArrayPositions,
+			Start:          loc.Pos,
+			End:            loc.Pos + 1,
+			Terms:          []SpanTerm{{Term: s.term, Location: loc}},
+		})
+	}
+	sortSpans(s.spans)
+	return len(s.spans) > 0
+}
+
+func (s *SpanTermSearcher) Spans() []*Span {
+	return s.spans
+}
+
+func (s *SpanTermSearcher) Weight() float64 {
+	return s.searcher.Weight()
+}
+
+func (s *SpanTermSearcher) SetQueryNorm(qnorm float64) {
+	s.searcher.SetQueryNorm(qnorm)
+}
+
+func (s *SpanTermSearcher) Count() uint64 {
+	return s.searcher.Count()
+}
+
+func (s *SpanTermSearcher) Close() error {
+	return s.searcher.Close()
+}
+
+func (s *SpanTermSearcher) Min() int {
+	return 0
+}
+
+func (s *SpanTermSearcher) DocumentMatchPoolSize() int {
+	return s.searcher.DocumentMatchPoolSize()
+}
@@ -0,0 +1,358 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edwindvinas/bleve/document"
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/index/store/gtreap"
+	"github.com/edwindvinas/bleve/index/upsidedown"
+	"github.com/edwindvinas/bleve/search"
+)
+
+func TestSpanSearchers(t *testing.T) {
+	analysisQueue := index.NewAnalysisQueue(1)
+	i, err := upsidedown.NewUpsideDownCouch(
+		gtreap.Name,
+		map[string]interface{}{
+			"path": "",
+		},
+		analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = i.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := map[string]string{
+		"1": "the contract includes a termination clause",
+		"2": "termination of the contract",
+		"3": "contract renewal and extension terms apply before any termination",
+		"4": "no relevant words",
+	}
+	for id, body := range docs {
+		err = i.Update(document.NewDocument(id).
+			AddField(document.NewTextFieldCustom("body", []uint64{}, []byte(body),
+				twoDocIndexDescIndexingOptions, testAnalyzer)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	indexReader, err := i.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err = indexReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	options := search.SearcherOptions{Explain: true}
+	term := func(term string) SpanSearcher {
+		rv, err := NewSpanTermSearcher(indexReader, term, "body", 1.0, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rv
+	}
+	near := func(slop int, inOrder bool, clauses ...SpanSearcher) SpanSearcher {
+		rv, err := NewSpanNearSearcher(indexReader, clauses, slop, inOrder, 1.0, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rv
+	}
+	or := func(clauses ...SpanSearcher) SpanSearcher {
+		rv, err := NewSpanOrSearcher(indexReader, clauses, 1.0, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rv
+	}
+	not := func(include, exclude SpanSearcher, pre, post int) SpanSearcher {
+		rv, err := NewSpanNotSearcher(indexReader, include, exclude, pre, post, 1.0, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rv
+	}
+	first := func(match SpanSearcher, end int) SpanSearcher {
+		rv, err := NewSpanFirstSearcher(indexReader, match, end, 1.0, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rv
+	}
+
+	tests := []struct {
+		searcher SpanSearcher
+		want     map[string][][2]uint64
+	}{
+		{
+			searcher: term("contract"),
+			want: map[string][][2]uint64{
+				"1": {{2, 3}},
+				"2": {{4, 5}},
+				"3": {{1, 2}},
+			},
+		},
+		{
+			searcher: near(5, true, term("contract"), term("termination")),
+			want: map[string][][2]uint64{
+				"1": {{2, 6}},
+			},
+		},
+		{
+			searcher: near(1, true, term("contract"), term("termination")),
+			want:     map[string][][2]uint64{},
+		},
+		{
+			searcher: near(5, false, term("contract"), term("termination")),
+			want: map[string][][2]uint64{
+				"1": {{2, 6}},
+				"2": {{1, 5}},
+			},
+		},
+		{
+			searcher: near(0, true, near(5, true, term("contract"), term("termination")), term("clause")),
+			want: map[string][][2]uint64{
+				"1": {{2, 7}},
+			},
+		},
+		{
+			searcher: or(near(5, true, term("contract"), term("termination")), term("renewal")),
+			want: map[string][][2]uint64{
+				"1": {{2, 6}},
+				"3": {{2, 3}},
+			},
+		},
+		{
+			searcher: not(term("contract"), term("the"), 1, 0),
+			want: map[string][][2]uint64{
+				"3": {{1, 2}},
+			},
+		},
+		{
+			searcher: not(term("termination"), term("contract"), 0, 0),
+			want: map[string][][2]uint64{
+				"1": {{5, 6}},
+				"2": {{1, 2}},
+				"3": {{9, 10}},
+			},
+		},
+		{
+			searcher: first(term("contract"), 2),
+			want: map[string][][2]uint64{
+				"1": {{2, 3}},
+				"3": {{1, 2}},
+			},
+		},
+		{
+			searcher: first(near(5, false, term("contract"), term("termination")), 4),
+			want: map[string][][2]uint64{
+				"2": {{1, 5}},
+			},
+		},
+	}
+
+	for testIndex, test := range tests {
+		ctx := &search.SearchContext{
+			DocumentMatchPool: search.NewDocumentMatchPool(test.searcher.DocumentMatchPoolSize(), 0),
+		}
+		got := make(map[string][][2]uint64)
+		next, err := test.searcher.Next(ctx)
+		for err == nil && next != nil {
+			id, err := indexReader.ExternalID(next.IndexInternalID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, span := range test.searcher.Spans() {
+				got[id] = append(got[id], [2]uint64{span.Start, span.End})
+				for _, term := range span.Terms {
+					if _, ok := next.Locations["body"][term.Term]; !ok {
+						t.Errorf("test %d: expected location of %s in %s", testIndex, term.Term, id)
+					}
+				}
+			}
+			if next.Score <= 0 {
+				t.Errorf("test %d: expected a positive score for %s", testIndex, id)
+			}
+			ctx.DocumentMatchPool.Put(next)
+			next, err = test.searcher.Next(ctx)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("test %d: expected %v, got %v", testIndex, test.want, got)
+		}
+		err = test.searcher.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSpanNearSearcherAdvance(t *testing.T) {
+	twoDocIndexReader, err := twoDocIndex.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := twoDocIndexReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	var clauses []SpanSearcher
+	for _, term := range []string{"beer", "beer"} {
+		ts, err := NewSpanTermSearcher(twoDocIndexReader, term, "desc", 1.0, search.SearcherOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		clauses = append(clauses, ts)
+	}
+	searcher, err := NewSpanNearSearcher(twoDocIndexReader, clauses, 0, true, 1.0, search.SearcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := searcher.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	ctx := &search.SearchContext{
+		DocumentMatchPool: search.NewDocumentMatchPool(searcher.DocumentMatchPoolSize(), 0),
+	}
+	// only 1 and 4 have beer twice in a row
+	next, err := searcher.Advance(ctx, index.IndexInternalID("2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == nil || !next.IndexInternalID.Equals(index.IndexInternalID("4")) {
+		t.Fatalf("expected to advance to 4, got %v", next)
+	}
+	if len(searcher.Spans()) != 64 {
+		t.Errorf("expected 64 spans, got %d", len(searcher.Spans()))
+	}
+	next, err = searcher.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != nil {
+		t.Errorf("expected no more matches, got %v", next)
+	}
+}
+
+func TestSpanNearSearcherManySpans(t *testing.T) {
+	analysisQueue := index.NewAnalysisQueue(1)
+	i, err := upsidedown.NewUpsideDownCouch(
+		gtreap.Name,
+		map[string]interface{}{
+			"path": "",
+		},
+		analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = i.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := strings.Repeat("alpha beta gamma delta ", 200)
+	err = i.Update(document.NewDocument("1").
+		AddField(document.NewTextFieldCustom("body", []uint64{}, []byte(body),
+			twoDocIndexDescIndexingOptions, testAnalyzer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexReader, err := i.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err = indexReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// trying every combination of spans would take 200^4 steps
+	tests := []struct {
+		inOrder bool
+		terms   []string
+		spans   int
+	}{
+		{
+			inOrder: true,
+			terms:   []string{"alpha", "beta", "gamma", "delta"},
+			spans:   200,
+		},
+		{
+			inOrder: false,
+			terms:   []string{"delta", "gamma", "beta", "alpha"},
+			spans:   797,
+		},
+	}
+	for _, test := range tests {
+		var clauses []SpanSearcher
+		for _, term := range test.terms {
+			ts, err := NewSpanTermSearcher(indexReader, term, "body", 1.0, search.SearcherOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			clauses = append(clauses, ts)
+		}
+		searcher, err := NewSpanNearSearcher(indexReader, clauses, 0, test.inOrder, 1.0, search.SearcherOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := &search.SearchContext{
+			DocumentMatchPool: search.NewDocumentMatchPool(searcher.DocumentMatchPoolSize(), 0),
+		}
+		next, err := searcher.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next == nil {
+			t.Fatalf("expected a match in order %t", test.inOrder)
+		}
+		spans := searcher.Spans()
+		if len(spans) != test.spans {
+			t.Errorf("expected %d spans in order %t, got %d", test.spans, test.inOrder, len(spans))
+		}
+		for _, span := range spans {
+			if span.End-span.Start != 4 {
+				t.Errorf("expected spans of 4 terms, got %d-%d", span.Start, span.End)
+			}
+		}
+		err = searcher.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -23,7 +23,7 @@ import (
 type TermSearcher struct {
 	indexReader index.IndexReader
 	reader      index.TermFieldReader
-	scorer      *scorer.TermQueryScorer
+	scorer      search.TermScorer
 	tfd         index.TermFieldDoc
 }
 
@@ -32,17 +32,7 @@ func NewTermSearcher(indexReader index.IndexReader, term string, field string, b
 	if err != nil {
 		return nil, err
 	}
-	count, err := indexReader.DocCount()
-	if err != nil {
-		_ = reader.Close()
-		return nil, err
-	}
-	scorer := scorer.NewTermQueryScorer([]byte(term), field, boost, count, reader.Count(), options)
-	return &TermSearcher{
-		indexReader: indexReader,
-		reader:      reader,
-		scorer:      scorer,
-	}, nil
+	return newTermSearcherFromReader(indexReader, reader, []byte(term), field, boost, options)
 }
 
 func NewTermSearcherBytes(indexReader index.IndexReader, term []byte, field string, boost float64, options search.SearcherOptions) (*TermSearcher, error) {
@@ -50,19 +40,39 @@ func NewTermSearcherBytes(indexReader index.IndexReader, term []byte, field stri
 	if err != nil {
 		return nil, err
 	}
-	count, err := indexReader.DocCount()
+	return newTermSearcherFromReader(indexReader, reader, term, field, boost, options)
+}
+
+func newTermSearcherFromReader(indexReader index.IndexReader, reader index.TermFieldReader, term []byte, field string, boost float64, options search.SearcherOptions) (*TermSearcher, error) {
+	termScorer, err := newTermScorer(indexReader, reader, term, field, boost, options)
 	if err != nil {
 		_ = reader.Close()
 		return nil, err
 	}
-	scorer := scorer.NewTermQueryScorer(term, field, boost, count, reader.Count(), options)
 	return &TermSearcher{
 		indexReader: indexReader,
 		reader:      reader,
-		scorer:      scorer,
+		scorer:      termScorer,
 	}, nil
 }
 
+// newTermScorer builds the scorer for a term, using the similarity
+// configured for the field if there is one, and the default tf-idf
+// scorer otherwise.
+func newTermScorer(indexReader index.IndexReader, reader index.TermFieldReader, term []byte, field string, boost float64, options search.SearcherOptions) (search.TermScorer, error) {
+	if options.Similarities != nil {
+		similarity := options.Similarities.SimilarityForField(field)
+		if similarity != nil {
+			return similarity.TermScorer(indexReader, term, field, boost, reader.Count(), options)
+		}
+	}
+	count, err := indexReader.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	return scorer.NewTermQueryScorer(term, field, boost, count, reader.Count(), options), nil
+}
+
 func (s *TermSearcher) Count() uint64 {
 	return s.reader.Count()
 }
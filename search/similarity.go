@@ -0,0 +1,42 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"github.com/edwindvinas/bleve/index"
+)
+
+// A TermScorer computes the score of documents matching a single term
+// in a single field.
+type TermScorer interface {
+	Weight() float64
+	SetQueryNorm(qnorm float64)
+	Score(ctx *SearchContext, termMatch *index.TermFieldDoc) *DocumentMatch
+}
+
+// A Similarity describes how documents matching a term are scored.
+type Similarity interface {
+	// TermScorer returns a TermScorer for the term in the field.
+	// docTerm is the number of documents containing the term.
+	TermScorer(indexReader index.IndexReader, queryTerm []byte,
+		queryField string, queryBoost float64, docTerm uint64,
+		options SearcherOptions) (TermScorer, error)
+}
+
+// A SimilarityLookup resolves the Similarity to use when scoring a field.
+// A nil Similarity selects the default TF-IDF scoring.
+type SimilarityLookup interface {
+	SimilarityForField(field string) Similarity
+}
@@ -0,0 +1,85 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bm25 implements the Okapi BM25 similarity.
+//
+// The average field length is read from the index when it supports
+// index.IndexReaderFieldStats, otherwise length normalization is disabled.
+package bm25
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/registry"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/scorer"
+)
+
+const Name = "bm25"
+
+const DefaultK1 = 1.2
+const DefaultB = 0.75
+
+type Similarity struct {
+	k1 float64
+	b  float64
+}
+
+func New(k1, b float64) *Similarity {
+	return &Similarity{
+		k1: k1,
+		b:  b,
+	}
+}
+
+func (s *Similarity) TermScorer(indexReader index.IndexReader, queryTerm []byte, queryField string, queryBoost float64, docTerm uint64, options search.SearcherOptions) (search.TermScorer, error) {
+	count, err := indexReader.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	var avgFieldLength float64
+	if fsr, ok := indexReader.(index.IndexReaderFieldStats); ok {
+		stats, err := fsr.FieldStats(queryField)
+		if err != nil {
+			return nil, err
+		}
+		avgFieldLength = stats.AvgLength()
+	}
+	return scorer.NewBM25TermQueryScorer(queryTerm, queryField, queryBoost, count, docTerm, avgFieldLength, s.k1, s.b, options), nil
+}
+
+func Constructor(config map[string]interface{}, cache *registry.Cache) (search.Similarity, error) {
+	k1 := DefaultK1
+	b := DefaultB
+
+	if k1Val, ok := config["k1"]; ok {
+		k1, ok = k1Val.(float64)
+		if !ok || k1 < 0 {
+			return nil, fmt.Errorf("bm25 k1 must be a non-negative number")
+		}
+	}
+	if bVal, ok := config["b"]; ok {
+		b, ok = bVal.(float64)
+		if !ok || b < 0 || b > 1 {
+			return nil, fmt.Errorf("bm25 b must be a number between 0 and 1")
+		}
+	}
+
+	return New(k1, b), nil
+}
+
+func init() {
+	registry.RegisterSimilarity(Name, Constructor)
+}
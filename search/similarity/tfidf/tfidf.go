@@ -0,0 +1,47 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfidf
+
+import (
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/registry"
+	"github.com/edwindvinas/bleve/search"
+	"github.com/edwindvinas/bleve/search/scorer"
+)
+
+const Name = "tfidf"
+
+// Similarity is the classic TF-IDF scoring used by default.
+type Similarity struct{}
+
+func New() *Similarity {
+	return &Similarity{}
+}
+
+func (s *Similarity) TermScorer(indexReader index.IndexReader, queryTerm []byte, queryField string, queryBoost float64, docTerm uint64, options search.SearcherOptions) (search.TermScorer, error) {
+	count, err := indexReader.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	return scorer.NewTermQueryScorer(queryTerm, queryField, queryBoost, count, docTerm, options), nil
+}
+
+func Constructor(config map[string]interface{}, cache *registry.Cache) (search.Similarity, error) {
+	return New(), nil
+}
+
+func init() {
+	registry.RegisterSimilarity(Name, Constructor)
+}
@@ -38,6 +38,18 @@ type SearchSort interface {
 	RequiresFields() []string
 }
 
+// ReversibleSearchSort is implemented by the search sorts which can be
+// reversed, as needed to search before a hit. All of the search sorts of
+// this package implement it.
+type ReversibleSearchSort interface {
+	SearchSort
+
+	// Reverse reverses the order of the sort
+	Reverse()
+	// Copy returns a copy of the sort, so it can be reversed
+	Copy() SearchSort
+}
+
 func ParseSearchSortObj(input map[string]interface{}) (SearchSort, error) {
 	descending, ok := input["desc"].(bool)
 	by, ok := input["by"].(string)
@@ -240,6 +252,33 @@ func (so SortOrder) Compare(cachedScoring, cachedDesc []bool, i, j *DocumentMatc
 	return -1
 }
 
+// Reversible returns an error if one of the search sorts
+// cannot be reversed
+func (so SortOrder) Reversible() error {
+	for _, soi := range so {
+		if _, ok := soi.(ReversibleSearchSort); !ok {
+			return fmt.Errorf("search sort %T cannot be reversed", soi)
+		}
+	}
+	return nil
+}
+
+// Reversed returns a copy of the sort order, with each
+// of the search sorts reversed
+func (so SortOrder) Reversed() (SortOrder, error) {
+	err := so.Reversible()
+	if err != nil {
+		return nil, err
+	}
+	rv := make(SortOrder, len(so))
+	for i, soi := range so {
+		rsoi := soi.(ReversibleSearchSort).Copy().(ReversibleSearchSort)
+		rsoi.Reverse()
+		rv[i] = rsoi
+	}
+	return rv, nil
+}
+
 func (so SortOrder) RequiresScore() bool {
 	rv := false
 	for _, soi := range so {
@@ -475,6 +514,24 @@ func (s *SortField) MarshalJSON() ([]byte, error) {
 	return json.Marshal(sfm)
 }
 
+// Reverse reverses the order of this sort, missing values
+// remain at the same position relative to the other values
+func (s *SortField) Reverse() {
+	s.Desc = !s.Desc
+	if s.Missing == SortFieldMissingFirst {
+		s.Missing = SortFieldMissingLast
+	} else {
+		s.Missing = SortFieldMissingFirst
+	}
+}
+
+// Copy returns a copy of this SortField
+func (s *SortField) Copy() SearchSort {
+	rv := *s
+	rv.values = nil
+	return &rv
+}
+
 // SortDocID will sort results by the document identifier
 type SortDocID struct {
 	Desc bool
@@ -512,6 +569,17 @@ func (s *SortDocID) MarshalJSON() ([]byte, error) {
 	return json.Marshal("_id")
 }
 
+// Reverse reverses the order of this sort
+func (s *SortDocID) Reverse() {
+	s.Desc = !s.Desc
+}
+
+// Copy returns a copy of this SortDocID
+func (s *SortDocID) Copy() SearchSort {
+	rv := *s
+	return &rv
+}
+
 // SortScore will sort results by the document match score
 type SortScore struct {
 	Desc bool
@@ -523,7 +591,8 @@ func (s *SortScore) UpdateVisitor(field string, term []byte) {
 
 }
 
-// Value returns the sort value of the DocumentMatch
+// Value returns the sort value of the DocumentMatch, a placeholder
+// replaced by the score itself in the sort values of the hits returned
 func (s *SortScore) Value(i *DocumentMatch) string {
 	return "_score"
 }
@@ -549,6 +618,17 @@ func (s *SortScore) MarshalJSON() ([]byte, error) {
 	return json.Marshal("_score")
 }
 
+// Reverse reverses the order of this sort
+func (s *SortScore) Reverse() {
+	s.Desc = !s.Desc
+}
+
+// Copy returns a copy of this SortScore
+func (s *SortScore) Copy() SearchSort {
+	rv := *s
+	return &rv
+}
+
 var maxDistance = string(numeric.MustNewPrefixCodedInt64(math.MaxInt64, 0))
 
 // NewSortGeoDistance creates SearchSort instance for sorting documents by
@@ -675,3 +755,15 @@ func (s *SortGeoDistance) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(sfm)
 }
+
+// Reverse reverses the order of this sort
+func (s *SortGeoDistance) Reverse() {
+	s.Desc = !s.Desc
+}
+
+// Copy returns a copy of this SortGeoDistance
+func (s *SortGeoDistance) Copy() SearchSort {
+	rv := *s
+	rv.values = nil
+	return &rv
+}
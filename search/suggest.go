@@ -0,0 +1,327 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"math"
+	"sort"
+
+	"github.com/edwindvinas/bleve/index"
+)
+
+const (
+	// SuggestModeMissing only suggests corrections for terms which
+	// are not in the index.
+	SuggestModeMissing = "missing"
+	// SuggestModePopular only suggests corrections which occur in more
+	// documents than the original term.
+	SuggestModePopular = "popular"
+	// SuggestModeAlways suggests corrections for every term.
+	SuggestModeAlways = "always"
+)
+
+// phraseBeamWidth limits the number of partial rewrites kept while
+// building phrase suggestions.
+const phraseBeamWidth = 32
+
+// SuggestOption is a single proposed correction. For term suggestions
+// Freq is the number of documents containing the term.
+type SuggestOption struct {
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+	Freq  uint64  `json:"freq,omitempty"`
+}
+
+// SuggestOptions orders options by score, then by frequency.
+type SuggestOptions []*SuggestOption
+
+func (so SuggestOptions) Len() int      { return len(so) }
+func (so SuggestOptions) Swap(i, j int) { so[i], so[j] = so[j], so[i] }
+func (so SuggestOptions) Less(i, j int) bool {
+	if so[i].Score != so[j].Score {
+		return so[i].Score > so[j].Score
+	}
+	if so[i].Freq != so[j].Freq {
+		return so[i].Freq > so[j].Freq
+	}
+	return so[i].Text < so[j].Text
+}
+
+// TermSuggestion holds the corrections for one term of the suggest text.
+// Start and End are the byte offsets of the term in the text, Freq is the
+// number of documents containing the term itself.
+type TermSuggestion struct {
+	Text    string         `json:"text"`
+	Start   int            `json:"start"`
+	End     int            `json:"end"`
+	Freq    uint64         `json:"freq"`
+	Options SuggestOptions `json:"options"`
+}
+
+// SuggestTerm finds the terms of the field within maxEdits of term.
+// Only terms sharing the first prefixLength runes of term are considered,
+// which avoids visiting the whole field dictionary.
+func SuggestTerm(indexReader index.IndexReader, field, term string,
+	maxEdits, prefixLength int) (rv *TermSuggestion, err error) {
+	rv = &TermSuggestion{
+		Text:    term,
+		Options: SuggestOptions{},
+	}
+
+	tfr, err := indexReader.TermFieldReader([]byte(term), field, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	rv.Freq = tfr.Count()
+	err = tfr.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: we don't byte slice the term for a prefix because of runes.
+	prefixTerm := ""
+	for i, r := range term {
+		if i < prefixLength {
+			prefixTerm += string(r)
+		} else {
+			break
+		}
+	}
+
+	var fieldDict index.FieldDict
+	if len(prefixTerm) > 0 {
+		fieldDict, err = indexReader.FieldDictPrefix(field, []byte(prefixTerm))
+	} else {
+		fieldDict, err = indexReader.FieldDict(field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := fieldDict.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	tfd, err := fieldDict.Next()
+	for err == nil && tfd != nil {
+		if tfd.Term != term {
+			ld, exceeded := LevenshteinDistanceMax(term, tfd.Term, maxEdits)
+			if !exceeded && ld <= maxEdits {
+				rv.Options = append(rv.Options, &SuggestOption{
+					Text:  tfd.Term,
+					Score: suggestScore(term, tfd.Term, ld),
+					Freq:  tfd.Count,
+				})
+			}
+		}
+		tfd, err = fieldDict.Next()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rv, nil
+}
+
+// suggestScore normalizes the edit distance by the length of the longer
+// term, so a single edit counts for less in longer terms.
+func suggestScore(term, candidate string, distance int) float64 {
+	length := len(term)
+	if len(candidate) > length {
+		length = len(candidate)
+	}
+	if length == 0 {
+		return 0
+	}
+	return 1 - float64(distance)/float64(length)
+}
+
+// Merge combines the suggestions for the same term from another index.
+func (ts *TermSuggestion) Merge(other *TermSuggestion) {
+	ts.Freq += other.Freq
+	for _, option := range other.Options {
+		ts.Options = ts.Options.add(option)
+	}
+}
+
+func (so SuggestOptions) add(option *SuggestOption) SuggestOptions {
+	for _, existing := range so {
+		if existing.Text == option.Text {
+			existing.Freq += option.Freq
+			return so
+		}
+	}
+	return append(so, option)
+}
+
+// Fixup applies the suggest mode, orders the options and trims them to
+// size.
+func (ts *TermSuggestion) Fixup(mode string, size int) {
+	switch mode {
+	case SuggestModeAlways:
+	case SuggestModePopular:
+		j := 0
+		for _, option := range ts.Options {
+			if option.Freq > ts.Freq {
+				ts.Options[j] = option
+				j++
+			}
+		}
+		ts.Options = ts.Options[:j]
+	default:
+		if ts.Freq > 0 {
+			ts.Options = SuggestOptions{}
+		}
+	}
+	sort.Sort(ts.Options)
+	if len(ts.Options) > size {
+		ts.Options = ts.Options[:size]
+	}
+}
+
+// SuggestResult describes the suggestions for a piece of text. Phrases
+// holds rewrites of the whole text, built from the term suggestions.
+type SuggestResult struct {
+	Text    string            `json:"text"`
+	Terms   []*TermSuggestion `json:"terms"`
+	Phrases SuggestOptions    `json:"phrases,omitempty"`
+}
+
+// Merge will merge together the suggestions computed by another index
+// for the same text.
+func (sr *SuggestResult) Merge(other *SuggestResult) {
+	if len(sr.Terms) != len(other.Terms) {
+		return
+	}
+	for i, term := range sr.Terms {
+		term.Merge(other.Terms[i])
+	}
+}
+
+// Fixup finalizes the term suggestions, and when phrase is true builds
+// the phrase suggestions from them.
+func (sr *SuggestResult) Fixup(mode string, size int, phrase bool) {
+	for _, term := range sr.Terms {
+		term.Fixup(mode, size)
+	}
+	sr.Phrases = nil
+	if phrase {
+		sr.Phrases = sr.buildPhrases(size)
+	}
+}
+
+type phraseCandidate struct {
+	text   string
+	weight float64
+}
+
+// candidates returns the alternatives for this term when rewriting the
+// whole text. Terms are weighted by their score and document frequency,
+// so rewrites using common terms score higher. The original term is only
+// an alternative if it is in the index, or if there are no corrections.
+func (ts *TermSuggestion) candidates() []phraseCandidate {
+	rv := make([]phraseCandidate, 0, len(ts.Options)+1)
+	if ts.Freq > 0 {
+		rv = append(rv, phraseCandidate{
+			text:   ts.Text,
+			weight: math.Log1p(float64(ts.Freq)),
+		})
+	} else if len(ts.Options) == 0 {
+		rv = append(rv, phraseCandidate{
+			text:   ts.Text,
+			weight: 1,
+		})
+	}
+	for _, option := range ts.Options {
+		rv = append(rv, phraseCandidate{
+			text:   option.Text,
+			weight: option.Score * math.Log1p(float64(option.Freq)),
+		})
+	}
+	return rv
+}
+
+type partialPhrase struct {
+	terms   []string
+	weight  float64
+	changed bool
+}
+
+type partialPhrases []*partialPhrase
+
+func (pp partialPhrases) Len() int           { return len(pp) }
+func (pp partialPhrases) Swap(i, j int)      { pp[i], pp[j] = pp[j], pp[i] }
+func (pp partialPhrases) Less(i, j int) bool { return pp[i].weight > pp[j].weight }
+
+func (sr *SuggestResult) buildPhrases(size int) SuggestOptions {
+	if len(sr.Terms) == 0 {
+		return nil
+	}
+
+	beam := []*partialPhrase{{weight: 1}}
+	for i, term := range sr.Terms {
+		next := make([]*partialPhrase, 0, len(beam))
+		for _, p := range beam {
+			for _, c := range term.candidates() {
+				terms := make([]string, i+1)
+				copy(terms, p.terms)
+				terms[i] = c.text
+				next = append(next, &partialPhrase{
+					terms:   terms,
+					weight:  p.weight * c.weight,
+					changed: p.changed || c.text != term.Text,
+				})
+			}
+		}
+		sort.Stable(partialPhrases(next))
+		if len(next) > phraseBeamWidth {
+			next = next[:phraseBeamWidth]
+		}
+		beam = next
+	}
+
+	rv := SuggestOptions{}
+	for _, p := range beam {
+		if !p.changed || p.weight <= 0 {
+			continue
+		}
+		rv = append(rv, &SuggestOption{
+			Text:  sr.rewrite(p.terms),
+			Score: math.Pow(p.weight, 1/float64(len(sr.Terms))),
+		})
+		if len(rv) >= size {
+			break
+		}
+	}
+	return rv
+}
+
+// rewrite replaces each term in the text with its replacement, keeping
+// the text between the terms.
+func (sr *SuggestResult) rewrite(replacements []string) string {
+	rv := make([]byte, 0, len(sr.Text))
+	last := 0
+	for i, term := range sr.Terms {
+		if term.Start < last || term.End > len(sr.Text) {
+			continue
+		}
+		rv = append(rv, sr.Text[last:term.Start]...)
+		rv = append(rv, replacements[i]...)
+		last = term.End
+	}
+	rv = append(rv, sr.Text[last:]...)
+	return string(rv)
+}
@@ -0,0 +1,110 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTermSuggestionFixup(t *testing.T) {
+	newSuggestion := func() *TermSuggestion {
+		return &TermSuggestion{
+			Text: "cat",
+			Freq: 2,
+			Options: SuggestOptions{
+				{Text: "bat", Score: 0.66, Freq: 1},
+				{Text: "cot", Score: 0.66, Freq: 5},
+				{Text: "cart", Score: 0.75, Freq: 3},
+			},
+		}
+	}
+
+	tests := []struct {
+		mode string
+		size int
+		want []string
+	}{
+		{SuggestModeMissing, 5, []string{}},
+		{SuggestModeAlways, 5, []string{"cart", "cot", "bat"}},
+		{SuggestModeAlways, 2, []string{"cart", "cot"}},
+		{SuggestModePopular, 5, []string{"cart", "cot"}},
+	}
+
+	for _, test := range tests {
+		ts := newSuggestion()
+		ts.Fixup(test.mode, test.size)
+		got := []string{}
+		for _, option := range ts.Options {
+			got = append(got, option.Text)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expected %v for mode %s size %d, got %v", test.want, test.mode, test.size, got)
+		}
+	}
+}
+
+func TestSuggestResultMerge(t *testing.T) {
+	sr := &SuggestResult{
+		Text: "cat",
+		Terms: []*TermSuggestion{
+			{Text: "cat", Options: SuggestOptions{{Text: "bat", Score: 0.66, Freq: 1}}},
+		},
+	}
+	sr.Merge(&SuggestResult{
+		Text: "cat",
+		Terms: []*TermSuggestion{
+			{Text: "cat", Freq: 4, Options: SuggestOptions{
+				{Text: "bat", Score: 0.66, Freq: 2},
+				{Text: "hat", Score: 0.66, Freq: 1},
+			}},
+		},
+	})
+
+	expected := &TermSuggestion{
+		Text: "cat",
+		Freq: 4,
+		Options: SuggestOptions{
+			{Text: "bat", Score: 0.66, Freq: 3},
+			{Text: "hat", Score: 0.66, Freq: 1},
+		},
+	}
+	if !reflect.DeepEqual(sr.Terms[0], expected) {
+		t.Errorf("expected %#v, got %#v", expected, sr.Terms[0])
+	}
+}
+
+func TestSuggestResultPhrases(t *testing.T) {
+	sr := &SuggestResult{
+		Text: "Teh  cat!",
+		Terms: []*TermSuggestion{
+			{Text: "teh", Start: 0, End: 3, Options: SuggestOptions{
+				{Text: "the", Score: 0.33, Freq: 100},
+				{Text: "ten", Score: 0.66, Freq: 1},
+			}},
+			{Text: "cat", Start: 5, End: 8, Freq: 10},
+		},
+	}
+	sr.Fixup(SuggestModeMissing, 5, true)
+
+	got := []string{}
+	for _, phrase := range sr.Phrases {
+		got = append(got, phrase.Text)
+	}
+	expected := []string{"the  cat!", "ten  cat!"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected phrases %v, got %v", expected, got)
+	}
+}
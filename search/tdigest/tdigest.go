@@ -0,0 +1,185 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tdigest implements the merging t-digest, a compact summary of a
+// distribution which gives accurate estimates of its quantiles, especially
+// the extreme ones. Digests built separately can be merged together.
+package tdigest
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultCompression trades accuracy for size, a digest keeps roughly
+// this many centroids.
+const DefaultCompression = 100
+
+// Centroid summarizes Count values around Mean.
+type Centroid struct {
+	Mean  float64
+	Count float64
+}
+
+type centroids []Centroid
+
+func (c centroids) Len() int           { return len(c) }
+func (c centroids) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c centroids) Less(i, j int) bool { return c[i].Mean < c[j].Mean }
+
+type TDigest struct {
+	compression float64
+	merged      centroids
+	unmerged    centroids
+	count       float64
+	min         float64
+	max         float64
+}
+
+func New(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds a single value to the digest.
+func (t *TDigest) Add(x float64) {
+	t.AddWeighted(x, 1)
+}
+
+// AddWeighted adds a value seen count times to the digest.
+func (t *TDigest) AddWeighted(x, count float64) {
+	if math.IsNaN(x) || count <= 0 {
+		return
+	}
+	t.unmerged = append(t.unmerged, Centroid{Mean: x, Count: count})
+	t.count += count
+	if x < t.min {
+		t.min = x
+	}
+	if x > t.max {
+		t.max = x
+	}
+	if len(t.unmerged) > int(t.compression)*4 {
+		t.compress()
+	}
+}
+
+// Merge adds all of the values summarized by other to this digest.
+func (t *TDigest) Merge(other *TDigest) {
+	if other == nil {
+		return
+	}
+	for _, c := range other.merged {
+		t.AddWeighted(c.Mean, c.Count)
+	}
+	for _, c := range other.unmerged {
+		t.AddWeighted(c.Mean, c.Count)
+	}
+}
+
+// Count returns the number of values added to the digest.
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Centroids returns the centroids summarizing the digest, ordered by mean.
+func (t *TDigest) Centroids() []Centroid {
+	t.compress()
+	rv := make([]Centroid, len(t.merged))
+	copy(rv, t.merged)
+	return rv
+}
+
+// scale maps a quantile to the scale where each centroid may span at
+// most 1, keeping the centroids near the tails small.
+func (t *TDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (t *TDigest) compress() {
+	if len(t.unmerged) == 0 {
+		return
+	}
+	all := make(centroids, 0, len(t.merged)+len(t.unmerged))
+	all = append(all, t.merged...)
+	all = append(all, t.unmerged...)
+	sort.Stable(all)
+
+	rv := make(centroids, 0, len(t.merged)+1)
+	current := all[0]
+	soFar := 0.0
+	kLeft := t.scale(0)
+	for _, c := range all[1:] {
+		q := (soFar + current.Count + c.Count) / t.count
+		if t.scale(q)-kLeft <= 1 {
+			// merge into the current centroid
+			current.Count += c.Count
+			current.Mean += (c.Mean - current.Mean) * c.Count / current.Count
+		} else {
+			soFar += current.Count
+			kLeft = t.scale(soFar / t.count)
+			rv = append(rv, current)
+			current = c
+		}
+	}
+	rv = append(rv, current)
+
+	t.merged = rv
+	t.unmerged = t.unmerged[:0]
+}
+
+// Quantile estimates the value below which the fraction q of the values
+// fall. It returns NaN for an empty digest.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if len(t.merged) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+	if len(t.merged) == 1 {
+		return t.merged[0].Mean
+	}
+
+	// each centroid is centered at the middle of the values it holds,
+	// interpolate between the neighboring centers
+	index := q * t.count
+	first := t.merged[0]
+	if index < first.Count/2 {
+		return t.min + (first.Mean-t.min)*index/(first.Count/2)
+	}
+	soFar := 0.0
+	for i := 0; i < len(t.merged)-1; i++ {
+		left, right := t.merged[i], t.merged[i+1]
+		leftCenter := soFar + left.Count/2
+		rightCenter := soFar + left.Count + right.Count/2
+		if index < rightCenter {
+			return left.Mean + (right.Mean-left.Mean)*(index-leftCenter)/(rightCenter-leftCenter)
+		}
+		soFar += left.Count
+	}
+	last := t.merged[len(t.merged)-1]
+	lastCenter := t.count - last.Count/2
+	return last.Mean + (t.max-last.Mean)*(index-lastCenter)/(last.Count/2)
+}
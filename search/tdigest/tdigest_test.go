@@ -0,0 +1,86 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdigest
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestQuantileSmall(t *testing.T) {
+	td := New(DefaultCompression)
+	for _, x := range []float64{5, 1, 4, 2, 3} {
+		td.Add(x)
+	}
+	tests := []struct {
+		q, want float64
+	}{
+		{0, 1},
+		{0.5, 3},
+		{1, 5},
+		{0.1, 1},
+		{0.9, 5},
+	}
+	for _, test := range tests {
+		got := td.Quantile(test.q)
+		if got != test.want {
+			t.Errorf("expected quantile %f to be %f, got %f", test.q, test.want, got)
+		}
+	}
+
+	if !math.IsNaN(New(DefaultCompression).Quantile(0.5)) {
+		t.Errorf("expected NaN quantile for empty digest")
+	}
+}
+
+func TestQuantileAccuracy(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, 100000)
+	td1 := New(DefaultCompression)
+	td2 := New(DefaultCompression)
+	for i := range values {
+		values[i] = r.NormFloat64()*10 + 50
+		if i%2 == 0 {
+			td1.Add(values[i])
+		} else {
+			td2.Add(values[i])
+		}
+	}
+	sort.Float64s(values)
+
+	// a digest built by merging two halves is as good as one built whole
+	td1.Merge(td2)
+	if td1.Count() != float64(len(values)) {
+		t.Errorf("expected count %d, got %f", len(values), td1.Count())
+	}
+	if len(td1.Centroids()) > 2*DefaultCompression {
+		t.Errorf("expected at most %d centroids, got %d", 2*DefaultCompression, len(td1.Centroids()))
+	}
+
+	for _, q := range []float64{0.001, 0.01, 0.25, 0.5, 0.75, 0.99, 0.999} {
+		got := td1.Quantile(q)
+		// compare the rank of the estimate with the requested quantile
+		rank := float64(sort.SearchFloat64s(values, got)) / float64(len(values))
+		tolerance := 0.01
+		if q < 0.05 || q > 0.95 {
+			tolerance = 0.002
+		}
+		if math.Abs(rank-q) > tolerance {
+			t.Errorf("quantile %f estimate %f has rank %f", q, got, rank)
+		}
+	}
+}
@@ -326,3 +326,36 @@ func TestFacetNumericDateRangeRequests(t *testing.T) {
 	}
 
 }
+
+func TestRescoreCombine(t *testing.T) {
+	tests := []struct {
+		mode     string
+		expected float64
+	}{
+		{"", 14},
+		{RescoreScoreModeTotal, 14},
+		{RescoreScoreModeMultiply, 24},
+		{RescoreScoreModeAvg, 7},
+		{RescoreScoreModeMax, 12},
+		{RescoreScoreModeMin, 2},
+	}
+	for _, test := range tests {
+		r := NewRescoreRequest(NewMatchAllQuery(), 0)
+		r.QueryWeight = 0.5
+		r.RescoreQueryWeight = 3
+		r.ScoreMode = test.mode
+		if err := r.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		got := r.combine(4, 4)
+		if got != test.expected {
+			t.Errorf("%s: expected %f, got %f", test.mode, test.expected, got)
+		}
+	}
+
+	r := NewRescoreRequest(NewMatchAllQuery(), 0)
+	r.ScoreMode = "median"
+	if r.Validate() == nil {
+		t.Errorf("expected error for unknown score mode")
+	}
+}
@@ -0,0 +1,156 @@
+//  Copyright (c) 2017 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/edwindvinas/bleve/index"
+	"github.com/edwindvinas/bleve/search"
+)
+
+const (
+	DefaultSuggestSize         = 5
+	DefaultSuggestMaxEdits     = 2
+	DefaultSuggestPrefixLength = 1
+)
+
+// A SuggestRequest asks for spelling corrections ("did you mean") of
+// Text, using the terms indexed in Field.
+// Text is analyzed with Analyzer, or with the analyzer of the field if
+// Analyzer is empty, and corrections are proposed for each of the
+// resulting terms. Corrections are within MaxEdits edits of the term,
+// DefaultSuggestMaxEdits if nil, and ranked by edit distance, then by
+// the number of documents containing them.
+// PrefixLength is the number of leading runes a correction must share
+// with the term, which avoids visiting the whole dictionary for each
+// term. It defaults to DefaultSuggestPrefixLength if nil, so corrections
+// of the first rune of a term are only proposed when it is set to 0.
+// Mode controls which terms get corrections, one of
+// search.SuggestModeMissing (the default), search.SuggestModePopular or
+// search.SuggestModeAlways.
+// Phrase also requests rewrites of the whole text, built by combining
+// the term corrections.
+type SuggestRequest struct {
+	Text         string `json:"text"`
+	Field        string `json:"field,omitempty"`
+	Analyzer     string `json:"analyzer,omitempty"`
+	Size         int    `json:"size,omitempty"`
+	MaxEdits     *int   `json:"max_edits,omitempty"`
+	PrefixLength *int   `json:"prefix_length,omitempty"`
+	Mode         string `json:"mode,omitempty"`
+	Phrase       bool   `json:"phrase,omitempty"`
+}
+
+// NewSuggestRequest creates a SuggestRequest correcting the text using
+// the terms of the field, with default values for all other parameters.
+func NewSuggestRequest(text, field string) *SuggestRequest {
+	return &SuggestRequest{
+		Text:  text,
+		Field: field,
+	}
+}
+
+// SetMaxEdits sets the number of edits of the corrections.
+func (r *SuggestRequest) SetMaxEdits(maxEdits int) {
+	r.MaxEdits = &maxEdits
+}
+
+// SetPrefixLength sets the number of leading runes the corrections
+// share with the term, 0 for none.
+func (r *SuggestRequest) SetPrefixLength(prefixLength int) {
+	r.PrefixLength = &prefixLength
+}
+
+func (r *SuggestRequest) Validate() error {
+	if r.Size < 0 {
+		return fmt.Errorf("suggest size must not be negative")
+	}
+	if r.MaxEdits != nil && *r.MaxEdits < 0 {
+		return fmt.Errorf("suggest max edits must not be negative")
+	}
+	if r.PrefixLength != nil && *r.PrefixLength < 0 {
+		return fmt.Errorf("suggest prefix length must not be negative")
+	}
+	switch r.Mode {
+	case "", search.SuggestModeMissing, search.SuggestModePopular, search.SuggestModeAlways:
+	default:
+		return fmt.Errorf("unknown suggest mode '%s'", r.Mode)
+	}
+	return nil
+}
+
+func (r *SuggestRequest) size() int {
+	if r.Size == 0 {
+		return DefaultSuggestSize
+	}
+	return r.Size
+}
+
+func (r *SuggestRequest) maxEdits() int {
+	if r.MaxEdits == nil {
+		return DefaultSuggestMaxEdits
+	}
+	return *r.MaxEdits
+}
+
+func (r *SuggestRequest) prefixLength() int {
+	if r.PrefixLength == nil {
+		return DefaultSuggestPrefixLength
+	}
+	return *r.PrefixLength
+}
+
+func (r *SuggestRequest) fixup(sr *search.SuggestResult) {
+	sr.Fixup(r.Mode, r.size(), r.Phrase)
+}
+
+func (i *indexImpl) suggest(indexReader index.IndexReader,
+	req *SuggestRequest) (*search.SuggestResult, error) {
+	field := req.Field
+	if field == "" {
+		field = i.m.DefaultSearchField()
+	}
+	analyzerName := req.Analyzer
+	if analyzerName == "" {
+		analyzerName = i.m.AnalyzerNameForPath(field)
+	}
+	analyzer := i.m.AnalyzerNamed(analyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+	}
+
+	rv := &search.SuggestResult{
+		Text:  req.Text,
+		Terms: []*search.TermSuggestion{},
+	}
+	last := 0
+	for _, token := range analyzer.Analyze([]byte(req.Text)) {
+		// skip tokens overlapping the previous one, such as synonyms
+		if token.Start < last {
+			continue
+		}
+		last = token.End
+		ts, err := search.SuggestTerm(indexReader, field, string(token.Term),
+			req.maxEdits(), req.prefixLength())
+		if err != nil {
+			return nil, err
+		}
+		ts.Start = token.Start
+		ts.End = token.End
+		rv.Terms = append(rv.Terms, ts)
+	}
+	return rv, nil
+}